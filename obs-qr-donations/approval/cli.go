@@ -0,0 +1,98 @@
+package approval
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// RunCLI runs an approval prompt on in and out, e.g. a terminal, deciding
+// as approver by. It understands:
+//
+//	list                      show the pending requests
+//	approve <id>              approve a request
+//	reject <id> [reason...]   reject a request
+//
+// It returns at the end of in or when ctx is done. A read in progress is
+// then abandoned rather than interrupted, as io.Reader has no way to.
+func RunCLI(ctx context.Context, q *Queue, by string, in io.Reader, out io.Writer) error {
+	lines := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	q.printPending(out)
+	for {
+		fmt.Fprint(out, "> ")
+		var line string
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			return err
+		case line = <-lines:
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var err error
+		switch cmd := fields[0]; {
+		case cmd == "list" || cmd == "ls":
+			q.printPending(out)
+			continue
+		case (cmd == "approve" || cmd == "a") && len(fields) == 2:
+			err = q.Approve(fields[1], by)
+		case (cmd == "reject" || cmd == "r") && len(fields) >= 2:
+			err = q.Reject(fields[1], by, strings.Join(fields[2:], " "))
+		default:
+			fmt.Fprintln(out, "commands: list, approve <id>, reject <id> [reason...]")
+			continue
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+		} else {
+			fmt.Fprintln(out, "done")
+		}
+	}
+}
+
+func (q *Queue) printPending(out io.Writer) {
+	reqs := q.Pending()
+	if len(reqs) == 0 {
+		fmt.Fprintln(out, "no pending payments")
+		return
+	}
+	now := q.now()
+	for _, r := range reqs {
+		fmt.Fprintf(out, "%s  expires in %s\n", r.ID, r.Deadline.Sub(now).Round(time.Second))
+		for _, p := range r.Payments {
+			amount := p.Amount + " sats"
+			if p.TokenID != "" {
+				amount = p.Amount + " units of " + p.TokenID
+			}
+			fmt.Fprintf(out, "  %-9s  %s  to %s\n", p.Kind, amount, shorten(p.Destination))
+		}
+	}
+}
+
+// shorten cuts long destinations, such as invoices, to fit a line.
+func shorten(s string) string {
+	const maxLen = 48
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-12] + "…" + s[len(s)-11:]
+}
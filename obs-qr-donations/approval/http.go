@@ -0,0 +1,75 @@
+package approval
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxDecisionSize bounds the body of decision requests.
+const maxDecisionSize = 16 << 10
+
+// Handler returns the HTTP endpoint of the queue. Every request must carry
+// "Authorization: Bearer <Config.Token>". It serves:
+//
+//	GET  /pending               the pending requests, as a JSON array
+//	POST /pending/{id}/approve  body {"by": "alice"}
+//	POST /pending/{id}/reject   body {"by": "alice", "reason": "too much"}
+//
+// Decisions answer 204, or 404 for requests that are not pending. The
+// endpoint has no TLS: serve it on a loopback address, or behind a proxy
+// that terminates TLS.
+func (q *Queue) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /pending", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(q.Pending())
+	})
+	mux.HandleFunc("POST /pending/{id}/approve", func(w http.ResponseWriter, r *http.Request) {
+		q.serveDecision(w, r, true)
+	})
+	mux.HandleFunc("POST /pending/{id}/reject", func(w http.ResponseWriter, r *http.Request) {
+		q.serveDecision(w, r, false)
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !q.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="approval"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (q *Queue) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && q.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(q.token)) == 1
+}
+
+func (q *Queue) serveDecision(w http.ResponseWriter, r *http.Request, approve bool) {
+	var body struct {
+		By     string `json:"by"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxDecisionSize)).Decode(&body); err != nil || body.By == "" {
+		http.Error(w, `body must be {"by": "<approver>"}`, http.StatusBadRequest)
+		return
+	}
+	var err error
+	if approve {
+		err = q.Approve(r.PathValue("id"), body.By)
+	} else {
+		err = q.Reject(r.PathValue("id"), body.By, body.Reason)
+	}
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
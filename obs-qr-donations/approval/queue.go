@@ -0,0 +1,263 @@
+// Package approval holds outgoing payments until someone signs them off.
+// A Queue is a PaymentObserver: its BeforeSend parks the payments the SDK
+// is about to make and waits for an approver to approve or reject them,
+// refusing them if nobody does in time. That way a moderator at the OBS
+// dock's send dialog cannot make a large payout from the stream wallet on
+// their own.
+//
+// Approvers decide through the local HTTP endpoint of Handler, the
+// terminal prompt of RunCLI, or from Go, reading Config.Notify and calling
+// Approve or Reject:
+//
+//	q := approval.New(approval.Config{MinSats: 100_000, Token: os.Getenv("APPROVAL_TOKEN")})
+//	builder.WithPaymentObserver(q)
+//	go http.ListenAndServe("127.0.0.1:8089", q.Handler())
+package approval
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/sdkutil"
+)
+
+// DefaultTimeout is how long payments wait for a decision.
+const DefaultTimeout = 5 * time.Minute
+
+// Errors identifying why payments were refused. They can be tested with
+// errors.Is; the errors returned by BeforeSend are also
+// *breez_sdk_spark.PaymentObserverError values, as the SDK expects.
+var (
+	ErrRejected = errors.New("approval: rejected")
+	ErrTimeout  = errors.New("approval: no decision in time")
+	ErrClosed   = errors.New("approval: queue closed")
+	// ErrNotFound is returned by Approve and Reject for requests that are
+	// not pending: unknown, already decided or timed out.
+	ErrNotFound = errors.New("approval: no such pending request")
+)
+
+// Config configures a Queue.
+type Config struct {
+	// MinSats is the amount from which bitcoin payments need approval.
+	// Batches of smaller bitcoin payments go through at once. Token
+	// payments always need approval, their units not being comparable.
+	MinSats uint64
+	// Timeout defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Notify, when set, receives every new request, for approvers written
+	// in Go. Sends do not block: requests are not delivered while the
+	// channel is full.
+	Notify chan<- Request
+	// Token is the bearer token Handler requires. Handler refuses every
+	// request while it is empty.
+	Token string
+	// Logger receives a record of every request and decision. Defaults to
+	// no logging.
+	Logger *slog.Logger
+}
+
+// Request is a batch of payments waiting for a decision.
+type Request struct {
+	ID       string    `json:"id"`
+	Payments []Payment `json:"payments"`
+	Created  time.Time `json:"created"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// Payment describes a payment of a Request.
+type Payment = sdkutil.Payment
+
+type decision struct {
+	approved bool
+	by       string
+	reason   string
+}
+
+type pending struct {
+	req     Request
+	decided chan decision
+}
+
+// Queue is a breez_sdk_spark.PaymentObserver waiting for approval of the
+// payments it observes.
+type Queue struct {
+	minSats uint64
+	timeout time.Duration
+	notify  chan<- Request
+	token   string
+	logger  *slog.Logger
+	now     func() time.Time
+
+	mu      sync.Mutex
+	closed  bool
+	pending map[string]*pending
+}
+
+var _ breez_sdk_spark.PaymentObserver = (*Queue)(nil)
+
+// New returns a Queue for cfg.
+func New(cfg Config) *Queue {
+	q := &Queue{
+		minSats: cfg.MinSats,
+		timeout: cfg.Timeout,
+		notify:  cfg.Notify,
+		token:   cfg.Token,
+		logger:  cfg.Logger,
+		now:     time.Now,
+		pending: make(map[string]*pending),
+	}
+	if q.timeout == 0 {
+		q.timeout = DefaultTimeout
+	}
+	if q.logger == nil {
+		q.logger = slog.New(slog.DiscardHandler)
+	}
+	return q
+}
+
+// BeforeSend waits until the payments are approved, unless none needs
+// approval, and returns an error if they are rejected, time out or the
+// queue is closed.
+func (q *Queue) BeforeSend(payments []breez_sdk_spark.ProvisionalPayment) error {
+	if !q.needsApproval(payments) {
+		return nil
+	}
+	now := q.now()
+	p := &pending{
+		req:     Request{ID: newID(), Created: now, Deadline: now.Add(q.timeout)},
+		decided: make(chan decision, 1),
+	}
+	for _, payment := range payments {
+		p.req.Payments = append(p.req.Payments, sdkutil.Describe(payment))
+	}
+
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return refuse(ErrClosed, p.req.ID)
+	}
+	q.pending[p.req.ID] = p
+	q.mu.Unlock()
+	q.logger.Info("payment awaiting approval", "request_id", p.req.ID, "payments", len(payments))
+	if q.notify != nil {
+		select {
+		case q.notify <- p.req:
+		default:
+			q.logger.Warn("approval notification dropped", "request_id", p.req.ID)
+		}
+	}
+
+	timer := time.NewTimer(q.timeout)
+	defer timer.Stop()
+	var d decision
+	select {
+	case d = <-p.decided:
+	case <-timer.C:
+		q.mu.Lock()
+		if _, ok := q.pending[p.req.ID]; ok {
+			delete(q.pending, p.req.ID)
+			q.mu.Unlock()
+			q.logger.Warn("payment approval timed out", "request_id", p.req.ID)
+			return refuse(ErrTimeout, p.req.ID)
+		}
+		q.mu.Unlock()
+		// Decided while timing out.
+		d = <-p.decided
+	}
+	if !d.approved {
+		if d.by == "" {
+			return refuse(ErrClosed, p.req.ID)
+		}
+		detail := fmt.Sprintf("%s by %s", p.req.ID, d.by)
+		if d.reason != "" {
+			detail += ": " + d.reason
+		}
+		return refuse(ErrRejected, detail)
+	}
+	return nil
+}
+
+func (q *Queue) needsApproval(payments []breez_sdk_spark.ProvisionalPayment) bool {
+	return slices.ContainsFunc(payments, func(p breez_sdk_spark.ProvisionalPayment) bool {
+		if _, token := p.Details.(breez_sdk_spark.ProvisionalPaymentDetailsToken); token {
+			return true
+		}
+		return p.Amount == nil || p.Amount.Cmp(new(big.Int).SetUint64(q.minSats)) >= 0
+	})
+}
+
+// Pending returns the requests waiting for a decision, oldest first.
+func (q *Queue) Pending() []Request {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	reqs := make([]Request, 0, len(q.pending))
+	for _, p := range q.pending {
+		reqs = append(reqs, p.req)
+	}
+	slices.SortFunc(reqs, func(a, b Request) int {
+		if c := a.Created.Compare(b.Created); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return reqs
+}
+
+// Approve lets the payments of request id be sent. by names the approver
+// for the log.
+func (q *Queue) Approve(id, by string) error {
+	return q.decide(id, decision{approved: true, by: by})
+}
+
+// Reject refuses the payments of request id, giving the reason to the
+// SDK.
+func (q *Queue) Reject(id, by, reason string) error {
+	return q.decide(id, decision{by: by, reason: reason})
+}
+
+func (q *Queue) decide(id string, d decision) error {
+	if d.by == "" {
+		return errors.New("approval: the approver must be named")
+	}
+	q.mu.Lock()
+	p, ok := q.pending[id]
+	delete(q.pending, id)
+	q.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	q.logger.Info("payment decided", "request_id", id, "approved", d.approved, "by", d.by, "reason", d.reason)
+	p.decided <- d
+	return nil
+}
+
+// Close rejects the pending requests and any later one.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	for id, p := range q.pending {
+		delete(q.pending, id)
+		p.decided <- decision{}
+	}
+	return nil
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func refuse(reason error, detail string) error {
+	return sdkutil.Refuse(reason, breez_sdk_spark.NewPaymentObserverErrorGeneric, detail)
+}
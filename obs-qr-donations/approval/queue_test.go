@@ -0,0 +1,209 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+func lightning(id string, sats int64) breez_sdk_spark.ProvisionalPayment {
+	return breez_sdk_spark.ProvisionalPayment{PaymentId: id, Amount: big.NewInt(sats), Details: breez_sdk_spark.ProvisionalPaymentDetailsLightning{Invoice: "lnbc1500u1pjexample"}}
+}
+
+// send runs BeforeSend in the background.
+func send(q *Queue, payments ...breez_sdk_spark.ProvisionalPayment) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- q.BeforeSend(payments) }()
+	return done
+}
+
+func wait(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("BeforeSend did not return")
+		return nil
+	}
+}
+
+func TestChannelApprover(t *testing.T) {
+	notify := make(chan Request, 1)
+	q := New(Config{MinSats: 10_000, Notify: notify})
+
+	// Small payments go through.
+	if err := q.BeforeSend([]breez_sdk_spark.ProvisionalPayment{lightning("tip", 9_999)}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := send(q, lightning("payout", 150_000), breez_sdk_spark.ProvisionalPayment{
+		PaymentId: "usdb",
+		Amount:    big.NewInt(5),
+		Details:   breez_sdk_spark.ProvisionalPaymentDetailsToken{TokenId: "btkn1usdb", PayRequest: "spark1editor"},
+	})
+	req := <-notify
+	want := []Payment{
+		{PaymentID: "payout", Kind: "lightning", Amount: "150000", Destination: "lnbc1500u1pjexample"},
+		{PaymentID: "usdb", Kind: "token", Amount: "5", TokenID: "btkn1usdb", Destination: "spark1editor"},
+	}
+	if len(req.Payments) != 2 || req.Payments[0] != want[0] || req.Payments[1] != want[1] {
+		t.Fatalf("request = %+v", req)
+	}
+	if pending := q.Pending(); len(pending) != 1 || pending[0].ID != req.ID {
+		t.Fatalf("pending = %+v", pending)
+	}
+	if err := q.Approve(req.ID, ""); err == nil {
+		t.Fatal("approved anonymously")
+	}
+	if err := q.Approve(req.ID, "streamer"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait(t, done); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Approve(req.ID, "streamer"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("second decision: %v", err)
+	}
+
+	done = send(q, lightning("payout2", 150_000))
+	req = <-notify
+	q.Reject(req.ID, "streamer", "not today")
+	err := wait(t, done)
+	var sdkErr *breez_sdk_spark.PaymentObserverError
+	if !errors.Is(err, ErrRejected) || !errors.As(err, &sdkErr) || !strings.Contains(err.Error(), "not today") {
+		t.Fatalf("got %v", err)
+	}
+	if len(q.Pending()) != 0 {
+		t.Fatal("decided request still pending")
+	}
+}
+
+func TestTimeoutAndClose(t *testing.T) {
+	q := New(Config{Timeout: 20 * time.Millisecond})
+	if err := wait(t, send(q, lightning("a", 1))); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("got %v", err)
+	}
+	if len(q.Pending()) != 0 {
+		t.Fatal("timed out request still pending")
+	}
+
+	q = New(Config{})
+	done := send(q, lightning("a", 1))
+	for len(q.Pending()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	q.Close()
+	if err := wait(t, done); !errors.Is(err, ErrClosed) {
+		t.Fatalf("pending at close: %v", err)
+	}
+	if err := q.BeforeSend([]breez_sdk_spark.ProvisionalPayment{lightning("b", 1)}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("after close: %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	notify := make(chan Request, 1)
+	q := New(Config{Notify: notify, Token: "s3cret"})
+	srv := httptest.NewServer(q.Handler())
+	defer srv.Close()
+	call := func(method, path, token, body string) (int, string) {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	done := send(q, lightning("payout", 150_000))
+	id := (<-notify).ID
+
+	if status, _ := call("GET", "/pending", "", ""); status != http.StatusUnauthorized {
+		t.Fatalf("no token: %d", status)
+	}
+	if status, _ := call("GET", "/pending", "guess", ""); status != http.StatusUnauthorized {
+		t.Fatalf("wrong token: %d", status)
+	}
+	status, body := call("GET", "/pending", "s3cret", "")
+	var pending []Request
+	if status != 200 || json.Unmarshal([]byte(body), &pending) != nil || len(pending) != 1 || pending[0].Payments[0].Amount != "150000" {
+		t.Fatalf("list: %d %s", status, body)
+	}
+	if status, _ := call("POST", "/pending/"+id+"/approve", "s3cret", `{}`); status != http.StatusBadRequest {
+		t.Fatalf("anonymous approval: %d", status)
+	}
+	if status, _ := call("POST", "/pending/nope/approve", "s3cret", `{"by": "editor"}`); status != http.StatusNotFound {
+		t.Fatalf("unknown id: %d", status)
+	}
+	if status, _ := call("POST", "/pending/"+id+"/reject", "s3cret", `{"by": "editor", "reason": "wrong address"}`); status != http.StatusNoContent {
+		t.Fatalf("reject: %d", status)
+	}
+	if err := wait(t, done); !errors.Is(err, ErrRejected) || !strings.Contains(err.Error(), "wrong address") {
+		t.Fatalf("got %v", err)
+	}
+
+	// Without a token, nobody gets in.
+	open := httptest.NewServer(New(Config{}).Handler())
+	defer open.Close()
+	resp, err := http.Get(open.URL + "/pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("no configured token: %d", resp.StatusCode)
+	}
+}
+
+func TestCLI(t *testing.T) {
+	notify := make(chan Request, 2)
+	q := New(Config{Notify: notify})
+	first := send(q, lightning("a", 150_000))
+	second := send(q, lightning("b", 150_000))
+	ids := map[string]string{}
+	for range 2 {
+		req := <-notify
+		ids[req.Payments[0].PaymentID] = req.ID
+	}
+
+	in, input := io.Pipe()
+	var out strings.Builder
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ran := make(chan error, 1)
+	go func() { ran <- RunCLI(ctx, q, "mod", in, &out) }()
+
+	io.WriteString(input, "frobnicate\napprove "+ids["a"]+"\n")
+	if err := wait(t, first); err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(input, "reject "+ids["b"]+" over budget\n")
+	if err := wait(t, second); !errors.Is(err, ErrRejected) || !strings.Contains(err.Error(), "by mod: over budget") {
+		t.Fatalf("got %v", err)
+	}
+	io.WriteString(input, "list\n")
+	input.Close()
+	if err := <-ran; err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"lightning  150000 sats  to lnbc1500u1pjexample", "commands:", "done", "no pending payments"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,596 @@
+// Package bitcoindtest provides a fake bitcoind JSON-RPC server for tests.
+//
+// The server keeps a small chain in memory: blocks, a mempool, the outputs
+// paying each address and per-target fee estimates. It answers the RPCs the
+// bitcoind package uses, the way Bitcoin Core does, including its error
+// codes. Handle overrides single methods to inject failures.
+package bitcoindtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Error is an RPC error returned by a handler.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// Handler answers one RPC. Returning an *Error sends it as an RPC error; any
+// other error becomes a -1 miscellaneous error.
+type Handler func(params []json.RawMessage) (any, error)
+
+// GenesisTime is the timestamp of block 0. Each later block is ten minutes
+// after the previous one.
+const GenesisTime = 1_700_000_000
+
+type tx struct {
+	hex    string
+	height int // -1 while in the mempool
+}
+
+type output struct {
+	address string
+	txid    string
+	vout    uint32
+	sats    uint64
+}
+
+// Server is a fake bitcoind. Create it with NewServer and Close it when
+// done.
+type Server struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	user, password string
+	blocks         []string // block hashes by height
+	txs            map[string]*tx
+	outputs        []output
+	feeRates       map[uint32]string
+	mempoolMinFee  string
+	txIndex        bool
+	wallets        map[string]map[string]bool // wallet name to watched addresses
+	handlers       map[string]Handler
+	calls          map[string]int
+}
+
+// NewServer starts a server with only the genesis block, transaction index
+// enabled, no fee estimates and a mempool minimum fee of 1 sat/vB.
+func NewServer() *Server {
+	s := &Server{
+		txs:           map[string]*tx{},
+		feeRates:      map[uint32]string{},
+		mempoolMinFee: "0.00001000",
+		txIndex:       true,
+		wallets:       map[string]map[string]bool{},
+		handlers:      map[string]Handler{},
+		calls:         map[string]int{},
+	}
+	s.blocks = []string{blockHash(0)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func blockHash(height int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("block %d", height)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Txid returns the id of a raw transaction the way bitcoind computes it for
+// transactions without witness data.
+func Txid(rawHex string) (string, error) {
+	raw, err := hex.DecodeString(rawHex)
+	if err != nil || len(raw) == 0 {
+		return "", fmt.Errorf("TX decode failed")
+	}
+	first := sha256.Sum256(raw)
+	second := sha256.Sum256(first[:])
+	for i, j := 0, len(second)-1; i < j; i, j = i+1, j-1 {
+		second[i], second[j] = second[j], second[i]
+	}
+	return hex.EncodeToString(second[:]), nil
+}
+
+// SetCredentials makes the server require HTTP basic auth.
+func (s *Server) SetCredentials(user, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.user, s.password = user, password
+}
+
+// SetTxIndex toggles -txindex. Without it getrawtransaction only finds
+// mempool transactions.
+func (s *Server) SetTxIndex(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txIndex = enabled
+}
+
+// SetFeeRate sets the estimatesmartfee answer for target, in BTC/kvB. A
+// request is answered from the nearest configured target at or above it.
+func (s *Server) SetFeeRate(target uint32, btcPerKvB string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.feeRates[target] = btcPerKvB
+}
+
+// SetMempoolMinFee sets mempoolminfee in BTC/kvB.
+func (s *Server) SetMempoolMinFee(btcPerKvB string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mempoolMinFee = btcPerKvB
+}
+
+// CreateWallet adds an empty watch-only wallet.
+func (s *Server) CreateWallet(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wallets[name] = map[string]bool{}
+}
+
+// Handle overrides method. A nil handler restores the built-in one.
+func (s *Server) Handle(method string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if h == nil {
+		delete(s.handlers, method)
+		return
+	}
+	s.handlers[method] = h
+}
+
+// Calls reports how often method was called.
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// Height returns the height of the tip.
+func (s *Server) Height() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.blocks) - 1
+}
+
+// BlockHash returns the hash of the block at height.
+func (s *Server) BlockHash(height int) string {
+	return blockHash(height)
+}
+
+// AddTransaction puts a transaction into the mempool and returns its txid.
+func (s *Server) AddTransaction(rawHex string) (string, error) {
+	txid, err := Txid(rawHex)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.txs[txid]; !ok {
+		s.txs[txid] = &tx{hex: rawHex, height: -1}
+	}
+	return txid, nil
+}
+
+// Pay records that output vout of the mempool or chain transaction txid pays
+// sats to address.
+func (s *Server) Pay(address, txid string, vout uint32, sats uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outputs = append(s.outputs, output{address: address, txid: txid, vout: vout, sats: sats})
+}
+
+// Spend removes an output from the UTXO set.
+func (s *Server) Spend(txid string, vout uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, o := range s.outputs {
+		if o.txid == txid && o.vout == vout {
+			s.outputs = append(s.outputs[:i], s.outputs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Mine appends n blocks. The first one confirms every mempool transaction.
+func (s *Server) Mine(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		height := len(s.blocks)
+		s.blocks = append(s.blocks, blockHash(height))
+		for _, t := range s.txs {
+			if t.height < 0 {
+				t.height = height
+			}
+		}
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, password := s.user, s.password
+	s.mu.Unlock()
+	if user != "" || password != "" {
+		u, p, ok := r.BasicAuth()
+		if !ok || u != user || p != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Parse error", http.StatusInternalServerError)
+		return
+	}
+	wallet := ""
+	if name, ok := strings.CutPrefix(r.URL.Path, "/wallet/"); ok {
+		wallet = name
+	}
+
+	result, err := s.dispatch(wallet, req.Method, req.Params)
+	resp := map[string]any{"id": req.ID, "result": result, "error": nil}
+	status := http.StatusOK
+	if err != nil {
+		rpcErr, ok := err.(*Error)
+		if !ok {
+			rpcErr = &Error{Code: -1, Message: err.Error()}
+		}
+		resp["result"] = nil
+		resp["error"] = rpcErr
+		status = http.StatusInternalServerError
+		if rpcErr.Code == -32601 || rpcErr.Code == -18 {
+			status = http.StatusNotFound
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) dispatch(wallet, method string, params []json.RawMessage) (any, error) {
+	s.mu.Lock()
+	s.calls[method]++
+	h, ok := s.handlers[method]
+	s.mu.Unlock()
+	if ok {
+		return h(params)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var watched map[string]bool
+	if wallet != "" {
+		if watched, ok = s.wallets[wallet]; !ok {
+			return nil, &Error{Code: -18, Message: fmt.Sprintf("Requested wallet does not exist or is not loaded: %s", wallet)}
+		}
+	}
+	switch method {
+	case "getblockcount":
+		return len(s.blocks) - 1, nil
+	case "getblockhash":
+		var height int
+		if err := param(params, 0, &height); err != nil {
+			return nil, err
+		}
+		if height < 0 || height >= len(s.blocks) {
+			return nil, &Error{Code: -8, Message: "Block height out of range"}
+		}
+		return s.blocks[height], nil
+	case "getblockheader":
+		var hash string
+		if err := param(params, 0, &hash); err != nil {
+			return nil, err
+		}
+		for height, h := range s.blocks {
+			if h == hash {
+				return map[string]any{
+					"hash":          h,
+					"height":        height,
+					"time":          GenesisTime + 600*height,
+					"confirmations": len(s.blocks) - height,
+				}, nil
+			}
+		}
+		return nil, &Error{Code: -5, Message: "Block not found"}
+	case "getmempoolinfo":
+		return map[string]any{
+			"loaded":        true,
+			"size":          s.mempoolSize(),
+			"mempoolminfee": json.Number(s.mempoolMinFee),
+			"minrelaytxfee": json.Number("0.00001000"),
+		}, nil
+	case "estimatesmartfee":
+		var target uint32
+		if err := param(params, 0, &target); err != nil {
+			return nil, err
+		}
+		if target < 1 || target > 1008 {
+			return nil, &Error{Code: -8, Message: "Invalid conf_target, must be between 1 and 1008"}
+		}
+		return s.estimate(target), nil
+	case "getrawtransaction":
+		return s.getRawTransaction(params)
+	case "sendrawtransaction":
+		return s.sendRawTransaction(params)
+	case "scantxoutset":
+		return s.scanTxOutSet(params)
+	case "getdescriptorinfo":
+		var desc string
+		if err := param(params, 0, &desc); err != nil {
+			return nil, err
+		}
+		if _, err := descriptorAddress(desc); err != nil {
+			return nil, err
+		}
+		return map[string]any{"descriptor": desc + "#fakechk0", "checksum": "fakechk0"}, nil
+	case "importdescriptors":
+		if watched == nil {
+			return nil, &Error{Code: -18, Message: "No wallet is loaded"}
+		}
+		var requests []struct {
+			Desc string `json:"desc"`
+		}
+		if err := param(params, 0, &requests); err != nil {
+			return nil, err
+		}
+		var results []map[string]any
+		for _, r := range requests {
+			address, err := descriptorAddress(r.Desc)
+			if err != nil {
+				results = append(results, map[string]any{"success": false, "error": err})
+				continue
+			}
+			watched[address] = true
+			results = append(results, map[string]any{"success": true})
+		}
+		return results, nil
+	case "listunspent":
+		if watched == nil {
+			return nil, &Error{Code: -18, Message: "No wallet is loaded"}
+		}
+		return s.listUnspent(watched, params)
+	case "gettransaction":
+		if watched == nil {
+			return nil, &Error{Code: -18, Message: "No wallet is loaded"}
+		}
+		return s.getWalletTransaction(watched, params)
+	}
+	return nil, &Error{Code: -32601, Message: "Method not found"}
+}
+
+func param(params []json.RawMessage, i int, v any) error {
+	if i >= len(params) {
+		return &Error{Code: -1, Message: fmt.Sprintf("missing parameter %d", i+1)}
+	}
+	if err := json.Unmarshal(params[i], v); err != nil {
+		return &Error{Code: -3, Message: fmt.Sprintf("parameter %d: %v", i+1, err)}
+	}
+	return nil
+}
+
+var addressPattern = regexp.MustCompile(`^[a-zA-Z0-9]{14,90}$`)
+
+// descriptorAddress extracts the address of an addr() descriptor. Anything
+// alphanumeric of a plausible length passes as an address.
+func descriptorAddress(desc string) (string, error) {
+	desc, _, _ = strings.Cut(desc, "#")
+	address, ok := strings.CutPrefix(desc, "addr(")
+	if !ok || !strings.HasSuffix(address, ")") {
+		return "", &Error{Code: -5, Message: fmt.Sprintf("'%s' is not a valid descriptor function", desc)}
+	}
+	address = strings.TrimSuffix(address, ")")
+	if !addressPattern.MatchString(address) {
+		return "", &Error{Code: -5, Message: fmt.Sprintf("Address is not valid: %s", address)}
+	}
+	return address, nil
+}
+
+func (s *Server) mempoolSize() int {
+	n := 0
+	for _, t := range s.txs {
+		if t.height < 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) estimate(target uint32) map[string]any {
+	targets := make([]uint32, 0, len(s.feeRates))
+	for t := range s.feeRates {
+		targets = append(targets, t)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+	for _, t := range targets {
+		if t >= target {
+			return map[string]any{"feerate": json.Number(s.feeRates[t]), "blocks": t}
+		}
+	}
+	return map[string]any{"errors": []string{"Insufficient data or no feerate found"}, "blocks": 0}
+}
+
+func (s *Server) confirmations(t *tx) int {
+	if t.height < 0 {
+		return 0
+	}
+	return len(s.blocks) - t.height
+}
+
+func (s *Server) getRawTransaction(params []json.RawMessage) (any, error) {
+	var txid string
+	if err := param(params, 0, &txid); err != nil {
+		return nil, err
+	}
+	var verbose bool
+	if len(params) > 1 {
+		var v any
+		if err := param(params, 1, &v); err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case bool:
+			verbose = v
+		case float64:
+			verbose = v > 0
+		}
+	}
+	t, ok := s.txs[txid]
+	if !ok || (!s.txIndex && t.height >= 0) {
+		return nil, &Error{Code: -5, Message: "No such mempool or blockchain transaction. Use gettransaction for wallet transactions."}
+	}
+	if !verbose {
+		return t.hex, nil
+	}
+	result := map[string]any{"txid": txid, "hex": t.hex}
+	if t.height >= 0 {
+		result["blockhash"] = s.blocks[t.height]
+		result["confirmations"] = s.confirmations(t)
+		result["blocktime"] = GenesisTime + 600*t.height
+	}
+	return result, nil
+}
+
+func (s *Server) sendRawTransaction(params []json.RawMessage) (any, error) {
+	var rawHex string
+	if err := param(params, 0, &rawHex); err != nil {
+		return nil, err
+	}
+	txid, err := Txid(rawHex)
+	if err != nil {
+		return nil, &Error{Code: -22, Message: "TX decode failed. Make sure the tx has at least one input."}
+	}
+	if t, ok := s.txs[txid]; ok {
+		if t.height >= 0 {
+			return nil, &Error{Code: -27, Message: "Transaction already in block chain"}
+		}
+		return nil, &Error{Code: -26, Message: "txn-already-in-mempool"}
+	}
+	s.txs[txid] = &tx{hex: rawHex, height: -1}
+	return txid, nil
+}
+
+func btc(sats uint64) json.Number {
+	return json.Number(fmt.Sprintf("%d.%08d", sats/100_000_000, sats%100_000_000))
+}
+
+func (s *Server) scanTxOutSet(params []json.RawMessage) (any, error) {
+	var action string
+	if err := param(params, 0, &action); err != nil {
+		return nil, err
+	}
+	if action != "start" {
+		return nil, &Error{Code: -8, Message: "Invalid action '" + action + "'"}
+	}
+	var descs []string
+	if err := param(params, 1, &descs); err != nil {
+		return nil, err
+	}
+	addresses := map[string]bool{}
+	for _, d := range descs {
+		address, err := descriptorAddress(d)
+		if err != nil {
+			return nil, err
+		}
+		addresses[address] = true
+	}
+	unspents := []map[string]any{}
+	var total uint64
+	for _, o := range s.outputs {
+		t, ok := s.txs[o.txid]
+		if !addresses[o.address] || !ok || t.height < 0 {
+			continue
+		}
+		unspents = append(unspents, map[string]any{
+			"txid":      o.txid,
+			"vout":      o.vout,
+			"desc":      "addr(" + o.address + ")#fakechk0",
+			"amount":    btc(o.sats),
+			"height":    t.height,
+			"blockhash": s.blocks[t.height],
+		})
+		total += o.sats
+	}
+	return map[string]any{
+		"success":      true,
+		"txouts":       len(s.outputs),
+		"height":       len(s.blocks) - 1,
+		"bestblock":    s.blocks[len(s.blocks)-1],
+		"unspents":     unspents,
+		"total_amount": btc(total),
+	}, nil
+}
+
+func (s *Server) listUnspent(watched map[string]bool, params []json.RawMessage) (any, error) {
+	filter := map[string]bool{}
+	if len(params) > 2 {
+		var addresses []string
+		if err := param(params, 2, &addresses); err != nil {
+			return nil, err
+		}
+		for _, a := range addresses {
+			if !addressPattern.MatchString(a) {
+				return nil, &Error{Code: -5, Message: "Invalid Bitcoin address: " + a}
+			}
+			filter[a] = true
+		}
+	}
+	unspent := []map[string]any{}
+	for _, o := range s.outputs {
+		t, ok := s.txs[o.txid]
+		if !ok || !watched[o.address] || (len(filter) > 0 && !filter[o.address]) {
+			continue
+		}
+		unspent = append(unspent, map[string]any{
+			"txid":          o.txid,
+			"vout":          o.vout,
+			"address":       o.address,
+			"amount":        btc(o.sats),
+			"confirmations": s.confirmations(t),
+			"spendable":     false,
+			"solvable":      false,
+		})
+	}
+	return unspent, nil
+}
+
+func (s *Server) getWalletTransaction(watched map[string]bool, params []json.RawMessage) (any, error) {
+	var txid string
+	if err := param(params, 0, &txid); err != nil {
+		return nil, err
+	}
+	t, ok := s.txs[txid]
+	mine := false
+	for _, o := range s.outputs {
+		if o.txid == txid && watched[o.address] {
+			mine = true
+		}
+	}
+	if !ok || !mine {
+		return nil, &Error{Code: -5, Message: "Invalid or non-wallet transaction id"}
+	}
+	result := map[string]any{"txid": txid, "hex": t.hex, "confirmations": s.confirmations(t)}
+	if t.height >= 0 {
+		result["blockhash"] = s.blocks[t.height]
+		result["blockheight"] = t.height
+		result["blocktime"] = GenesisTime + 600*t.height
+	}
+	return result, nil
+}
@@ -0,0 +1,431 @@
+// Package bitcoind implements the SDK BitcoinChainService on top of a
+// Bitcoin Core node's JSON-RPC interface, for streamers who run their own
+// node instead of trusting a public block explorer.
+//
+// Transaction lookups use getrawtransaction, which needs -txindex for
+// transactions that are neither in the mempool nor in the configured
+// wallet. Client implements breez_sdk_spark.BitcoinChainServiceCtx:
+//
+//	c, err := bitcoind.New(bitcoind.Config{
+//		URL:        "http://127.0.0.1:8332",
+//		CookieFile: "/home/streamer/.bitcoin/.cookie",
+//	})
+//	...
+//	builder.WithChainService(breez_sdk_spark.NewBitcoinChainServiceFromCtx(c))
+package bitcoind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// UTXOSource selects how GetAddressUtxos finds the outputs of an address.
+type UTXOSource int
+
+const (
+	// ScanTxOutSet scans the node's UTXO set with scantxoutset. It needs no
+	// wallet but only sees confirmed outputs, and a scan takes a while on
+	// mainnet.
+	ScanTxOutSet UTXOSource = iota
+	// Wallet asks a watch-only descriptor wallet with listunspent. It also
+	// sees unconfirmed outputs, but the wallet must watch the address first;
+	// see Client.Watch.
+	Wallet
+)
+
+// Config configures a Client.
+type Config struct {
+	// URL of the RPC server, e.g. "http://127.0.0.1:8332".
+	URL string
+	// Credentials are the rpcuser and rpcpassword, or an rpcauth entry.
+	Credentials *breez_sdk_spark.Credentials
+	// CookieFile is the path of bitcoind's .cookie file. It takes
+	// precedence over Credentials.
+	CookieFile string
+	// Wallet names the watch-only wallet used with the Wallet UTXO source.
+	// It is also used to find transactions when the node has no txindex.
+	Wallet string
+	// UTXOSource selects how GetAddressUtxos works. Defaults to
+	// ScanTxOutSet.
+	UTXOSource UTXOSource
+	// HTTPClient sends the requests. Defaults to a client with a two minute
+	// timeout, since scantxoutset is slow.
+	HTTPClient *http.Client
+}
+
+// Client is a bitcoind backed breez_sdk_spark.BitcoinChainServiceCtx.
+type Client struct {
+	rpc        *rpcClient
+	walletPath string
+	utxoSource UTXOSource
+
+	mu      sync.Mutex
+	headers map[string]blockHeader
+}
+
+var _ breez_sdk_spark.BitcoinChainServiceCtx = (*Client)(nil)
+
+// maxCachedHeaders bounds the block header cache. Headers never change for
+// a given hash, so the cache only needs clearing to stay small.
+const maxCachedHeaders = 4096
+
+// New returns a Client for cfg.
+func New(cfg Config) (*Client, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RPC URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid RPC URL %q: scheme must be http or https", cfg.URL)
+	}
+	if cfg.UTXOSource == Wallet && cfg.Wallet == "" {
+		return nil, fmt.Errorf("the Wallet UTXO source needs a wallet name")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 2 * time.Minute}
+	}
+	c := &Client{
+		rpc: &rpcClient{
+			url:         strings.TrimRight(cfg.URL, "/"),
+			credentials: cfg.Credentials,
+			cookieFile:  cfg.CookieFile,
+			http:        httpClient,
+		},
+		utxoSource: cfg.UTXOSource,
+		headers:    map[string]blockHeader{},
+	}
+	if cfg.Wallet != "" {
+		c.walletPath = "/wallet/" + url.PathEscape(cfg.Wallet)
+	}
+	return c, nil
+}
+
+type blockHeader struct {
+	Hash   string `json:"hash"`
+	Height uint32 `json:"height"`
+	Time   uint64 `json:"time"`
+}
+
+func (c *Client) header(ctx context.Context, hash string) (blockHeader, error) {
+	c.mu.Lock()
+	h, ok := c.headers[hash]
+	c.mu.Unlock()
+	if ok {
+		return h, nil
+	}
+	if err := c.rpc.call(ctx, "", "getblockheader", &h, hash, true); err != nil {
+		return h, err
+	}
+	c.mu.Lock()
+	if len(c.headers) >= maxCachedHeaders {
+		clear(c.headers)
+	}
+	c.headers[hash] = h
+	c.mu.Unlock()
+	return h, nil
+}
+
+func (c *Client) headerAt(ctx context.Context, height uint32) (blockHeader, error) {
+	var hash string
+	if err := c.rpc.call(ctx, "", "getblockhash", &hash, height); err != nil {
+		return blockHeader{}, err
+	}
+	return c.header(ctx, hash)
+}
+
+func confirmedStatus(h blockHeader) breez_sdk_spark.TxStatus {
+	height, t := h.Height, h.Time
+	return breez_sdk_spark.TxStatus{Confirmed: true, BlockHeight: &height, BlockTime: &t}
+}
+
+// btcToSats converts an RPC amount in BTC to satoshis without going through
+// float64.
+func btcToSats(amount json.Number) (uint64, error) {
+	r, ok := new(big.Rat).SetString(amount.String())
+	if !ok || r.Sign() < 0 {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	r.Mul(r, big.NewRat(100_000_000, 1))
+	if !r.IsInt() || !r.Num().IsUint64() {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	return r.Num().Uint64(), nil
+}
+
+// Watch imports address into the configured watch-only wallet so the Wallet
+// UTXO source sees it. With rescan the wallet rescans the whole chain for
+// past outputs, which can take hours on mainnet; otherwise only outputs
+// from now on are seen.
+func (c *Client) Watch(ctx context.Context, address string, rescan bool) error {
+	if c.walletPath == "" {
+		return fmt.Errorf("no wallet configured")
+	}
+	var info struct {
+		Descriptor string `json:"descriptor"`
+	}
+	if err := c.rpc.call(ctx, "", "getdescriptorinfo", &info, "addr("+address+")"); err != nil {
+		if code := rpcCode(err); code == codeInvalidAddressOrKey || code == codeInvalidParameter {
+			return breez_sdk_spark.NewChainServiceErrorInvalidAddress(address)
+		}
+		return chainErr("getdescriptorinfo", err)
+	}
+	var timestamp any = "now"
+	if rescan {
+		timestamp = 0
+	}
+	var results []struct {
+		Success bool      `json:"success"`
+		Error   *RPCError `json:"error"`
+	}
+	request := []map[string]any{{"desc": info.Descriptor, "timestamp": timestamp}}
+	if err := c.rpc.call(ctx, c.walletPath, "importdescriptors", &results, request); err != nil {
+		return chainErr("importdescriptors", err)
+	}
+	if len(results) != 1 || !results[0].Success {
+		if len(results) == 1 && results[0].Error != nil {
+			return chainErr("importdescriptors", results[0].Error)
+		}
+		return breez_sdk_spark.NewChainServiceErrorGeneric("bitcoind: importdescriptors failed")
+	}
+	return nil
+}
+
+func (c *Client) GetAddressUtxos(ctx context.Context, address string) ([]breez_sdk_spark.Utxo, error) {
+	if address == "" {
+		return nil, breez_sdk_spark.NewChainServiceErrorInvalidAddress("empty address")
+	}
+	if c.utxoSource == Wallet {
+		return c.walletUtxos(ctx, address)
+	}
+	return c.scanUtxos(ctx, address)
+}
+
+func (c *Client) scanUtxos(ctx context.Context, address string) ([]breez_sdk_spark.Utxo, error) {
+	var scan struct {
+		Success  bool `json:"success"`
+		Unspents []struct {
+			Txid      string      `json:"txid"`
+			Vout      uint32      `json:"vout"`
+			Amount    json.Number `json:"amount"`
+			Height    uint32      `json:"height"`
+			BlockHash string      `json:"blockhash"`
+		} `json:"unspents"`
+	}
+	err := c.rpc.call(ctx, "", "scantxoutset", &scan, "start", []string{"addr(" + address + ")"})
+	if err != nil {
+		switch code := rpcCode(err); {
+		case code == codeInvalidParameter && strings.Contains(err.Error(), "in progress"):
+			return nil, breez_sdk_spark.NewChainServiceErrorServiceConnectivity(err.Error())
+		case code == codeInvalidAddressOrKey || code == codeInvalidParameter:
+			return nil, breez_sdk_spark.NewChainServiceErrorInvalidAddress(address)
+		}
+		return nil, chainErr("scantxoutset", err)
+	}
+	if !scan.Success {
+		return nil, breez_sdk_spark.NewChainServiceErrorGeneric("bitcoind: scantxoutset was aborted")
+	}
+	utxos := make([]breez_sdk_spark.Utxo, 0, len(scan.Unspents))
+	for _, u := range scan.Unspents {
+		value, err := btcToSats(u.Amount)
+		if err != nil {
+			return nil, chainErr("scantxoutset", err)
+		}
+		var h blockHeader
+		if u.BlockHash != "" {
+			h, err = c.header(ctx, u.BlockHash)
+		} else {
+			h, err = c.headerAt(ctx, u.Height)
+		}
+		if err != nil {
+			return nil, chainErr("getblockheader", err)
+		}
+		utxos = append(utxos, breez_sdk_spark.Utxo{Txid: u.Txid, Vout: u.Vout, Value: value, Status: confirmedStatus(h)})
+	}
+	return utxos, nil
+}
+
+func (c *Client) walletUtxos(ctx context.Context, address string) ([]breez_sdk_spark.Utxo, error) {
+	var unspent []struct {
+		Txid          string      `json:"txid"`
+		Vout          uint32      `json:"vout"`
+		Amount        json.Number `json:"amount"`
+		Confirmations uint32      `json:"confirmations"`
+	}
+	err := c.rpc.call(ctx, c.walletPath, "listunspent", &unspent, 0, 9999999, []string{address})
+	if err != nil {
+		if rpcCode(err) == codeInvalidAddressOrKey {
+			return nil, breez_sdk_spark.NewChainServiceErrorInvalidAddress(address)
+		}
+		return nil, chainErr("listunspent", err)
+	}
+	var tip uint32
+	for _, u := range unspent {
+		if u.Confirmations > 0 {
+			if err := c.rpc.call(ctx, "", "getblockcount", &tip); err != nil {
+				return nil, chainErr("getblockcount", err)
+			}
+			break
+		}
+	}
+	utxos := make([]breez_sdk_spark.Utxo, 0, len(unspent))
+	for _, u := range unspent {
+		value, err := btcToSats(u.Amount)
+		if err != nil {
+			return nil, chainErr("listunspent", err)
+		}
+		utxo := breez_sdk_spark.Utxo{Txid: u.Txid, Vout: u.Vout, Value: value}
+		if u.Confirmations > 0 && u.Confirmations <= tip+1 {
+			h, err := c.headerAt(ctx, tip-u.Confirmations+1)
+			if err != nil {
+				return nil, chainErr("getblockheader", err)
+			}
+			utxo.Status = confirmedStatus(h)
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
+// walletTx looks txid up in the configured wallet, for nodes without a
+// transaction index.
+func (c *Client) walletTx(ctx context.Context, txid string) (string, string, error) {
+	var tx struct {
+		Hex       string `json:"hex"`
+		BlockHash string `json:"blockhash"`
+	}
+	if err := c.rpc.call(ctx, c.walletPath, "gettransaction", &tx, txid, true); err != nil {
+		return "", "", err
+	}
+	return tx.Hex, tx.BlockHash, nil
+}
+
+func (c *Client) GetTransactionStatus(ctx context.Context, txid string) (breez_sdk_spark.TxStatus, error) {
+	var tx struct {
+		BlockHash string `json:"blockhash"`
+	}
+	err := c.rpc.call(ctx, "", "getrawtransaction", &tx, txid, true)
+	if err != nil && rpcCode(err) == codeInvalidAddressOrKey && c.walletPath != "" {
+		_, tx.BlockHash, err = c.walletTx(ctx, txid)
+	}
+	if err != nil {
+		return breez_sdk_spark.TxStatus{}, chainErr("getrawtransaction", err)
+	}
+	if tx.BlockHash == "" {
+		return breez_sdk_spark.TxStatus{}, nil
+	}
+	h, err := c.header(ctx, tx.BlockHash)
+	if err != nil {
+		return breez_sdk_spark.TxStatus{}, chainErr("getblockheader", err)
+	}
+	return confirmedStatus(h), nil
+}
+
+func (c *Client) GetTransactionHex(ctx context.Context, txid string) (string, error) {
+	var hex string
+	err := c.rpc.call(ctx, "", "getrawtransaction", &hex, txid, false)
+	if err != nil && rpcCode(err) == codeInvalidAddressOrKey && c.walletPath != "" {
+		hex, _, err = c.walletTx(ctx, txid)
+	}
+	if err != nil {
+		return "", chainErr("getrawtransaction", err)
+	}
+	return hex, nil
+}
+
+// BroadcastTransaction submits tx with sendrawtransaction. A transaction
+// the node already has counts as broadcast, so retries are harmless.
+func (c *Client) BroadcastTransaction(ctx context.Context, tx string) error {
+	err := c.rpc.call(ctx, "", "sendrawtransaction", nil, strings.TrimSpace(tx))
+	switch code := rpcCode(err); {
+	case err == nil, code == codeVerifyAlreadyInChain:
+		return nil
+	case code == codeVerifyRejected && (strings.Contains(err.Error(), "txn-already-in-mempool") ||
+		strings.Contains(err.Error(), "txn-already-known")):
+		return nil
+	}
+	return chainErr("sendrawtransaction", err)
+}
+
+// Confirmation targets, in blocks, behind each RecommendedFees field.
+// bitcoind estimates at most 1008 blocks ahead.
+var feeTargets = [...]uint32{1, 3, 6, 25, 1008}
+
+// RecommendedFees builds the fees from estimatesmartfee. Targets the node
+// cannot estimate yet, as on a fresh regtest chain, fall back to the
+// mempool's minimum fee, and slower targets never cost more than faster
+// ones.
+func (c *Client) RecommendedFees(ctx context.Context) (breez_sdk_spark.RecommendedFees, error) {
+	var mempool struct {
+		MempoolMinFee json.Number `json:"mempoolminfee"`
+		MinRelayTxFee json.Number `json:"minrelaytxfee"`
+	}
+	if err := c.rpc.call(ctx, "", "getmempoolinfo", &mempool); err != nil {
+		return breez_sdk_spark.RecommendedFees{}, chainErr("getmempoolinfo", err)
+	}
+	floor := uint64(1)
+	for _, rate := range []json.Number{mempool.MempoolMinFee, mempool.MinRelayTxFee} {
+		if rate == "" {
+			continue
+		}
+		satPerVbyte, err := satPerVbyte(rate)
+		if err != nil {
+			return breez_sdk_spark.RecommendedFees{}, chainErr("getmempoolinfo", err)
+		}
+		floor = max(floor, satPerVbyte)
+	}
+
+	var fees [len(feeTargets)]uint64
+	for i, target := range feeTargets {
+		var estimate struct {
+			FeeRate json.Number `json:"feerate"`
+		}
+		if err := c.rpc.call(ctx, "", "estimatesmartfee", &estimate, target); err != nil {
+			return breez_sdk_spark.RecommendedFees{}, chainErr("estimatesmartfee", err)
+		}
+		fees[i] = floor
+		if estimate.FeeRate != "" {
+			rate, err := satPerVbyte(estimate.FeeRate)
+			if err != nil {
+				return breez_sdk_spark.RecommendedFees{}, chainErr("estimatesmartfee", err)
+			}
+			fees[i] = max(floor, rate)
+		}
+	}
+	for i := len(fees) - 2; i >= 0; i-- {
+		fees[i] = max(fees[i], fees[i+1])
+	}
+	return breez_sdk_spark.RecommendedFees{
+		FastestFee:  fees[0],
+		HalfHourFee: fees[1],
+		HourFee:     fees[2],
+		EconomyFee:  fees[3],
+		MinimumFee:  fees[4],
+	}, nil
+}
+
+// satPerVbyte converts a fee rate in BTC/kvB to sat/vB, rounded up.
+func satPerVbyte(rate json.Number) (uint64, error) {
+	r, ok := new(big.Rat).SetString(rate.String())
+	if !ok || r.Sign() < 0 {
+		return 0, fmt.Errorf("invalid fee rate %q", rate)
+	}
+	r.Mul(r, big.NewRat(100_000, 1))
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if !q.IsUint64() {
+		return 0, fmt.Errorf("invalid fee rate %q", rate)
+	}
+	return q.Uint64(), nil
+}
@@ -0,0 +1,284 @@
+package bitcoind
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/bitcoind/bitcoindtest"
+)
+
+const donationAddress = "bcrt1qdonationaddress0000"
+
+func newServer(t *testing.T) *bitcoindtest.Server {
+	s := bitcoindtest.NewServer()
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newClient(t *testing.T, cfg Config) *Client {
+	t.Helper()
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func addTx(t *testing.T, s *bitcoindtest.Server, rawHex string) string {
+	t.Helper()
+	txid, err := s.AddTransaction(rawHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txid
+}
+
+func TestScanTxOutSetUtxos(t *testing.T) {
+	s := newServer(t)
+	s.SetCredentials("rpc", "secret")
+	s.Mine(100)
+	confirmed := addTx(t, s, "0100")
+	s.Pay(donationAddress, confirmed, 1, 123_456_789)
+	s.Pay("bcrt1qsomeoneelse000000", confirmed, 0, 5)
+	s.Mine(1)
+	pending := addTx(t, s, "0200")
+	s.Pay(donationAddress, pending, 0, 1000)
+
+	c := newClient(t, Config{URL: s.URL, Credentials: &breez_sdk_spark.Credentials{Username: "rpc", Password: "secret"}})
+	utxos, err := c.GetAddressUtxos(context.Background(), donationAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 {
+		t.Fatalf("got %+v, want only the confirmed output", utxos)
+	}
+	u := utxos[0]
+	if u.Txid != confirmed || u.Vout != 1 || u.Value != 123_456_789 {
+		t.Fatalf("utxo = %+v", u)
+	}
+	if !u.Status.Confirmed || *u.Status.BlockHeight != 101 || *u.Status.BlockTime != bitcoindtest.GenesisTime+600*101 {
+		t.Fatalf("status = %+v", u.Status)
+	}
+
+	if _, err := c.GetAddressUtxos(context.Background(), "not an address"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorInvalidAddress) {
+		t.Fatalf("invalid address: got %v", err)
+	}
+}
+
+func TestWalletUtxos(t *testing.T) {
+	s := newServer(t)
+	s.CreateWallet("donations")
+	s.Mine(10)
+	confirmed := addTx(t, s, "0100")
+	s.Pay(donationAddress, confirmed, 0, 50_000)
+	s.Mine(3)
+	pending := addTx(t, s, "0200")
+	s.Pay(donationAddress, pending, 2, 7_000)
+
+	c := newClient(t, Config{URL: s.URL, Wallet: "donations", UTXOSource: Wallet})
+	if utxos, err := c.GetAddressUtxos(context.Background(), donationAddress); err != nil || len(utxos) != 0 {
+		t.Fatalf("before watching: %+v, %v", utxos, err)
+	}
+	if err := c.Watch(context.Background(), donationAddress, true); err != nil {
+		t.Fatal(err)
+	}
+	utxos, err := c.GetAddressUtxos(context.Background(), donationAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 2 {
+		t.Fatalf("utxos = %+v", utxos)
+	}
+	byTxid := map[string]breez_sdk_spark.Utxo{utxos[0].Txid: utxos[0], utxos[1].Txid: utxos[1]}
+	if u := byTxid[confirmed]; !u.Status.Confirmed || *u.Status.BlockHeight != 11 || u.Value != 50_000 {
+		t.Fatalf("confirmed utxo = %+v", u)
+	}
+	if u := byTxid[pending]; u.Status.Confirmed || u.Status.BlockHeight != nil || u.Vout != 2 {
+		t.Fatalf("pending utxo = %+v", u)
+	}
+
+	if err := c.Watch(context.Background(), "bad address!", false); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorInvalidAddress) {
+		t.Fatalf("watching an invalid address: got %v", err)
+	}
+	if _, err := New(Config{URL: s.URL, UTXOSource: Wallet}); err == nil {
+		t.Fatal("Wallet source without a wallet name was accepted")
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	s := newServer(t)
+	txid := addTx(t, s, "02000000")
+	c := newClient(t, Config{URL: s.URL})
+	ctx := context.Background()
+
+	status, err := c.GetTransactionStatus(ctx, txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Confirmed {
+		t.Fatalf("mempool transaction reported confirmed: %+v", status)
+	}
+	s.Mine(2)
+	status, err = c.GetTransactionStatus(ctx, txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Confirmed || *status.BlockHeight != 1 || *status.BlockTime != bitcoindtest.GenesisTime+600 {
+		t.Fatalf("status = %+v", status)
+	}
+	if hex, err := c.GetTransactionHex(ctx, txid); err != nil || hex != "02000000" {
+		t.Fatalf("hex = %q, %v", hex, err)
+	}
+	if _, err := c.GetTransactionHex(ctx, "00"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) {
+		t.Fatalf("unknown tx: got %v", err)
+	}
+
+	// Block headers are cached.
+	before := s.Calls("getblockheader")
+	c.GetTransactionStatus(ctx, txid)
+	if s.Calls("getblockheader") != before {
+		t.Fatal("block header fetched again")
+	}
+}
+
+func TestWalletTransactionWithoutTxIndex(t *testing.T) {
+	s := newServer(t)
+	s.SetTxIndex(false)
+	s.CreateWallet("donations")
+	txid := addTx(t, s, "0300")
+	s.Pay(donationAddress, txid, 0, 1)
+	s.Mine(1)
+
+	c := newClient(t, Config{URL: s.URL})
+	if _, err := c.GetTransactionHex(context.Background(), txid); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) {
+		t.Fatalf("without txindex or wallet: got %v", err)
+	}
+
+	c = newClient(t, Config{URL: s.URL, Wallet: "donations"})
+	if err := c.Watch(context.Background(), donationAddress, true); err != nil {
+		t.Fatal(err)
+	}
+	if hex, err := c.GetTransactionHex(context.Background(), txid); err != nil || hex != "0300" {
+		t.Fatalf("hex = %q, %v", hex, err)
+	}
+	status, err := c.GetTransactionStatus(context.Background(), txid)
+	if err != nil || !status.Confirmed || *status.BlockHeight != 1 {
+		t.Fatalf("status = %+v, %v", status, err)
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	s := newServer(t)
+	c := newClient(t, Config{URL: s.URL})
+	ctx := context.Background()
+
+	if err := c.BroadcastTransaction(ctx, "0400\n"); err != nil {
+		t.Fatal(err)
+	}
+	txid, _ := bitcoindtest.Txid("0400")
+	if hex, err := c.GetTransactionHex(ctx, txid); err != nil || hex != "0400" {
+		t.Fatalf("broadcast tx not in mempool: %q, %v", hex, err)
+	}
+	// Resending is harmless, in the mempool and once mined.
+	if err := c.BroadcastTransaction(ctx, "0400"); err != nil {
+		t.Fatalf("rebroadcast from mempool: %v", err)
+	}
+	s.Mine(1)
+	if err := c.BroadcastTransaction(ctx, "0400"); err != nil {
+		t.Fatalf("rebroadcast after mining: %v", err)
+	}
+	if err := c.BroadcastTransaction(ctx, "zz"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) {
+		t.Fatalf("undecodable tx: got %v", err)
+	}
+	s.Handle("sendrawtransaction", func([]json.RawMessage) (any, error) {
+		return nil, &bitcoindtest.Error{Code: -26, Message: "min relay fee not met"}
+	})
+	if err := c.BroadcastTransaction(ctx, "0500"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) {
+		t.Fatalf("rejected tx: got %v", err)
+	}
+}
+
+func TestRecommendedFees(t *testing.T) {
+	s := newServer(t)
+	c := newClient(t, Config{URL: s.URL})
+
+	// A fresh chain has no estimates; everything falls back to the floor.
+	fees, err := c.RecommendedFees(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (breez_sdk_spark.RecommendedFees{FastestFee: 1, HalfHourFee: 1, HourFee: 1, EconomyFee: 1, MinimumFee: 1}); fees != want {
+		t.Fatalf("fees = %+v, want %+v", fees, want)
+	}
+
+	s.SetFeeRate(2, "0.00020100")  // 20.1 sat/vB
+	s.SetFeeRate(6, "0.00010000")  // 10 sat/vB
+	s.SetFeeRate(25, "0.00012000") // out of order: 12 sat/vB
+	s.SetMempoolMinFee("0.00003000")
+	fees, err = c.RecommendedFees(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := breez_sdk_spark.RecommendedFees{FastestFee: 21, HalfHourFee: 12, HourFee: 12, EconomyFee: 12, MinimumFee: 3}
+	if fees != want {
+		t.Fatalf("fees = %+v, want %+v", fees, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := newServer(t)
+	s.SetCredentials("rpc", "secret")
+	ctx := context.Background()
+
+	c := newClient(t, Config{URL: s.URL, Credentials: &breez_sdk_spark.Credentials{Username: "rpc", Password: "wrong"}})
+	if _, err := c.GetTransactionHex(ctx, "00"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("bad credentials: got %v", err)
+	}
+
+	cookie := filepath.Join(t.TempDir(), ".cookie")
+	if err := os.WriteFile(cookie, []byte("rpc:secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c = newClient(t, Config{URL: s.URL, CookieFile: cookie})
+	if _, err := c.RecommendedFees(ctx); err != nil {
+		t.Fatalf("cookie auth: %v", err)
+	}
+
+	s.Handle("getmempoolinfo", func([]json.RawMessage) (any, error) {
+		return nil, &bitcoindtest.Error{Code: -28, Message: "Loading block index…"}
+	})
+	if _, err := c.RecommendedFees(ctx); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("warming up: got %v", err)
+	}
+
+	s.Handle("scantxoutset", func([]json.RawMessage) (any, error) {
+		return nil, &bitcoindtest.Error{Code: -8, Message: "Scan already in progress, use action \"abort\" or \"status\""}
+	})
+	if _, err := c.GetAddressUtxos(ctx, donationAddress); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("concurrent scan: got %v", err)
+	}
+
+	s.Close()
+	if _, err := c.GetTransactionStatus(ctx, "00"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("node down: got %v", err)
+	}
+}
+
+func TestBtcToSats(t *testing.T) {
+	for in, want := range map[string]uint64{"0.00000001": 1, "1": 100_000_000, "20999999.97690000": 2099999997690000, "1e-05": 1000} {
+		got, err := btcToSats(json.Number(in))
+		if err != nil || got != want {
+			t.Errorf("btcToSats(%s) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"0.000000001", "-1", "abc"} {
+		if _, err := btcToSats(json.Number(in)); err == nil {
+			t.Errorf("btcToSats(%s) succeeded", in)
+		}
+	}
+}
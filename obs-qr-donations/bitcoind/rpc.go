@@ -0,0 +1,166 @@
+package bitcoind
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// maxResponseSize bounds how much of a response body is read.
+const maxResponseSize = 32 << 20
+
+// Bitcoin Core RPC error codes this package reacts to.
+const (
+	codeInvalidAddressOrKey  = -5
+	codeInvalidParameter     = -8
+	codeVerifyRejected       = -26
+	codeVerifyAlreadyInChain = -27
+	codeInWarmup             = -28
+)
+
+// RPCError is an error returned by bitcoind.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("bitcoind: %s (code %d)", e.Message, e.Code)
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+type rpcClient struct {
+	url         string
+	credentials *breez_sdk_spark.Credentials
+	cookieFile  string
+	http        *http.Client
+	nextID      atomic.Uint64
+}
+
+// auth returns the basic auth pair. The cookie file is read on every call
+// because bitcoind writes a new one each time it starts.
+func (c *rpcClient) auth() (string, string, error) {
+	if c.cookieFile != "" {
+		data, err := os.ReadFile(c.cookieFile)
+		if err != nil {
+			return "", "", err
+		}
+		user, pass, ok := strings.Cut(strings.TrimSpace(string(data)), ":")
+		if !ok {
+			return "", "", fmt.Errorf("malformed cookie file %s", c.cookieFile)
+		}
+		return user, pass, nil
+	}
+	if c.credentials != nil {
+		return c.credentials.Username, c.credentials.Password, nil
+	}
+	return "", "", nil
+}
+
+// call invokes method on the endpoint at path ("" for the node, or
+// "/wallet/<name>") and decodes the result into out. Transport and
+// authentication failures come back as ServiceConnectivity errors, RPC
+// errors as *RPCError.
+func (c *rpcClient) call(ctx context.Context, path, method string, out any, params ...any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "1.0", ID: c.nextID.Add(1), Method: method, Params: params})
+	if err != nil {
+		return breez_sdk_spark.NewChainServiceErrorGeneric(err.Error())
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return breez_sdk_spark.NewChainServiceErrorGeneric(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	user, pass, err := c.auth()
+	if err != nil {
+		return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(err.Error())
+	}
+	if user != "" || pass != "" {
+		req.SetBasicAuth(user, pass)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(err.Error())
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(err.Error())
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(
+			fmt.Sprintf("bitcoind: %s: authentication failed (HTTP %d)", method, resp.StatusCode))
+	}
+	// Errors come back with HTTP 500 or 404 and a JSON body, so the body is
+	// decoded whatever the status.
+	var r rpcResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		if resp.StatusCode/100 != 2 {
+			return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(
+				fmt.Sprintf("bitcoind: %s: HTTP %d", method, resp.StatusCode))
+		}
+		return breez_sdk_spark.NewChainServiceErrorGeneric(fmt.Sprintf("bitcoind: %s: %v", method, err))
+	}
+	if r.Error != nil {
+		return r.Error
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(r.Result, out); err != nil {
+		return breez_sdk_spark.NewChainServiceErrorGeneric(fmt.Sprintf("bitcoind: %s: decoding result: %v", method, err))
+	}
+	return nil
+}
+
+// chainErr maps err to a *ChainServiceError. RPC errors are Generic except
+// while bitcoind is still starting up.
+func chainErr(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		msg := fmt.Sprintf("bitcoind: %s: %s (code %d)", method, rpcErr.Message, rpcErr.Code)
+		if rpcErr.Code == codeInWarmup {
+			return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(msg)
+		}
+		return breez_sdk_spark.NewChainServiceErrorGeneric(msg)
+	}
+	var chainErr *breez_sdk_spark.ChainServiceError
+	if errors.As(err, &chainErr) {
+		return err
+	}
+	return breez_sdk_spark.NewChainServiceErrorGeneric(err.Error())
+}
+
+func rpcCode(err error) int {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code
+	}
+	return 0
+}
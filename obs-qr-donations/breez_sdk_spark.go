@@ -9773,4 +9773,8703 @@ func (c FfiConverterLnurlReceiveMetadata) Lower(value LnurlReceiveMetadata) C.Ru
 	return LowerIntoRustBuffer[LnurlReceiveMetadata](c, value)
 }
 
-func (c FfiConverterLnurlR
\ No newline at end of file
+func (c FfiConverterLnurlReceiveMetadata) Write(writer io.Writer, value LnurlReceiveMetadata) {
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.NostrZapRequest)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.NostrZapReceipt)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.SenderComment)
+}
+
+type FfiDestroyerLnurlReceiveMetadata struct{}
+
+func (_ FfiDestroyerLnurlReceiveMetadata) Destroy(value LnurlReceiveMetadata) {
+	value.Destroy()
+}
+
+// Represents the withdraw LNURL info
+type LnurlWithdrawInfo struct {
+	WithdrawUrl string
+}
+
+func (r *LnurlWithdrawInfo) Destroy() {
+	FfiDestroyerString{}.Destroy(r.WithdrawUrl)
+}
+
+type FfiConverterLnurlWithdrawInfo struct{}
+
+var FfiConverterLnurlWithdrawInfoINSTANCE = FfiConverterLnurlWithdrawInfo{}
+
+func (c FfiConverterLnurlWithdrawInfo) Lift(rb RustBufferI) LnurlWithdrawInfo {
+	return LiftFromRustBuffer[LnurlWithdrawInfo](c, rb)
+}
+
+func (c FfiConverterLnurlWithdrawInfo) Read(reader io.Reader) LnurlWithdrawInfo {
+	return LnurlWithdrawInfo{
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterLnurlWithdrawInfo) Lower(value LnurlWithdrawInfo) C.RustBuffer {
+	return LowerIntoRustBuffer[LnurlWithdrawInfo](c, value)
+}
+
+func (c FfiConverterLnurlWithdrawInfo) Write(writer io.Writer, value LnurlWithdrawInfo) {
+	FfiConverterStringINSTANCE.Write(writer, value.WithdrawUrl)
+}
+
+type FfiDestroyerLnurlWithdrawInfo struct{}
+
+func (_ FfiDestroyerLnurlWithdrawInfo) Destroy(value LnurlWithdrawInfo) {
+	value.Destroy()
+}
+
+type LnurlWithdrawRequest struct {
+	// The amount to withdraw in satoshis
+	// Must be within the min and max withdrawable limits
+	AmountSats      uint64
+	WithdrawRequest LnurlWithdrawRequestDetails
+	// If provided, the method will wait for the payment to be completed within the
+	// specified time. If not provided, the method will not wait for the payment to complete.
+	CompletionTimeoutSecs *uint32
+}
+
+func (r *LnurlWithdrawRequest) Destroy() {
+	FfiDestroyerUint64{}.Destroy(r.AmountSats)
+	FfiDestroyerLnurlWithdrawRequestDetails{}.Destroy(r.WithdrawRequest)
+	FfiDestroyerOptionalUint32{}.Destroy(r.CompletionTimeoutSecs)
+}
+
+type FfiConverterLnurlWithdrawRequest struct{}
+
+var FfiConverterLnurlWithdrawRequestINSTANCE = FfiConverterLnurlWithdrawRequest{}
+
+func (c FfiConverterLnurlWithdrawRequest) Lift(rb RustBufferI) LnurlWithdrawRequest {
+	return LiftFromRustBuffer[LnurlWithdrawRequest](c, rb)
+}
+
+func (c FfiConverterLnurlWithdrawRequest) Read(reader io.Reader) LnurlWithdrawRequest {
+	return LnurlWithdrawRequest{
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterLnurlWithdrawRequestDetailsINSTANCE.Read(reader),
+		FfiConverterOptionalUint32INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterLnurlWithdrawRequest) Lower(value LnurlWithdrawRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[LnurlWithdrawRequest](c, value)
+}
+
+func (c FfiConverterLnurlWithdrawRequest) Write(writer io.Writer, value LnurlWithdrawRequest) {
+	FfiConverterUint64INSTANCE.Write(writer, value.AmountSats)
+	FfiConverterLnurlWithdrawRequestDetailsINSTANCE.Write(writer, value.WithdrawRequest)
+	FfiConverterOptionalUint32INSTANCE.Write(writer, value.CompletionTimeoutSecs)
+}
+
+type FfiDestroyerLnurlWithdrawRequest struct{}
+
+func (_ FfiDestroyerLnurlWithdrawRequest) Destroy(value LnurlWithdrawRequest) {
+	value.Destroy()
+}
+
+type LnurlWithdrawRequestDetails struct {
+	Callback           string
+	K1                 string
+	DefaultDescription string
+	// The minimum amount, in millisats, that this LNURL-withdraw endpoint accepts
+	MinWithdrawable uint64
+	// The maximum amount, in millisats, that this LNURL-withdraw endpoint accepts
+	MaxWithdrawable uint64
+}
+
+func (r *LnurlWithdrawRequestDetails) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Callback)
+	FfiDestroyerString{}.Destroy(r.K1)
+	FfiDestroyerString{}.Destroy(r.DefaultDescription)
+	FfiDestroyerUint64{}.Destroy(r.MinWithdrawable)
+	FfiDestroyerUint64{}.Destroy(r.MaxWithdrawable)
+}
+
+type FfiConverterLnurlWithdrawRequestDetails struct{}
+
+var FfiConverterLnurlWithdrawRequestDetailsINSTANCE = FfiConverterLnurlWithdrawRequestDetails{}
+
+func (c FfiConverterLnurlWithdrawRequestDetails) Lift(rb RustBufferI) LnurlWithdrawRequestDetails {
+	return LiftFromRustBuffer[LnurlWithdrawRequestDetails](c, rb)
+}
+
+func (c FfiConverterLnurlWithdrawRequestDetails) Read(reader io.Reader) LnurlWithdrawRequestDetails {
+	return LnurlWithdrawRequestDetails{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterLnurlWithdrawRequestDetails) Lower(value LnurlWithdrawRequestDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[LnurlWithdrawRequestDetails](c, value)
+}
+
+func (c FfiConverterLnurlWithdrawRequestDetails) Write(writer io.Writer, value LnurlWithdrawRequestDetails) {
+	FfiConverterStringINSTANCE.Write(writer, value.Callback)
+	FfiConverterStringINSTANCE.Write(writer, value.K1)
+	FfiConverterStringINSTANCE.Write(writer, value.DefaultDescription)
+	FfiConverterUint64INSTANCE.Write(writer, value.MinWithdrawable)
+	FfiConverterUint64INSTANCE.Write(writer, value.MaxWithdrawable)
+}
+
+type FfiDestroyerLnurlWithdrawRequestDetails struct{}
+
+func (_ FfiDestroyerLnurlWithdrawRequestDetails) Destroy(value LnurlWithdrawRequestDetails) {
+	value.Destroy()
+}
+
+type LnurlWithdrawResponse struct {
+	// The Lightning invoice generated for the LNURL withdraw
+	PaymentRequest string
+	Payment        *Payment
+}
+
+func (r *LnurlWithdrawResponse) Destroy() {
+	FfiDestroyerString{}.Destroy(r.PaymentRequest)
+	FfiDestroyerOptionalPayment{}.Destroy(r.Payment)
+}
+
+type FfiConverterLnurlWithdrawResponse struct{}
+
+var FfiConverterLnurlWithdrawResponseINSTANCE = FfiConverterLnurlWithdrawResponse{}
+
+func (c FfiConverterLnurlWithdrawResponse) Lift(rb RustBufferI) LnurlWithdrawResponse {
+	return LiftFromRustBuffer[LnurlWithdrawResponse](c, rb)
+}
+
+func (c FfiConverterLnurlWithdrawResponse) Read(reader io.Reader) LnurlWithdrawResponse {
+	return LnurlWithdrawResponse{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterOptionalPaymentINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterLnurlWithdrawResponse) Lower(value LnurlWithdrawResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[LnurlWithdrawResponse](c, value)
+}
+
+func (c FfiConverterLnurlWithdrawResponse) Write(writer io.Writer, value LnurlWithdrawResponse) {
+	FfiConverterStringINSTANCE.Write(writer, value.PaymentRequest)
+	FfiConverterOptionalPaymentINSTANCE.Write(writer, value.Payment)
+}
+
+type FfiDestroyerLnurlWithdrawResponse struct{}
+
+func (_ FfiDestroyerLnurlWithdrawResponse) Destroy(value LnurlWithdrawResponse) {
+	value.Destroy()
+}
+
+// Locale-specific settings for the representation of a currency
+type LocaleOverrides struct {
+	Locale  string
+	Spacing *uint32
+	Symbol  Symbol
+}
+
+func (r *LocaleOverrides) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Locale)
+	FfiDestroyerOptionalUint32{}.Destroy(r.Spacing)
+	FfiDestroyerSymbol{}.Destroy(r.Symbol)
+}
+
+type FfiConverterLocaleOverrides struct{}
+
+var FfiConverterLocaleOverridesINSTANCE = FfiConverterLocaleOverrides{}
+
+func (c FfiConverterLocaleOverrides) Lift(rb RustBufferI) LocaleOverrides {
+	return LiftFromRustBuffer[LocaleOverrides](c, rb)
+}
+
+func (c FfiConverterLocaleOverrides) Read(reader io.Reader) LocaleOverrides {
+	return LocaleOverrides{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterOptionalUint32INSTANCE.Read(reader),
+		FfiConverterSymbolINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterLocaleOverrides) Lower(value LocaleOverrides) C.RustBuffer {
+	return LowerIntoRustBuffer[LocaleOverrides](c, value)
+}
+
+func (c FfiConverterLocaleOverrides) Write(writer io.Writer, value LocaleOverrides) {
+	FfiConverterStringINSTANCE.Write(writer, value.Locale)
+	FfiConverterOptionalUint32INSTANCE.Write(writer, value.Spacing)
+	FfiConverterSymbolINSTANCE.Write(writer, value.Symbol)
+}
+
+type FfiDestroyerLocaleOverrides struct{}
+
+func (_ FfiDestroyerLocaleOverrides) Destroy(value LocaleOverrides) {
+	value.Destroy()
+}
+
+// Localized name of a currency
+type LocalizedName struct {
+	Locale string
+	Name   string
+}
+
+func (r *LocalizedName) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Locale)
+	FfiDestroyerString{}.Destroy(r.Name)
+}
+
+type FfiConverterLocalizedName struct{}
+
+var FfiConverterLocalizedNameINSTANCE = FfiConverterLocalizedName{}
+
+func (c FfiConverterLocalizedName) Lift(rb RustBufferI) LocalizedName {
+	return LiftFromRustBuffer[LocalizedName](c, rb)
+}
+
+func (c FfiConverterLocalizedName) Read(reader io.Reader) LocalizedName {
+	return LocalizedName{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterLocalizedName) Lower(value LocalizedName) C.RustBuffer {
+	return LowerIntoRustBuffer[LocalizedName](c, value)
+}
+
+func (c FfiConverterLocalizedName) Write(writer io.Writer, value LocalizedName) {
+	FfiConverterStringINSTANCE.Write(writer, value.Locale)
+	FfiConverterStringINSTANCE.Write(writer, value.Name)
+}
+
+type FfiDestroyerLocalizedName struct{}
+
+func (_ FfiDestroyerLocalizedName) Destroy(value LocalizedName) {
+	value.Destroy()
+}
+
+type LogEntry struct {
+	Line  string
+	Level string
+}
+
+func (r *LogEntry) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Line)
+	FfiDestroyerString{}.Destroy(r.Level)
+}
+
+type FfiConverterLogEntry struct{}
+
+var FfiConverterLogEntryINSTANCE = FfiConverterLogEntry{}
+
+func (c FfiConverterLogEntry) Lift(rb RustBufferI) LogEntry {
+	return LiftFromRustBuffer[LogEntry](c, rb)
+}
+
+func (c FfiConverterLogEntry) Read(reader io.Reader) LogEntry {
+	return LogEntry{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterLogEntry) Lower(value LogEntry) C.RustBuffer {
+	return LowerIntoRustBuffer[LogEntry](c, value)
+}
+
+func (c FfiConverterLogEntry) Write(writer io.Writer, value LogEntry) {
+	FfiConverterStringINSTANCE.Write(writer, value.Line)
+	FfiConverterStringINSTANCE.Write(writer, value.Level)
+}
+
+type FfiDestroyerLogEntry struct{}
+
+func (_ FfiDestroyerLogEntry) Destroy(value LogEntry) {
+	value.Destroy()
+}
+
+type MessageSuccessActionData struct {
+	Message string
+}
+
+func (r *MessageSuccessActionData) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Message)
+}
+
+type FfiConverterMessageSuccessActionData struct{}
+
+var FfiConverterMessageSuccessActionDataINSTANCE = FfiConverterMessageSuccessActionData{}
+
+func (c FfiConverterMessageSuccessActionData) Lift(rb RustBufferI) MessageSuccessActionData {
+	return LiftFromRustBuffer[MessageSuccessActionData](c, rb)
+}
+
+func (c FfiConverterMessageSuccessActionData) Read(reader io.Reader) MessageSuccessActionData {
+	return MessageSuccessActionData{
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterMessageSuccessActionData) Lower(value MessageSuccessActionData) C.RustBuffer {
+	return LowerIntoRustBuffer[MessageSuccessActionData](c, value)
+}
+
+func (c FfiConverterMessageSuccessActionData) Write(writer io.Writer, value MessageSuccessActionData) {
+	FfiConverterStringINSTANCE.Write(writer, value.Message)
+}
+
+type FfiDestroyerMessageSuccessActionData struct{}
+
+func (_ FfiDestroyerMessageSuccessActionData) Destroy(value MessageSuccessActionData) {
+	value.Destroy()
+}
+
+type MintIssuerTokenRequest struct {
+	Amount u128
+}
+
+func (r *MintIssuerTokenRequest) Destroy() {
+	FfiDestroyerTypeu128{}.Destroy(r.Amount)
+}
+
+type FfiConverterMintIssuerTokenRequest struct{}
+
+var FfiConverterMintIssuerTokenRequestINSTANCE = FfiConverterMintIssuerTokenRequest{}
+
+func (c FfiConverterMintIssuerTokenRequest) Lift(rb RustBufferI) MintIssuerTokenRequest {
+	return LiftFromRustBuffer[MintIssuerTokenRequest](c, rb)
+}
+
+func (c FfiConverterMintIssuerTokenRequest) Read(reader io.Reader) MintIssuerTokenRequest {
+	return MintIssuerTokenRequest{
+		FfiConverterTypeu128INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterMintIssuerTokenRequest) Lower(value MintIssuerTokenRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[MintIssuerTokenRequest](c, value)
+}
+
+func (c FfiConverterMintIssuerTokenRequest) Write(writer io.Writer, value MintIssuerTokenRequest) {
+	FfiConverterTypeu128INSTANCE.Write(writer, value.Amount)
+}
+
+type FfiDestroyerMintIssuerTokenRequest struct{}
+
+func (_ FfiDestroyerMintIssuerTokenRequest) Destroy(value MintIssuerTokenRequest) {
+	value.Destroy()
+}
+
+type OutgoingChange struct {
+	Change RecordChange
+	Parent *Record
+}
+
+func (r *OutgoingChange) Destroy() {
+	FfiDestroyerRecordChange{}.Destroy(r.Change)
+	FfiDestroyerOptionalRecord{}.Destroy(r.Parent)
+}
+
+type FfiConverterOutgoingChange struct{}
+
+var FfiConverterOutgoingChangeINSTANCE = FfiConverterOutgoingChange{}
+
+func (c FfiConverterOutgoingChange) Lift(rb RustBufferI) OutgoingChange {
+	return LiftFromRustBuffer[OutgoingChange](c, rb)
+}
+
+func (c FfiConverterOutgoingChange) Read(reader io.Reader) OutgoingChange {
+	return OutgoingChange{
+		FfiConverterRecordChangeINSTANCE.Read(reader),
+		FfiConverterOptionalRecordINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterOutgoingChange) Lower(value OutgoingChange) C.RustBuffer {
+	return LowerIntoRustBuffer[OutgoingChange](c, value)
+}
+
+func (c FfiConverterOutgoingChange) Write(writer io.Writer, value OutgoingChange) {
+	FfiConverterRecordChangeINSTANCE.Write(writer, value.Change)
+	FfiConverterOptionalRecordINSTANCE.Write(writer, value.Parent)
+}
+
+type FfiDestroyerOutgoingChange struct{}
+
+func (_ FfiDestroyerOutgoingChange) Destroy(value OutgoingChange) {
+	value.Destroy()
+}
+
+// Represents a payment (sent or received)
+type Payment struct {
+	// Unique identifier for the payment
+	Id string
+	// Type of payment (send or receive)
+	PaymentType PaymentType
+	// Status of the payment
+	Status PaymentStatus
+	// Amount in satoshis or token base units
+	Amount u128
+	// Fee paid in satoshis or token base units
+	Fees u128
+	// Timestamp of when the payment was created
+	Timestamp uint64
+	// Method of payment. Sometimes the payment details is empty so this field
+	// is used to determine the payment method.
+	Method PaymentMethod
+	// Details of the payment
+	Details *PaymentDetails
+}
+
+func (r *Payment) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Id)
+	FfiDestroyerPaymentType{}.Destroy(r.PaymentType)
+	FfiDestroyerPaymentStatus{}.Destroy(r.Status)
+	FfiDestroyerTypeu128{}.Destroy(r.Amount)
+	FfiDestroyerTypeu128{}.Destroy(r.Fees)
+	FfiDestroyerUint64{}.Destroy(r.Timestamp)
+	FfiDestroyerPaymentMethod{}.Destroy(r.Method)
+	FfiDestroyerOptionalPaymentDetails{}.Destroy(r.Details)
+}
+
+type FfiConverterPayment struct{}
+
+var FfiConverterPaymentINSTANCE = FfiConverterPayment{}
+
+func (c FfiConverterPayment) Lift(rb RustBufferI) Payment {
+	return LiftFromRustBuffer[Payment](c, rb)
+}
+
+func (c FfiConverterPayment) Read(reader io.Reader) Payment {
+	return Payment{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterPaymentTypeINSTANCE.Read(reader),
+		FfiConverterPaymentStatusINSTANCE.Read(reader),
+		FfiConverterTypeu128INSTANCE.Read(reader),
+		FfiConverterTypeu128INSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterPaymentMethodINSTANCE.Read(reader),
+		FfiConverterOptionalPaymentDetailsINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterPayment) Lower(value Payment) C.RustBuffer {
+	return LowerIntoRustBuffer[Payment](c, value)
+}
+
+func (c FfiConverterPayment) Write(writer io.Writer, value Payment) {
+	FfiConverterStringINSTANCE.Write(writer, value.Id)
+	FfiConverterPaymentTypeINSTANCE.Write(writer, value.PaymentType)
+	FfiConverterPaymentStatusINSTANCE.Write(writer, value.Status)
+	FfiConverterTypeu128INSTANCE.Write(writer, value.Amount)
+	FfiConverterTypeu128INSTANCE.Write(writer, value.Fees)
+	FfiConverterUint64INSTANCE.Write(writer, value.Timestamp)
+	FfiConverterPaymentMethodINSTANCE.Write(writer, value.Method)
+	FfiConverterOptionalPaymentDetailsINSTANCE.Write(writer, value.Details)
+}
+
+type FfiDestroyerPayment struct{}
+
+func (_ FfiDestroyerPayment) Destroy(value Payment) {
+	value.Destroy()
+}
+
+// Metadata associated with a payment that cannot be derived from the Spark operator.
+type PaymentMetadata struct {
+	LnurlPayInfo      *LnurlPayInfo
+	LnurlWithdrawInfo *LnurlWithdrawInfo
+	LnurlDescription  *string
+}
+
+func (r *PaymentMetadata) Destroy() {
+	FfiDestroyerOptionalLnurlPayInfo{}.Destroy(r.LnurlPayInfo)
+	FfiDestroyerOptionalLnurlWithdrawInfo{}.Destroy(r.LnurlWithdrawInfo)
+	FfiDestroyerOptionalString{}.Destroy(r.LnurlDescription)
+}
+
+type FfiConverterPaymentMetadata struct{}
+
+var FfiConverterPaymentMetadataINSTANCE = FfiConverterPaymentMetadata{}
+
+func (c FfiConverterPaymentMetadata) Lift(rb RustBufferI) PaymentMetadata {
+	return LiftFromRustBuffer[PaymentMetadata](c, rb)
+}
+
+func (c FfiConverterPaymentMetadata) Read(reader io.Reader) PaymentMetadata {
+	return PaymentMetadata{
+		FfiConverterOptionalLnurlPayInfoINSTANCE.Read(reader),
+		FfiConverterOptionalLnurlWithdrawInfoINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterPaymentMetadata) Lower(value PaymentMetadata) C.RustBuffer {
+	return LowerIntoRustBuffer[PaymentMetadata](c, value)
+}
+
+func (c FfiConverterPaymentMetadata) Write(writer io.Writer, value PaymentMetadata) {
+	FfiConverterOptionalLnurlPayInfoINSTANCE.Write(writer, value.LnurlPayInfo)
+	FfiConverterOptionalLnurlWithdrawInfoINSTANCE.Write(writer, value.LnurlWithdrawInfo)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.LnurlDescription)
+}
+
+type FfiDestroyerPaymentMetadata struct{}
+
+func (_ FfiDestroyerPaymentMetadata) Destroy(value PaymentMetadata) {
+	value.Destroy()
+}
+
+type PaymentRequestSource struct {
+	Bip21Uri      *string
+	Bip353Address *string
+}
+
+func (r *PaymentRequestSource) Destroy() {
+	FfiDestroyerOptionalString{}.Destroy(r.Bip21Uri)
+	FfiDestroyerOptionalString{}.Destroy(r.Bip353Address)
+}
+
+type FfiConverterPaymentRequestSource struct{}
+
+var FfiConverterPaymentRequestSourceINSTANCE = FfiConverterPaymentRequestSource{}
+
+func (c FfiConverterPaymentRequestSource) Lift(rb RustBufferI) PaymentRequestSource {
+	return LiftFromRustBuffer[PaymentRequestSource](c, rb)
+}
+
+func (c FfiConverterPaymentRequestSource) Read(reader io.Reader) PaymentRequestSource {
+	return PaymentRequestSource{
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterPaymentRequestSource) Lower(value PaymentRequestSource) C.RustBuffer {
+	return LowerIntoRustBuffer[PaymentRequestSource](c, value)
+}
+
+func (c FfiConverterPaymentRequestSource) Write(writer io.Writer, value PaymentRequestSource) {
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Bip21Uri)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Bip353Address)
+}
+
+type FfiDestroyerPaymentRequestSource struct{}
+
+func (_ FfiDestroyerPaymentRequestSource) Destroy(value PaymentRequestSource) {
+	value.Destroy()
+}
+
+type PrepareLnurlPayRequest struct {
+	AmountSats               uint64
+	PayRequest               LnurlPayRequestDetails
+	Comment                  *string
+	ValidateSuccessActionUrl *bool
+}
+
+func (r *PrepareLnurlPayRequest) Destroy() {
+	FfiDestroyerUint64{}.Destroy(r.AmountSats)
+	FfiDestroyerLnurlPayRequestDetails{}.Destroy(r.PayRequest)
+	FfiDestroyerOptionalString{}.Destroy(r.Comment)
+	FfiDestroyerOptionalBool{}.Destroy(r.ValidateSuccessActionUrl)
+}
+
+type FfiConverterPrepareLnurlPayRequest struct{}
+
+var FfiConverterPrepareLnurlPayRequestINSTANCE = FfiConverterPrepareLnurlPayRequest{}
+
+func (c FfiConverterPrepareLnurlPayRequest) Lift(rb RustBufferI) PrepareLnurlPayRequest {
+	return LiftFromRustBuffer[PrepareLnurlPayRequest](c, rb)
+}
+
+func (c FfiConverterPrepareLnurlPayRequest) Read(reader io.Reader) PrepareLnurlPayRequest {
+	return PrepareLnurlPayRequest{
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterLnurlPayRequestDetailsINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterOptionalBoolINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterPrepareLnurlPayRequest) Lower(value PrepareLnurlPayRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[PrepareLnurlPayRequest](c, value)
+}
+
+func (c FfiConverterPrepareLnurlPayRequest) Write(writer io.Writer, value PrepareLnurlPayRequest) {
+	FfiConverterUint64INSTANCE.Write(writer, value.AmountSats)
+	FfiConverterLnurlPayRequestDetailsINSTANCE.Write(writer, value.PayRequest)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Comment)
+	FfiConverterOptionalBoolINSTANCE.Write(writer, value.ValidateSuccessActionUrl)
+}
+
+type FfiDestroyerPrepareLnurlPayRequest struct{}
+
+func (_ FfiDestroyerPrepareLnurlPayRequest) Destroy(value PrepareLnurlPayRequest) {
+	value.Destroy()
+}
+
+type PrepareLnurlPayResponse struct {
+	AmountSats     uint64
+	Comment        *string
+	PayRequest     LnurlPayRequestDetails
+	FeeSats        uint64
+	InvoiceDetails Bolt11InvoiceDetails
+	SuccessAction  *SuccessAction
+}
+
+func (r *PrepareLnurlPayResponse) Destroy() {
+	FfiDestroyerUint64{}.Destroy(r.AmountSats)
+	FfiDestroyerOptionalString{}.Destroy(r.Comment)
+	FfiDestroyerLnurlPayRequestDetails{}.Destroy(r.PayRequest)
+	FfiDestroyerUint64{}.Destroy(r.FeeSats)
+	FfiDestroyerBolt11InvoiceDetails{}.Destroy(r.InvoiceDetails)
+	FfiDestroyerOptionalSuccessAction{}.Destroy(r.SuccessAction)
+}
+
+type FfiConverterPrepareLnurlPayResponse struct{}
+
+var FfiConverterPrepareLnurlPayResponseINSTANCE = FfiConverterPrepareLnurlPayResponse{}
+
+func (c FfiConverterPrepareLnurlPayResponse) Lift(rb RustBufferI) PrepareLnurlPayResponse {
+	return LiftFromRustBuffer[PrepareLnurlPayResponse](c, rb)
+}
+
+func (c FfiConverterPrepareLnurlPayResponse) Read(reader io.Reader) PrepareLnurlPayResponse {
+	return PrepareLnurlPayResponse{
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterLnurlPayRequestDetailsINSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterBolt11InvoiceDetailsINSTANCE.Read(reader),
+		FfiConverterOptionalSuccessActionINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterPrepareLnurlPayResponse) Lower(value PrepareLnurlPayResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[PrepareLnurlPayResponse](c, value)
+}
+
+func (c FfiConverterPrepareLnurlPayResponse) Write(writer io.Writer, value PrepareLnurlPayResponse) {
+	FfiConverterUint64INSTANCE.Write(writer, value.AmountSats)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Comment)
+	FfiConverterLnurlPayRequestDetailsINSTANCE.Write(writer, value.PayRequest)
+	FfiConverterUint64INSTANCE.Write(writer, value.FeeSats)
+	FfiConverterBolt11InvoiceDetailsINSTANCE.Write(writer, value.InvoiceDetails)
+	FfiConverterOptionalSuccessActionINSTANCE.Write(writer, value.SuccessAction)
+}
+
+type FfiDestroyerPrepareLnurlPayResponse struct{}
+
+func (_ FfiDestroyerPrepareLnurlPayResponse) Destroy(value PrepareLnurlPayResponse) {
+	value.Destroy()
+}
+
+type PrepareSendPaymentRequest struct {
+	PaymentRequest string
+	// Amount to send. By default is denominated in sats.
+	// If a token identifier is provided, the amount will be denominated in the token base units.
+	Amount *u128
+	// If provided, the payment will be for a token
+	// May only be provided if the payment request is a spark address
+	TokenIdentifier *string
+}
+
+func (r *PrepareSendPaymentRequest) Destroy() {
+	FfiDestroyerString{}.Destroy(r.PaymentRequest)
+	FfiDestroyerOptionalTypeu128{}.Destroy(r.Amount)
+	FfiDestroyerOptionalString{}.Destroy(r.TokenIdentifier)
+}
+
+type FfiConverterPrepareSendPaymentRequest struct{}
+
+var FfiConverterPrepareSendPaymentRequestINSTANCE = FfiConverterPrepareSendPaymentRequest{}
+
+func (c FfiConverterPrepareSendPaymentRequest) Lift(rb RustBufferI) PrepareSendPaymentRequest {
+	return LiftFromRustBuffer[PrepareSendPaymentRequest](c, rb)
+}
+
+func (c FfiConverterPrepareSendPaymentRequest) Read(reader io.Reader) PrepareSendPaymentRequest {
+	return PrepareSendPaymentRequest{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterOptionalTypeu128INSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterPrepareSendPaymentRequest) Lower(value PrepareSendPaymentRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[PrepareSendPaymentRequest](c, value)
+}
+
+func (c FfiConverterPrepareSendPaymentRequest) Write(writer io.Writer, value PrepareSendPaymentRequest) {
+	FfiConverterStringINSTANCE.Write(writer, value.PaymentRequest)
+	FfiConverterOptionalTypeu128INSTANCE.Write(writer, value.Amount)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.TokenIdentifier)
+}
+
+type FfiDestroyerPrepareSendPaymentRequest struct{}
+
+func (_ FfiDestroyerPrepareSendPaymentRequest) Destroy(value PrepareSendPaymentRequest) {
+	value.Destroy()
+}
+
+type PrepareSendPaymentResponse struct {
+	PaymentMethod SendPaymentMethod
+	// Amount to send. By default is denominated in sats.
+	// If a token identifier is provided, the amount will be denominated in the token base units.
+	Amount u128
+	// The presence of this field indicates that the payment is for a token
+	// If empty, it is a Bitcoin payment
+	TokenIdentifier *string
+}
+
+func (r *PrepareSendPaymentResponse) Destroy() {
+	FfiDestroyerSendPaymentMethod{}.Destroy(r.PaymentMethod)
+	FfiDestroyerTypeu128{}.Destroy(r.Amount)
+	FfiDestroyerOptionalString{}.Destroy(r.TokenIdentifier)
+}
+
+type FfiConverterPrepareSendPaymentResponse struct{}
+
+var FfiConverterPrepareSendPaymentResponseINSTANCE = FfiConverterPrepareSendPaymentResponse{}
+
+func (c FfiConverterPrepareSendPaymentResponse) Lift(rb RustBufferI) PrepareSendPaymentResponse {
+	return LiftFromRustBuffer[PrepareSendPaymentResponse](c, rb)
+}
+
+func (c FfiConverterPrepareSendPaymentResponse) Read(reader io.Reader) PrepareSendPaymentResponse {
+	return PrepareSendPaymentResponse{
+		FfiConverterSendPaymentMethodINSTANCE.Read(reader),
+		FfiConverterTypeu128INSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterPrepareSendPaymentResponse) Lower(value PrepareSendPaymentResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[PrepareSendPaymentResponse](c, value)
+}
+
+func (c FfiConverterPrepareSendPaymentResponse) Write(writer io.Writer, value PrepareSendPaymentResponse) {
+	FfiConverterSendPaymentMethodINSTANCE.Write(writer, value.PaymentMethod)
+	FfiConverterTypeu128INSTANCE.Write(writer, value.Amount)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.TokenIdentifier)
+}
+
+type FfiDestroyerPrepareSendPaymentResponse struct{}
+
+func (_ FfiDestroyerPrepareSendPaymentResponse) Destroy(value PrepareSendPaymentResponse) {
+	value.Destroy()
+}
+
+type ProvisionalPayment struct {
+	// Unique identifier for the payment
+	PaymentId string
+	// Amount in satoshis or token base units
+	Amount u128
+	// Details of the payment
+	Details ProvisionalPaymentDetails
+}
+
+func (r *ProvisionalPayment) Destroy() {
+	FfiDestroyerString{}.Destroy(r.PaymentId)
+	FfiDestroyerTypeu128{}.Destroy(r.Amount)
+	FfiDestroyerProvisionalPaymentDetails{}.Destroy(r.Details)
+}
+
+type FfiConverterProvisionalPayment struct{}
+
+var FfiConverterProvisionalPaymentINSTANCE = FfiConverterProvisionalPayment{}
+
+func (c FfiConverterProvisionalPayment) Lift(rb RustBufferI) ProvisionalPayment {
+	return LiftFromRustBuffer[ProvisionalPayment](c, rb)
+}
+
+func (c FfiConverterProvisionalPayment) Read(reader io.Reader) ProvisionalPayment {
+	return ProvisionalPayment{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterTypeu128INSTANCE.Read(reader),
+		FfiConverterProvisionalPaymentDetailsINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterProvisionalPayment) Lower(value ProvisionalPayment) C.RustBuffer {
+	return LowerIntoRustBuffer[ProvisionalPayment](c, value)
+}
+
+func (c FfiConverterProvisionalPayment) Write(writer io.Writer, value ProvisionalPayment) {
+	FfiConverterStringINSTANCE.Write(writer, value.PaymentId)
+	FfiConverterTypeu128INSTANCE.Write(writer, value.Amount)
+	FfiConverterProvisionalPaymentDetailsINSTANCE.Write(writer, value.Details)
+}
+
+type FfiDestroyerProvisionalPayment struct{}
+
+func (_ FfiDestroyerProvisionalPayment) Destroy(value ProvisionalPayment) {
+	value.Destroy()
+}
+
+// Denominator in an exchange rate
+type Rate struct {
+	Coin  string
+	Value float64
+}
+
+func (r *Rate) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Coin)
+	FfiDestroyerFloat64{}.Destroy(r.Value)
+}
+
+type FfiConverterRate struct{}
+
+var FfiConverterRateINSTANCE = FfiConverterRate{}
+
+func (c FfiConverterRate) Lift(rb RustBufferI) Rate {
+	return LiftFromRustBuffer[Rate](c, rb)
+}
+
+func (c FfiConverterRate) Read(reader io.Reader) Rate {
+	return Rate{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterFloat64INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterRate) Lower(value Rate) C.RustBuffer {
+	return LowerIntoRustBuffer[Rate](c, value)
+}
+
+func (c FfiConverterRate) Write(writer io.Writer, value Rate) {
+	FfiConverterStringINSTANCE.Write(writer, value.Coin)
+	FfiConverterFloat64INSTANCE.Write(writer, value.Value)
+}
+
+type FfiDestroyerRate struct{}
+
+func (_ FfiDestroyerRate) Destroy(value Rate) {
+	value.Destroy()
+}
+
+type ReceivePaymentRequest struct {
+	PaymentMethod ReceivePaymentMethod
+}
+
+func (r *ReceivePaymentRequest) Destroy() {
+	FfiDestroyerReceivePaymentMethod{}.Destroy(r.PaymentMethod)
+}
+
+type FfiConverterReceivePaymentRequest struct{}
+
+var FfiConverterReceivePaymentRequestINSTANCE = FfiConverterReceivePaymentRequest{}
+
+func (c FfiConverterReceivePaymentRequest) Lift(rb RustBufferI) ReceivePaymentRequest {
+	return LiftFromRustBuffer[ReceivePaymentRequest](c, rb)
+}
+
+func (c FfiConverterReceivePaymentRequest) Read(reader io.Reader) ReceivePaymentRequest {
+	return ReceivePaymentRequest{
+		FfiConverterReceivePaymentMethodINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterReceivePaymentRequest) Lower(value ReceivePaymentRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[ReceivePaymentRequest](c, value)
+}
+
+func (c FfiConverterReceivePaymentRequest) Write(writer io.Writer, value ReceivePaymentRequest) {
+	FfiConverterReceivePaymentMethodINSTANCE.Write(writer, value.PaymentMethod)
+}
+
+type FfiDestroyerReceivePaymentRequest struct{}
+
+func (_ FfiDestroyerReceivePaymentRequest) Destroy(value ReceivePaymentRequest) {
+	value.Destroy()
+}
+
+type ReceivePaymentResponse struct {
+	PaymentRequest string
+	// Fee to pay to receive the payment
+	// Denominated in sats or token base units
+	Fee u128
+}
+
+func (r *ReceivePaymentResponse) Destroy() {
+	FfiDestroyerString{}.Destroy(r.PaymentRequest)
+	FfiDestroyerTypeu128{}.Destroy(r.Fee)
+}
+
+type FfiConverterReceivePaymentResponse struct{}
+
+var FfiConverterReceivePaymentResponseINSTANCE = FfiConverterReceivePaymentResponse{}
+
+func (c FfiConverterReceivePaymentResponse) Lift(rb RustBufferI) ReceivePaymentResponse {
+	return LiftFromRustBuffer[ReceivePaymentResponse](c, rb)
+}
+
+func (c FfiConverterReceivePaymentResponse) Read(reader io.Reader) ReceivePaymentResponse {
+	return ReceivePaymentResponse{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterTypeu128INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterReceivePaymentResponse) Lower(value ReceivePaymentResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[ReceivePaymentResponse](c, value)
+}
+
+func (c FfiConverterReceivePaymentResponse) Write(writer io.Writer, value ReceivePaymentResponse) {
+	FfiConverterStringINSTANCE.Write(writer, value.PaymentRequest)
+	FfiConverterTypeu128INSTANCE.Write(writer, value.Fee)
+}
+
+type FfiDestroyerReceivePaymentResponse struct{}
+
+func (_ FfiDestroyerReceivePaymentResponse) Destroy(value ReceivePaymentResponse) {
+	value.Destroy()
+}
+
+type RecommendedFees struct {
+	FastestFee  uint64
+	HalfHourFee uint64
+	HourFee     uint64
+	EconomyFee  uint64
+	MinimumFee  uint64
+}
+
+func (r *RecommendedFees) Destroy() {
+	FfiDestroyerUint64{}.Destroy(r.FastestFee)
+	FfiDestroyerUint64{}.Destroy(r.HalfHourFee)
+	FfiDestroyerUint64{}.Destroy(r.HourFee)
+	FfiDestroyerUint64{}.Destroy(r.EconomyFee)
+	FfiDestroyerUint64{}.Destroy(r.MinimumFee)
+}
+
+type FfiConverterRecommendedFees struct{}
+
+var FfiConverterRecommendedFeesINSTANCE = FfiConverterRecommendedFees{}
+
+func (c FfiConverterRecommendedFees) Lift(rb RustBufferI) RecommendedFees {
+	return LiftFromRustBuffer[RecommendedFees](c, rb)
+}
+
+func (c FfiConverterRecommendedFees) Read(reader io.Reader) RecommendedFees {
+	return RecommendedFees{
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterRecommendedFees) Lower(value RecommendedFees) C.RustBuffer {
+	return LowerIntoRustBuffer[RecommendedFees](c, value)
+}
+
+func (c FfiConverterRecommendedFees) Write(writer io.Writer, value RecommendedFees) {
+	FfiConverterUint64INSTANCE.Write(writer, value.FastestFee)
+	FfiConverterUint64INSTANCE.Write(writer, value.HalfHourFee)
+	FfiConverterUint64INSTANCE.Write(writer, value.HourFee)
+	FfiConverterUint64INSTANCE.Write(writer, value.EconomyFee)
+	FfiConverterUint64INSTANCE.Write(writer, value.MinimumFee)
+}
+
+type FfiDestroyerRecommendedFees struct{}
+
+func (_ FfiDestroyerRecommendedFees) Destroy(value RecommendedFees) {
+	value.Destroy()
+}
+
+type Record struct {
+	Id            RecordId
+	Revision      uint64
+	SchemaVersion string
+	Data          map[string]string
+}
+
+func (r *Record) Destroy() {
+	FfiDestroyerRecordId{}.Destroy(r.Id)
+	FfiDestroyerUint64{}.Destroy(r.Revision)
+	FfiDestroyerString{}.Destroy(r.SchemaVersion)
+	FfiDestroyerMapStringString{}.Destroy(r.Data)
+}
+
+type FfiConverterRecord struct{}
+
+var FfiConverterRecordINSTANCE = FfiConverterRecord{}
+
+func (c FfiConverterRecord) Lift(rb RustBufferI) Record {
+	return LiftFromRustBuffer[Record](c, rb)
+}
+
+func (c FfiConverterRecord) Read(reader io.Reader) Record {
+	return Record{
+		FfiConverterRecordIdINSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterMapStringStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterRecord) Lower(value Record) C.RustBuffer {
+	return LowerIntoRustBuffer[Record](c, value)
+}
+
+func (c FfiConverterRecord) Write(writer io.Writer, value Record) {
+	FfiConverterRecordIdINSTANCE.Write(writer, value.Id)
+	FfiConverterUint64INSTANCE.Write(writer, value.Revision)
+	FfiConverterStringINSTANCE.Write(writer, value.SchemaVersion)
+	FfiConverterMapStringStringINSTANCE.Write(writer, value.Data)
+}
+
+type FfiDestroyerRecord struct{}
+
+func (_ FfiDestroyerRecord) Destroy(value Record) {
+	value.Destroy()
+}
+
+type RecordChange struct {
+	Id            RecordId
+	SchemaVersion string
+	UpdatedFields map[string]string
+	Revision      uint64
+}
+
+func (r *RecordChange) Destroy() {
+	FfiDestroyerRecordId{}.Destroy(r.Id)
+	FfiDestroyerString{}.Destroy(r.SchemaVersion)
+	FfiDestroyerMapStringString{}.Destroy(r.UpdatedFields)
+	FfiDestroyerUint64{}.Destroy(r.Revision)
+}
+
+type FfiConverterRecordChange struct{}
+
+var FfiConverterRecordChangeINSTANCE = FfiConverterRecordChange{}
+
+func (c FfiConverterRecordChange) Lift(rb RustBufferI) RecordChange {
+	return LiftFromRustBuffer[RecordChange](c, rb)
+}
+
+func (c FfiConverterRecordChange) Read(reader io.Reader) RecordChange {
+	return RecordChange{
+		FfiConverterRecordIdINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterMapStringStringINSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterRecordChange) Lower(value RecordChange) C.RustBuffer {
+	return LowerIntoRustBuffer[RecordChange](c, value)
+}
+
+func (c FfiConverterRecordChange) Write(writer io.Writer, value RecordChange) {
+	FfiConverterRecordIdINSTANCE.Write(writer, value.Id)
+	FfiConverterStringINSTANCE.Write(writer, value.SchemaVersion)
+	FfiConverterMapStringStringINSTANCE.Write(writer, value.UpdatedFields)
+	FfiConverterUint64INSTANCE.Write(writer, value.Revision)
+}
+
+type FfiDestroyerRecordChange struct{}
+
+func (_ FfiDestroyerRecordChange) Destroy(value RecordChange) {
+	value.Destroy()
+}
+
+type RecordId struct {
+	Type   string
+	DataId string
+}
+
+func (r *RecordId) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Type)
+	FfiDestroyerString{}.Destroy(r.DataId)
+}
+
+type FfiConverterRecordId struct{}
+
+var FfiConverterRecordIdINSTANCE = FfiConverterRecordId{}
+
+func (c FfiConverterRecordId) Lift(rb RustBufferI) RecordId {
+	return LiftFromRustBuffer[RecordId](c, rb)
+}
+
+func (c FfiConverterRecordId) Read(reader io.Reader) RecordId {
+	return RecordId{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterRecordId) Lower(value RecordId) C.RustBuffer {
+	return LowerIntoRustBuffer[RecordId](c, value)
+}
+
+func (c FfiConverterRecordId) Write(writer io.Writer, value RecordId) {
+	FfiConverterStringINSTANCE.Write(writer, value.Type)
+	FfiConverterStringINSTANCE.Write(writer, value.DataId)
+}
+
+type FfiDestroyerRecordId struct{}
+
+func (_ FfiDestroyerRecordId) Destroy(value RecordId) {
+	value.Destroy()
+}
+
+type RefundDepositRequest struct {
+	Txid               string
+	Vout               uint32
+	DestinationAddress string
+	Fee                Fee
+}
+
+func (r *RefundDepositRequest) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Txid)
+	FfiDestroyerUint32{}.Destroy(r.Vout)
+	FfiDestroyerString{}.Destroy(r.DestinationAddress)
+	FfiDestroyerFee{}.Destroy(r.Fee)
+}
+
+type FfiConverterRefundDepositRequest struct{}
+
+var FfiConverterRefundDepositRequestINSTANCE = FfiConverterRefundDepositRequest{}
+
+func (c FfiConverterRefundDepositRequest) Lift(rb RustBufferI) RefundDepositRequest {
+	return LiftFromRustBuffer[RefundDepositRequest](c, rb)
+}
+
+func (c FfiConverterRefundDepositRequest) Read(reader io.Reader) RefundDepositRequest {
+	return RefundDepositRequest{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterUint32INSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterFeeINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterRefundDepositRequest) Lower(value RefundDepositRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[RefundDepositRequest](c, value)
+}
+
+func (c FfiConverterRefundDepositRequest) Write(writer io.Writer, value RefundDepositRequest) {
+	FfiConverterStringINSTANCE.Write(writer, value.Txid)
+	FfiConverterUint32INSTANCE.Write(writer, value.Vout)
+	FfiConverterStringINSTANCE.Write(writer, value.DestinationAddress)
+	FfiConverterFeeINSTANCE.Write(writer, value.Fee)
+}
+
+type FfiDestroyerRefundDepositRequest struct{}
+
+func (_ FfiDestroyerRefundDepositRequest) Destroy(value RefundDepositRequest) {
+	value.Destroy()
+}
+
+type RefundDepositResponse struct {
+	TxId  string
+	TxHex string
+}
+
+func (r *RefundDepositResponse) Destroy() {
+	FfiDestroyerString{}.Destroy(r.TxId)
+	FfiDestroyerString{}.Destroy(r.TxHex)
+}
+
+type FfiConverterRefundDepositResponse struct{}
+
+var FfiConverterRefundDepositResponseINSTANCE = FfiConverterRefundDepositResponse{}
+
+func (c FfiConverterRefundDepositResponse) Lift(rb RustBufferI) RefundDepositResponse {
+	return LiftFromRustBuffer[RefundDepositResponse](c, rb)
+}
+
+func (c FfiConverterRefundDepositResponse) Read(reader io.Reader) RefundDepositResponse {
+	return RefundDepositResponse{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterRefundDepositResponse) Lower(value RefundDepositResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[RefundDepositResponse](c, value)
+}
+
+func (c FfiConverterRefundDepositResponse) Write(writer io.Writer, value RefundDepositResponse) {
+	FfiConverterStringINSTANCE.Write(writer, value.TxId)
+	FfiConverterStringINSTANCE.Write(writer, value.TxHex)
+}
+
+type FfiDestroyerRefundDepositResponse struct{}
+
+func (_ FfiDestroyerRefundDepositResponse) Destroy(value RefundDepositResponse) {
+	value.Destroy()
+}
+
+type RegisterLightningAddressRequest struct {
+	Username    string
+	Description *string
+}
+
+func (r *RegisterLightningAddressRequest) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Username)
+	FfiDestroyerOptionalString{}.Destroy(r.Description)
+}
+
+type FfiConverterRegisterLightningAddressRequest struct{}
+
+var FfiConverterRegisterLightningAddressRequestINSTANCE = FfiConverterRegisterLightningAddressRequest{}
+
+func (c FfiConverterRegisterLightningAddressRequest) Lift(rb RustBufferI) RegisterLightningAddressRequest {
+	return LiftFromRustBuffer[RegisterLightningAddressRequest](c, rb)
+}
+
+func (c FfiConverterRegisterLightningAddressRequest) Read(reader io.Reader) RegisterLightningAddressRequest {
+	return RegisterLightningAddressRequest{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterRegisterLightningAddressRequest) Lower(value RegisterLightningAddressRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[RegisterLightningAddressRequest](c, value)
+}
+
+func (c FfiConverterRegisterLightningAddressRequest) Write(writer io.Writer, value RegisterLightningAddressRequest) {
+	FfiConverterStringINSTANCE.Write(writer, value.Username)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Description)
+}
+
+type FfiDestroyerRegisterLightningAddressRequest struct{}
+
+func (_ FfiDestroyerRegisterLightningAddressRequest) Destroy(value RegisterLightningAddressRequest) {
+	value.Destroy()
+}
+
+type RestResponse struct {
+	Status uint16
+	Body   string
+}
+
+func (r *RestResponse) Destroy() {
+	FfiDestroyerUint16{}.Destroy(r.Status)
+	FfiDestroyerString{}.Destroy(r.Body)
+}
+
+type FfiConverterRestResponse struct{}
+
+var FfiConverterRestResponseINSTANCE = FfiConverterRestResponse{}
+
+func (c FfiConverterRestResponse) Lift(rb RustBufferI) RestResponse {
+	return LiftFromRustBuffer[RestResponse](c, rb)
+}
+
+func (c FfiConverterRestResponse) Read(reader io.Reader) RestResponse {
+	return RestResponse{
+		FfiConverterUint16INSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterRestResponse) Lower(value RestResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[RestResponse](c, value)
+}
+
+func (c FfiConverterRestResponse) Write(writer io.Writer, value RestResponse) {
+	FfiConverterUint16INSTANCE.Write(writer, value.Status)
+	FfiConverterStringINSTANCE.Write(writer, value.Body)
+}
+
+type FfiDestroyerRestResponse struct{}
+
+func (_ FfiDestroyerRestResponse) Destroy(value RestResponse) {
+	value.Destroy()
+}
+
+type SendOnchainFeeQuote struct {
+	Id          string
+	ExpiresAt   uint64
+	SpeedFast   SendOnchainSpeedFeeQuote
+	SpeedMedium SendOnchainSpeedFeeQuote
+	SpeedSlow   SendOnchainSpeedFeeQuote
+}
+
+func (r *SendOnchainFeeQuote) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Id)
+	FfiDestroyerUint64{}.Destroy(r.ExpiresAt)
+	FfiDestroyerSendOnchainSpeedFeeQuote{}.Destroy(r.SpeedFast)
+	FfiDestroyerSendOnchainSpeedFeeQuote{}.Destroy(r.SpeedMedium)
+	FfiDestroyerSendOnchainSpeedFeeQuote{}.Destroy(r.SpeedSlow)
+}
+
+type FfiConverterSendOnchainFeeQuote struct{}
+
+var FfiConverterSendOnchainFeeQuoteINSTANCE = FfiConverterSendOnchainFeeQuote{}
+
+func (c FfiConverterSendOnchainFeeQuote) Lift(rb RustBufferI) SendOnchainFeeQuote {
+	return LiftFromRustBuffer[SendOnchainFeeQuote](c, rb)
+}
+
+func (c FfiConverterSendOnchainFeeQuote) Read(reader io.Reader) SendOnchainFeeQuote {
+	return SendOnchainFeeQuote{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterSendOnchainSpeedFeeQuoteINSTANCE.Read(reader),
+		FfiConverterSendOnchainSpeedFeeQuoteINSTANCE.Read(reader),
+		FfiConverterSendOnchainSpeedFeeQuoteINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSendOnchainFeeQuote) Lower(value SendOnchainFeeQuote) C.RustBuffer {
+	return LowerIntoRustBuffer[SendOnchainFeeQuote](c, value)
+}
+
+func (c FfiConverterSendOnchainFeeQuote) Write(writer io.Writer, value SendOnchainFeeQuote) {
+	FfiConverterStringINSTANCE.Write(writer, value.Id)
+	FfiConverterUint64INSTANCE.Write(writer, value.ExpiresAt)
+	FfiConverterSendOnchainSpeedFeeQuoteINSTANCE.Write(writer, value.SpeedFast)
+	FfiConverterSendOnchainSpeedFeeQuoteINSTANCE.Write(writer, value.SpeedMedium)
+	FfiConverterSendOnchainSpeedFeeQuoteINSTANCE.Write(writer, value.SpeedSlow)
+}
+
+type FfiDestroyerSendOnchainFeeQuote struct{}
+
+func (_ FfiDestroyerSendOnchainFeeQuote) Destroy(value SendOnchainFeeQuote) {
+	value.Destroy()
+}
+
+type SendOnchainSpeedFeeQuote struct {
+	UserFeeSat        uint64
+	L1BroadcastFeeSat uint64
+}
+
+func (r *SendOnchainSpeedFeeQuote) Destroy() {
+	FfiDestroyerUint64{}.Destroy(r.UserFeeSat)
+	FfiDestroyerUint64{}.Destroy(r.L1BroadcastFeeSat)
+}
+
+type FfiConverterSendOnchainSpeedFeeQuote struct{}
+
+var FfiConverterSendOnchainSpeedFeeQuoteINSTANCE = FfiConverterSendOnchainSpeedFeeQuote{}
+
+func (c FfiConverterSendOnchainSpeedFeeQuote) Lift(rb RustBufferI) SendOnchainSpeedFeeQuote {
+	return LiftFromRustBuffer[SendOnchainSpeedFeeQuote](c, rb)
+}
+
+func (c FfiConverterSendOnchainSpeedFeeQuote) Read(reader io.Reader) SendOnchainSpeedFeeQuote {
+	return SendOnchainSpeedFeeQuote{
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSendOnchainSpeedFeeQuote) Lower(value SendOnchainSpeedFeeQuote) C.RustBuffer {
+	return LowerIntoRustBuffer[SendOnchainSpeedFeeQuote](c, value)
+}
+
+func (c FfiConverterSendOnchainSpeedFeeQuote) Write(writer io.Writer, value SendOnchainSpeedFeeQuote) {
+	FfiConverterUint64INSTANCE.Write(writer, value.UserFeeSat)
+	FfiConverterUint64INSTANCE.Write(writer, value.L1BroadcastFeeSat)
+}
+
+type FfiDestroyerSendOnchainSpeedFeeQuote struct{}
+
+func (_ FfiDestroyerSendOnchainSpeedFeeQuote) Destroy(value SendOnchainSpeedFeeQuote) {
+	value.Destroy()
+}
+
+type SendPaymentRequest struct {
+	PrepareResponse PrepareSendPaymentResponse
+	Options         *SendPaymentOptions
+	// The optional idempotency key for all Spark based transfers (excludes token payments).
+	// If set, providing the same idempotency key for multiple requests will ensure that only one
+	// payment is made. If an idempotency key is re-used, the same payment will be returned.
+	// The idempotency key must be a valid UUID.
+	IdempotencyKey *string
+}
+
+func (r *SendPaymentRequest) Destroy() {
+	FfiDestroyerPrepareSendPaymentResponse{}.Destroy(r.PrepareResponse)
+	FfiDestroyerOptionalSendPaymentOptions{}.Destroy(r.Options)
+	FfiDestroyerOptionalString{}.Destroy(r.IdempotencyKey)
+}
+
+type FfiConverterSendPaymentRequest struct{}
+
+var FfiConverterSendPaymentRequestINSTANCE = FfiConverterSendPaymentRequest{}
+
+func (c FfiConverterSendPaymentRequest) Lift(rb RustBufferI) SendPaymentRequest {
+	return LiftFromRustBuffer[SendPaymentRequest](c, rb)
+}
+
+func (c FfiConverterSendPaymentRequest) Read(reader io.Reader) SendPaymentRequest {
+	return SendPaymentRequest{
+		FfiConverterPrepareSendPaymentResponseINSTANCE.Read(reader),
+		FfiConverterOptionalSendPaymentOptionsINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSendPaymentRequest) Lower(value SendPaymentRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[SendPaymentRequest](c, value)
+}
+
+func (c FfiConverterSendPaymentRequest) Write(writer io.Writer, value SendPaymentRequest) {
+	FfiConverterPrepareSendPaymentResponseINSTANCE.Write(writer, value.PrepareResponse)
+	FfiConverterOptionalSendPaymentOptionsINSTANCE.Write(writer, value.Options)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.IdempotencyKey)
+}
+
+type FfiDestroyerSendPaymentRequest struct{}
+
+func (_ FfiDestroyerSendPaymentRequest) Destroy(value SendPaymentRequest) {
+	value.Destroy()
+}
+
+type SendPaymentResponse struct {
+	Payment Payment
+}
+
+func (r *SendPaymentResponse) Destroy() {
+	FfiDestroyerPayment{}.Destroy(r.Payment)
+}
+
+type FfiConverterSendPaymentResponse struct{}
+
+var FfiConverterSendPaymentResponseINSTANCE = FfiConverterSendPaymentResponse{}
+
+func (c FfiConverterSendPaymentResponse) Lift(rb RustBufferI) SendPaymentResponse {
+	return LiftFromRustBuffer[SendPaymentResponse](c, rb)
+}
+
+func (c FfiConverterSendPaymentResponse) Read(reader io.Reader) SendPaymentResponse {
+	return SendPaymentResponse{
+		FfiConverterPaymentINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSendPaymentResponse) Lower(value SendPaymentResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[SendPaymentResponse](c, value)
+}
+
+func (c FfiConverterSendPaymentResponse) Write(writer io.Writer, value SendPaymentResponse) {
+	FfiConverterPaymentINSTANCE.Write(writer, value.Payment)
+}
+
+type FfiDestroyerSendPaymentResponse struct{}
+
+func (_ FfiDestroyerSendPaymentResponse) Destroy(value SendPaymentResponse) {
+	value.Destroy()
+}
+
+type SetLnurlMetadataItem struct {
+	PaymentHash     string
+	SenderComment   *string
+	NostrZapRequest *string
+	NostrZapReceipt *string
+}
+
+func (r *SetLnurlMetadataItem) Destroy() {
+	FfiDestroyerString{}.Destroy(r.PaymentHash)
+	FfiDestroyerOptionalString{}.Destroy(r.SenderComment)
+	FfiDestroyerOptionalString{}.Destroy(r.NostrZapRequest)
+	FfiDestroyerOptionalString{}.Destroy(r.NostrZapReceipt)
+}
+
+type FfiConverterSetLnurlMetadataItem struct{}
+
+var FfiConverterSetLnurlMetadataItemINSTANCE = FfiConverterSetLnurlMetadataItem{}
+
+func (c FfiConverterSetLnurlMetadataItem) Lift(rb RustBufferI) SetLnurlMetadataItem {
+	return LiftFromRustBuffer[SetLnurlMetadataItem](c, rb)
+}
+
+func (c FfiConverterSetLnurlMetadataItem) Read(reader io.Reader) SetLnurlMetadataItem {
+	return SetLnurlMetadataItem{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSetLnurlMetadataItem) Lower(value SetLnurlMetadataItem) C.RustBuffer {
+	return LowerIntoRustBuffer[SetLnurlMetadataItem](c, value)
+}
+
+func (c FfiConverterSetLnurlMetadataItem) Write(writer io.Writer, value SetLnurlMetadataItem) {
+	FfiConverterStringINSTANCE.Write(writer, value.PaymentHash)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.SenderComment)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.NostrZapRequest)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.NostrZapReceipt)
+}
+
+type FfiDestroyerSetLnurlMetadataItem struct{}
+
+func (_ FfiDestroyerSetLnurlMetadataItem) Destroy(value SetLnurlMetadataItem) {
+	value.Destroy()
+}
+
+type SignMessageRequest struct {
+	Message string
+	// If true, the signature will be encoded in compact format instead of DER format
+	Compact bool
+}
+
+func (r *SignMessageRequest) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Message)
+	FfiDestroyerBool{}.Destroy(r.Compact)
+}
+
+type FfiConverterSignMessageRequest struct{}
+
+var FfiConverterSignMessageRequestINSTANCE = FfiConverterSignMessageRequest{}
+
+func (c FfiConverterSignMessageRequest) Lift(rb RustBufferI) SignMessageRequest {
+	return LiftFromRustBuffer[SignMessageRequest](c, rb)
+}
+
+func (c FfiConverterSignMessageRequest) Read(reader io.Reader) SignMessageRequest {
+	return SignMessageRequest{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterBoolINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSignMessageRequest) Lower(value SignMessageRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[SignMessageRequest](c, value)
+}
+
+func (c FfiConverterSignMessageRequest) Write(writer io.Writer, value SignMessageRequest) {
+	FfiConverterStringINSTANCE.Write(writer, value.Message)
+	FfiConverterBoolINSTANCE.Write(writer, value.Compact)
+}
+
+type FfiDestroyerSignMessageRequest struct{}
+
+func (_ FfiDestroyerSignMessageRequest) Destroy(value SignMessageRequest) {
+	value.Destroy()
+}
+
+type SignMessageResponse struct {
+	Pubkey string
+	// The DER or compact hex encoded signature
+	Signature string
+}
+
+func (r *SignMessageResponse) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Pubkey)
+	FfiDestroyerString{}.Destroy(r.Signature)
+}
+
+type FfiConverterSignMessageResponse struct{}
+
+var FfiConverterSignMessageResponseINSTANCE = FfiConverterSignMessageResponse{}
+
+func (c FfiConverterSignMessageResponse) Lift(rb RustBufferI) SignMessageResponse {
+	return LiftFromRustBuffer[SignMessageResponse](c, rb)
+}
+
+func (c FfiConverterSignMessageResponse) Read(reader io.Reader) SignMessageResponse {
+	return SignMessageResponse{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSignMessageResponse) Lower(value SignMessageResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[SignMessageResponse](c, value)
+}
+
+func (c FfiConverterSignMessageResponse) Write(writer io.Writer, value SignMessageResponse) {
+	FfiConverterStringINSTANCE.Write(writer, value.Pubkey)
+	FfiConverterStringINSTANCE.Write(writer, value.Signature)
+}
+
+type FfiDestroyerSignMessageResponse struct{}
+
+func (_ FfiDestroyerSignMessageResponse) Destroy(value SignMessageResponse) {
+	value.Destroy()
+}
+
+type SilentPaymentAddressDetails struct {
+	Address string
+	Network BitcoinNetwork
+	Source  PaymentRequestSource
+}
+
+func (r *SilentPaymentAddressDetails) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Address)
+	FfiDestroyerBitcoinNetwork{}.Destroy(r.Network)
+	FfiDestroyerPaymentRequestSource{}.Destroy(r.Source)
+}
+
+type FfiConverterSilentPaymentAddressDetails struct{}
+
+var FfiConverterSilentPaymentAddressDetailsINSTANCE = FfiConverterSilentPaymentAddressDetails{}
+
+func (c FfiConverterSilentPaymentAddressDetails) Lift(rb RustBufferI) SilentPaymentAddressDetails {
+	return LiftFromRustBuffer[SilentPaymentAddressDetails](c, rb)
+}
+
+func (c FfiConverterSilentPaymentAddressDetails) Read(reader io.Reader) SilentPaymentAddressDetails {
+	return SilentPaymentAddressDetails{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterBitcoinNetworkINSTANCE.Read(reader),
+		FfiConverterPaymentRequestSourceINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSilentPaymentAddressDetails) Lower(value SilentPaymentAddressDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[SilentPaymentAddressDetails](c, value)
+}
+
+func (c FfiConverterSilentPaymentAddressDetails) Write(writer io.Writer, value SilentPaymentAddressDetails) {
+	FfiConverterStringINSTANCE.Write(writer, value.Address)
+	FfiConverterBitcoinNetworkINSTANCE.Write(writer, value.Network)
+	FfiConverterPaymentRequestSourceINSTANCE.Write(writer, value.Source)
+}
+
+type FfiDestroyerSilentPaymentAddressDetails struct{}
+
+func (_ FfiDestroyerSilentPaymentAddressDetails) Destroy(value SilentPaymentAddressDetails) {
+	value.Destroy()
+}
+
+type SparkAddressDetails struct {
+	// The raw address string
+	Address string
+	// The identity public key of the address owner
+	IdentityPublicKey string
+	Network           BitcoinNetwork
+	Source            PaymentRequestSource
+}
+
+func (r *SparkAddressDetails) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Address)
+	FfiDestroyerString{}.Destroy(r.IdentityPublicKey)
+	FfiDestroyerBitcoinNetwork{}.Destroy(r.Network)
+	FfiDestroyerPaymentRequestSource{}.Destroy(r.Source)
+}
+
+type FfiConverterSparkAddressDetails struct{}
+
+var FfiConverterSparkAddressDetailsINSTANCE = FfiConverterSparkAddressDetails{}
+
+func (c FfiConverterSparkAddressDetails) Lift(rb RustBufferI) SparkAddressDetails {
+	return LiftFromRustBuffer[SparkAddressDetails](c, rb)
+}
+
+func (c FfiConverterSparkAddressDetails) Read(reader io.Reader) SparkAddressDetails {
+	return SparkAddressDetails{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterBitcoinNetworkINSTANCE.Read(reader),
+		FfiConverterPaymentRequestSourceINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSparkAddressDetails) Lower(value SparkAddressDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[SparkAddressDetails](c, value)
+}
+
+func (c FfiConverterSparkAddressDetails) Write(writer io.Writer, value SparkAddressDetails) {
+	FfiConverterStringINSTANCE.Write(writer, value.Address)
+	FfiConverterStringINSTANCE.Write(writer, value.IdentityPublicKey)
+	FfiConverterBitcoinNetworkINSTANCE.Write(writer, value.Network)
+	FfiConverterPaymentRequestSourceINSTANCE.Write(writer, value.Source)
+}
+
+type FfiDestroyerSparkAddressDetails struct{}
+
+func (_ FfiDestroyerSparkAddressDetails) Destroy(value SparkAddressDetails) {
+	value.Destroy()
+}
+
+type SparkHtlcDetails struct {
+	// The payment hash of the HTLC
+	PaymentHash string
+	// The preimage of the HTLC. Empty until receiver has released it.
+	Preimage *string
+	// The expiry time of the HTLC in seconds since the Unix epoch
+	ExpiryTime uint64
+	// The HTLC status
+	Status SparkHtlcStatus
+}
+
+func (r *SparkHtlcDetails) Destroy() {
+	FfiDestroyerString{}.Destroy(r.PaymentHash)
+	FfiDestroyerOptionalString{}.Destroy(r.Preimage)
+	FfiDestroyerUint64{}.Destroy(r.ExpiryTime)
+	FfiDestroyerSparkHtlcStatus{}.Destroy(r.Status)
+}
+
+type FfiConverterSparkHtlcDetails struct{}
+
+var FfiConverterSparkHtlcDetailsINSTANCE = FfiConverterSparkHtlcDetails{}
+
+func (c FfiConverterSparkHtlcDetails) Lift(rb RustBufferI) SparkHtlcDetails {
+	return LiftFromRustBuffer[SparkHtlcDetails](c, rb)
+}
+
+func (c FfiConverterSparkHtlcDetails) Read(reader io.Reader) SparkHtlcDetails {
+	return SparkHtlcDetails{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterSparkHtlcStatusINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSparkHtlcDetails) Lower(value SparkHtlcDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[SparkHtlcDetails](c, value)
+}
+
+func (c FfiConverterSparkHtlcDetails) Write(writer io.Writer, value SparkHtlcDetails) {
+	FfiConverterStringINSTANCE.Write(writer, value.PaymentHash)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Preimage)
+	FfiConverterUint64INSTANCE.Write(writer, value.ExpiryTime)
+	FfiConverterSparkHtlcStatusINSTANCE.Write(writer, value.Status)
+}
+
+type FfiDestroyerSparkHtlcDetails struct{}
+
+func (_ FfiDestroyerSparkHtlcDetails) Destroy(value SparkHtlcDetails) {
+	value.Destroy()
+}
+
+type SparkHtlcOptions struct {
+	// The payment hash of the HTLC. The receiver will need to provide the associated preimage to claim it.
+	PaymentHash string
+	// The duration of the HTLC in seconds.
+	// After this time, the HTLC will be returned.
+	ExpiryDurationSecs uint64
+}
+
+func (r *SparkHtlcOptions) Destroy() {
+	FfiDestroyerString{}.Destroy(r.PaymentHash)
+	FfiDestroyerUint64{}.Destroy(r.ExpiryDurationSecs)
+}
+
+type FfiConverterSparkHtlcOptions struct{}
+
+var FfiConverterSparkHtlcOptionsINSTANCE = FfiConverterSparkHtlcOptions{}
+
+func (c FfiConverterSparkHtlcOptions) Lift(rb RustBufferI) SparkHtlcOptions {
+	return LiftFromRustBuffer[SparkHtlcOptions](c, rb)
+}
+
+func (c FfiConverterSparkHtlcOptions) Read(reader io.Reader) SparkHtlcOptions {
+	return SparkHtlcOptions{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSparkHtlcOptions) Lower(value SparkHtlcOptions) C.RustBuffer {
+	return LowerIntoRustBuffer[SparkHtlcOptions](c, value)
+}
+
+func (c FfiConverterSparkHtlcOptions) Write(writer io.Writer, value SparkHtlcOptions) {
+	FfiConverterStringINSTANCE.Write(writer, value.PaymentHash)
+	FfiConverterUint64INSTANCE.Write(writer, value.ExpiryDurationSecs)
+}
+
+type FfiDestroyerSparkHtlcOptions struct{}
+
+func (_ FfiDestroyerSparkHtlcOptions) Destroy(value SparkHtlcOptions) {
+	value.Destroy()
+}
+
+type SparkInvoiceDetails struct {
+	// The raw invoice string
+	Invoice string
+	// The identity public key of the invoice issuer
+	IdentityPublicKey string
+	// The network of the invoice
+	Network BitcoinNetwork
+	// The amount denominated in sats or token base units
+	Amount *u128
+	// The token identifier of the token payment. Absence indicates that the payment is a Bitcoin payment.
+	TokenIdentifier *string
+	// Optional expiry time. If not provided, the invoice will never expire.
+	ExpiryTime *uint64
+	// Optional description.
+	Description *string
+	// If set, the invoice may only be fulfilled by a payer with this public key.
+	SenderPublicKey *string
+}
+
+func (r *SparkInvoiceDetails) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Invoice)
+	FfiDestroyerString{}.Destroy(r.IdentityPublicKey)
+	FfiDestroyerBitcoinNetwork{}.Destroy(r.Network)
+	FfiDestroyerOptionalTypeu128{}.Destroy(r.Amount)
+	FfiDestroyerOptionalString{}.Destroy(r.TokenIdentifier)
+	FfiDestroyerOptionalUint64{}.Destroy(r.ExpiryTime)
+	FfiDestroyerOptionalString{}.Destroy(r.Description)
+	FfiDestroyerOptionalString{}.Destroy(r.SenderPublicKey)
+}
+
+type FfiConverterSparkInvoiceDetails struct{}
+
+var FfiConverterSparkInvoiceDetailsINSTANCE = FfiConverterSparkInvoiceDetails{}
+
+func (c FfiConverterSparkInvoiceDetails) Lift(rb RustBufferI) SparkInvoiceDetails {
+	return LiftFromRustBuffer[SparkInvoiceDetails](c, rb)
+}
+
+func (c FfiConverterSparkInvoiceDetails) Read(reader io.Reader) SparkInvoiceDetails {
+	return SparkInvoiceDetails{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterBitcoinNetworkINSTANCE.Read(reader),
+		FfiConverterOptionalTypeu128INSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterOptionalUint64INSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSparkInvoiceDetails) Lower(value SparkInvoiceDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[SparkInvoiceDetails](c, value)
+}
+
+func (c FfiConverterSparkInvoiceDetails) Write(writer io.Writer, value SparkInvoiceDetails) {
+	FfiConverterStringINSTANCE.Write(writer, value.Invoice)
+	FfiConverterStringINSTANCE.Write(writer, value.IdentityPublicKey)
+	FfiConverterBitcoinNetworkINSTANCE.Write(writer, value.Network)
+	FfiConverterOptionalTypeu128INSTANCE.Write(writer, value.Amount)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.TokenIdentifier)
+	FfiConverterOptionalUint64INSTANCE.Write(writer, value.ExpiryTime)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Description)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.SenderPublicKey)
+}
+
+type FfiDestroyerSparkInvoiceDetails struct{}
+
+func (_ FfiDestroyerSparkInvoiceDetails) Destroy(value SparkInvoiceDetails) {
+	value.Destroy()
+}
+
+type SparkInvoicePaymentDetails struct {
+	// Represents the spark invoice description
+	Description *string
+	// The raw spark invoice string
+	Invoice string
+}
+
+func (r *SparkInvoicePaymentDetails) Destroy() {
+	FfiDestroyerOptionalString{}.Destroy(r.Description)
+	FfiDestroyerString{}.Destroy(r.Invoice)
+}
+
+type FfiConverterSparkInvoicePaymentDetails struct{}
+
+var FfiConverterSparkInvoicePaymentDetailsINSTANCE = FfiConverterSparkInvoicePaymentDetails{}
+
+func (c FfiConverterSparkInvoicePaymentDetails) Lift(rb RustBufferI) SparkInvoicePaymentDetails {
+	return LiftFromRustBuffer[SparkInvoicePaymentDetails](c, rb)
+}
+
+func (c FfiConverterSparkInvoicePaymentDetails) Read(reader io.Reader) SparkInvoicePaymentDetails {
+	return SparkInvoicePaymentDetails{
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSparkInvoicePaymentDetails) Lower(value SparkInvoicePaymentDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[SparkInvoicePaymentDetails](c, value)
+}
+
+func (c FfiConverterSparkInvoicePaymentDetails) Write(writer io.Writer, value SparkInvoicePaymentDetails) {
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Description)
+	FfiConverterStringINSTANCE.Write(writer, value.Invoice)
+}
+
+type FfiDestroyerSparkInvoicePaymentDetails struct{}
+
+func (_ FfiDestroyerSparkInvoicePaymentDetails) Destroy(value SparkInvoicePaymentDetails) {
+	value.Destroy()
+}
+
+// Settings for the symbol representation of a currency
+type Symbol struct {
+	Grapheme *string
+	Template *string
+	Rtl      *bool
+	Position *uint32
+}
+
+func (r *Symbol) Destroy() {
+	FfiDestroyerOptionalString{}.Destroy(r.Grapheme)
+	FfiDestroyerOptionalString{}.Destroy(r.Template)
+	FfiDestroyerOptionalBool{}.Destroy(r.Rtl)
+	FfiDestroyerOptionalUint32{}.Destroy(r.Position)
+}
+
+type FfiConverterSymbol struct{}
+
+var FfiConverterSymbolINSTANCE = FfiConverterSymbol{}
+
+func (c FfiConverterSymbol) Lift(rb RustBufferI) Symbol {
+	return LiftFromRustBuffer[Symbol](c, rb)
+}
+
+func (c FfiConverterSymbol) Read(reader io.Reader) Symbol {
+	return Symbol{
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterOptionalStringINSTANCE.Read(reader),
+		FfiConverterOptionalBoolINSTANCE.Read(reader),
+		FfiConverterOptionalUint32INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterSymbol) Lower(value Symbol) C.RustBuffer {
+	return LowerIntoRustBuffer[Symbol](c, value)
+}
+
+func (c FfiConverterSymbol) Write(writer io.Writer, value Symbol) {
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Grapheme)
+	FfiConverterOptionalStringINSTANCE.Write(writer, value.Template)
+	FfiConverterOptionalBoolINSTANCE.Write(writer, value.Rtl)
+	FfiConverterOptionalUint32INSTANCE.Write(writer, value.Position)
+}
+
+type FfiDestroyerSymbol struct{}
+
+func (_ FfiDestroyerSymbol) Destroy(value Symbol) {
+	value.Destroy()
+}
+
+// Request to sync the wallet with the Spark network
+type SyncWalletRequest struct {
+}
+
+func (r *SyncWalletRequest) Destroy() {
+}
+
+type FfiConverterSyncWalletRequest struct{}
+
+var FfiConverterSyncWalletRequestINSTANCE = FfiConverterSyncWalletRequest{}
+
+func (c FfiConverterSyncWalletRequest) Lift(rb RustBufferI) SyncWalletRequest {
+	return LiftFromRustBuffer[SyncWalletRequest](c, rb)
+}
+
+func (c FfiConverterSyncWalletRequest) Read(reader io.Reader) SyncWalletRequest {
+	return SyncWalletRequest{}
+}
+
+func (c FfiConverterSyncWalletRequest) Lower(value SyncWalletRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[SyncWalletRequest](c, value)
+}
+
+func (c FfiConverterSyncWalletRequest) Write(writer io.Writer, value SyncWalletRequest) {
+}
+
+type FfiDestroyerSyncWalletRequest struct{}
+
+func (_ FfiDestroyerSyncWalletRequest) Destroy(value SyncWalletRequest) {
+	value.Destroy()
+}
+
+type SyncWalletResponse struct {
+}
+
+func (r *SyncWalletResponse) Destroy() {
+}
+
+type FfiConverterSyncWalletResponse struct{}
+
+var FfiConverterSyncWalletResponseINSTANCE = FfiConverterSyncWalletResponse{}
+
+func (c FfiConverterSyncWalletResponse) Lift(rb RustBufferI) SyncWalletResponse {
+	return LiftFromRustBuffer[SyncWalletResponse](c, rb)
+}
+
+func (c FfiConverterSyncWalletResponse) Read(reader io.Reader) SyncWalletResponse {
+	return SyncWalletResponse{}
+}
+
+func (c FfiConverterSyncWalletResponse) Lower(value SyncWalletResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[SyncWalletResponse](c, value)
+}
+
+func (c FfiConverterSyncWalletResponse) Write(writer io.Writer, value SyncWalletResponse) {
+}
+
+type FfiDestroyerSyncWalletResponse struct{}
+
+func (_ FfiDestroyerSyncWalletResponse) Destroy(value SyncWalletResponse) {
+	value.Destroy()
+}
+
+type TokenBalance struct {
+	Balance       u128
+	TokenMetadata TokenMetadata
+}
+
+func (r *TokenBalance) Destroy() {
+	FfiDestroyerTypeu128{}.Destroy(r.Balance)
+	FfiDestroyerTokenMetadata{}.Destroy(r.TokenMetadata)
+}
+
+type FfiConverterTokenBalance struct{}
+
+var FfiConverterTokenBalanceINSTANCE = FfiConverterTokenBalance{}
+
+func (c FfiConverterTokenBalance) Lift(rb RustBufferI) TokenBalance {
+	return LiftFromRustBuffer[TokenBalance](c, rb)
+}
+
+func (c FfiConverterTokenBalance) Read(reader io.Reader) TokenBalance {
+	return TokenBalance{
+		FfiConverterTypeu128INSTANCE.Read(reader),
+		FfiConverterTokenMetadataINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterTokenBalance) Lower(value TokenBalance) C.RustBuffer {
+	return LowerIntoRustBuffer[TokenBalance](c, value)
+}
+
+func (c FfiConverterTokenBalance) Write(writer io.Writer, value TokenBalance) {
+	FfiConverterTypeu128INSTANCE.Write(writer, value.Balance)
+	FfiConverterTokenMetadataINSTANCE.Write(writer, value.TokenMetadata)
+}
+
+type FfiDestroyerTokenBalance struct{}
+
+func (_ FfiDestroyerTokenBalance) Destroy(value TokenBalance) {
+	value.Destroy()
+}
+
+type TokenMetadata struct {
+	Identifier string
+	// Hex representation of the issuer public key
+	IssuerPublicKey string
+	Name            string
+	Ticker          string
+	// Number of decimals the token uses
+	Decimals    uint32
+	MaxSupply   u128
+	IsFreezable bool
+}
+
+func (r *TokenMetadata) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Identifier)
+	FfiDestroyerString{}.Destroy(r.IssuerPublicKey)
+	FfiDestroyerString{}.Destroy(r.Name)
+	FfiDestroyerString{}.Destroy(r.Ticker)
+	FfiDestroyerUint32{}.Destroy(r.Decimals)
+	FfiDestroyerTypeu128{}.Destroy(r.MaxSupply)
+	FfiDestroyerBool{}.Destroy(r.IsFreezable)
+}
+
+type FfiConverterTokenMetadata struct{}
+
+var FfiConverterTokenMetadataINSTANCE = FfiConverterTokenMetadata{}
+
+func (c FfiConverterTokenMetadata) Lift(rb RustBufferI) TokenMetadata {
+	return LiftFromRustBuffer[TokenMetadata](c, rb)
+}
+
+func (c FfiConverterTokenMetadata) Read(reader io.Reader) TokenMetadata {
+	return TokenMetadata{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterUint32INSTANCE.Read(reader),
+		FfiConverterTypeu128INSTANCE.Read(reader),
+		FfiConverterBoolINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterTokenMetadata) Lower(value TokenMetadata) C.RustBuffer {
+	return LowerIntoRustBuffer[TokenMetadata](c, value)
+}
+
+func (c FfiConverterTokenMetadata) Write(writer io.Writer, value TokenMetadata) {
+	FfiConverterStringINSTANCE.Write(writer, value.Identifier)
+	FfiConverterStringINSTANCE.Write(writer, value.IssuerPublicKey)
+	FfiConverterStringINSTANCE.Write(writer, value.Name)
+	FfiConverterStringINSTANCE.Write(writer, value.Ticker)
+	FfiConverterUint32INSTANCE.Write(writer, value.Decimals)
+	FfiConverterTypeu128INSTANCE.Write(writer, value.MaxSupply)
+	FfiConverterBoolINSTANCE.Write(writer, value.IsFreezable)
+}
+
+type FfiDestroyerTokenMetadata struct{}
+
+func (_ FfiDestroyerTokenMetadata) Destroy(value TokenMetadata) {
+	value.Destroy()
+}
+
+type TxStatus struct {
+	Confirmed   bool
+	BlockHeight *uint32
+	BlockTime   *uint64
+}
+
+func (r *TxStatus) Destroy() {
+	FfiDestroyerBool{}.Destroy(r.Confirmed)
+	FfiDestroyerOptionalUint32{}.Destroy(r.BlockHeight)
+	FfiDestroyerOptionalUint64{}.Destroy(r.BlockTime)
+}
+
+type FfiConverterTxStatus struct{}
+
+var FfiConverterTxStatusINSTANCE = FfiConverterTxStatus{}
+
+func (c FfiConverterTxStatus) Lift(rb RustBufferI) TxStatus {
+	return LiftFromRustBuffer[TxStatus](c, rb)
+}
+
+func (c FfiConverterTxStatus) Read(reader io.Reader) TxStatus {
+	return TxStatus{
+		FfiConverterBoolINSTANCE.Read(reader),
+		FfiConverterOptionalUint32INSTANCE.Read(reader),
+		FfiConverterOptionalUint64INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterTxStatus) Lower(value TxStatus) C.RustBuffer {
+	return LowerIntoRustBuffer[TxStatus](c, value)
+}
+
+func (c FfiConverterTxStatus) Write(writer io.Writer, value TxStatus) {
+	FfiConverterBoolINSTANCE.Write(writer, value.Confirmed)
+	FfiConverterOptionalUint32INSTANCE.Write(writer, value.BlockHeight)
+	FfiConverterOptionalUint64INSTANCE.Write(writer, value.BlockTime)
+}
+
+type FfiDestroyerTxStatus struct{}
+
+func (_ FfiDestroyerTxStatus) Destroy(value TxStatus) {
+	value.Destroy()
+}
+
+type UnfreezeIssuerTokenRequest struct {
+	Address string
+}
+
+func (r *UnfreezeIssuerTokenRequest) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Address)
+}
+
+type FfiConverterUnfreezeIssuerTokenRequest struct{}
+
+var FfiConverterUnfreezeIssuerTokenRequestINSTANCE = FfiConverterUnfreezeIssuerTokenRequest{}
+
+func (c FfiConverterUnfreezeIssuerTokenRequest) Lift(rb RustBufferI) UnfreezeIssuerTokenRequest {
+	return LiftFromRustBuffer[UnfreezeIssuerTokenRequest](c, rb)
+}
+
+func (c FfiConverterUnfreezeIssuerTokenRequest) Read(reader io.Reader) UnfreezeIssuerTokenRequest {
+	return UnfreezeIssuerTokenRequest{
+		FfiConverterStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterUnfreezeIssuerTokenRequest) Lower(value UnfreezeIssuerTokenRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[UnfreezeIssuerTokenRequest](c, value)
+}
+
+func (c FfiConverterUnfreezeIssuerTokenRequest) Write(writer io.Writer, value UnfreezeIssuerTokenRequest) {
+	FfiConverterStringINSTANCE.Write(writer, value.Address)
+}
+
+type FfiDestroyerUnfreezeIssuerTokenRequest struct{}
+
+func (_ FfiDestroyerUnfreezeIssuerTokenRequest) Destroy(value UnfreezeIssuerTokenRequest) {
+	value.Destroy()
+}
+
+type UnfreezeIssuerTokenResponse struct {
+	ImpactedOutputIds   []string
+	ImpactedTokenAmount u128
+}
+
+func (r *UnfreezeIssuerTokenResponse) Destroy() {
+	FfiDestroyerSequenceString{}.Destroy(r.ImpactedOutputIds)
+	FfiDestroyerTypeu128{}.Destroy(r.ImpactedTokenAmount)
+}
+
+type FfiConverterUnfreezeIssuerTokenResponse struct{}
+
+var FfiConverterUnfreezeIssuerTokenResponseINSTANCE = FfiConverterUnfreezeIssuerTokenResponse{}
+
+func (c FfiConverterUnfreezeIssuerTokenResponse) Lift(rb RustBufferI) UnfreezeIssuerTokenResponse {
+	return LiftFromRustBuffer[UnfreezeIssuerTokenResponse](c, rb)
+}
+
+func (c FfiConverterUnfreezeIssuerTokenResponse) Read(reader io.Reader) UnfreezeIssuerTokenResponse {
+	return UnfreezeIssuerTokenResponse{
+		FfiConverterSequenceStringINSTANCE.Read(reader),
+		FfiConverterTypeu128INSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterUnfreezeIssuerTokenResponse) Lower(value UnfreezeIssuerTokenResponse) C.RustBuffer {
+	return LowerIntoRustBuffer[UnfreezeIssuerTokenResponse](c, value)
+}
+
+func (c FfiConverterUnfreezeIssuerTokenResponse) Write(writer io.Writer, value UnfreezeIssuerTokenResponse) {
+	FfiConverterSequenceStringINSTANCE.Write(writer, value.ImpactedOutputIds)
+	FfiConverterTypeu128INSTANCE.Write(writer, value.ImpactedTokenAmount)
+}
+
+type FfiDestroyerUnfreezeIssuerTokenResponse struct{}
+
+func (_ FfiDestroyerUnfreezeIssuerTokenResponse) Destroy(value UnfreezeIssuerTokenResponse) {
+	value.Destroy()
+}
+
+type UnversionedRecordChange struct {
+	Id            RecordId
+	SchemaVersion string
+	UpdatedFields map[string]string
+}
+
+func (r *UnversionedRecordChange) Destroy() {
+	FfiDestroyerRecordId{}.Destroy(r.Id)
+	FfiDestroyerString{}.Destroy(r.SchemaVersion)
+	FfiDestroyerMapStringString{}.Destroy(r.UpdatedFields)
+}
+
+type FfiConverterUnversionedRecordChange struct{}
+
+var FfiConverterUnversionedRecordChangeINSTANCE = FfiConverterUnversionedRecordChange{}
+
+func (c FfiConverterUnversionedRecordChange) Lift(rb RustBufferI) UnversionedRecordChange {
+	return LiftFromRustBuffer[UnversionedRecordChange](c, rb)
+}
+
+func (c FfiConverterUnversionedRecordChange) Read(reader io.Reader) UnversionedRecordChange {
+	return UnversionedRecordChange{
+		FfiConverterRecordIdINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterMapStringStringINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterUnversionedRecordChange) Lower(value UnversionedRecordChange) C.RustBuffer {
+	return LowerIntoRustBuffer[UnversionedRecordChange](c, value)
+}
+
+func (c FfiConverterUnversionedRecordChange) Write(writer io.Writer, value UnversionedRecordChange) {
+	FfiConverterRecordIdINSTANCE.Write(writer, value.Id)
+	FfiConverterStringINSTANCE.Write(writer, value.SchemaVersion)
+	FfiConverterMapStringStringINSTANCE.Write(writer, value.UpdatedFields)
+}
+
+type FfiDestroyerUnversionedRecordChange struct{}
+
+func (_ FfiDestroyerUnversionedRecordChange) Destroy(value UnversionedRecordChange) {
+	value.Destroy()
+}
+
+type UpdateUserSettingsRequest struct {
+	SparkPrivateModeEnabled *bool
+}
+
+func (r *UpdateUserSettingsRequest) Destroy() {
+	FfiDestroyerOptionalBool{}.Destroy(r.SparkPrivateModeEnabled)
+}
+
+type FfiConverterUpdateUserSettingsRequest struct{}
+
+var FfiConverterUpdateUserSettingsRequestINSTANCE = FfiConverterUpdateUserSettingsRequest{}
+
+func (c FfiConverterUpdateUserSettingsRequest) Lift(rb RustBufferI) UpdateUserSettingsRequest {
+	return LiftFromRustBuffer[UpdateUserSettingsRequest](c, rb)
+}
+
+func (c FfiConverterUpdateUserSettingsRequest) Read(reader io.Reader) UpdateUserSettingsRequest {
+	return UpdateUserSettingsRequest{
+		FfiConverterOptionalBoolINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterUpdateUserSettingsRequest) Lower(value UpdateUserSettingsRequest) C.RustBuffer {
+	return LowerIntoRustBuffer[UpdateUserSettingsRequest](c, value)
+}
+
+func (c FfiConverterUpdateUserSettingsRequest) Write(writer io.Writer, value UpdateUserSettingsRequest) {
+	FfiConverterOptionalBoolINSTANCE.Write(writer, value.SparkPrivateModeEnabled)
+}
+
+type FfiDestroyerUpdateUserSettingsRequest struct{}
+
+func (_ FfiDestroyerUpdateUserSettingsRequest) Destroy(value UpdateUserSettingsRequest) {
+	value.Destroy()
+}
+
+type UrlSuccessActionData struct {
+	// Contents description, up to 144 characters
+	Description string
+	// URL of the success action
+	Url string
+	// Indicates the success URL domain matches the LNURL callback domain.
+	//
+	// See <https://github.com/lnurl/luds/blob/luds/09.md>
+	MatchesCallbackDomain bool
+}
+
+func (r *UrlSuccessActionData) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Description)
+	FfiDestroyerString{}.Destroy(r.Url)
+	FfiDestroyerBool{}.Destroy(r.MatchesCallbackDomain)
+}
+
+type FfiConverterUrlSuccessActionData struct{}
+
+var FfiConverterUrlSuccessActionDataINSTANCE = FfiConverterUrlSuccessActionData{}
+
+func (c FfiConverterUrlSuccessActionData) Lift(rb RustBufferI) UrlSuccessActionData {
+	return LiftFromRustBuffer[UrlSuccessActionData](c, rb)
+}
+
+func (c FfiConverterUrlSuccessActionData) Read(reader io.Reader) UrlSuccessActionData {
+	return UrlSuccessActionData{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterBoolINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterUrlSuccessActionData) Lower(value UrlSuccessActionData) C.RustBuffer {
+	return LowerIntoRustBuffer[UrlSuccessActionData](c, value)
+}
+
+func (c FfiConverterUrlSuccessActionData) Write(writer io.Writer, value UrlSuccessActionData) {
+	FfiConverterStringINSTANCE.Write(writer, value.Description)
+	FfiConverterStringINSTANCE.Write(writer, value.Url)
+	FfiConverterBoolINSTANCE.Write(writer, value.MatchesCallbackDomain)
+}
+
+type FfiDestroyerUrlSuccessActionData struct{}
+
+func (_ FfiDestroyerUrlSuccessActionData) Destroy(value UrlSuccessActionData) {
+	value.Destroy()
+}
+
+type UserSettings struct {
+	SparkPrivateModeEnabled bool
+}
+
+func (r *UserSettings) Destroy() {
+	FfiDestroyerBool{}.Destroy(r.SparkPrivateModeEnabled)
+}
+
+type FfiConverterUserSettings struct{}
+
+var FfiConverterUserSettingsINSTANCE = FfiConverterUserSettings{}
+
+func (c FfiConverterUserSettings) Lift(rb RustBufferI) UserSettings {
+	return LiftFromRustBuffer[UserSettings](c, rb)
+}
+
+func (c FfiConverterUserSettings) Read(reader io.Reader) UserSettings {
+	return UserSettings{
+		FfiConverterBoolINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterUserSettings) Lower(value UserSettings) C.RustBuffer {
+	return LowerIntoRustBuffer[UserSettings](c, value)
+}
+
+func (c FfiConverterUserSettings) Write(writer io.Writer, value UserSettings) {
+	FfiConverterBoolINSTANCE.Write(writer, value.SparkPrivateModeEnabled)
+}
+
+type FfiDestroyerUserSettings struct{}
+
+func (_ FfiDestroyerUserSettings) Destroy(value UserSettings) {
+	value.Destroy()
+}
+
+type Utxo struct {
+	Txid   string
+	Vout   uint32
+	Value  uint64
+	Status TxStatus
+}
+
+func (r *Utxo) Destroy() {
+	FfiDestroyerString{}.Destroy(r.Txid)
+	FfiDestroyerUint32{}.Destroy(r.Vout)
+	FfiDestroyerUint64{}.Destroy(r.Value)
+	FfiDestroyerTxStatus{}.Destroy(r.Status)
+}
+
+type FfiConverterUtxo struct{}
+
+var FfiConverterUtxoINSTANCE = FfiConverterUtxo{}
+
+func (c FfiConverterUtxo) Lift(rb RustBufferI) Utxo {
+	return LiftFromRustBuffer[Utxo](c, rb)
+}
+
+func (c FfiConverterUtxo) Read(reader io.Reader) Utxo {
+	return Utxo{
+		FfiConverterStringINSTANCE.Read(reader),
+		FfiConverterUint32INSTANCE.Read(reader),
+		FfiConverterUint64INSTANCE.Read(reader),
+		FfiConverterTxStatusINSTANCE.Read(reader),
+	}
+}
+
+func (c FfiConverterUtxo) Lower(value Utxo) C.RustBuffer {
+	return LowerIntoRustBuffer[Utxo](c, value)
+}
+
+func (c FfiConverterUtxo) Write(writer io.Writer, value Utxo) {
+	FfiConverterStringINSTANCE.Write(writer, value.Txid)
+	FfiConverterUint32INSTANCE.Write(writer, value.Vout)
+	FfiConverterUint64INSTANCE.Write(writer, value.Value)
+	FfiConverterTxStatusINSTANCE.Write(writer, value.Status)
+}
+
+type FfiDestroyerUtxo struct{}
+
+func (_ FfiDestroyerUtxo) Destroy(value Utxo) {
+	value.Destroy()
+}
+
+// Result of decryption of [`AesSuccessActionData`] payload
+type AesSuccessActionDataResult interface {
+	Destroy()
+}
+type AesSuccessActionDataResultDecrypted struct {
+	Data AesSuccessActionDataDecrypted
+}
+
+func (e AesSuccessActionDataResultDecrypted) Destroy() {
+	FfiDestroyerAesSuccessActionDataDecrypted{}.Destroy(e.Data)
+}
+
+type AesSuccessActionDataResultErrorStatus struct {
+	Reason string
+}
+
+func (e AesSuccessActionDataResultErrorStatus) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Reason)
+}
+
+type FfiConverterAesSuccessActionDataResult struct{}
+
+var FfiConverterAesSuccessActionDataResultINSTANCE = FfiConverterAesSuccessActionDataResult{}
+
+func (c FfiConverterAesSuccessActionDataResult) Lift(rb RustBufferI) AesSuccessActionDataResult {
+	return LiftFromRustBuffer[AesSuccessActionDataResult](c, rb)
+}
+
+func (c FfiConverterAesSuccessActionDataResult) Lower(value AesSuccessActionDataResult) C.RustBuffer {
+	return LowerIntoRustBuffer[AesSuccessActionDataResult](c, value)
+}
+func (FfiConverterAesSuccessActionDataResult) Read(reader io.Reader) AesSuccessActionDataResult {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return AesSuccessActionDataResultDecrypted{
+			FfiConverterAesSuccessActionDataDecryptedINSTANCE.Read(reader),
+		}
+	case 2:
+		return AesSuccessActionDataResultErrorStatus{
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterAesSuccessActionDataResult.Read()", id))
+	}
+}
+
+func (FfiConverterAesSuccessActionDataResult) Write(writer io.Writer, value AesSuccessActionDataResult) {
+	switch variant_value := value.(type) {
+	case AesSuccessActionDataResultDecrypted:
+		writeInt32(writer, 1)
+		FfiConverterAesSuccessActionDataDecryptedINSTANCE.Write(writer, variant_value.Data)
+	case AesSuccessActionDataResultErrorStatus:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Reason)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterAesSuccessActionDataResult.Write", value))
+	}
+}
+
+type FfiDestroyerAesSuccessActionDataResult struct{}
+
+func (_ FfiDestroyerAesSuccessActionDataResult) Destroy(value AesSuccessActionDataResult) {
+	value.Destroy()
+}
+
+type Amount interface {
+	Destroy()
+}
+type AmountBitcoin struct {
+	AmountMsat uint64
+}
+
+func (e AmountBitcoin) Destroy() {
+	FfiDestroyerUint64{}.Destroy(e.AmountMsat)
+}
+
+// An amount of currency specified using ISO 4712.
+type AmountCurrency struct {
+	Iso4217Code      string
+	FractionalAmount uint64
+}
+
+func (e AmountCurrency) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Iso4217Code)
+	FfiDestroyerUint64{}.Destroy(e.FractionalAmount)
+}
+
+type FfiConverterAmount struct{}
+
+var FfiConverterAmountINSTANCE = FfiConverterAmount{}
+
+func (c FfiConverterAmount) Lift(rb RustBufferI) Amount {
+	return LiftFromRustBuffer[Amount](c, rb)
+}
+
+func (c FfiConverterAmount) Lower(value Amount) C.RustBuffer {
+	return LowerIntoRustBuffer[Amount](c, value)
+}
+func (FfiConverterAmount) Read(reader io.Reader) Amount {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return AmountBitcoin{
+			FfiConverterUint64INSTANCE.Read(reader),
+		}
+	case 2:
+		return AmountCurrency{
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterUint64INSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterAmount.Read()", id))
+	}
+}
+
+func (FfiConverterAmount) Write(writer io.Writer, value Amount) {
+	switch variant_value := value.(type) {
+	case AmountBitcoin:
+		writeInt32(writer, 1)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.AmountMsat)
+	case AmountCurrency:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Iso4217Code)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.FractionalAmount)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterAmount.Write", value))
+	}
+}
+
+type FfiDestroyerAmount struct{}
+
+func (_ FfiDestroyerAmount) Destroy(value Amount) {
+	value.Destroy()
+}
+
+// A field of [`ListPaymentsRequest`] when listing payments filtered by asset
+type AssetFilter interface {
+	Destroy()
+}
+type AssetFilterBitcoin struct {
+}
+
+func (e AssetFilterBitcoin) Destroy() {
+}
+
+type AssetFilterToken struct {
+	TokenIdentifier *string
+}
+
+func (e AssetFilterToken) Destroy() {
+	FfiDestroyerOptionalString{}.Destroy(e.TokenIdentifier)
+}
+
+type FfiConverterAssetFilter struct{}
+
+var FfiConverterAssetFilterINSTANCE = FfiConverterAssetFilter{}
+
+func (c FfiConverterAssetFilter) Lift(rb RustBufferI) AssetFilter {
+	return LiftFromRustBuffer[AssetFilter](c, rb)
+}
+
+func (c FfiConverterAssetFilter) Lower(value AssetFilter) C.RustBuffer {
+	return LowerIntoRustBuffer[AssetFilter](c, value)
+}
+func (FfiConverterAssetFilter) Read(reader io.Reader) AssetFilter {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return AssetFilterBitcoin{}
+	case 2:
+		return AssetFilterToken{
+			FfiConverterOptionalStringINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterAssetFilter.Read()", id))
+	}
+}
+
+func (FfiConverterAssetFilter) Write(writer io.Writer, value AssetFilter) {
+	switch variant_value := value.(type) {
+	case AssetFilterBitcoin:
+		writeInt32(writer, 1)
+	case AssetFilterToken:
+		writeInt32(writer, 2)
+		FfiConverterOptionalStringINSTANCE.Write(writer, variant_value.TokenIdentifier)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterAssetFilter.Write", value))
+	}
+}
+
+type FfiDestroyerAssetFilter struct{}
+
+func (_ FfiDestroyerAssetFilter) Destroy(value AssetFilter) {
+	value.Destroy()
+}
+
+type BitcoinNetwork uint
+
+const (
+	// Mainnet
+	BitcoinNetworkBitcoin  BitcoinNetwork = 1
+	BitcoinNetworkTestnet3 BitcoinNetwork = 2
+	BitcoinNetworkTestnet4 BitcoinNetwork = 3
+	BitcoinNetworkSignet   BitcoinNetwork = 4
+	BitcoinNetworkRegtest  BitcoinNetwork = 5
+)
+
+type FfiConverterBitcoinNetwork struct{}
+
+var FfiConverterBitcoinNetworkINSTANCE = FfiConverterBitcoinNetwork{}
+
+func (c FfiConverterBitcoinNetwork) Lift(rb RustBufferI) BitcoinNetwork {
+	return LiftFromRustBuffer[BitcoinNetwork](c, rb)
+}
+
+func (c FfiConverterBitcoinNetwork) Lower(value BitcoinNetwork) C.RustBuffer {
+	return LowerIntoRustBuffer[BitcoinNetwork](c, value)
+}
+func (FfiConverterBitcoinNetwork) Read(reader io.Reader) BitcoinNetwork {
+	id := readInt32(reader)
+	return BitcoinNetwork(id)
+}
+
+func (FfiConverterBitcoinNetwork) Write(writer io.Writer, value BitcoinNetwork) {
+	writeInt32(writer, int32(value))
+}
+
+type FfiDestroyerBitcoinNetwork struct{}
+
+func (_ FfiDestroyerBitcoinNetwork) Destroy(value BitcoinNetwork) {
+}
+
+type ChainApiType uint
+
+const (
+	ChainApiTypeEsplora      ChainApiType = 1
+	ChainApiTypeMempoolSpace ChainApiType = 2
+)
+
+type FfiConverterChainApiType struct{}
+
+var FfiConverterChainApiTypeINSTANCE = FfiConverterChainApiType{}
+
+func (c FfiConverterChainApiType) Lift(rb RustBufferI) ChainApiType {
+	return LiftFromRustBuffer[ChainApiType](c, rb)
+}
+
+func (c FfiConverterChainApiType) Lower(value ChainApiType) C.RustBuffer {
+	return LowerIntoRustBuffer[ChainApiType](c, value)
+}
+func (FfiConverterChainApiType) Read(reader io.Reader) ChainApiType {
+	id := readInt32(reader)
+	return ChainApiType(id)
+}
+
+func (FfiConverterChainApiType) Write(writer io.Writer, value ChainApiType) {
+	writeInt32(writer, int32(value))
+}
+
+type FfiDestroyerChainApiType struct{}
+
+func (_ FfiDestroyerChainApiType) Destroy(value ChainApiType) {
+}
+
+type ChainServiceError struct {
+	err error
+}
+
+// Convience method to turn *ChainServiceError into error
+// Avoiding treating nil pointer as non nil error interface
+func (err *ChainServiceError) AsError() error {
+	if err == nil {
+		return nil
+	} else {
+		return err
+	}
+}
+
+func (err ChainServiceError) Error() string {
+	return fmt.Sprintf("ChainServiceError: %s", err.err.Error())
+}
+
+func (err ChainServiceError) Unwrap() error {
+	return err.err
+}
+
+// Err* are used for checking error type with `errors.Is`
+var ErrChainServiceErrorInvalidAddress = fmt.Errorf("ChainServiceErrorInvalidAddress")
+var ErrChainServiceErrorServiceConnectivity = fmt.Errorf("ChainServiceErrorServiceConnectivity")
+var ErrChainServiceErrorGeneric = fmt.Errorf("ChainServiceErrorGeneric")
+
+// Variant structs
+type ChainServiceErrorInvalidAddress struct {
+	Field0 string
+}
+
+func NewChainServiceErrorInvalidAddress(
+	var0 string,
+) *ChainServiceError {
+	return &ChainServiceError{err: &ChainServiceErrorInvalidAddress{
+		Field0: var0}}
+}
+
+func (e ChainServiceErrorInvalidAddress) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ChainServiceErrorInvalidAddress) Error() string {
+	return fmt.Sprint("InvalidAddress",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ChainServiceErrorInvalidAddress) Is(target error) bool {
+	return target == ErrChainServiceErrorInvalidAddress
+}
+
+type ChainServiceErrorServiceConnectivity struct {
+	Field0 string
+}
+
+func NewChainServiceErrorServiceConnectivity(
+	var0 string,
+) *ChainServiceError {
+	return &ChainServiceError{err: &ChainServiceErrorServiceConnectivity{
+		Field0: var0}}
+}
+
+func (e ChainServiceErrorServiceConnectivity) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ChainServiceErrorServiceConnectivity) Error() string {
+	return fmt.Sprint("ServiceConnectivity",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ChainServiceErrorServiceConnectivity) Is(target error) bool {
+	return target == ErrChainServiceErrorServiceConnectivity
+}
+
+type ChainServiceErrorGeneric struct {
+	Field0 string
+}
+
+func NewChainServiceErrorGeneric(
+	var0 string,
+) *ChainServiceError {
+	return &ChainServiceError{err: &ChainServiceErrorGeneric{
+		Field0: var0}}
+}
+
+func (e ChainServiceErrorGeneric) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ChainServiceErrorGeneric) Error() string {
+	return fmt.Sprint("Generic",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ChainServiceErrorGeneric) Is(target error) bool {
+	return target == ErrChainServiceErrorGeneric
+}
+
+type FfiConverterChainServiceError struct{}
+
+var FfiConverterChainServiceErrorINSTANCE = FfiConverterChainServiceError{}
+
+func (c FfiConverterChainServiceError) Lift(eb RustBufferI) *ChainServiceError {
+	return LiftFromRustBuffer[*ChainServiceError](c, eb)
+}
+
+func (c FfiConverterChainServiceError) Lower(value *ChainServiceError) C.RustBuffer {
+	return LowerIntoRustBuffer[*ChainServiceError](c, value)
+}
+
+func (c FfiConverterChainServiceError) Read(reader io.Reader) *ChainServiceError {
+	errorID := readUint32(reader)
+
+	switch errorID {
+	case 1:
+		return &ChainServiceError{&ChainServiceErrorInvalidAddress{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 2:
+		return &ChainServiceError{&ChainServiceErrorServiceConnectivity{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 3:
+		return &ChainServiceError{&ChainServiceErrorGeneric{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	default:
+		panic(fmt.Sprintf("Unknown error code %d in FfiConverterChainServiceError.Read()", errorID))
+	}
+}
+
+func (c FfiConverterChainServiceError) Write(writer io.Writer, value *ChainServiceError) {
+	switch variantValue := value.err.(type) {
+	case *ChainServiceErrorInvalidAddress:
+		writeInt32(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ChainServiceErrorServiceConnectivity:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ChainServiceErrorGeneric:
+		writeInt32(writer, 3)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiConverterChainServiceError.Write", value))
+	}
+}
+
+type FfiDestroyerChainServiceError struct{}
+
+func (_ FfiDestroyerChainServiceError) Destroy(value *ChainServiceError) {
+	switch variantValue := value.err.(type) {
+	case *ChainServiceErrorInvalidAddress:
+		variantValue.destroy()
+	case *ChainServiceErrorServiceConnectivity:
+		variantValue.destroy()
+	case *ChainServiceErrorGeneric:
+		variantValue.destroy()
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiDestroyerChainServiceError.Destroy", value))
+	}
+}
+
+type DepositClaimError interface {
+	Destroy()
+}
+type DepositClaimErrorMaxDepositClaimFeeExceeded struct {
+	Tx                         string
+	Vout                       uint32
+	MaxFee                     *Fee
+	RequiredFeeSats            uint64
+	RequiredFeeRateSatPerVbyte uint64
+}
+
+func (e DepositClaimErrorMaxDepositClaimFeeExceeded) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Tx)
+	FfiDestroyerUint32{}.Destroy(e.Vout)
+	FfiDestroyerOptionalFee{}.Destroy(e.MaxFee)
+	FfiDestroyerUint64{}.Destroy(e.RequiredFeeSats)
+	FfiDestroyerUint64{}.Destroy(e.RequiredFeeRateSatPerVbyte)
+}
+
+type DepositClaimErrorMissingUtxo struct {
+	Tx   string
+	Vout uint32
+}
+
+func (e DepositClaimErrorMissingUtxo) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Tx)
+	FfiDestroyerUint32{}.Destroy(e.Vout)
+}
+
+type DepositClaimErrorGeneric struct {
+	Message string
+}
+
+func (e DepositClaimErrorGeneric) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Message)
+}
+
+type FfiConverterDepositClaimError struct{}
+
+var FfiConverterDepositClaimErrorINSTANCE = FfiConverterDepositClaimError{}
+
+func (c FfiConverterDepositClaimError) Lift(rb RustBufferI) DepositClaimError {
+	return LiftFromRustBuffer[DepositClaimError](c, rb)
+}
+
+func (c FfiConverterDepositClaimError) Lower(value DepositClaimError) C.RustBuffer {
+	return LowerIntoRustBuffer[DepositClaimError](c, value)
+}
+func (FfiConverterDepositClaimError) Read(reader io.Reader) DepositClaimError {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return DepositClaimErrorMaxDepositClaimFeeExceeded{
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterUint32INSTANCE.Read(reader),
+			FfiConverterOptionalFeeINSTANCE.Read(reader),
+			FfiConverterUint64INSTANCE.Read(reader),
+			FfiConverterUint64INSTANCE.Read(reader),
+		}
+	case 2:
+		return DepositClaimErrorMissingUtxo{
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterUint32INSTANCE.Read(reader),
+		}
+	case 3:
+		return DepositClaimErrorGeneric{
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterDepositClaimError.Read()", id))
+	}
+}
+
+func (FfiConverterDepositClaimError) Write(writer io.Writer, value DepositClaimError) {
+	switch variant_value := value.(type) {
+	case DepositClaimErrorMaxDepositClaimFeeExceeded:
+		writeInt32(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Tx)
+		FfiConverterUint32INSTANCE.Write(writer, variant_value.Vout)
+		FfiConverterOptionalFeeINSTANCE.Write(writer, variant_value.MaxFee)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.RequiredFeeSats)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.RequiredFeeRateSatPerVbyte)
+	case DepositClaimErrorMissingUtxo:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Tx)
+		FfiConverterUint32INSTANCE.Write(writer, variant_value.Vout)
+	case DepositClaimErrorGeneric:
+		writeInt32(writer, 3)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Message)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterDepositClaimError.Write", value))
+	}
+}
+
+type FfiDestroyerDepositClaimError struct{}
+
+func (_ FfiDestroyerDepositClaimError) Destroy(value DepositClaimError) {
+	value.Destroy()
+}
+
+type Fee interface {
+	Destroy()
+}
+type FeeFixed struct {
+	Amount uint64
+}
+
+func (e FeeFixed) Destroy() {
+	FfiDestroyerUint64{}.Destroy(e.Amount)
+}
+
+type FeeRate struct {
+	SatPerVbyte uint64
+}
+
+func (e FeeRate) Destroy() {
+	FfiDestroyerUint64{}.Destroy(e.SatPerVbyte)
+}
+
+type FfiConverterFee struct{}
+
+var FfiConverterFeeINSTANCE = FfiConverterFee{}
+
+func (c FfiConverterFee) Lift(rb RustBufferI) Fee {
+	return LiftFromRustBuffer[Fee](c, rb)
+}
+
+func (c FfiConverterFee) Lower(value Fee) C.RustBuffer {
+	return LowerIntoRustBuffer[Fee](c, value)
+}
+func (FfiConverterFee) Read(reader io.Reader) Fee {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return FeeFixed{
+			FfiConverterUint64INSTANCE.Read(reader),
+		}
+	case 2:
+		return FeeRate{
+			FfiConverterUint64INSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterFee.Read()", id))
+	}
+}
+
+func (FfiConverterFee) Write(writer io.Writer, value Fee) {
+	switch variant_value := value.(type) {
+	case FeeFixed:
+		writeInt32(writer, 1)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.Amount)
+	case FeeRate:
+		writeInt32(writer, 2)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.SatPerVbyte)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterFee.Write", value))
+	}
+}
+
+type FfiDestroyerFee struct{}
+
+func (_ FfiDestroyerFee) Destroy(value Fee) {
+	value.Destroy()
+}
+
+type InputType interface {
+	Destroy()
+}
+type InputTypeBitcoinAddress struct {
+	Field0 BitcoinAddressDetails
+}
+
+func (e InputTypeBitcoinAddress) Destroy() {
+	FfiDestroyerBitcoinAddressDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeBolt11Invoice struct {
+	Field0 Bolt11InvoiceDetails
+}
+
+func (e InputTypeBolt11Invoice) Destroy() {
+	FfiDestroyerBolt11InvoiceDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeBolt12Invoice struct {
+	Field0 Bolt12InvoiceDetails
+}
+
+func (e InputTypeBolt12Invoice) Destroy() {
+	FfiDestroyerBolt12InvoiceDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeBolt12Offer struct {
+	Field0 Bolt12OfferDetails
+}
+
+func (e InputTypeBolt12Offer) Destroy() {
+	FfiDestroyerBolt12OfferDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeLightningAddress struct {
+	Field0 LightningAddressDetails
+}
+
+func (e InputTypeLightningAddress) Destroy() {
+	FfiDestroyerLightningAddressDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeLnurlPay struct {
+	Field0 LnurlPayRequestDetails
+}
+
+func (e InputTypeLnurlPay) Destroy() {
+	FfiDestroyerLnurlPayRequestDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeSilentPaymentAddress struct {
+	Field0 SilentPaymentAddressDetails
+}
+
+func (e InputTypeSilentPaymentAddress) Destroy() {
+	FfiDestroyerSilentPaymentAddressDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeLnurlAuth struct {
+	Field0 LnurlAuthRequestDetails
+}
+
+func (e InputTypeLnurlAuth) Destroy() {
+	FfiDestroyerLnurlAuthRequestDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeUrl struct {
+	Field0 string
+}
+
+func (e InputTypeUrl) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+type InputTypeBip21 struct {
+	Field0 Bip21Details
+}
+
+func (e InputTypeBip21) Destroy() {
+	FfiDestroyerBip21Details{}.Destroy(e.Field0)
+}
+
+type InputTypeBolt12InvoiceRequest struct {
+	Field0 Bolt12InvoiceRequestDetails
+}
+
+func (e InputTypeBolt12InvoiceRequest) Destroy() {
+	FfiDestroyerBolt12InvoiceRequestDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeLnurlWithdraw struct {
+	Field0 LnurlWithdrawRequestDetails
+}
+
+func (e InputTypeLnurlWithdraw) Destroy() {
+	FfiDestroyerLnurlWithdrawRequestDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeSparkAddress struct {
+	Field0 SparkAddressDetails
+}
+
+func (e InputTypeSparkAddress) Destroy() {
+	FfiDestroyerSparkAddressDetails{}.Destroy(e.Field0)
+}
+
+type InputTypeSparkInvoice struct {
+	Field0 SparkInvoiceDetails
+}
+
+func (e InputTypeSparkInvoice) Destroy() {
+	FfiDestroyerSparkInvoiceDetails{}.Destroy(e.Field0)
+}
+
+type FfiConverterInputType struct{}
+
+var FfiConverterInputTypeINSTANCE = FfiConverterInputType{}
+
+func (c FfiConverterInputType) Lift(rb RustBufferI) InputType {
+	return LiftFromRustBuffer[InputType](c, rb)
+}
+
+func (c FfiConverterInputType) Lower(value InputType) C.RustBuffer {
+	return LowerIntoRustBuffer[InputType](c, value)
+}
+func (FfiConverterInputType) Read(reader io.Reader) InputType {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return InputTypeBitcoinAddress{
+			FfiConverterBitcoinAddressDetailsINSTANCE.Read(reader),
+		}
+	case 2:
+		return InputTypeBolt11Invoice{
+			FfiConverterBolt11InvoiceDetailsINSTANCE.Read(reader),
+		}
+	case 3:
+		return InputTypeBolt12Invoice{
+			FfiConverterBolt12InvoiceDetailsINSTANCE.Read(reader),
+		}
+	case 4:
+		return InputTypeBolt12Offer{
+			FfiConverterBolt12OfferDetailsINSTANCE.Read(reader),
+		}
+	case 5:
+		return InputTypeLightningAddress{
+			FfiConverterLightningAddressDetailsINSTANCE.Read(reader),
+		}
+	case 6:
+		return InputTypeLnurlPay{
+			FfiConverterLnurlPayRequestDetailsINSTANCE.Read(reader),
+		}
+	case 7:
+		return InputTypeSilentPaymentAddress{
+			FfiConverterSilentPaymentAddressDetailsINSTANCE.Read(reader),
+		}
+	case 8:
+		return InputTypeLnurlAuth{
+			FfiConverterLnurlAuthRequestDetailsINSTANCE.Read(reader),
+		}
+	case 9:
+		return InputTypeUrl{
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	case 10:
+		return InputTypeBip21{
+			FfiConverterBip21DetailsINSTANCE.Read(reader),
+		}
+	case 11:
+		return InputTypeBolt12InvoiceRequest{
+			FfiConverterBolt12InvoiceRequestDetailsINSTANCE.Read(reader),
+		}
+	case 12:
+		return InputTypeLnurlWithdraw{
+			FfiConverterLnurlWithdrawRequestDetailsINSTANCE.Read(reader),
+		}
+	case 13:
+		return InputTypeSparkAddress{
+			FfiConverterSparkAddressDetailsINSTANCE.Read(reader),
+		}
+	case 14:
+		return InputTypeSparkInvoice{
+			FfiConverterSparkInvoiceDetailsINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterInputType.Read()", id))
+	}
+}
+
+func (FfiConverterInputType) Write(writer io.Writer, value InputType) {
+	switch variant_value := value.(type) {
+	case InputTypeBitcoinAddress:
+		writeInt32(writer, 1)
+		FfiConverterBitcoinAddressDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeBolt11Invoice:
+		writeInt32(writer, 2)
+		FfiConverterBolt11InvoiceDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeBolt12Invoice:
+		writeInt32(writer, 3)
+		FfiConverterBolt12InvoiceDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeBolt12Offer:
+		writeInt32(writer, 4)
+		FfiConverterBolt12OfferDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeLightningAddress:
+		writeInt32(writer, 5)
+		FfiConverterLightningAddressDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeLnurlPay:
+		writeInt32(writer, 6)
+		FfiConverterLnurlPayRequestDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeSilentPaymentAddress:
+		writeInt32(writer, 7)
+		FfiConverterSilentPaymentAddressDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeLnurlAuth:
+		writeInt32(writer, 8)
+		FfiConverterLnurlAuthRequestDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeUrl:
+		writeInt32(writer, 9)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeBip21:
+		writeInt32(writer, 10)
+		FfiConverterBip21DetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeBolt12InvoiceRequest:
+		writeInt32(writer, 11)
+		FfiConverterBolt12InvoiceRequestDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeLnurlWithdraw:
+		writeInt32(writer, 12)
+		FfiConverterLnurlWithdrawRequestDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeSparkAddress:
+		writeInt32(writer, 13)
+		FfiConverterSparkAddressDetailsINSTANCE.Write(writer, variant_value.Field0)
+	case InputTypeSparkInvoice:
+		writeInt32(writer, 14)
+		FfiConverterSparkInvoiceDetailsINSTANCE.Write(writer, variant_value.Field0)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterInputType.Write", value))
+	}
+}
+
+type FfiDestroyerInputType struct{}
+
+func (_ FfiDestroyerInputType) Destroy(value InputType) {
+	value.Destroy()
+}
+
+type KeySetType uint
+
+const (
+	KeySetTypeDefault       KeySetType = 1
+	KeySetTypeTaproot       KeySetType = 2
+	KeySetTypeNativeSegwit  KeySetType = 3
+	KeySetTypeWrappedSegwit KeySetType = 4
+	KeySetTypeLegacy        KeySetType = 5
+)
+
+type FfiConverterKeySetType struct{}
+
+var FfiConverterKeySetTypeINSTANCE = FfiConverterKeySetType{}
+
+func (c FfiConverterKeySetType) Lift(rb RustBufferI) KeySetType {
+	return LiftFromRustBuffer[KeySetType](c, rb)
+}
+
+func (c FfiConverterKeySetType) Lower(value KeySetType) C.RustBuffer {
+	return LowerIntoRustBuffer[KeySetType](c, value)
+}
+func (FfiConverterKeySetType) Read(reader io.Reader) KeySetType {
+	id := readInt32(reader)
+	return KeySetType(id)
+}
+
+func (FfiConverterKeySetType) Write(writer io.Writer, value KeySetType) {
+	writeInt32(writer, int32(value))
+}
+
+type FfiDestroyerKeySetType struct{}
+
+func (_ FfiDestroyerKeySetType) Destroy(value KeySetType) {
+}
+
+type MaxFee interface {
+	Destroy()
+}
+type MaxFeeFixed struct {
+	Amount uint64
+}
+
+func (e MaxFeeFixed) Destroy() {
+	FfiDestroyerUint64{}.Destroy(e.Amount)
+}
+
+type MaxFeeRate struct {
+	SatPerVbyte uint64
+}
+
+func (e MaxFeeRate) Destroy() {
+	FfiDestroyerUint64{}.Destroy(e.SatPerVbyte)
+}
+
+type MaxFeeNetworkRecommended struct {
+	LeewaySatPerVbyte uint64
+}
+
+func (e MaxFeeNetworkRecommended) Destroy() {
+	FfiDestroyerUint64{}.Destroy(e.LeewaySatPerVbyte)
+}
+
+type FfiConverterMaxFee struct{}
+
+var FfiConverterMaxFeeINSTANCE = FfiConverterMaxFee{}
+
+func (c FfiConverterMaxFee) Lift(rb RustBufferI) MaxFee {
+	return LiftFromRustBuffer[MaxFee](c, rb)
+}
+
+func (c FfiConverterMaxFee) Lower(value MaxFee) C.RustBuffer {
+	return LowerIntoRustBuffer[MaxFee](c, value)
+}
+func (FfiConverterMaxFee) Read(reader io.Reader) MaxFee {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return MaxFeeFixed{
+			FfiConverterUint64INSTANCE.Read(reader),
+		}
+	case 2:
+		return MaxFeeRate{
+			FfiConverterUint64INSTANCE.Read(reader),
+		}
+	case 3:
+		return MaxFeeNetworkRecommended{
+			FfiConverterUint64INSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterMaxFee.Read()", id))
+	}
+}
+
+func (FfiConverterMaxFee) Write(writer io.Writer, value MaxFee) {
+	switch variant_value := value.(type) {
+	case MaxFeeFixed:
+		writeInt32(writer, 1)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.Amount)
+	case MaxFeeRate:
+		writeInt32(writer, 2)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.SatPerVbyte)
+	case MaxFeeNetworkRecommended:
+		writeInt32(writer, 3)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.LeewaySatPerVbyte)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterMaxFee.Write", value))
+	}
+}
+
+type FfiDestroyerMaxFee struct{}
+
+func (_ FfiDestroyerMaxFee) Destroy(value MaxFee) {
+	value.Destroy()
+}
+
+type Network uint
+
+const (
+	NetworkMainnet Network = 1
+	NetworkRegtest Network = 2
+)
+
+type FfiConverterNetwork struct{}
+
+var FfiConverterNetworkINSTANCE = FfiConverterNetwork{}
+
+func (c FfiConverterNetwork) Lift(rb RustBufferI) Network {
+	return LiftFromRustBuffer[Network](c, rb)
+}
+
+func (c FfiConverterNetwork) Lower(value Network) C.RustBuffer {
+	return LowerIntoRustBuffer[Network](c, value)
+}
+func (FfiConverterNetwork) Read(reader io.Reader) Network {
+	id := readInt32(reader)
+	return Network(id)
+}
+
+func (FfiConverterNetwork) Write(writer io.Writer, value Network) {
+	writeInt32(writer, int32(value))
+}
+
+type FfiDestroyerNetwork struct{}
+
+func (_ FfiDestroyerNetwork) Destroy(value Network) {
+}
+
+type OnchainConfirmationSpeed uint
+
+const (
+	OnchainConfirmationSpeedFast   OnchainConfirmationSpeed = 1
+	OnchainConfirmationSpeedMedium OnchainConfirmationSpeed = 2
+	OnchainConfirmationSpeedSlow   OnchainConfirmationSpeed = 3
+)
+
+type FfiConverterOnchainConfirmationSpeed struct{}
+
+var FfiConverterOnchainConfirmationSpeedINSTANCE = FfiConverterOnchainConfirmationSpeed{}
+
+func (c FfiConverterOnchainConfirmationSpeed) Lift(rb RustBufferI) OnchainConfirmationSpeed {
+	return LiftFromRustBuffer[OnchainConfirmationSpeed](c, rb)
+}
+
+func (c FfiConverterOnchainConfirmationSpeed) Lower(value OnchainConfirmationSpeed) C.RustBuffer {
+	return LowerIntoRustBuffer[OnchainConfirmationSpeed](c, value)
+}
+func (FfiConverterOnchainConfirmationSpeed) Read(reader io.Reader) OnchainConfirmationSpeed {
+	id := readInt32(reader)
+	return OnchainConfirmationSpeed(id)
+}
+
+func (FfiConverterOnchainConfirmationSpeed) Write(writer io.Writer, value OnchainConfirmationSpeed) {
+	writeInt32(writer, int32(value))
+}
+
+type FfiDestroyerOnchainConfirmationSpeed struct{}
+
+func (_ FfiDestroyerOnchainConfirmationSpeed) Destroy(value OnchainConfirmationSpeed) {
+}
+
+type PaymentDetails interface {
+	Destroy()
+}
+type PaymentDetailsSpark struct {
+	InvoiceDetails *SparkInvoicePaymentDetails
+	HtlcDetails    *SparkHtlcDetails
+}
+
+func (e PaymentDetailsSpark) Destroy() {
+	FfiDestroyerOptionalSparkInvoicePaymentDetails{}.Destroy(e.InvoiceDetails)
+	FfiDestroyerOptionalSparkHtlcDetails{}.Destroy(e.HtlcDetails)
+}
+
+type PaymentDetailsToken struct {
+	Metadata       TokenMetadata
+	TxHash         string
+	InvoiceDetails *SparkInvoicePaymentDetails
+}
+
+func (e PaymentDetailsToken) Destroy() {
+	FfiDestroyerTokenMetadata{}.Destroy(e.Metadata)
+	FfiDestroyerString{}.Destroy(e.TxHash)
+	FfiDestroyerOptionalSparkInvoicePaymentDetails{}.Destroy(e.InvoiceDetails)
+}
+
+type PaymentDetailsLightning struct {
+	Description          *string
+	Preimage             *string
+	Invoice              string
+	PaymentHash          string
+	DestinationPubkey    string
+	LnurlPayInfo         *LnurlPayInfo
+	LnurlWithdrawInfo    *LnurlWithdrawInfo
+	LnurlReceiveMetadata *LnurlReceiveMetadata
+}
+
+func (e PaymentDetailsLightning) Destroy() {
+	FfiDestroyerOptionalString{}.Destroy(e.Description)
+	FfiDestroyerOptionalString{}.Destroy(e.Preimage)
+	FfiDestroyerString{}.Destroy(e.Invoice)
+	FfiDestroyerString{}.Destroy(e.PaymentHash)
+	FfiDestroyerString{}.Destroy(e.DestinationPubkey)
+	FfiDestroyerOptionalLnurlPayInfo{}.Destroy(e.LnurlPayInfo)
+	FfiDestroyerOptionalLnurlWithdrawInfo{}.Destroy(e.LnurlWithdrawInfo)
+	FfiDestroyerOptionalLnurlReceiveMetadata{}.Destroy(e.LnurlReceiveMetadata)
+}
+
+type PaymentDetailsWithdraw struct {
+	TxId string
+}
+
+func (e PaymentDetailsWithdraw) Destroy() {
+	FfiDestroyerString{}.Destroy(e.TxId)
+}
+
+type PaymentDetailsDeposit struct {
+	TxId string
+}
+
+func (e PaymentDetailsDeposit) Destroy() {
+	FfiDestroyerString{}.Destroy(e.TxId)
+}
+
+type FfiConverterPaymentDetails struct{}
+
+var FfiConverterPaymentDetailsINSTANCE = FfiConverterPaymentDetails{}
+
+func (c FfiConverterPaymentDetails) Lift(rb RustBufferI) PaymentDetails {
+	return LiftFromRustBuffer[PaymentDetails](c, rb)
+}
+
+func (c FfiConverterPaymentDetails) Lower(value PaymentDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[PaymentDetails](c, value)
+}
+func (FfiConverterPaymentDetails) Read(reader io.Reader) PaymentDetails {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return PaymentDetailsSpark{
+			FfiConverterOptionalSparkInvoicePaymentDetailsINSTANCE.Read(reader),
+			FfiConverterOptionalSparkHtlcDetailsINSTANCE.Read(reader),
+		}
+	case 2:
+		return PaymentDetailsToken{
+			FfiConverterTokenMetadataINSTANCE.Read(reader),
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterOptionalSparkInvoicePaymentDetailsINSTANCE.Read(reader),
+		}
+	case 3:
+		return PaymentDetailsLightning{
+			FfiConverterOptionalStringINSTANCE.Read(reader),
+			FfiConverterOptionalStringINSTANCE.Read(reader),
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterOptionalLnurlPayInfoINSTANCE.Read(reader),
+			FfiConverterOptionalLnurlWithdrawInfoINSTANCE.Read(reader),
+			FfiConverterOptionalLnurlReceiveMetadataINSTANCE.Read(reader),
+		}
+	case 4:
+		return PaymentDetailsWithdraw{
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	case 5:
+		return PaymentDetailsDeposit{
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterPaymentDetails.Read()", id))
+	}
+}
+
+func (FfiConverterPaymentDetails) Write(writer io.Writer, value PaymentDetails) {
+	switch variant_value := value.(type) {
+	case PaymentDetailsSpark:
+		writeInt32(writer, 1)
+		FfiConverterOptionalSparkInvoicePaymentDetailsINSTANCE.Write(writer, variant_value.InvoiceDetails)
+		FfiConverterOptionalSparkHtlcDetailsINSTANCE.Write(writer, variant_value.HtlcDetails)
+	case PaymentDetailsToken:
+		writeInt32(writer, 2)
+		FfiConverterTokenMetadataINSTANCE.Write(writer, variant_value.Metadata)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.TxHash)
+		FfiConverterOptionalSparkInvoicePaymentDetailsINSTANCE.Write(writer, variant_value.InvoiceDetails)
+	case PaymentDetailsLightning:
+		writeInt32(writer, 3)
+		FfiConverterOptionalStringINSTANCE.Write(writer, variant_value.Description)
+		FfiConverterOptionalStringINSTANCE.Write(writer, variant_value.Preimage)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Invoice)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.PaymentHash)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.DestinationPubkey)
+		FfiConverterOptionalLnurlPayInfoINSTANCE.Write(writer, variant_value.LnurlPayInfo)
+		FfiConverterOptionalLnurlWithdrawInfoINSTANCE.Write(writer, variant_value.LnurlWithdrawInfo)
+		FfiConverterOptionalLnurlReceiveMetadataINSTANCE.Write(writer, variant_value.LnurlReceiveMetadata)
+	case PaymentDetailsWithdraw:
+		writeInt32(writer, 4)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.TxId)
+	case PaymentDetailsDeposit:
+		writeInt32(writer, 5)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.TxId)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterPaymentDetails.Write", value))
+	}
+}
+
+type FfiDestroyerPaymentDetails struct{}
+
+func (_ FfiDestroyerPaymentDetails) Destroy(value PaymentDetails) {
+	value.Destroy()
+}
+
+type PaymentMethod uint
+
+const (
+	PaymentMethodLightning PaymentMethod = 1
+	PaymentMethodSpark     PaymentMethod = 2
+	PaymentMethodToken     PaymentMethod = 3
+	PaymentMethodDeposit   PaymentMethod = 4
+	PaymentMethodWithdraw  PaymentMethod = 5
+	PaymentMethodUnknown   PaymentMethod = 6
+)
+
+type FfiConverterPaymentMethod struct{}
+
+var FfiConverterPaymentMethodINSTANCE = FfiConverterPaymentMethod{}
+
+func (c FfiConverterPaymentMethod) Lift(rb RustBufferI) PaymentMethod {
+	return LiftFromRustBuffer[PaymentMethod](c, rb)
+}
+
+func (c FfiConverterPaymentMethod) Lower(value PaymentMethod) C.RustBuffer {
+	return LowerIntoRustBuffer[PaymentMethod](c, value)
+}
+func (FfiConverterPaymentMethod) Read(reader io.Reader) PaymentMethod {
+	id := readInt32(reader)
+	return PaymentMethod(id)
+}
+
+func (FfiConverterPaymentMethod) Write(writer io.Writer, value PaymentMethod) {
+	writeInt32(writer, int32(value))
+}
+
+type FfiDestroyerPaymentMethod struct{}
+
+func (_ FfiDestroyerPaymentMethod) Destroy(value PaymentMethod) {
+}
+
+type PaymentObserverError struct {
+	err error
+}
+
+// Convience method to turn *PaymentObserverError into error
+// Avoiding treating nil pointer as non nil error interface
+func (err *PaymentObserverError) AsError() error {
+	if err == nil {
+		return nil
+	} else {
+		return err
+	}
+}
+
+func (err PaymentObserverError) Error() string {
+	return fmt.Sprintf("PaymentObserverError: %s", err.err.Error())
+}
+
+func (err PaymentObserverError) Unwrap() error {
+	return err.err
+}
+
+// Err* are used for checking error type with `errors.Is`
+var ErrPaymentObserverErrorServiceConnectivity = fmt.Errorf("PaymentObserverErrorServiceConnectivity")
+var ErrPaymentObserverErrorGeneric = fmt.Errorf("PaymentObserverErrorGeneric")
+
+// Variant structs
+type PaymentObserverErrorServiceConnectivity struct {
+	Field0 string
+}
+
+func NewPaymentObserverErrorServiceConnectivity(
+	var0 string,
+) *PaymentObserverError {
+	return &PaymentObserverError{err: &PaymentObserverErrorServiceConnectivity{
+		Field0: var0}}
+}
+
+func (e PaymentObserverErrorServiceConnectivity) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err PaymentObserverErrorServiceConnectivity) Error() string {
+	return fmt.Sprint("ServiceConnectivity",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self PaymentObserverErrorServiceConnectivity) Is(target error) bool {
+	return target == ErrPaymentObserverErrorServiceConnectivity
+}
+
+type PaymentObserverErrorGeneric struct {
+	Field0 string
+}
+
+func NewPaymentObserverErrorGeneric(
+	var0 string,
+) *PaymentObserverError {
+	return &PaymentObserverError{err: &PaymentObserverErrorGeneric{
+		Field0: var0}}
+}
+
+func (e PaymentObserverErrorGeneric) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err PaymentObserverErrorGeneric) Error() string {
+	return fmt.Sprint("Generic",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self PaymentObserverErrorGeneric) Is(target error) bool {
+	return target == ErrPaymentObserverErrorGeneric
+}
+
+type FfiConverterPaymentObserverError struct{}
+
+var FfiConverterPaymentObserverErrorINSTANCE = FfiConverterPaymentObserverError{}
+
+func (c FfiConverterPaymentObserverError) Lift(eb RustBufferI) *PaymentObserverError {
+	return LiftFromRustBuffer[*PaymentObserverError](c, eb)
+}
+
+func (c FfiConverterPaymentObserverError) Lower(value *PaymentObserverError) C.RustBuffer {
+	return LowerIntoRustBuffer[*PaymentObserverError](c, value)
+}
+
+func (c FfiConverterPaymentObserverError) Read(reader io.Reader) *PaymentObserverError {
+	errorID := readUint32(reader)
+
+	switch errorID {
+	case 1:
+		return &PaymentObserverError{&PaymentObserverErrorServiceConnectivity{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 2:
+		return &PaymentObserverError{&PaymentObserverErrorGeneric{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	default:
+		panic(fmt.Sprintf("Unknown error code %d in FfiConverterPaymentObserverError.Read()", errorID))
+	}
+}
+
+func (c FfiConverterPaymentObserverError) Write(writer io.Writer, value *PaymentObserverError) {
+	switch variantValue := value.err.(type) {
+	case *PaymentObserverErrorServiceConnectivity:
+		writeInt32(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *PaymentObserverErrorGeneric:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiConverterPaymentObserverError.Write", value))
+	}
+}
+
+type FfiDestroyerPaymentObserverError struct{}
+
+func (_ FfiDestroyerPaymentObserverError) Destroy(value *PaymentObserverError) {
+	switch variantValue := value.err.(type) {
+	case *PaymentObserverErrorServiceConnectivity:
+		variantValue.destroy()
+	case *PaymentObserverErrorGeneric:
+		variantValue.destroy()
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiDestroyerPaymentObserverError.Destroy", value))
+	}
+}
+
+// The status of a payment
+type PaymentStatus uint
+
+const (
+	// Payment is completed successfully
+	PaymentStatusCompleted PaymentStatus = 1
+	// Payment is in progress
+	PaymentStatusPending PaymentStatus = 2
+	// Payment has failed
+	PaymentStatusFailed PaymentStatus = 3
+)
+
+type FfiConverterPaymentStatus struct{}
+
+var FfiConverterPaymentStatusINSTANCE = FfiConverterPaymentStatus{}
+
+func (c FfiConverterPaymentStatus) Lift(rb RustBufferI) PaymentStatus {
+	return LiftFromRustBuffer[PaymentStatus](c, rb)
+}
+
+func (c FfiConverterPaymentStatus) Lower(value PaymentStatus) C.RustBuffer {
+	return LowerIntoRustBuffer[PaymentStatus](c, value)
+}
+func (FfiConverterPaymentStatus) Read(reader io.Reader) PaymentStatus {
+	id := readInt32(reader)
+	return PaymentStatus(id)
+}
+
+func (FfiConverterPaymentStatus) Write(writer io.Writer, value PaymentStatus) {
+	writeInt32(writer, int32(value))
+}
+
+type FfiDestroyerPaymentStatus struct{}
+
+func (_ FfiDestroyerPaymentStatus) Destroy(value PaymentStatus) {
+}
+
+// The type of payment
+type PaymentType uint
+
+const (
+	// Payment sent from this wallet
+	PaymentTypeSend PaymentType = 1
+	// Payment received to this wallet
+	PaymentTypeReceive PaymentType = 2
+)
+
+type FfiConverterPaymentType struct{}
+
+var FfiConverterPaymentTypeINSTANCE = FfiConverterPaymentType{}
+
+func (c FfiConverterPaymentType) Lift(rb RustBufferI) PaymentType {
+	return LiftFromRustBuffer[PaymentType](c, rb)
+}
+
+func (c FfiConverterPaymentType) Lower(value PaymentType) C.RustBuffer {
+	return LowerIntoRustBuffer[PaymentType](c, value)
+}
+func (FfiConverterPaymentType) Read(reader io.Reader) PaymentType {
+	id := readInt32(reader)
+	return PaymentType(id)
+}
+
+func (FfiConverterPaymentType) Write(writer io.Writer, value PaymentType) {
+	writeInt32(writer, int32(value))
+}
+
+type FfiDestroyerPaymentType struct{}
+
+func (_ FfiDestroyerPaymentType) Destroy(value PaymentType) {
+}
+
+type ProvisionalPaymentDetails interface {
+	Destroy()
+}
+
+// Onchain Bitcoin address
+type ProvisionalPaymentDetailsBitcoin struct {
+	WithdrawalAddress string
+}
+
+func (e ProvisionalPaymentDetailsBitcoin) Destroy() {
+	FfiDestroyerString{}.Destroy(e.WithdrawalAddress)
+}
+
+// BOLT11 invoice
+type ProvisionalPaymentDetailsLightning struct {
+	Invoice string
+}
+
+func (e ProvisionalPaymentDetailsLightning) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Invoice)
+}
+
+// Spark address or invoice
+type ProvisionalPaymentDetailsSpark struct {
+	PayRequest string
+}
+
+func (e ProvisionalPaymentDetailsSpark) Destroy() {
+	FfiDestroyerString{}.Destroy(e.PayRequest)
+}
+
+// Token identifier and Spark address or invoice
+type ProvisionalPaymentDetailsToken struct {
+	TokenId    string
+	PayRequest string
+}
+
+func (e ProvisionalPaymentDetailsToken) Destroy() {
+	FfiDestroyerString{}.Destroy(e.TokenId)
+	FfiDestroyerString{}.Destroy(e.PayRequest)
+}
+
+type FfiConverterProvisionalPaymentDetails struct{}
+
+var FfiConverterProvisionalPaymentDetailsINSTANCE = FfiConverterProvisionalPaymentDetails{}
+
+func (c FfiConverterProvisionalPaymentDetails) Lift(rb RustBufferI) ProvisionalPaymentDetails {
+	return LiftFromRustBuffer[ProvisionalPaymentDetails](c, rb)
+}
+
+func (c FfiConverterProvisionalPaymentDetails) Lower(value ProvisionalPaymentDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[ProvisionalPaymentDetails](c, value)
+}
+func (FfiConverterProvisionalPaymentDetails) Read(reader io.Reader) ProvisionalPaymentDetails {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return ProvisionalPaymentDetailsBitcoin{
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	case 2:
+		return ProvisionalPaymentDetailsLightning{
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	case 3:
+		return ProvisionalPaymentDetailsSpark{
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	case 4:
+		return ProvisionalPaymentDetailsToken{
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterProvisionalPaymentDetails.Read()", id))
+	}
+}
+
+func (FfiConverterProvisionalPaymentDetails) Write(writer io.Writer, value ProvisionalPaymentDetails) {
+	switch variant_value := value.(type) {
+	case ProvisionalPaymentDetailsBitcoin:
+		writeInt32(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.WithdrawalAddress)
+	case ProvisionalPaymentDetailsLightning:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Invoice)
+	case ProvisionalPaymentDetailsSpark:
+		writeInt32(writer, 3)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.PayRequest)
+	case ProvisionalPaymentDetailsToken:
+		writeInt32(writer, 4)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.TokenId)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.PayRequest)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterProvisionalPaymentDetails.Write", value))
+	}
+}
+
+type FfiDestroyerProvisionalPaymentDetails struct{}
+
+func (_ FfiDestroyerProvisionalPaymentDetails) Destroy(value ProvisionalPaymentDetails) {
+	value.Destroy()
+}
+
+type ReceivePaymentMethod interface {
+	Destroy()
+}
+type ReceivePaymentMethodSparkAddress struct {
+}
+
+func (e ReceivePaymentMethodSparkAddress) Destroy() {
+}
+
+type ReceivePaymentMethodSparkInvoice struct {
+	Amount          *u128
+	TokenIdentifier *string
+	ExpiryTime      *uint64
+	Description     *string
+	SenderPublicKey *string
+}
+
+func (e ReceivePaymentMethodSparkInvoice) Destroy() {
+	FfiDestroyerOptionalTypeu128{}.Destroy(e.Amount)
+	FfiDestroyerOptionalString{}.Destroy(e.TokenIdentifier)
+	FfiDestroyerOptionalUint64{}.Destroy(e.ExpiryTime)
+	FfiDestroyerOptionalString{}.Destroy(e.Description)
+	FfiDestroyerOptionalString{}.Destroy(e.SenderPublicKey)
+}
+
+type ReceivePaymentMethodBitcoinAddress struct {
+}
+
+func (e ReceivePaymentMethodBitcoinAddress) Destroy() {
+}
+
+type ReceivePaymentMethodBolt11Invoice struct {
+	Description string
+	AmountSats  *uint64
+	ExpirySecs  *uint32
+}
+
+func (e ReceivePaymentMethodBolt11Invoice) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Description)
+	FfiDestroyerOptionalUint64{}.Destroy(e.AmountSats)
+	FfiDestroyerOptionalUint32{}.Destroy(e.ExpirySecs)
+}
+
+type FfiConverterReceivePaymentMethod struct{}
+
+var FfiConverterReceivePaymentMethodINSTANCE = FfiConverterReceivePaymentMethod{}
+
+func (c FfiConverterReceivePaymentMethod) Lift(rb RustBufferI) ReceivePaymentMethod {
+	return LiftFromRustBuffer[ReceivePaymentMethod](c, rb)
+}
+
+func (c FfiConverterReceivePaymentMethod) Lower(value ReceivePaymentMethod) C.RustBuffer {
+	return LowerIntoRustBuffer[ReceivePaymentMethod](c, value)
+}
+func (FfiConverterReceivePaymentMethod) Read(reader io.Reader) ReceivePaymentMethod {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return ReceivePaymentMethodSparkAddress{}
+	case 2:
+		return ReceivePaymentMethodSparkInvoice{
+			FfiConverterOptionalTypeu128INSTANCE.Read(reader),
+			FfiConverterOptionalStringINSTANCE.Read(reader),
+			FfiConverterOptionalUint64INSTANCE.Read(reader),
+			FfiConverterOptionalStringINSTANCE.Read(reader),
+			FfiConverterOptionalStringINSTANCE.Read(reader),
+		}
+	case 3:
+		return ReceivePaymentMethodBitcoinAddress{}
+	case 4:
+		return ReceivePaymentMethodBolt11Invoice{
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterOptionalUint64INSTANCE.Read(reader),
+			FfiConverterOptionalUint32INSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterReceivePaymentMethod.Read()", id))
+	}
+}
+
+func (FfiConverterReceivePaymentMethod) Write(writer io.Writer, value ReceivePaymentMethod) {
+	switch variant_value := value.(type) {
+	case ReceivePaymentMethodSparkAddress:
+		writeInt32(writer, 1)
+	case ReceivePaymentMethodSparkInvoice:
+		writeInt32(writer, 2)
+		FfiConverterOptionalTypeu128INSTANCE.Write(writer, variant_value.Amount)
+		FfiConverterOptionalStringINSTANCE.Write(writer, variant_value.TokenIdentifier)
+		FfiConverterOptionalUint64INSTANCE.Write(writer, variant_value.ExpiryTime)
+		FfiConverterOptionalStringINSTANCE.Write(writer, variant_value.Description)
+		FfiConverterOptionalStringINSTANCE.Write(writer, variant_value.SenderPublicKey)
+	case ReceivePaymentMethodBitcoinAddress:
+		writeInt32(writer, 3)
+	case ReceivePaymentMethodBolt11Invoice:
+		writeInt32(writer, 4)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Description)
+		FfiConverterOptionalUint64INSTANCE.Write(writer, variant_value.AmountSats)
+		FfiConverterOptionalUint32INSTANCE.Write(writer, variant_value.ExpirySecs)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterReceivePaymentMethod.Write", value))
+	}
+}
+
+type FfiDestroyerReceivePaymentMethod struct{}
+
+func (_ FfiDestroyerReceivePaymentMethod) Destroy(value ReceivePaymentMethod) {
+	value.Destroy()
+}
+
+// Error type for the `BreezSdk`
+type SdkError struct {
+	err error
+}
+
+// Convience method to turn *SdkError into error
+// Avoiding treating nil pointer as non nil error interface
+func (err *SdkError) AsError() error {
+	if err == nil {
+		return nil
+	} else {
+		return err
+	}
+}
+
+func (err SdkError) Error() string {
+	return fmt.Sprintf("SdkError: %s", err.err.Error())
+}
+
+func (err SdkError) Unwrap() error {
+	return err.err
+}
+
+// Err* are used for checking error type with `errors.Is`
+var ErrSdkErrorSparkError = fmt.Errorf("SdkErrorSparkError")
+var ErrSdkErrorInvalidUuid = fmt.Errorf("SdkErrorInvalidUuid")
+var ErrSdkErrorInvalidInput = fmt.Errorf("SdkErrorInvalidInput")
+var ErrSdkErrorNetworkError = fmt.Errorf("SdkErrorNetworkError")
+var ErrSdkErrorStorageError = fmt.Errorf("SdkErrorStorageError")
+var ErrSdkErrorChainServiceError = fmt.Errorf("SdkErrorChainServiceError")
+var ErrSdkErrorMaxDepositClaimFeeExceeded = fmt.Errorf("SdkErrorMaxDepositClaimFeeExceeded")
+var ErrSdkErrorMissingUtxo = fmt.Errorf("SdkErrorMissingUtxo")
+var ErrSdkErrorLnurlError = fmt.Errorf("SdkErrorLnurlError")
+var ErrSdkErrorGeneric = fmt.Errorf("SdkErrorGeneric")
+
+// Variant structs
+type SdkErrorSparkError struct {
+	Field0 string
+}
+
+func NewSdkErrorSparkError(
+	var0 string,
+) *SdkError {
+	return &SdkError{err: &SdkErrorSparkError{
+		Field0: var0}}
+}
+
+func (e SdkErrorSparkError) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SdkErrorSparkError) Error() string {
+	return fmt.Sprint("SparkError",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SdkErrorSparkError) Is(target error) bool {
+	return target == ErrSdkErrorSparkError
+}
+
+type SdkErrorInvalidUuid struct {
+	Field0 string
+}
+
+func NewSdkErrorInvalidUuid(
+	var0 string,
+) *SdkError {
+	return &SdkError{err: &SdkErrorInvalidUuid{
+		Field0: var0}}
+}
+
+func (e SdkErrorInvalidUuid) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SdkErrorInvalidUuid) Error() string {
+	return fmt.Sprint("InvalidUuid",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SdkErrorInvalidUuid) Is(target error) bool {
+	return target == ErrSdkErrorInvalidUuid
+}
+
+type SdkErrorInvalidInput struct {
+	Field0 string
+}
+
+func NewSdkErrorInvalidInput(
+	var0 string,
+) *SdkError {
+	return &SdkError{err: &SdkErrorInvalidInput{
+		Field0: var0}}
+}
+
+func (e SdkErrorInvalidInput) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SdkErrorInvalidInput) Error() string {
+	return fmt.Sprint("InvalidInput",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SdkErrorInvalidInput) Is(target error) bool {
+	return target == ErrSdkErrorInvalidInput
+}
+
+type SdkErrorNetworkError struct {
+	Field0 string
+}
+
+func NewSdkErrorNetworkError(
+	var0 string,
+) *SdkError {
+	return &SdkError{err: &SdkErrorNetworkError{
+		Field0: var0}}
+}
+
+func (e SdkErrorNetworkError) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SdkErrorNetworkError) Error() string {
+	return fmt.Sprint("NetworkError",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SdkErrorNetworkError) Is(target error) bool {
+	return target == ErrSdkErrorNetworkError
+}
+
+type SdkErrorStorageError struct {
+	Field0 string
+}
+
+func NewSdkErrorStorageError(
+	var0 string,
+) *SdkError {
+	return &SdkError{err: &SdkErrorStorageError{
+		Field0: var0}}
+}
+
+func (e SdkErrorStorageError) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SdkErrorStorageError) Error() string {
+	return fmt.Sprint("StorageError",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SdkErrorStorageError) Is(target error) bool {
+	return target == ErrSdkErrorStorageError
+}
+
+type SdkErrorChainServiceError struct {
+	Field0 string
+}
+
+func NewSdkErrorChainServiceError(
+	var0 string,
+) *SdkError {
+	return &SdkError{err: &SdkErrorChainServiceError{
+		Field0: var0}}
+}
+
+func (e SdkErrorChainServiceError) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SdkErrorChainServiceError) Error() string {
+	return fmt.Sprint("ChainServiceError",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SdkErrorChainServiceError) Is(target error) bool {
+	return target == ErrSdkErrorChainServiceError
+}
+
+type SdkErrorMaxDepositClaimFeeExceeded struct {
+	Tx                         string
+	Vout                       uint32
+	MaxFee                     *Fee
+	RequiredFeeSats            uint64
+	RequiredFeeRateSatPerVbyte uint64
+}
+
+func NewSdkErrorMaxDepositClaimFeeExceeded(
+	tx string,
+	vout uint32,
+	maxFee *Fee,
+	requiredFeeSats uint64,
+	requiredFeeRateSatPerVbyte uint64,
+) *SdkError {
+	return &SdkError{err: &SdkErrorMaxDepositClaimFeeExceeded{
+		Tx:                         tx,
+		Vout:                       vout,
+		MaxFee:                     maxFee,
+		RequiredFeeSats:            requiredFeeSats,
+		RequiredFeeRateSatPerVbyte: requiredFeeRateSatPerVbyte}}
+}
+
+func (e SdkErrorMaxDepositClaimFeeExceeded) destroy() {
+	FfiDestroyerString{}.Destroy(e.Tx)
+	FfiDestroyerUint32{}.Destroy(e.Vout)
+	FfiDestroyerOptionalFee{}.Destroy(e.MaxFee)
+	FfiDestroyerUint64{}.Destroy(e.RequiredFeeSats)
+	FfiDestroyerUint64{}.Destroy(e.RequiredFeeRateSatPerVbyte)
+}
+
+func (err SdkErrorMaxDepositClaimFeeExceeded) Error() string {
+	return fmt.Sprint("MaxDepositClaimFeeExceeded",
+		": ",
+
+		"Tx=",
+		err.Tx,
+		", ",
+		"Vout=",
+		err.Vout,
+		", ",
+		"MaxFee=",
+		err.MaxFee,
+		", ",
+		"RequiredFeeSats=",
+		err.RequiredFeeSats,
+		", ",
+		"RequiredFeeRateSatPerVbyte=",
+		err.RequiredFeeRateSatPerVbyte,
+	)
+}
+
+func (self SdkErrorMaxDepositClaimFeeExceeded) Is(target error) bool {
+	return target == ErrSdkErrorMaxDepositClaimFeeExceeded
+}
+
+type SdkErrorMissingUtxo struct {
+	Tx   string
+	Vout uint32
+}
+
+func NewSdkErrorMissingUtxo(
+	tx string,
+	vout uint32,
+) *SdkError {
+	return &SdkError{err: &SdkErrorMissingUtxo{
+		Tx:   tx,
+		Vout: vout}}
+}
+
+func (e SdkErrorMissingUtxo) destroy() {
+	FfiDestroyerString{}.Destroy(e.Tx)
+	FfiDestroyerUint32{}.Destroy(e.Vout)
+}
+
+func (err SdkErrorMissingUtxo) Error() string {
+	return fmt.Sprint("MissingUtxo",
+		": ",
+
+		"Tx=",
+		err.Tx,
+		", ",
+		"Vout=",
+		err.Vout,
+	)
+}
+
+func (self SdkErrorMissingUtxo) Is(target error) bool {
+	return target == ErrSdkErrorMissingUtxo
+}
+
+type SdkErrorLnurlError struct {
+	Field0 string
+}
+
+func NewSdkErrorLnurlError(
+	var0 string,
+) *SdkError {
+	return &SdkError{err: &SdkErrorLnurlError{
+		Field0: var0}}
+}
+
+func (e SdkErrorLnurlError) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SdkErrorLnurlError) Error() string {
+	return fmt.Sprint("LnurlError",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SdkErrorLnurlError) Is(target error) bool {
+	return target == ErrSdkErrorLnurlError
+}
+
+type SdkErrorGeneric struct {
+	Field0 string
+}
+
+func NewSdkErrorGeneric(
+	var0 string,
+) *SdkError {
+	return &SdkError{err: &SdkErrorGeneric{
+		Field0: var0}}
+}
+
+func (e SdkErrorGeneric) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SdkErrorGeneric) Error() string {
+	return fmt.Sprint("Generic",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SdkErrorGeneric) Is(target error) bool {
+	return target == ErrSdkErrorGeneric
+}
+
+type FfiConverterSdkError struct{}
+
+var FfiConverterSdkErrorINSTANCE = FfiConverterSdkError{}
+
+func (c FfiConverterSdkError) Lift(eb RustBufferI) *SdkError {
+	return LiftFromRustBuffer[*SdkError](c, eb)
+}
+
+func (c FfiConverterSdkError) Lower(value *SdkError) C.RustBuffer {
+	return LowerIntoRustBuffer[*SdkError](c, value)
+}
+
+func (c FfiConverterSdkError) Read(reader io.Reader) *SdkError {
+	errorID := readUint32(reader)
+
+	switch errorID {
+	case 1:
+		return &SdkError{&SdkErrorSparkError{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 2:
+		return &SdkError{&SdkErrorInvalidUuid{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 3:
+		return &SdkError{&SdkErrorInvalidInput{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 4:
+		return &SdkError{&SdkErrorNetworkError{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 5:
+		return &SdkError{&SdkErrorStorageError{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 6:
+		return &SdkError{&SdkErrorChainServiceError{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 7:
+		return &SdkError{&SdkErrorMaxDepositClaimFeeExceeded{
+			Tx:                         FfiConverterStringINSTANCE.Read(reader),
+			Vout:                       FfiConverterUint32INSTANCE.Read(reader),
+			MaxFee:                     FfiConverterOptionalFeeINSTANCE.Read(reader),
+			RequiredFeeSats:            FfiConverterUint64INSTANCE.Read(reader),
+			RequiredFeeRateSatPerVbyte: FfiConverterUint64INSTANCE.Read(reader),
+		}}
+	case 8:
+		return &SdkError{&SdkErrorMissingUtxo{
+			Tx:   FfiConverterStringINSTANCE.Read(reader),
+			Vout: FfiConverterUint32INSTANCE.Read(reader),
+		}}
+	case 9:
+		return &SdkError{&SdkErrorLnurlError{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 10:
+		return &SdkError{&SdkErrorGeneric{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	default:
+		panic(fmt.Sprintf("Unknown error code %d in FfiConverterSdkError.Read()", errorID))
+	}
+}
+
+func (c FfiConverterSdkError) Write(writer io.Writer, value *SdkError) {
+	switch variantValue := value.err.(type) {
+	case *SdkErrorSparkError:
+		writeInt32(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *SdkErrorInvalidUuid:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *SdkErrorInvalidInput:
+		writeInt32(writer, 3)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *SdkErrorNetworkError:
+		writeInt32(writer, 4)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *SdkErrorStorageError:
+		writeInt32(writer, 5)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *SdkErrorChainServiceError:
+		writeInt32(writer, 6)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *SdkErrorMaxDepositClaimFeeExceeded:
+		writeInt32(writer, 7)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Tx)
+		FfiConverterUint32INSTANCE.Write(writer, variantValue.Vout)
+		FfiConverterOptionalFeeINSTANCE.Write(writer, variantValue.MaxFee)
+		FfiConverterUint64INSTANCE.Write(writer, variantValue.RequiredFeeSats)
+		FfiConverterUint64INSTANCE.Write(writer, variantValue.RequiredFeeRateSatPerVbyte)
+	case *SdkErrorMissingUtxo:
+		writeInt32(writer, 8)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Tx)
+		FfiConverterUint32INSTANCE.Write(writer, variantValue.Vout)
+	case *SdkErrorLnurlError:
+		writeInt32(writer, 9)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *SdkErrorGeneric:
+		writeInt32(writer, 10)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiConverterSdkError.Write", value))
+	}
+}
+
+type FfiDestroyerSdkError struct{}
+
+func (_ FfiDestroyerSdkError) Destroy(value *SdkError) {
+	switch variantValue := value.err.(type) {
+	case *SdkErrorSparkError:
+		variantValue.destroy()
+	case *SdkErrorInvalidUuid:
+		variantValue.destroy()
+	case *SdkErrorInvalidInput:
+		variantValue.destroy()
+	case *SdkErrorNetworkError:
+		variantValue.destroy()
+	case *SdkErrorStorageError:
+		variantValue.destroy()
+	case *SdkErrorChainServiceError:
+		variantValue.destroy()
+	case *SdkErrorMaxDepositClaimFeeExceeded:
+		variantValue.destroy()
+	case *SdkErrorMissingUtxo:
+		variantValue.destroy()
+	case *SdkErrorLnurlError:
+		variantValue.destroy()
+	case *SdkErrorGeneric:
+		variantValue.destroy()
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiDestroyerSdkError.Destroy", value))
+	}
+}
+
+// Events emitted by the SDK
+type SdkEvent interface {
+	Destroy()
+}
+type SdkEventSynced struct {
+}
+
+func (e SdkEventSynced) Destroy() {
+}
+
+type SdkEventDataSynced struct {
+	DidPullNewRecords bool
+}
+
+func (e SdkEventDataSynced) Destroy() {
+	FfiDestroyerBool{}.Destroy(e.DidPullNewRecords)
+}
+
+type SdkEventUnclaimedDeposits struct {
+	UnclaimedDeposits []DepositInfo
+}
+
+func (e SdkEventUnclaimedDeposits) Destroy() {
+	FfiDestroyerSequenceDepositInfo{}.Destroy(e.UnclaimedDeposits)
+}
+
+type SdkEventClaimedDeposits struct {
+	ClaimedDeposits []DepositInfo
+}
+
+func (e SdkEventClaimedDeposits) Destroy() {
+	FfiDestroyerSequenceDepositInfo{}.Destroy(e.ClaimedDeposits)
+}
+
+type SdkEventPaymentSucceeded struct {
+	Payment Payment
+}
+
+func (e SdkEventPaymentSucceeded) Destroy() {
+	FfiDestroyerPayment{}.Destroy(e.Payment)
+}
+
+type SdkEventPaymentPending struct {
+	Payment Payment
+}
+
+func (e SdkEventPaymentPending) Destroy() {
+	FfiDestroyerPayment{}.Destroy(e.Payment)
+}
+
+type SdkEventPaymentFailed struct {
+	Payment Payment
+}
+
+func (e SdkEventPaymentFailed) Destroy() {
+	FfiDestroyerPayment{}.Destroy(e.Payment)
+}
+
+type FfiConverterSdkEvent struct{}
+
+var FfiConverterSdkEventINSTANCE = FfiConverterSdkEvent{}
+
+func (c FfiConverterSdkEvent) Lift(rb RustBufferI) SdkEvent {
+	return LiftFromRustBuffer[SdkEvent](c, rb)
+}
+
+func (c FfiConverterSdkEvent) Lower(value SdkEvent) C.RustBuffer {
+	return LowerIntoRustBuffer[SdkEvent](c, value)
+}
+func (FfiConverterSdkEvent) Read(reader io.Reader) SdkEvent {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return SdkEventSynced{}
+	case 2:
+		return SdkEventDataSynced{
+			FfiConverterBoolINSTANCE.Read(reader),
+		}
+	case 3:
+		return SdkEventUnclaimedDeposits{
+			FfiConverterSequenceDepositInfoINSTANCE.Read(reader),
+		}
+	case 4:
+		return SdkEventClaimedDeposits{
+			FfiConverterSequenceDepositInfoINSTANCE.Read(reader),
+		}
+	case 5:
+		return SdkEventPaymentSucceeded{
+			FfiConverterPaymentINSTANCE.Read(reader),
+		}
+	case 6:
+		return SdkEventPaymentPending{
+			FfiConverterPaymentINSTANCE.Read(reader),
+		}
+	case 7:
+		return SdkEventPaymentFailed{
+			FfiConverterPaymentINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSdkEvent.Read()", id))
+	}
+}
+
+func (FfiConverterSdkEvent) Write(writer io.Writer, value SdkEvent) {
+	switch variant_value := value.(type) {
+	case SdkEventSynced:
+		writeInt32(writer, 1)
+	case SdkEventDataSynced:
+		writeInt32(writer, 2)
+		FfiConverterBoolINSTANCE.Write(writer, variant_value.DidPullNewRecords)
+	case SdkEventUnclaimedDeposits:
+		writeInt32(writer, 3)
+		FfiConverterSequenceDepositInfoINSTANCE.Write(writer, variant_value.UnclaimedDeposits)
+	case SdkEventClaimedDeposits:
+		writeInt32(writer, 4)
+		FfiConverterSequenceDepositInfoINSTANCE.Write(writer, variant_value.ClaimedDeposits)
+	case SdkEventPaymentSucceeded:
+		writeInt32(writer, 5)
+		FfiConverterPaymentINSTANCE.Write(writer, variant_value.Payment)
+	case SdkEventPaymentPending:
+		writeInt32(writer, 6)
+		FfiConverterPaymentINSTANCE.Write(writer, variant_value.Payment)
+	case SdkEventPaymentFailed:
+		writeInt32(writer, 7)
+		FfiConverterPaymentINSTANCE.Write(writer, variant_value.Payment)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSdkEvent.Write", value))
+	}
+}
+
+type FfiDestroyerSdkEvent struct{}
+
+func (_ FfiDestroyerSdkEvent) Destroy(value SdkEvent) {
+	value.Destroy()
+}
+
+// Represents the seed for wallet generation, either as a mnemonic phrase with an optional
+// passphrase or as raw entropy bytes.
+type Seed interface {
+	Destroy()
+}
+
+// A BIP-39 mnemonic, with an optional passphrase
+type SeedMnemonic struct {
+	Mnemonic   string
+	Passphrase *string
+}
+
+func (e SeedMnemonic) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Mnemonic)
+	FfiDestroyerOptionalString{}.Destroy(e.Passphrase)
+}
+
+// Raw entropy bytes
+type SeedEntropy struct {
+	Field0 []byte
+}
+
+func (e SeedEntropy) Destroy() {
+	FfiDestroyerBytes{}.Destroy(e.Field0)
+}
+
+type FfiConverterSeed struct{}
+
+var FfiConverterSeedINSTANCE = FfiConverterSeed{}
+
+func (c FfiConverterSeed) Lift(rb RustBufferI) Seed {
+	return LiftFromRustBuffer[Seed](c, rb)
+}
+
+func (c FfiConverterSeed) Lower(value Seed) C.RustBuffer {
+	return LowerIntoRustBuffer[Seed](c, value)
+}
+func (FfiConverterSeed) Read(reader io.Reader) Seed {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return SeedMnemonic{
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterOptionalStringINSTANCE.Read(reader),
+		}
+	case 2:
+		return SeedEntropy{
+			FfiConverterBytesINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSeed.Read()", id))
+	}
+}
+
+func (FfiConverterSeed) Write(writer io.Writer, value Seed) {
+	switch variant_value := value.(type) {
+	case SeedMnemonic:
+		writeInt32(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Mnemonic)
+		FfiConverterOptionalStringINSTANCE.Write(writer, variant_value.Passphrase)
+	case SeedEntropy:
+		writeInt32(writer, 2)
+		FfiConverterBytesINSTANCE.Write(writer, variant_value.Field0)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSeed.Write", value))
+	}
+}
+
+type FfiDestroyerSeed struct{}
+
+func (_ FfiDestroyerSeed) Destroy(value Seed) {
+	value.Destroy()
+}
+
+type SendPaymentMethod interface {
+	Destroy()
+}
+type SendPaymentMethodBitcoinAddress struct {
+	Address  BitcoinAddressDetails
+	FeeQuote SendOnchainFeeQuote
+}
+
+func (e SendPaymentMethodBitcoinAddress) Destroy() {
+	FfiDestroyerBitcoinAddressDetails{}.Destroy(e.Address)
+	FfiDestroyerSendOnchainFeeQuote{}.Destroy(e.FeeQuote)
+}
+
+type SendPaymentMethodBolt11Invoice struct {
+	InvoiceDetails       Bolt11InvoiceDetails
+	SparkTransferFeeSats *uint64
+	LightningFeeSats     uint64
+}
+
+func (e SendPaymentMethodBolt11Invoice) Destroy() {
+	FfiDestroyerBolt11InvoiceDetails{}.Destroy(e.InvoiceDetails)
+	FfiDestroyerOptionalUint64{}.Destroy(e.SparkTransferFeeSats)
+	FfiDestroyerUint64{}.Destroy(e.LightningFeeSats)
+}
+
+type SendPaymentMethodSparkAddress struct {
+	Address         string
+	Fee             u128
+	TokenIdentifier *string
+}
+
+func (e SendPaymentMethodSparkAddress) Destroy() {
+	FfiDestroyerString{}.Destroy(e.Address)
+	FfiDestroyerTypeu128{}.Destroy(e.Fee)
+	FfiDestroyerOptionalString{}.Destroy(e.TokenIdentifier)
+}
+
+type SendPaymentMethodSparkInvoice struct {
+	SparkInvoiceDetails SparkInvoiceDetails
+	Fee                 u128
+	TokenIdentifier     *string
+}
+
+func (e SendPaymentMethodSparkInvoice) Destroy() {
+	FfiDestroyerSparkInvoiceDetails{}.Destroy(e.SparkInvoiceDetails)
+	FfiDestroyerTypeu128{}.Destroy(e.Fee)
+	FfiDestroyerOptionalString{}.Destroy(e.TokenIdentifier)
+}
+
+type FfiConverterSendPaymentMethod struct{}
+
+var FfiConverterSendPaymentMethodINSTANCE = FfiConverterSendPaymentMethod{}
+
+func (c FfiConverterSendPaymentMethod) Lift(rb RustBufferI) SendPaymentMethod {
+	return LiftFromRustBuffer[SendPaymentMethod](c, rb)
+}
+
+func (c FfiConverterSendPaymentMethod) Lower(value SendPaymentMethod) C.RustBuffer {
+	return LowerIntoRustBuffer[SendPaymentMethod](c, value)
+}
+func (FfiConverterSendPaymentMethod) Read(reader io.Reader) SendPaymentMethod {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return SendPaymentMethodBitcoinAddress{
+			FfiConverterBitcoinAddressDetailsINSTANCE.Read(reader),
+			FfiConverterSendOnchainFeeQuoteINSTANCE.Read(reader),
+		}
+	case 2:
+		return SendPaymentMethodBolt11Invoice{
+			FfiConverterBolt11InvoiceDetailsINSTANCE.Read(reader),
+			FfiConverterOptionalUint64INSTANCE.Read(reader),
+			FfiConverterUint64INSTANCE.Read(reader),
+		}
+	case 3:
+		return SendPaymentMethodSparkAddress{
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterTypeu128INSTANCE.Read(reader),
+			FfiConverterOptionalStringINSTANCE.Read(reader),
+		}
+	case 4:
+		return SendPaymentMethodSparkInvoice{
+			FfiConverterSparkInvoiceDetailsINSTANCE.Read(reader),
+			FfiConverterTypeu128INSTANCE.Read(reader),
+			FfiConverterOptionalStringINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSendPaymentMethod.Read()", id))
+	}
+}
+
+func (FfiConverterSendPaymentMethod) Write(writer io.Writer, value SendPaymentMethod) {
+	switch variant_value := value.(type) {
+	case SendPaymentMethodBitcoinAddress:
+		writeInt32(writer, 1)
+		FfiConverterBitcoinAddressDetailsINSTANCE.Write(writer, variant_value.Address)
+		FfiConverterSendOnchainFeeQuoteINSTANCE.Write(writer, variant_value.FeeQuote)
+	case SendPaymentMethodBolt11Invoice:
+		writeInt32(writer, 2)
+		FfiConverterBolt11InvoiceDetailsINSTANCE.Write(writer, variant_value.InvoiceDetails)
+		FfiConverterOptionalUint64INSTANCE.Write(writer, variant_value.SparkTransferFeeSats)
+		FfiConverterUint64INSTANCE.Write(writer, variant_value.LightningFeeSats)
+	case SendPaymentMethodSparkAddress:
+		writeInt32(writer, 3)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.Address)
+		FfiConverterTypeu128INSTANCE.Write(writer, variant_value.Fee)
+		FfiConverterOptionalStringINSTANCE.Write(writer, variant_value.TokenIdentifier)
+	case SendPaymentMethodSparkInvoice:
+		writeInt32(writer, 4)
+		FfiConverterSparkInvoiceDetailsINSTANCE.Write(writer, variant_value.SparkInvoiceDetails)
+		FfiConverterTypeu128INSTANCE.Write(writer, variant_value.Fee)
+		FfiConverterOptionalStringINSTANCE.Write(writer, variant_value.TokenIdentifier)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSendPaymentMethod.Write", value))
+	}
+}
+
+type FfiDestroyerSendPaymentMethod struct{}
+
+func (_ FfiDestroyerSendPaymentMethod) Destroy(value SendPaymentMethod) {
+	value.Destroy()
+}
+
+type SendPaymentOptions interface {
+	Destroy()
+}
+type SendPaymentOptionsBitcoinAddress struct {
+	ConfirmationSpeed OnchainConfirmationSpeed
+}
+
+func (e SendPaymentOptionsBitcoinAddress) Destroy() {
+	FfiDestroyerOnchainConfirmationSpeed{}.Destroy(e.ConfirmationSpeed)
+}
+
+type SendPaymentOptionsBolt11Invoice struct {
+	PreferSpark           bool
+	CompletionTimeoutSecs *uint32
+}
+
+func (e SendPaymentOptionsBolt11Invoice) Destroy() {
+	FfiDestroyerBool{}.Destroy(e.PreferSpark)
+	FfiDestroyerOptionalUint32{}.Destroy(e.CompletionTimeoutSecs)
+}
+
+type SendPaymentOptionsSparkAddress struct {
+	HtlcOptions *SparkHtlcOptions
+}
+
+func (e SendPaymentOptionsSparkAddress) Destroy() {
+	FfiDestroyerOptionalSparkHtlcOptions{}.Destroy(e.HtlcOptions)
+}
+
+type FfiConverterSendPaymentOptions struct{}
+
+var FfiConverterSendPaymentOptionsINSTANCE = FfiConverterSendPaymentOptions{}
+
+func (c FfiConverterSendPaymentOptions) Lift(rb RustBufferI) SendPaymentOptions {
+	return LiftFromRustBuffer[SendPaymentOptions](c, rb)
+}
+
+func (c FfiConverterSendPaymentOptions) Lower(value SendPaymentOptions) C.RustBuffer {
+	return LowerIntoRustBuffer[SendPaymentOptions](c, value)
+}
+func (FfiConverterSendPaymentOptions) Read(reader io.Reader) SendPaymentOptions {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return SendPaymentOptionsBitcoinAddress{
+			FfiConverterOnchainConfirmationSpeedINSTANCE.Read(reader),
+		}
+	case 2:
+		return SendPaymentOptionsBolt11Invoice{
+			FfiConverterBoolINSTANCE.Read(reader),
+			FfiConverterOptionalUint32INSTANCE.Read(reader),
+		}
+	case 3:
+		return SendPaymentOptionsSparkAddress{
+			FfiConverterOptionalSparkHtlcOptionsINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSendPaymentOptions.Read()", id))
+	}
+}
+
+func (FfiConverterSendPaymentOptions) Write(writer io.Writer, value SendPaymentOptions) {
+	switch variant_value := value.(type) {
+	case SendPaymentOptionsBitcoinAddress:
+		writeInt32(writer, 1)
+		FfiConverterOnchainConfirmationSpeedINSTANCE.Write(writer, variant_value.ConfirmationSpeed)
+	case SendPaymentOptionsBolt11Invoice:
+		writeInt32(writer, 2)
+		FfiConverterBoolINSTANCE.Write(writer, variant_value.PreferSpark)
+		FfiConverterOptionalUint32INSTANCE.Write(writer, variant_value.CompletionTimeoutSecs)
+	case SendPaymentOptionsSparkAddress:
+		writeInt32(writer, 3)
+		FfiConverterOptionalSparkHtlcOptionsINSTANCE.Write(writer, variant_value.HtlcOptions)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSendPaymentOptions.Write", value))
+	}
+}
+
+type FfiDestroyerSendPaymentOptions struct{}
+
+func (_ FfiDestroyerSendPaymentOptions) Destroy(value SendPaymentOptions) {
+	value.Destroy()
+}
+
+type ServiceConnectivityError struct {
+	err error
+}
+
+// Convience method to turn *ServiceConnectivityError into error
+// Avoiding treating nil pointer as non nil error interface
+func (err *ServiceConnectivityError) AsError() error {
+	if err == nil {
+		return nil
+	} else {
+		return err
+	}
+}
+
+func (err ServiceConnectivityError) Error() string {
+	return fmt.Sprintf("ServiceConnectivityError: %s", err.err.Error())
+}
+
+func (err ServiceConnectivityError) Unwrap() error {
+	return err.err
+}
+
+// Err* are used for checking error type with `errors.Is`
+var ErrServiceConnectivityErrorJson = fmt.Errorf("ServiceConnectivityErrorJson")
+var ErrServiceConnectivityErrorHttp = fmt.Errorf("ServiceConnectivityErrorHttp")
+var ErrServiceConnectivityErrorBuild = fmt.Errorf("ServiceConnectivityErrorBuild")
+var ErrServiceConnectivityErrorConnect = fmt.Errorf("ServiceConnectivityErrorConnect")
+var ErrServiceConnectivityErrorIo = fmt.Errorf("ServiceConnectivityErrorIo")
+var ErrServiceConnectivityErrorBody = fmt.Errorf("ServiceConnectivityErrorBody")
+var ErrServiceConnectivityErrorDecode = fmt.Errorf("ServiceConnectivityErrorDecode")
+var ErrServiceConnectivityErrorRedirect = fmt.Errorf("ServiceConnectivityErrorRedirect")
+var ErrServiceConnectivityErrorTimeout = fmt.Errorf("ServiceConnectivityErrorTimeout")
+var ErrServiceConnectivityErrorOther = fmt.Errorf("ServiceConnectivityErrorOther")
+
+// Variant structs
+type ServiceConnectivityErrorJson struct {
+	Field0 string
+}
+
+func NewServiceConnectivityErrorJson(
+	var0 string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorJson{
+		Field0: var0}}
+}
+
+func (e ServiceConnectivityErrorJson) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ServiceConnectivityErrorJson) Error() string {
+	return fmt.Sprint("Json",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ServiceConnectivityErrorJson) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorJson
+}
+
+type ServiceConnectivityErrorHttp struct {
+	Status uint16
+	Body   string
+}
+
+func NewServiceConnectivityErrorHttp(
+	status uint16,
+	body string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorHttp{
+		Status: status,
+		Body:   body}}
+}
+
+func (e ServiceConnectivityErrorHttp) destroy() {
+	FfiDestroyerUint16{}.Destroy(e.Status)
+	FfiDestroyerString{}.Destroy(e.Body)
+}
+
+func (err ServiceConnectivityErrorHttp) Error() string {
+	return fmt.Sprint("Http",
+		": ",
+
+		"Status=",
+		err.Status,
+		", ",
+		"Body=",
+		err.Body,
+	)
+}
+
+func (self ServiceConnectivityErrorHttp) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorHttp
+}
+
+type ServiceConnectivityErrorBuild struct {
+	Field0 string
+}
+
+func NewServiceConnectivityErrorBuild(
+	var0 string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorBuild{
+		Field0: var0}}
+}
+
+func (e ServiceConnectivityErrorBuild) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ServiceConnectivityErrorBuild) Error() string {
+	return fmt.Sprint("Build",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ServiceConnectivityErrorBuild) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorBuild
+}
+
+type ServiceConnectivityErrorConnect struct {
+	Field0 string
+}
+
+func NewServiceConnectivityErrorConnect(
+	var0 string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorConnect{
+		Field0: var0}}
+}
+
+func (e ServiceConnectivityErrorConnect) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ServiceConnectivityErrorConnect) Error() string {
+	return fmt.Sprint("Connect",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ServiceConnectivityErrorConnect) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorConnect
+}
+
+type ServiceConnectivityErrorIo struct {
+	Field0 string
+}
+
+func NewServiceConnectivityErrorIo(
+	var0 string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorIo{
+		Field0: var0}}
+}
+
+func (e ServiceConnectivityErrorIo) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ServiceConnectivityErrorIo) Error() string {
+	return fmt.Sprint("Io",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ServiceConnectivityErrorIo) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorIo
+}
+
+type ServiceConnectivityErrorBody struct {
+	Field0 string
+}
+
+func NewServiceConnectivityErrorBody(
+	var0 string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorBody{
+		Field0: var0}}
+}
+
+func (e ServiceConnectivityErrorBody) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ServiceConnectivityErrorBody) Error() string {
+	return fmt.Sprint("Body",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ServiceConnectivityErrorBody) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorBody
+}
+
+type ServiceConnectivityErrorDecode struct {
+	Field0 string
+}
+
+func NewServiceConnectivityErrorDecode(
+	var0 string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorDecode{
+		Field0: var0}}
+}
+
+func (e ServiceConnectivityErrorDecode) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ServiceConnectivityErrorDecode) Error() string {
+	return fmt.Sprint("Decode",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ServiceConnectivityErrorDecode) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorDecode
+}
+
+type ServiceConnectivityErrorRedirect struct {
+	Field0 string
+}
+
+func NewServiceConnectivityErrorRedirect(
+	var0 string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorRedirect{
+		Field0: var0}}
+}
+
+func (e ServiceConnectivityErrorRedirect) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ServiceConnectivityErrorRedirect) Error() string {
+	return fmt.Sprint("Redirect",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ServiceConnectivityErrorRedirect) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorRedirect
+}
+
+type ServiceConnectivityErrorTimeout struct {
+	Field0 string
+}
+
+func NewServiceConnectivityErrorTimeout(
+	var0 string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorTimeout{
+		Field0: var0}}
+}
+
+func (e ServiceConnectivityErrorTimeout) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ServiceConnectivityErrorTimeout) Error() string {
+	return fmt.Sprint("Timeout",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ServiceConnectivityErrorTimeout) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorTimeout
+}
+
+type ServiceConnectivityErrorOther struct {
+	Field0 string
+}
+
+func NewServiceConnectivityErrorOther(
+	var0 string,
+) *ServiceConnectivityError {
+	return &ServiceConnectivityError{err: &ServiceConnectivityErrorOther{
+		Field0: var0}}
+}
+
+func (e ServiceConnectivityErrorOther) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err ServiceConnectivityErrorOther) Error() string {
+	return fmt.Sprint("Other",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self ServiceConnectivityErrorOther) Is(target error) bool {
+	return target == ErrServiceConnectivityErrorOther
+}
+
+type FfiConverterServiceConnectivityError struct{}
+
+var FfiConverterServiceConnectivityErrorINSTANCE = FfiConverterServiceConnectivityError{}
+
+func (c FfiConverterServiceConnectivityError) Lift(eb RustBufferI) *ServiceConnectivityError {
+	return LiftFromRustBuffer[*ServiceConnectivityError](c, eb)
+}
+
+func (c FfiConverterServiceConnectivityError) Lower(value *ServiceConnectivityError) C.RustBuffer {
+	return LowerIntoRustBuffer[*ServiceConnectivityError](c, value)
+}
+
+func (c FfiConverterServiceConnectivityError) Read(reader io.Reader) *ServiceConnectivityError {
+	errorID := readUint32(reader)
+
+	switch errorID {
+	case 1:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorJson{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 2:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorHttp{
+			Status: FfiConverterUint16INSTANCE.Read(reader),
+			Body:   FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 3:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorBuild{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 4:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorConnect{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 5:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorIo{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 6:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorBody{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 7:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorDecode{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 8:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorRedirect{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 9:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorTimeout{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 10:
+		return &ServiceConnectivityError{&ServiceConnectivityErrorOther{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	default:
+		panic(fmt.Sprintf("Unknown error code %d in FfiConverterServiceConnectivityError.Read()", errorID))
+	}
+}
+
+func (c FfiConverterServiceConnectivityError) Write(writer io.Writer, value *ServiceConnectivityError) {
+	switch variantValue := value.err.(type) {
+	case *ServiceConnectivityErrorJson:
+		writeInt32(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ServiceConnectivityErrorHttp:
+		writeInt32(writer, 2)
+		FfiConverterUint16INSTANCE.Write(writer, variantValue.Status)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Body)
+	case *ServiceConnectivityErrorBuild:
+		writeInt32(writer, 3)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ServiceConnectivityErrorConnect:
+		writeInt32(writer, 4)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ServiceConnectivityErrorIo:
+		writeInt32(writer, 5)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ServiceConnectivityErrorBody:
+		writeInt32(writer, 6)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ServiceConnectivityErrorDecode:
+		writeInt32(writer, 7)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ServiceConnectivityErrorRedirect:
+		writeInt32(writer, 8)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ServiceConnectivityErrorTimeout:
+		writeInt32(writer, 9)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *ServiceConnectivityErrorOther:
+		writeInt32(writer, 10)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiConverterServiceConnectivityError.Write", value))
+	}
+}
+
+type FfiDestroyerServiceConnectivityError struct{}
+
+func (_ FfiDestroyerServiceConnectivityError) Destroy(value *ServiceConnectivityError) {
+	switch variantValue := value.err.(type) {
+	case *ServiceConnectivityErrorJson:
+		variantValue.destroy()
+	case *ServiceConnectivityErrorHttp:
+		variantValue.destroy()
+	case *ServiceConnectivityErrorBuild:
+		variantValue.destroy()
+	case *ServiceConnectivityErrorConnect:
+		variantValue.destroy()
+	case *ServiceConnectivityErrorIo:
+		variantValue.destroy()
+	case *ServiceConnectivityErrorBody:
+		variantValue.destroy()
+	case *ServiceConnectivityErrorDecode:
+		variantValue.destroy()
+	case *ServiceConnectivityErrorRedirect:
+		variantValue.destroy()
+	case *ServiceConnectivityErrorTimeout:
+		variantValue.destroy()
+	case *ServiceConnectivityErrorOther:
+		variantValue.destroy()
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiDestroyerServiceConnectivityError.Destroy", value))
+	}
+}
+
+type SparkHtlcStatus uint
+
+const (
+	// The HTLC is waiting for the preimage to be shared by the receiver
+	SparkHtlcStatusWaitingForPreimage SparkHtlcStatus = 1
+	// The HTLC preimage has been shared and the transfer can be or has been claimed by the receiver
+	SparkHtlcStatusPreimageShared SparkHtlcStatus = 2
+	// The HTLC has been returned to the sender due to expiry
+	SparkHtlcStatusReturned SparkHtlcStatus = 3
+)
+
+type FfiConverterSparkHtlcStatus struct{}
+
+var FfiConverterSparkHtlcStatusINSTANCE = FfiConverterSparkHtlcStatus{}
+
+func (c FfiConverterSparkHtlcStatus) Lift(rb RustBufferI) SparkHtlcStatus {
+	return LiftFromRustBuffer[SparkHtlcStatus](c, rb)
+}
+
+func (c FfiConverterSparkHtlcStatus) Lower(value SparkHtlcStatus) C.RustBuffer {
+	return LowerIntoRustBuffer[SparkHtlcStatus](c, value)
+}
+func (FfiConverterSparkHtlcStatus) Read(reader io.Reader) SparkHtlcStatus {
+	id := readInt32(reader)
+	return SparkHtlcStatus(id)
+}
+
+func (FfiConverterSparkHtlcStatus) Write(writer io.Writer, value SparkHtlcStatus) {
+	writeInt32(writer, int32(value))
+}
+
+type FfiDestroyerSparkHtlcStatus struct{}
+
+func (_ FfiDestroyerSparkHtlcStatus) Destroy(value SparkHtlcStatus) {
+}
+
+// Errors that can occur during storage operations
+type StorageError struct {
+	err error
+}
+
+// Convience method to turn *StorageError into error
+// Avoiding treating nil pointer as non nil error interface
+func (err *StorageError) AsError() error {
+	if err == nil {
+		return nil
+	} else {
+		return err
+	}
+}
+
+func (err StorageError) Error() string {
+	return fmt.Sprintf("StorageError: %s", err.err.Error())
+}
+
+func (err StorageError) Unwrap() error {
+	return err.err
+}
+
+// Err* are used for checking error type with `errors.Is`
+var ErrStorageErrorImplementation = fmt.Errorf("StorageErrorImplementation")
+var ErrStorageErrorInitializationError = fmt.Errorf("StorageErrorInitializationError")
+var ErrStorageErrorSerialization = fmt.Errorf("StorageErrorSerialization")
+
+// Variant structs
+type StorageErrorImplementation struct {
+	Field0 string
+}
+
+func NewStorageErrorImplementation(
+	var0 string,
+) *StorageError {
+	return &StorageError{err: &StorageErrorImplementation{
+		Field0: var0}}
+}
+
+func (e StorageErrorImplementation) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err StorageErrorImplementation) Error() string {
+	return fmt.Sprint("Implementation",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self StorageErrorImplementation) Is(target error) bool {
+	return target == ErrStorageErrorImplementation
+}
+
+// Database initialization error
+type StorageErrorInitializationError struct {
+	Field0 string
+}
+
+// Database initialization error
+func NewStorageErrorInitializationError(
+	var0 string,
+) *StorageError {
+	return &StorageError{err: &StorageErrorInitializationError{
+		Field0: var0}}
+}
+
+func (e StorageErrorInitializationError) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err StorageErrorInitializationError) Error() string {
+	return fmt.Sprint("InitializationError",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self StorageErrorInitializationError) Is(target error) bool {
+	return target == ErrStorageErrorInitializationError
+}
+
+type StorageErrorSerialization struct {
+	Field0 string
+}
+
+func NewStorageErrorSerialization(
+	var0 string,
+) *StorageError {
+	return &StorageError{err: &StorageErrorSerialization{
+		Field0: var0}}
+}
+
+func (e StorageErrorSerialization) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err StorageErrorSerialization) Error() string {
+	return fmt.Sprint("Serialization",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self StorageErrorSerialization) Is(target error) bool {
+	return target == ErrStorageErrorSerialization
+}
+
+type FfiConverterStorageError struct{}
+
+var FfiConverterStorageErrorINSTANCE = FfiConverterStorageError{}
+
+func (c FfiConverterStorageError) Lift(eb RustBufferI) *StorageError {
+	return LiftFromRustBuffer[*StorageError](c, eb)
+}
+
+func (c FfiConverterStorageError) Lower(value *StorageError) C.RustBuffer {
+	return LowerIntoRustBuffer[*StorageError](c, value)
+}
+
+func (c FfiConverterStorageError) Read(reader io.Reader) *StorageError {
+	errorID := readUint32(reader)
+
+	switch errorID {
+	case 1:
+		return &StorageError{&StorageErrorImplementation{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 2:
+		return &StorageError{&StorageErrorInitializationError{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 3:
+		return &StorageError{&StorageErrorSerialization{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	default:
+		panic(fmt.Sprintf("Unknown error code %d in FfiConverterStorageError.Read()", errorID))
+	}
+}
+
+func (c FfiConverterStorageError) Write(writer io.Writer, value *StorageError) {
+	switch variantValue := value.err.(type) {
+	case *StorageErrorImplementation:
+		writeInt32(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *StorageErrorInitializationError:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *StorageErrorSerialization:
+		writeInt32(writer, 3)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiConverterStorageError.Write", value))
+	}
+}
+
+type FfiDestroyerStorageError struct{}
+
+func (_ FfiDestroyerStorageError) Destroy(value *StorageError) {
+	switch variantValue := value.err.(type) {
+	case *StorageErrorImplementation:
+		variantValue.destroy()
+	case *StorageErrorInitializationError:
+		variantValue.destroy()
+	case *StorageErrorSerialization:
+		variantValue.destroy()
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiDestroyerStorageError.Destroy", value))
+	}
+}
+
+// Supported success action types
+//
+// Receiving any other (unsupported) success action type will result in a failed parsing,
+// which will abort the LNURL-pay workflow, as per LUD-09.
+type SuccessAction interface {
+	Destroy()
+}
+
+// AES type, described in LUD-10
+type SuccessActionAes struct {
+	Data AesSuccessActionData
+}
+
+func (e SuccessActionAes) Destroy() {
+	FfiDestroyerAesSuccessActionData{}.Destroy(e.Data)
+}
+
+// Message type, described in LUD-09
+type SuccessActionMessage struct {
+	Data MessageSuccessActionData
+}
+
+func (e SuccessActionMessage) Destroy() {
+	FfiDestroyerMessageSuccessActionData{}.Destroy(e.Data)
+}
+
+// URL type, described in LUD-09
+type SuccessActionUrl struct {
+	Data UrlSuccessActionData
+}
+
+func (e SuccessActionUrl) Destroy() {
+	FfiDestroyerUrlSuccessActionData{}.Destroy(e.Data)
+}
+
+type FfiConverterSuccessAction struct{}
+
+var FfiConverterSuccessActionINSTANCE = FfiConverterSuccessAction{}
+
+func (c FfiConverterSuccessAction) Lift(rb RustBufferI) SuccessAction {
+	return LiftFromRustBuffer[SuccessAction](c, rb)
+}
+
+func (c FfiConverterSuccessAction) Lower(value SuccessAction) C.RustBuffer {
+	return LowerIntoRustBuffer[SuccessAction](c, value)
+}
+func (FfiConverterSuccessAction) Read(reader io.Reader) SuccessAction {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return SuccessActionAes{
+			FfiConverterAesSuccessActionDataINSTANCE.Read(reader),
+		}
+	case 2:
+		return SuccessActionMessage{
+			FfiConverterMessageSuccessActionDataINSTANCE.Read(reader),
+		}
+	case 3:
+		return SuccessActionUrl{
+			FfiConverterUrlSuccessActionDataINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSuccessAction.Read()", id))
+	}
+}
+
+func (FfiConverterSuccessAction) Write(writer io.Writer, value SuccessAction) {
+	switch variant_value := value.(type) {
+	case SuccessActionAes:
+		writeInt32(writer, 1)
+		FfiConverterAesSuccessActionDataINSTANCE.Write(writer, variant_value.Data)
+	case SuccessActionMessage:
+		writeInt32(writer, 2)
+		FfiConverterMessageSuccessActionDataINSTANCE.Write(writer, variant_value.Data)
+	case SuccessActionUrl:
+		writeInt32(writer, 3)
+		FfiConverterUrlSuccessActionDataINSTANCE.Write(writer, variant_value.Data)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSuccessAction.Write", value))
+	}
+}
+
+type FfiDestroyerSuccessAction struct{}
+
+func (_ FfiDestroyerSuccessAction) Destroy(value SuccessAction) {
+	value.Destroy()
+}
+
+// [`SuccessAction`] where contents are ready to be consumed by the caller
+//
+// Contents are identical to [`SuccessAction`], except for AES where the ciphertext is decrypted.
+type SuccessActionProcessed interface {
+	Destroy()
+}
+
+// See [`SuccessAction::Aes`] for received payload
+//
+// See [`AesSuccessActionDataDecrypted`] for decrypted payload
+type SuccessActionProcessedAes struct {
+	Result AesSuccessActionDataResult
+}
+
+func (e SuccessActionProcessedAes) Destroy() {
+	FfiDestroyerAesSuccessActionDataResult{}.Destroy(e.Result)
+}
+
+// See [`SuccessAction::Message`]
+type SuccessActionProcessedMessage struct {
+	Data MessageSuccessActionData
+}
+
+func (e SuccessActionProcessedMessage) Destroy() {
+	FfiDestroyerMessageSuccessActionData{}.Destroy(e.Data)
+}
+
+// See [`SuccessAction::Url`]
+type SuccessActionProcessedUrl struct {
+	Data UrlSuccessActionData
+}
+
+func (e SuccessActionProcessedUrl) Destroy() {
+	FfiDestroyerUrlSuccessActionData{}.Destroy(e.Data)
+}
+
+type FfiConverterSuccessActionProcessed struct{}
+
+var FfiConverterSuccessActionProcessedINSTANCE = FfiConverterSuccessActionProcessed{}
+
+func (c FfiConverterSuccessActionProcessed) Lift(rb RustBufferI) SuccessActionProcessed {
+	return LiftFromRustBuffer[SuccessActionProcessed](c, rb)
+}
+
+func (c FfiConverterSuccessActionProcessed) Lower(value SuccessActionProcessed) C.RustBuffer {
+	return LowerIntoRustBuffer[SuccessActionProcessed](c, value)
+}
+func (FfiConverterSuccessActionProcessed) Read(reader io.Reader) SuccessActionProcessed {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return SuccessActionProcessedAes{
+			FfiConverterAesSuccessActionDataResultINSTANCE.Read(reader),
+		}
+	case 2:
+		return SuccessActionProcessedMessage{
+			FfiConverterMessageSuccessActionDataINSTANCE.Read(reader),
+		}
+	case 3:
+		return SuccessActionProcessedUrl{
+			FfiConverterUrlSuccessActionDataINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSuccessActionProcessed.Read()", id))
+	}
+}
+
+func (FfiConverterSuccessActionProcessed) Write(writer io.Writer, value SuccessActionProcessed) {
+	switch variant_value := value.(type) {
+	case SuccessActionProcessedAes:
+		writeInt32(writer, 1)
+		FfiConverterAesSuccessActionDataResultINSTANCE.Write(writer, variant_value.Result)
+	case SuccessActionProcessedMessage:
+		writeInt32(writer, 2)
+		FfiConverterMessageSuccessActionDataINSTANCE.Write(writer, variant_value.Data)
+	case SuccessActionProcessedUrl:
+		writeInt32(writer, 3)
+		FfiConverterUrlSuccessActionDataINSTANCE.Write(writer, variant_value.Data)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterSuccessActionProcessed.Write", value))
+	}
+}
+
+type FfiDestroyerSuccessActionProcessed struct{}
+
+func (_ FfiDestroyerSuccessActionProcessed) Destroy(value SuccessActionProcessed) {
+	value.Destroy()
+}
+
+// Errors that can occur during storage operations
+type SyncStorageError struct {
+	err error
+}
+
+// Convience method to turn *SyncStorageError into error
+// Avoiding treating nil pointer as non nil error interface
+func (err *SyncStorageError) AsError() error {
+	if err == nil {
+		return nil
+	} else {
+		return err
+	}
+}
+
+func (err SyncStorageError) Error() string {
+	return fmt.Sprintf("SyncStorageError: %s", err.err.Error())
+}
+
+func (err SyncStorageError) Unwrap() error {
+	return err.err
+}
+
+// Err* are used for checking error type with `errors.Is`
+var ErrSyncStorageErrorImplementation = fmt.Errorf("SyncStorageErrorImplementation")
+var ErrSyncStorageErrorInitializationError = fmt.Errorf("SyncStorageErrorInitializationError")
+var ErrSyncStorageErrorSerialization = fmt.Errorf("SyncStorageErrorSerialization")
+
+// Variant structs
+type SyncStorageErrorImplementation struct {
+	Field0 string
+}
+
+func NewSyncStorageErrorImplementation(
+	var0 string,
+) *SyncStorageError {
+	return &SyncStorageError{err: &SyncStorageErrorImplementation{
+		Field0: var0}}
+}
+
+func (e SyncStorageErrorImplementation) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SyncStorageErrorImplementation) Error() string {
+	return fmt.Sprint("Implementation",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SyncStorageErrorImplementation) Is(target error) bool {
+	return target == ErrSyncStorageErrorImplementation
+}
+
+// Database initialization error
+type SyncStorageErrorInitializationError struct {
+	Field0 string
+}
+
+// Database initialization error
+func NewSyncStorageErrorInitializationError(
+	var0 string,
+) *SyncStorageError {
+	return &SyncStorageError{err: &SyncStorageErrorInitializationError{
+		Field0: var0}}
+}
+
+func (e SyncStorageErrorInitializationError) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SyncStorageErrorInitializationError) Error() string {
+	return fmt.Sprint("InitializationError",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SyncStorageErrorInitializationError) Is(target error) bool {
+	return target == ErrSyncStorageErrorInitializationError
+}
+
+type SyncStorageErrorSerialization struct {
+	Field0 string
+}
+
+func NewSyncStorageErrorSerialization(
+	var0 string,
+) *SyncStorageError {
+	return &SyncStorageError{err: &SyncStorageErrorSerialization{
+		Field0: var0}}
+}
+
+func (e SyncStorageErrorSerialization) destroy() {
+	FfiDestroyerString{}.Destroy(e.Field0)
+}
+
+func (err SyncStorageErrorSerialization) Error() string {
+	return fmt.Sprint("Serialization",
+		": ",
+
+		"Field0=",
+		err.Field0,
+	)
+}
+
+func (self SyncStorageErrorSerialization) Is(target error) bool {
+	return target == ErrSyncStorageErrorSerialization
+}
+
+type FfiConverterSyncStorageError struct{}
+
+var FfiConverterSyncStorageErrorINSTANCE = FfiConverterSyncStorageError{}
+
+func (c FfiConverterSyncStorageError) Lift(eb RustBufferI) *SyncStorageError {
+	return LiftFromRustBuffer[*SyncStorageError](c, eb)
+}
+
+func (c FfiConverterSyncStorageError) Lower(value *SyncStorageError) C.RustBuffer {
+	return LowerIntoRustBuffer[*SyncStorageError](c, value)
+}
+
+func (c FfiConverterSyncStorageError) Read(reader io.Reader) *SyncStorageError {
+	errorID := readUint32(reader)
+
+	switch errorID {
+	case 1:
+		return &SyncStorageError{&SyncStorageErrorImplementation{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 2:
+		return &SyncStorageError{&SyncStorageErrorInitializationError{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	case 3:
+		return &SyncStorageError{&SyncStorageErrorSerialization{
+			Field0: FfiConverterStringINSTANCE.Read(reader),
+		}}
+	default:
+		panic(fmt.Sprintf("Unknown error code %d in FfiConverterSyncStorageError.Read()", errorID))
+	}
+}
+
+func (c FfiConverterSyncStorageError) Write(writer io.Writer, value *SyncStorageError) {
+	switch variantValue := value.err.(type) {
+	case *SyncStorageErrorImplementation:
+		writeInt32(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *SyncStorageErrorInitializationError:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	case *SyncStorageErrorSerialization:
+		writeInt32(writer, 3)
+		FfiConverterStringINSTANCE.Write(writer, variantValue.Field0)
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiConverterSyncStorageError.Write", value))
+	}
+}
+
+type FfiDestroyerSyncStorageError struct{}
+
+func (_ FfiDestroyerSyncStorageError) Destroy(value *SyncStorageError) {
+	switch variantValue := value.err.(type) {
+	case *SyncStorageErrorImplementation:
+		variantValue.destroy()
+	case *SyncStorageErrorInitializationError:
+		variantValue.destroy()
+	case *SyncStorageErrorSerialization:
+		variantValue.destroy()
+	default:
+		_ = variantValue
+		panic(fmt.Sprintf("invalid error value `%v` in FfiDestroyerSyncStorageError.Destroy", value))
+	}
+}
+
+type UpdateDepositPayload interface {
+	Destroy()
+}
+type UpdateDepositPayloadClaimError struct {
+	Error DepositClaimError
+}
+
+func (e UpdateDepositPayloadClaimError) Destroy() {
+	FfiDestroyerDepositClaimError{}.Destroy(e.Error)
+}
+
+type UpdateDepositPayloadRefund struct {
+	RefundTxid string
+	RefundTx   string
+}
+
+func (e UpdateDepositPayloadRefund) Destroy() {
+	FfiDestroyerString{}.Destroy(e.RefundTxid)
+	FfiDestroyerString{}.Destroy(e.RefundTx)
+}
+
+type FfiConverterUpdateDepositPayload struct{}
+
+var FfiConverterUpdateDepositPayloadINSTANCE = FfiConverterUpdateDepositPayload{}
+
+func (c FfiConverterUpdateDepositPayload) Lift(rb RustBufferI) UpdateDepositPayload {
+	return LiftFromRustBuffer[UpdateDepositPayload](c, rb)
+}
+
+func (c FfiConverterUpdateDepositPayload) Lower(value UpdateDepositPayload) C.RustBuffer {
+	return LowerIntoRustBuffer[UpdateDepositPayload](c, value)
+}
+func (FfiConverterUpdateDepositPayload) Read(reader io.Reader) UpdateDepositPayload {
+	id := readInt32(reader)
+	switch id {
+	case 1:
+		return UpdateDepositPayloadClaimError{
+			FfiConverterDepositClaimErrorINSTANCE.Read(reader),
+		}
+	case 2:
+		return UpdateDepositPayloadRefund{
+			FfiConverterStringINSTANCE.Read(reader),
+			FfiConverterStringINSTANCE.Read(reader),
+		}
+	default:
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterUpdateDepositPayload.Read()", id))
+	}
+}
+
+func (FfiConverterUpdateDepositPayload) Write(writer io.Writer, value UpdateDepositPayload) {
+	switch variant_value := value.(type) {
+	case UpdateDepositPayloadClaimError:
+		writeInt32(writer, 1)
+		FfiConverterDepositClaimErrorINSTANCE.Write(writer, variant_value.Error)
+	case UpdateDepositPayloadRefund:
+		writeInt32(writer, 2)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.RefundTxid)
+		FfiConverterStringINSTANCE.Write(writer, variant_value.RefundTx)
+	default:
+		_ = variant_value
+		panic(fmt.Sprintf("invalid enum value %v in FfiConverterUpdateDepositPayload.Write", value))
+	}
+}
+
+type FfiDestroyerUpdateDepositPayload struct{}
+
+func (_ FfiDestroyerUpdateDepositPayload) Destroy(value UpdateDepositPayload) {
+	value.Destroy()
+}
+
+// Trait that needs to be implemented to receive events from the SDK
+type EventListener interface {
+	// Called when an event occurs
+	OnEvent(event SdkEvent)
+}
+
+type FfiConverterCallbackInterfaceEventListener struct {
+	handleMap *concurrentHandleMap[EventListener]
+}
+
+var FfiConverterCallbackInterfaceEventListenerINSTANCE = FfiConverterCallbackInterfaceEventListener{
+	handleMap: newConcurrentHandleMap[EventListener](),
+}
+
+func (c FfiConverterCallbackInterfaceEventListener) Lift(handle uint64) EventListener {
+	val, ok := c.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	return val
+}
+
+func (c FfiConverterCallbackInterfaceEventListener) Read(reader io.Reader) EventListener {
+	return c.Lift(readUint64(reader))
+}
+
+func (c FfiConverterCallbackInterfaceEventListener) Lower(value EventListener) C.uint64_t {
+	return C.uint64_t(c.handleMap.insert(value))
+}
+
+func (c FfiConverterCallbackInterfaceEventListener) Write(writer io.Writer, value EventListener) {
+	writeUint64(writer, uint64(c.Lower(value)))
+}
+
+type FfiDestroyerCallbackInterfaceEventListener struct{}
+
+func (FfiDestroyerCallbackInterfaceEventListener) Destroy(value EventListener) {}
+
+//export breez_sdk_spark_cgo_dispatchCallbackInterfaceEventListenerMethod0
+func breez_sdk_spark_cgo_dispatchCallbackInterfaceEventListenerMethod0(uniffiHandle C.uint64_t, event C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterCallbackInterfaceEventListenerINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for compleation or cancel
+	go func() {
+		select {
+		case <-cancel:
+		case res := <-result:
+			C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+		}
+	}()
+
+	// Eval callback asynchroniously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		uniffiObj.OnEvent(
+			FfiConverterSdkEventINSTANCE.Lift(GoRustBuffer{
+				inner: event,
+			}),
+		)
+
+	}()
+}
+
+var UniffiVTableCallbackInterfaceEventListenerINSTANCE = C.UniffiVTableCallbackInterfaceEventListener{
+	onEvent: (C.UniffiCallbackInterfaceEventListenerMethod0)(C.breez_sdk_spark_cgo_dispatchCallbackInterfaceEventListenerMethod0),
+
+	uniffiFree: (C.UniffiCallbackInterfaceFree)(C.breez_sdk_spark_cgo_dispatchCallbackInterfaceEventListenerFree),
+}
+
+//export breez_sdk_spark_cgo_dispatchCallbackInterfaceEventListenerFree
+func breez_sdk_spark_cgo_dispatchCallbackInterfaceEventListenerFree(handle C.uint64_t) {
+	FfiConverterCallbackInterfaceEventListenerINSTANCE.handleMap.remove(uint64(handle))
+}
+
+func (c FfiConverterCallbackInterfaceEventListener) register() {
+	C.uniffi_breez_sdk_spark_fn_init_callback_vtable_eventlistener(&UniffiVTableCallbackInterfaceEventListenerINSTANCE)
+}
+
+type Logger interface {
+	Log(l LogEntry)
+}
+
+type FfiConverterCallbackInterfaceLogger struct {
+	handleMap *concurrentHandleMap[Logger]
+}
+
+var FfiConverterCallbackInterfaceLoggerINSTANCE = FfiConverterCallbackInterfaceLogger{
+	handleMap: newConcurrentHandleMap[Logger](),
+}
+
+func (c FfiConverterCallbackInterfaceLogger) Lift(handle uint64) Logger {
+	val, ok := c.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	return val
+}
+
+func (c FfiConverterCallbackInterfaceLogger) Read(reader io.Reader) Logger {
+	return c.Lift(readUint64(reader))
+}
+
+func (c FfiConverterCallbackInterfaceLogger) Lower(value Logger) C.uint64_t {
+	return C.uint64_t(c.handleMap.insert(value))
+}
+
+func (c FfiConverterCallbackInterfaceLogger) Write(writer io.Writer, value Logger) {
+	writeUint64(writer, uint64(c.Lower(value)))
+}
+
+type FfiDestroyerCallbackInterfaceLogger struct{}
+
+func (FfiDestroyerCallbackInterfaceLogger) Destroy(value Logger) {}
+
+//export breez_sdk_spark_cgo_dispatchCallbackInterfaceLoggerMethod0
+func breez_sdk_spark_cgo_dispatchCallbackInterfaceLoggerMethod0(uniffiHandle C.uint64_t, l C.RustBuffer, uniffiOutReturn unsafe.Pointer, callStatus *C.RustCallStatus) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterCallbackInterfaceLoggerINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+
+	uniffiObj.Log(
+		FfiConverterLogEntryINSTANCE.Lift(GoRustBuffer{
+			inner: l,
+		}),
+	)
+
+}
+
+var UniffiVTableCallbackInterfaceLoggerINSTANCE = C.UniffiVTableCallbackInterfaceLogger{
+	log: (C.UniffiCallbackInterfaceLoggerMethod0)(C.breez_sdk_spark_cgo_dispatchCallbackInterfaceLoggerMethod0),
+
+	uniffiFree: (C.UniffiCallbackInterfaceFree)(C.breez_sdk_spark_cgo_dispatchCallbackInterfaceLoggerFree),
+}
+
+//export breez_sdk_spark_cgo_dispatchCallbackInterfaceLoggerFree
+func breez_sdk_spark_cgo_dispatchCallbackInterfaceLoggerFree(handle C.uint64_t) {
+	FfiConverterCallbackInterfaceLoggerINSTANCE.handleMap.remove(uint64(handle))
+}
+
+func (c FfiConverterCallbackInterfaceLogger) register() {
+	C.uniffi_breez_sdk_spark_fn_init_callback_vtable_logger(&UniffiVTableCallbackInterfaceLoggerINSTANCE)
+}
+
+type FfiConverterOptionalUint32 struct{}
+
+var FfiConverterOptionalUint32INSTANCE = FfiConverterOptionalUint32{}
+
+func (c FfiConverterOptionalUint32) Lift(rb RustBufferI) *uint32 {
+	return LiftFromRustBuffer[*uint32](c, rb)
+}
+
+func (_ FfiConverterOptionalUint32) Read(reader io.Reader) *uint32 {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterUint32INSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalUint32) Lower(value *uint32) C.RustBuffer {
+	return LowerIntoRustBuffer[*uint32](c, value)
+}
+
+func (_ FfiConverterOptionalUint32) Write(writer io.Writer, value *uint32) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterUint32INSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalUint32 struct{}
+
+func (_ FfiDestroyerOptionalUint32) Destroy(value *uint32) {
+	if value != nil {
+		FfiDestroyerUint32{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalUint64 struct{}
+
+var FfiConverterOptionalUint64INSTANCE = FfiConverterOptionalUint64{}
+
+func (c FfiConverterOptionalUint64) Lift(rb RustBufferI) *uint64 {
+	return LiftFromRustBuffer[*uint64](c, rb)
+}
+
+func (_ FfiConverterOptionalUint64) Read(reader io.Reader) *uint64 {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterUint64INSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalUint64) Lower(value *uint64) C.RustBuffer {
+	return LowerIntoRustBuffer[*uint64](c, value)
+}
+
+func (_ FfiConverterOptionalUint64) Write(writer io.Writer, value *uint64) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterUint64INSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalUint64 struct{}
+
+func (_ FfiDestroyerOptionalUint64) Destroy(value *uint64) {
+	if value != nil {
+		FfiDestroyerUint64{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalBool struct{}
+
+var FfiConverterOptionalBoolINSTANCE = FfiConverterOptionalBool{}
+
+func (c FfiConverterOptionalBool) Lift(rb RustBufferI) *bool {
+	return LiftFromRustBuffer[*bool](c, rb)
+}
+
+func (_ FfiConverterOptionalBool) Read(reader io.Reader) *bool {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterBoolINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalBool) Lower(value *bool) C.RustBuffer {
+	return LowerIntoRustBuffer[*bool](c, value)
+}
+
+func (_ FfiConverterOptionalBool) Write(writer io.Writer, value *bool) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterBoolINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalBool struct{}
+
+func (_ FfiDestroyerOptionalBool) Destroy(value *bool) {
+	if value != nil {
+		FfiDestroyerBool{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalString struct{}
+
+var FfiConverterOptionalStringINSTANCE = FfiConverterOptionalString{}
+
+func (c FfiConverterOptionalString) Lift(rb RustBufferI) *string {
+	return LiftFromRustBuffer[*string](c, rb)
+}
+
+func (_ FfiConverterOptionalString) Read(reader io.Reader) *string {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterStringINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalString) Lower(value *string) C.RustBuffer {
+	return LowerIntoRustBuffer[*string](c, value)
+}
+
+func (_ FfiConverterOptionalString) Write(writer io.Writer, value *string) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterStringINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalString struct{}
+
+func (_ FfiDestroyerOptionalString) Destroy(value *string) {
+	if value != nil {
+		FfiDestroyerString{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalAmount struct{}
+
+var FfiConverterOptionalAmountINSTANCE = FfiConverterOptionalAmount{}
+
+func (c FfiConverterOptionalAmount) Lift(rb RustBufferI) *Amount {
+	return LiftFromRustBuffer[*Amount](c, rb)
+}
+
+func (_ FfiConverterOptionalAmount) Read(reader io.Reader) *Amount {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterAmountINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalAmount) Lower(value *Amount) C.RustBuffer {
+	return LowerIntoRustBuffer[*Amount](c, value)
+}
+
+func (_ FfiConverterOptionalAmount) Write(writer io.Writer, value *Amount) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterAmountINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalAmount struct{}
+
+func (_ FfiDestroyerOptionalAmount) Destroy(value *Amount) {
+	if value != nil {
+		FfiDestroyerAmount{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalAssetFilter struct{}
+
+var FfiConverterOptionalAssetFilterINSTANCE = FfiConverterOptionalAssetFilter{}
+
+func (c FfiConverterOptionalAssetFilter) Lift(rb RustBufferI) *AssetFilter {
+	return LiftFromRustBuffer[*AssetFilter](c, rb)
+}
+
+func (_ FfiConverterOptionalAssetFilter) Read(reader io.Reader) *AssetFilter {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterAssetFilterINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalAssetFilter) Lower(value *AssetFilter) C.RustBuffer {
+	return LowerIntoRustBuffer[*AssetFilter](c, value)
+}
+
+func (_ FfiConverterOptionalAssetFilter) Write(writer io.Writer, value *AssetFilter) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterAssetFilterINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalAssetFilter struct{}
+
+func (_ FfiDestroyerOptionalAssetFilter) Destroy(value *AssetFilter) {
+	if value != nil {
+		FfiDestroyerAssetFilter{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalCredentials struct{}
+
+var FfiConverterOptionalCredentialsINSTANCE = FfiConverterOptionalCredentials{}
+
+func (c FfiConverterOptionalCredentials) Lift(rb RustBufferI) *Credentials {
+	return LiftFromRustBuffer[*Credentials](c, rb)
+}
+
+func (_ FfiConverterOptionalCredentials) Read(reader io.Reader) *Credentials {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterCredentialsINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalCredentials) Lower(value *Credentials) C.RustBuffer {
+	return LowerIntoRustBuffer[*Credentials](c, value)
+}
+
+func (_ FfiConverterOptionalCredentials) Write(writer io.Writer, value *Credentials) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterCredentialsINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalCredentials struct{}
+
+func (_ FfiDestroyerOptionalCredentials) Destroy(value *Credentials) {
+	if value != nil {
+		FfiDestroyerCredentials{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalDepositClaimError struct{}
+
+var FfiConverterOptionalDepositClaimErrorINSTANCE = FfiConverterOptionalDepositClaimError{}
+
+func (c FfiConverterOptionalDepositClaimError) Lift(rb RustBufferI) *DepositClaimError {
+	return LiftFromRustBuffer[*DepositClaimError](c, rb)
+}
+
+func (_ FfiConverterOptionalDepositClaimError) Read(reader io.Reader) *DepositClaimError {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterDepositClaimErrorINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalDepositClaimError) Lower(value *DepositClaimError) C.RustBuffer {
+	return LowerIntoRustBuffer[*DepositClaimError](c, value)
+}
+
+func (_ FfiConverterOptionalDepositClaimError) Write(writer io.Writer, value *DepositClaimError) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterDepositClaimErrorINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalDepositClaimError struct{}
+
+func (_ FfiDestroyerOptionalDepositClaimError) Destroy(value *DepositClaimError) {
+	if value != nil {
+		FfiDestroyerDepositClaimError{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalFee struct{}
+
+var FfiConverterOptionalFeeINSTANCE = FfiConverterOptionalFee{}
+
+func (c FfiConverterOptionalFee) Lift(rb RustBufferI) *Fee {
+	return LiftFromRustBuffer[*Fee](c, rb)
+}
+
+func (_ FfiConverterOptionalFee) Read(reader io.Reader) *Fee {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterFeeINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalFee) Lower(value *Fee) C.RustBuffer {
+	return LowerIntoRustBuffer[*Fee](c, value)
+}
+
+func (_ FfiConverterOptionalFee) Write(writer io.Writer, value *Fee) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterFeeINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalFee struct{}
+
+func (_ FfiDestroyerOptionalFee) Destroy(value *Fee) {
+	if value != nil {
+		FfiDestroyerFee{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalLightningAddressInfo struct{}
+
+var FfiConverterOptionalLightningAddressInfoINSTANCE = FfiConverterOptionalLightningAddressInfo{}
+
+func (c FfiConverterOptionalLightningAddressInfo) Lift(rb RustBufferI) *LightningAddressInfo {
+	return LiftFromRustBuffer[*LightningAddressInfo](c, rb)
+}
+
+func (_ FfiConverterOptionalLightningAddressInfo) Read(reader io.Reader) *LightningAddressInfo {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterLightningAddressInfoINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalLightningAddressInfo) Lower(value *LightningAddressInfo) C.RustBuffer {
+	return LowerIntoRustBuffer[*LightningAddressInfo](c, value)
+}
+
+func (_ FfiConverterOptionalLightningAddressInfo) Write(writer io.Writer, value *LightningAddressInfo) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterLightningAddressInfoINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalLightningAddressInfo struct{}
+
+func (_ FfiDestroyerOptionalLightningAddressInfo) Destroy(value *LightningAddressInfo) {
+	if value != nil {
+		FfiDestroyerLightningAddressInfo{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalLnurlPayInfo struct{}
+
+var FfiConverterOptionalLnurlPayInfoINSTANCE = FfiConverterOptionalLnurlPayInfo{}
+
+func (c FfiConverterOptionalLnurlPayInfo) Lift(rb RustBufferI) *LnurlPayInfo {
+	return LiftFromRustBuffer[*LnurlPayInfo](c, rb)
+}
+
+func (_ FfiConverterOptionalLnurlPayInfo) Read(reader io.Reader) *LnurlPayInfo {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterLnurlPayInfoINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalLnurlPayInfo) Lower(value *LnurlPayInfo) C.RustBuffer {
+	return LowerIntoRustBuffer[*LnurlPayInfo](c, value)
+}
+
+func (_ FfiConverterOptionalLnurlPayInfo) Write(writer io.Writer, value *LnurlPayInfo) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterLnurlPayInfoINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalLnurlPayInfo struct{}
+
+func (_ FfiDestroyerOptionalLnurlPayInfo) Destroy(value *LnurlPayInfo) {
+	if value != nil {
+		FfiDestroyerLnurlPayInfo{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalLnurlReceiveMetadata struct{}
+
+var FfiConverterOptionalLnurlReceiveMetadataINSTANCE = FfiConverterOptionalLnurlReceiveMetadata{}
+
+func (c FfiConverterOptionalLnurlReceiveMetadata) Lift(rb RustBufferI) *LnurlReceiveMetadata {
+	return LiftFromRustBuffer[*LnurlReceiveMetadata](c, rb)
+}
+
+func (_ FfiConverterOptionalLnurlReceiveMetadata) Read(reader io.Reader) *LnurlReceiveMetadata {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterLnurlReceiveMetadataINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalLnurlReceiveMetadata) Lower(value *LnurlReceiveMetadata) C.RustBuffer {
+	return LowerIntoRustBuffer[*LnurlReceiveMetadata](c, value)
+}
+
+func (_ FfiConverterOptionalLnurlReceiveMetadata) Write(writer io.Writer, value *LnurlReceiveMetadata) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterLnurlReceiveMetadataINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalLnurlReceiveMetadata struct{}
+
+func (_ FfiDestroyerOptionalLnurlReceiveMetadata) Destroy(value *LnurlReceiveMetadata) {
+	if value != nil {
+		FfiDestroyerLnurlReceiveMetadata{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalLnurlWithdrawInfo struct{}
+
+var FfiConverterOptionalLnurlWithdrawInfoINSTANCE = FfiConverterOptionalLnurlWithdrawInfo{}
+
+func (c FfiConverterOptionalLnurlWithdrawInfo) Lift(rb RustBufferI) *LnurlWithdrawInfo {
+	return LiftFromRustBuffer[*LnurlWithdrawInfo](c, rb)
+}
+
+func (_ FfiConverterOptionalLnurlWithdrawInfo) Read(reader io.Reader) *LnurlWithdrawInfo {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterLnurlWithdrawInfoINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalLnurlWithdrawInfo) Lower(value *LnurlWithdrawInfo) C.RustBuffer {
+	return LowerIntoRustBuffer[*LnurlWithdrawInfo](c, value)
+}
+
+func (_ FfiConverterOptionalLnurlWithdrawInfo) Write(writer io.Writer, value *LnurlWithdrawInfo) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterLnurlWithdrawInfoINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalLnurlWithdrawInfo struct{}
+
+func (_ FfiDestroyerOptionalLnurlWithdrawInfo) Destroy(value *LnurlWithdrawInfo) {
+	if value != nil {
+		FfiDestroyerLnurlWithdrawInfo{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalMaxFee struct{}
+
+var FfiConverterOptionalMaxFeeINSTANCE = FfiConverterOptionalMaxFee{}
+
+func (c FfiConverterOptionalMaxFee) Lift(rb RustBufferI) *MaxFee {
+	return LiftFromRustBuffer[*MaxFee](c, rb)
+}
+
+func (_ FfiConverterOptionalMaxFee) Read(reader io.Reader) *MaxFee {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterMaxFeeINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalMaxFee) Lower(value *MaxFee) C.RustBuffer {
+	return LowerIntoRustBuffer[*MaxFee](c, value)
+}
+
+func (_ FfiConverterOptionalMaxFee) Write(writer io.Writer, value *MaxFee) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterMaxFeeINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalMaxFee struct{}
+
+func (_ FfiDestroyerOptionalMaxFee) Destroy(value *MaxFee) {
+	if value != nil {
+		FfiDestroyerMaxFee{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalOutgoingChange struct{}
+
+var FfiConverterOptionalOutgoingChangeINSTANCE = FfiConverterOptionalOutgoingChange{}
+
+func (c FfiConverterOptionalOutgoingChange) Lift(rb RustBufferI) *OutgoingChange {
+	return LiftFromRustBuffer[*OutgoingChange](c, rb)
+}
+
+func (_ FfiConverterOptionalOutgoingChange) Read(reader io.Reader) *OutgoingChange {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterOutgoingChangeINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalOutgoingChange) Lower(value *OutgoingChange) C.RustBuffer {
+	return LowerIntoRustBuffer[*OutgoingChange](c, value)
+}
+
+func (_ FfiConverterOptionalOutgoingChange) Write(writer io.Writer, value *OutgoingChange) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterOutgoingChangeINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalOutgoingChange struct{}
+
+func (_ FfiDestroyerOptionalOutgoingChange) Destroy(value *OutgoingChange) {
+	if value != nil {
+		FfiDestroyerOutgoingChange{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalPayment struct{}
+
+var FfiConverterOptionalPaymentINSTANCE = FfiConverterOptionalPayment{}
+
+func (c FfiConverterOptionalPayment) Lift(rb RustBufferI) *Payment {
+	return LiftFromRustBuffer[*Payment](c, rb)
+}
+
+func (_ FfiConverterOptionalPayment) Read(reader io.Reader) *Payment {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterPaymentINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalPayment) Lower(value *Payment) C.RustBuffer {
+	return LowerIntoRustBuffer[*Payment](c, value)
+}
+
+func (_ FfiConverterOptionalPayment) Write(writer io.Writer, value *Payment) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterPaymentINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalPayment struct{}
+
+func (_ FfiDestroyerOptionalPayment) Destroy(value *Payment) {
+	if value != nil {
+		FfiDestroyerPayment{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalPaymentDetails struct{}
+
+var FfiConverterOptionalPaymentDetailsINSTANCE = FfiConverterOptionalPaymentDetails{}
+
+func (c FfiConverterOptionalPaymentDetails) Lift(rb RustBufferI) *PaymentDetails {
+	return LiftFromRustBuffer[*PaymentDetails](c, rb)
+}
+
+func (_ FfiConverterOptionalPaymentDetails) Read(reader io.Reader) *PaymentDetails {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterPaymentDetailsINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalPaymentDetails) Lower(value *PaymentDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[*PaymentDetails](c, value)
+}
+
+func (_ FfiConverterOptionalPaymentDetails) Write(writer io.Writer, value *PaymentDetails) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterPaymentDetailsINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalPaymentDetails struct{}
+
+func (_ FfiDestroyerOptionalPaymentDetails) Destroy(value *PaymentDetails) {
+	if value != nil {
+		FfiDestroyerPaymentDetails{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalRecord struct{}
+
+var FfiConverterOptionalRecordINSTANCE = FfiConverterOptionalRecord{}
+
+func (c FfiConverterOptionalRecord) Lift(rb RustBufferI) *Record {
+	return LiftFromRustBuffer[*Record](c, rb)
+}
+
+func (_ FfiConverterOptionalRecord) Read(reader io.Reader) *Record {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterRecordINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalRecord) Lower(value *Record) C.RustBuffer {
+	return LowerIntoRustBuffer[*Record](c, value)
+}
+
+func (_ FfiConverterOptionalRecord) Write(writer io.Writer, value *Record) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterRecordINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalRecord struct{}
+
+func (_ FfiDestroyerOptionalRecord) Destroy(value *Record) {
+	if value != nil {
+		FfiDestroyerRecord{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSendPaymentOptions struct{}
+
+var FfiConverterOptionalSendPaymentOptionsINSTANCE = FfiConverterOptionalSendPaymentOptions{}
+
+func (c FfiConverterOptionalSendPaymentOptions) Lift(rb RustBufferI) *SendPaymentOptions {
+	return LiftFromRustBuffer[*SendPaymentOptions](c, rb)
+}
+
+func (_ FfiConverterOptionalSendPaymentOptions) Read(reader io.Reader) *SendPaymentOptions {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSendPaymentOptionsINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSendPaymentOptions) Lower(value *SendPaymentOptions) C.RustBuffer {
+	return LowerIntoRustBuffer[*SendPaymentOptions](c, value)
+}
+
+func (_ FfiConverterOptionalSendPaymentOptions) Write(writer io.Writer, value *SendPaymentOptions) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSendPaymentOptionsINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSendPaymentOptions struct{}
+
+func (_ FfiDestroyerOptionalSendPaymentOptions) Destroy(value *SendPaymentOptions) {
+	if value != nil {
+		FfiDestroyerSendPaymentOptions{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSparkHtlcDetails struct{}
+
+var FfiConverterOptionalSparkHtlcDetailsINSTANCE = FfiConverterOptionalSparkHtlcDetails{}
+
+func (c FfiConverterOptionalSparkHtlcDetails) Lift(rb RustBufferI) *SparkHtlcDetails {
+	return LiftFromRustBuffer[*SparkHtlcDetails](c, rb)
+}
+
+func (_ FfiConverterOptionalSparkHtlcDetails) Read(reader io.Reader) *SparkHtlcDetails {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSparkHtlcDetailsINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSparkHtlcDetails) Lower(value *SparkHtlcDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[*SparkHtlcDetails](c, value)
+}
+
+func (_ FfiConverterOptionalSparkHtlcDetails) Write(writer io.Writer, value *SparkHtlcDetails) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSparkHtlcDetailsINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSparkHtlcDetails struct{}
+
+func (_ FfiDestroyerOptionalSparkHtlcDetails) Destroy(value *SparkHtlcDetails) {
+	if value != nil {
+		FfiDestroyerSparkHtlcDetails{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSparkHtlcOptions struct{}
+
+var FfiConverterOptionalSparkHtlcOptionsINSTANCE = FfiConverterOptionalSparkHtlcOptions{}
+
+func (c FfiConverterOptionalSparkHtlcOptions) Lift(rb RustBufferI) *SparkHtlcOptions {
+	return LiftFromRustBuffer[*SparkHtlcOptions](c, rb)
+}
+
+func (_ FfiConverterOptionalSparkHtlcOptions) Read(reader io.Reader) *SparkHtlcOptions {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSparkHtlcOptionsINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSparkHtlcOptions) Lower(value *SparkHtlcOptions) C.RustBuffer {
+	return LowerIntoRustBuffer[*SparkHtlcOptions](c, value)
+}
+
+func (_ FfiConverterOptionalSparkHtlcOptions) Write(writer io.Writer, value *SparkHtlcOptions) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSparkHtlcOptionsINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSparkHtlcOptions struct{}
+
+func (_ FfiDestroyerOptionalSparkHtlcOptions) Destroy(value *SparkHtlcOptions) {
+	if value != nil {
+		FfiDestroyerSparkHtlcOptions{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSparkInvoicePaymentDetails struct{}
+
+var FfiConverterOptionalSparkInvoicePaymentDetailsINSTANCE = FfiConverterOptionalSparkInvoicePaymentDetails{}
+
+func (c FfiConverterOptionalSparkInvoicePaymentDetails) Lift(rb RustBufferI) *SparkInvoicePaymentDetails {
+	return LiftFromRustBuffer[*SparkInvoicePaymentDetails](c, rb)
+}
+
+func (_ FfiConverterOptionalSparkInvoicePaymentDetails) Read(reader io.Reader) *SparkInvoicePaymentDetails {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSparkInvoicePaymentDetailsINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSparkInvoicePaymentDetails) Lower(value *SparkInvoicePaymentDetails) C.RustBuffer {
+	return LowerIntoRustBuffer[*SparkInvoicePaymentDetails](c, value)
+}
+
+func (_ FfiConverterOptionalSparkInvoicePaymentDetails) Write(writer io.Writer, value *SparkInvoicePaymentDetails) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSparkInvoicePaymentDetailsINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSparkInvoicePaymentDetails struct{}
+
+func (_ FfiDestroyerOptionalSparkInvoicePaymentDetails) Destroy(value *SparkInvoicePaymentDetails) {
+	if value != nil {
+		FfiDestroyerSparkInvoicePaymentDetails{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSuccessAction struct{}
+
+var FfiConverterOptionalSuccessActionINSTANCE = FfiConverterOptionalSuccessAction{}
+
+func (c FfiConverterOptionalSuccessAction) Lift(rb RustBufferI) *SuccessAction {
+	return LiftFromRustBuffer[*SuccessAction](c, rb)
+}
+
+func (_ FfiConverterOptionalSuccessAction) Read(reader io.Reader) *SuccessAction {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSuccessActionINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSuccessAction) Lower(value *SuccessAction) C.RustBuffer {
+	return LowerIntoRustBuffer[*SuccessAction](c, value)
+}
+
+func (_ FfiConverterOptionalSuccessAction) Write(writer io.Writer, value *SuccessAction) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSuccessActionINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSuccessAction struct{}
+
+func (_ FfiDestroyerOptionalSuccessAction) Destroy(value *SuccessAction) {
+	if value != nil {
+		FfiDestroyerSuccessAction{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSuccessActionProcessed struct{}
+
+var FfiConverterOptionalSuccessActionProcessedINSTANCE = FfiConverterOptionalSuccessActionProcessed{}
+
+func (c FfiConverterOptionalSuccessActionProcessed) Lift(rb RustBufferI) *SuccessActionProcessed {
+	return LiftFromRustBuffer[*SuccessActionProcessed](c, rb)
+}
+
+func (_ FfiConverterOptionalSuccessActionProcessed) Read(reader io.Reader) *SuccessActionProcessed {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSuccessActionProcessedINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSuccessActionProcessed) Lower(value *SuccessActionProcessed) C.RustBuffer {
+	return LowerIntoRustBuffer[*SuccessActionProcessed](c, value)
+}
+
+func (_ FfiConverterOptionalSuccessActionProcessed) Write(writer io.Writer, value *SuccessActionProcessed) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSuccessActionProcessedINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSuccessActionProcessed struct{}
+
+func (_ FfiDestroyerOptionalSuccessActionProcessed) Destroy(value *SuccessActionProcessed) {
+	if value != nil {
+		FfiDestroyerSuccessActionProcessed{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSymbol struct{}
+
+var FfiConverterOptionalSymbolINSTANCE = FfiConverterOptionalSymbol{}
+
+func (c FfiConverterOptionalSymbol) Lift(rb RustBufferI) *Symbol {
+	return LiftFromRustBuffer[*Symbol](c, rb)
+}
+
+func (_ FfiConverterOptionalSymbol) Read(reader io.Reader) *Symbol {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSymbolINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSymbol) Lower(value *Symbol) C.RustBuffer {
+	return LowerIntoRustBuffer[*Symbol](c, value)
+}
+
+func (_ FfiConverterOptionalSymbol) Write(writer io.Writer, value *Symbol) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSymbolINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSymbol struct{}
+
+func (_ FfiDestroyerOptionalSymbol) Destroy(value *Symbol) {
+	if value != nil {
+		FfiDestroyerSymbol{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalCallbackInterfaceLogger struct{}
+
+var FfiConverterOptionalCallbackInterfaceLoggerINSTANCE = FfiConverterOptionalCallbackInterfaceLogger{}
+
+func (c FfiConverterOptionalCallbackInterfaceLogger) Lift(rb RustBufferI) *Logger {
+	return LiftFromRustBuffer[*Logger](c, rb)
+}
+
+func (_ FfiConverterOptionalCallbackInterfaceLogger) Read(reader io.Reader) *Logger {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterCallbackInterfaceLoggerINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalCallbackInterfaceLogger) Lower(value *Logger) C.RustBuffer {
+	return LowerIntoRustBuffer[*Logger](c, value)
+}
+
+func (_ FfiConverterOptionalCallbackInterfaceLogger) Write(writer io.Writer, value *Logger) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterCallbackInterfaceLoggerINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalCallbackInterfaceLogger struct{}
+
+func (_ FfiDestroyerOptionalCallbackInterfaceLogger) Destroy(value *Logger) {
+	if value != nil {
+		FfiDestroyerCallbackInterfaceLogger{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSequenceExternalInputParser struct{}
+
+var FfiConverterOptionalSequenceExternalInputParserINSTANCE = FfiConverterOptionalSequenceExternalInputParser{}
+
+func (c FfiConverterOptionalSequenceExternalInputParser) Lift(rb RustBufferI) *[]ExternalInputParser {
+	return LiftFromRustBuffer[*[]ExternalInputParser](c, rb)
+}
+
+func (_ FfiConverterOptionalSequenceExternalInputParser) Read(reader io.Reader) *[]ExternalInputParser {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSequenceExternalInputParserINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSequenceExternalInputParser) Lower(value *[]ExternalInputParser) C.RustBuffer {
+	return LowerIntoRustBuffer[*[]ExternalInputParser](c, value)
+}
+
+func (_ FfiConverterOptionalSequenceExternalInputParser) Write(writer io.Writer, value *[]ExternalInputParser) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSequenceExternalInputParserINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSequenceExternalInputParser struct{}
+
+func (_ FfiDestroyerOptionalSequenceExternalInputParser) Destroy(value *[]ExternalInputParser) {
+	if value != nil {
+		FfiDestroyerSequenceExternalInputParser{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSequencePaymentStatus struct{}
+
+var FfiConverterOptionalSequencePaymentStatusINSTANCE = FfiConverterOptionalSequencePaymentStatus{}
+
+func (c FfiConverterOptionalSequencePaymentStatus) Lift(rb RustBufferI) *[]PaymentStatus {
+	return LiftFromRustBuffer[*[]PaymentStatus](c, rb)
+}
+
+func (_ FfiConverterOptionalSequencePaymentStatus) Read(reader io.Reader) *[]PaymentStatus {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSequencePaymentStatusINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSequencePaymentStatus) Lower(value *[]PaymentStatus) C.RustBuffer {
+	return LowerIntoRustBuffer[*[]PaymentStatus](c, value)
+}
+
+func (_ FfiConverterOptionalSequencePaymentStatus) Write(writer io.Writer, value *[]PaymentStatus) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSequencePaymentStatusINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSequencePaymentStatus struct{}
+
+func (_ FfiDestroyerOptionalSequencePaymentStatus) Destroy(value *[]PaymentStatus) {
+	if value != nil {
+		FfiDestroyerSequencePaymentStatus{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSequencePaymentType struct{}
+
+var FfiConverterOptionalSequencePaymentTypeINSTANCE = FfiConverterOptionalSequencePaymentType{}
+
+func (c FfiConverterOptionalSequencePaymentType) Lift(rb RustBufferI) *[]PaymentType {
+	return LiftFromRustBuffer[*[]PaymentType](c, rb)
+}
+
+func (_ FfiConverterOptionalSequencePaymentType) Read(reader io.Reader) *[]PaymentType {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSequencePaymentTypeINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSequencePaymentType) Lower(value *[]PaymentType) C.RustBuffer {
+	return LowerIntoRustBuffer[*[]PaymentType](c, value)
+}
+
+func (_ FfiConverterOptionalSequencePaymentType) Write(writer io.Writer, value *[]PaymentType) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSequencePaymentTypeINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSequencePaymentType struct{}
+
+func (_ FfiDestroyerOptionalSequencePaymentType) Destroy(value *[]PaymentType) {
+	if value != nil {
+		FfiDestroyerSequencePaymentType{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalSequenceSparkHtlcStatus struct{}
+
+var FfiConverterOptionalSequenceSparkHtlcStatusINSTANCE = FfiConverterOptionalSequenceSparkHtlcStatus{}
+
+func (c FfiConverterOptionalSequenceSparkHtlcStatus) Lift(rb RustBufferI) *[]SparkHtlcStatus {
+	return LiftFromRustBuffer[*[]SparkHtlcStatus](c, rb)
+}
+
+func (_ FfiConverterOptionalSequenceSparkHtlcStatus) Read(reader io.Reader) *[]SparkHtlcStatus {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterSequenceSparkHtlcStatusINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalSequenceSparkHtlcStatus) Lower(value *[]SparkHtlcStatus) C.RustBuffer {
+	return LowerIntoRustBuffer[*[]SparkHtlcStatus](c, value)
+}
+
+func (_ FfiConverterOptionalSequenceSparkHtlcStatus) Write(writer io.Writer, value *[]SparkHtlcStatus) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterSequenceSparkHtlcStatusINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalSequenceSparkHtlcStatus struct{}
+
+func (_ FfiDestroyerOptionalSequenceSparkHtlcStatus) Destroy(value *[]SparkHtlcStatus) {
+	if value != nil {
+		FfiDestroyerSequenceSparkHtlcStatus{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalMapStringString struct{}
+
+var FfiConverterOptionalMapStringStringINSTANCE = FfiConverterOptionalMapStringString{}
+
+func (c FfiConverterOptionalMapStringString) Lift(rb RustBufferI) *map[string]string {
+	return LiftFromRustBuffer[*map[string]string](c, rb)
+}
+
+func (_ FfiConverterOptionalMapStringString) Read(reader io.Reader) *map[string]string {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterMapStringStringINSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalMapStringString) Lower(value *map[string]string) C.RustBuffer {
+	return LowerIntoRustBuffer[*map[string]string](c, value)
+}
+
+func (_ FfiConverterOptionalMapStringString) Write(writer io.Writer, value *map[string]string) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterMapStringStringINSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalMapStringString struct{}
+
+func (_ FfiDestroyerOptionalMapStringString) Destroy(value *map[string]string) {
+	if value != nil {
+		FfiDestroyerMapStringString{}.Destroy(*value)
+	}
+}
+
+type FfiConverterOptionalTypeu128 struct{}
+
+var FfiConverterOptionalTypeu128INSTANCE = FfiConverterOptionalTypeu128{}
+
+func (c FfiConverterOptionalTypeu128) Lift(rb RustBufferI) *u128 {
+	return LiftFromRustBuffer[*u128](c, rb)
+}
+
+func (_ FfiConverterOptionalTypeu128) Read(reader io.Reader) *u128 {
+	if readInt8(reader) == 0 {
+		return nil
+	}
+	temp := FfiConverterTypeu128INSTANCE.Read(reader)
+	return &temp
+}
+
+func (c FfiConverterOptionalTypeu128) Lower(value *u128) C.RustBuffer {
+	return LowerIntoRustBuffer[*u128](c, value)
+}
+
+func (_ FfiConverterOptionalTypeu128) Write(writer io.Writer, value *u128) {
+	if value == nil {
+		writeInt8(writer, 0)
+	} else {
+		writeInt8(writer, 1)
+		FfiConverterTypeu128INSTANCE.Write(writer, *value)
+	}
+}
+
+type FfiDestroyerOptionalTypeu128 struct{}
+
+func (_ FfiDestroyerOptionalTypeu128) Destroy(value *u128) {
+	if value != nil {
+		FfiDestroyerTypeu128{}.Destroy(*value)
+	}
+}
+
+type FfiConverterSequenceString struct{}
+
+var FfiConverterSequenceStringINSTANCE = FfiConverterSequenceString{}
+
+func (c FfiConverterSequenceString) Lift(rb RustBufferI) []string {
+	return LiftFromRustBuffer[[]string](c, rb)
+}
+
+func (c FfiConverterSequenceString) Read(reader io.Reader) []string {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]string, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterStringINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceString) Lower(value []string) C.RustBuffer {
+	return LowerIntoRustBuffer[[]string](c, value)
+}
+
+func (c FfiConverterSequenceString) Write(writer io.Writer, value []string) {
+	if len(value) > math.MaxInt32 {
+		panic("[]string is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterStringINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceString struct{}
+
+func (FfiDestroyerSequenceString) Destroy(sequence []string) {
+	for _, value := range sequence {
+		FfiDestroyerString{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceBip21Extra struct{}
+
+var FfiConverterSequenceBip21ExtraINSTANCE = FfiConverterSequenceBip21Extra{}
+
+func (c FfiConverterSequenceBip21Extra) Lift(rb RustBufferI) []Bip21Extra {
+	return LiftFromRustBuffer[[]Bip21Extra](c, rb)
+}
+
+func (c FfiConverterSequenceBip21Extra) Read(reader io.Reader) []Bip21Extra {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]Bip21Extra, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterBip21ExtraINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceBip21Extra) Lower(value []Bip21Extra) C.RustBuffer {
+	return LowerIntoRustBuffer[[]Bip21Extra](c, value)
+}
+
+func (c FfiConverterSequenceBip21Extra) Write(writer io.Writer, value []Bip21Extra) {
+	if len(value) > math.MaxInt32 {
+		panic("[]Bip21Extra is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterBip21ExtraINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceBip21Extra struct{}
+
+func (FfiDestroyerSequenceBip21Extra) Destroy(sequence []Bip21Extra) {
+	for _, value := range sequence {
+		FfiDestroyerBip21Extra{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceBolt11RouteHint struct{}
+
+var FfiConverterSequenceBolt11RouteHintINSTANCE = FfiConverterSequenceBolt11RouteHint{}
+
+func (c FfiConverterSequenceBolt11RouteHint) Lift(rb RustBufferI) []Bolt11RouteHint {
+	return LiftFromRustBuffer[[]Bolt11RouteHint](c, rb)
+}
+
+func (c FfiConverterSequenceBolt11RouteHint) Read(reader io.Reader) []Bolt11RouteHint {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]Bolt11RouteHint, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterBolt11RouteHintINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceBolt11RouteHint) Lower(value []Bolt11RouteHint) C.RustBuffer {
+	return LowerIntoRustBuffer[[]Bolt11RouteHint](c, value)
+}
+
+func (c FfiConverterSequenceBolt11RouteHint) Write(writer io.Writer, value []Bolt11RouteHint) {
+	if len(value) > math.MaxInt32 {
+		panic("[]Bolt11RouteHint is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterBolt11RouteHintINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceBolt11RouteHint struct{}
+
+func (FfiDestroyerSequenceBolt11RouteHint) Destroy(sequence []Bolt11RouteHint) {
+	for _, value := range sequence {
+		FfiDestroyerBolt11RouteHint{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceBolt11RouteHintHop struct{}
+
+var FfiConverterSequenceBolt11RouteHintHopINSTANCE = FfiConverterSequenceBolt11RouteHintHop{}
+
+func (c FfiConverterSequenceBolt11RouteHintHop) Lift(rb RustBufferI) []Bolt11RouteHintHop {
+	return LiftFromRustBuffer[[]Bolt11RouteHintHop](c, rb)
+}
+
+func (c FfiConverterSequenceBolt11RouteHintHop) Read(reader io.Reader) []Bolt11RouteHintHop {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]Bolt11RouteHintHop, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterBolt11RouteHintHopINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceBolt11RouteHintHop) Lower(value []Bolt11RouteHintHop) C.RustBuffer {
+	return LowerIntoRustBuffer[[]Bolt11RouteHintHop](c, value)
+}
+
+func (c FfiConverterSequenceBolt11RouteHintHop) Write(writer io.Writer, value []Bolt11RouteHintHop) {
+	if len(value) > math.MaxInt32 {
+		panic("[]Bolt11RouteHintHop is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterBolt11RouteHintHopINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceBolt11RouteHintHop struct{}
+
+func (FfiDestroyerSequenceBolt11RouteHintHop) Destroy(sequence []Bolt11RouteHintHop) {
+	for _, value := range sequence {
+		FfiDestroyerBolt11RouteHintHop{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceBolt12OfferBlindedPath struct{}
+
+var FfiConverterSequenceBolt12OfferBlindedPathINSTANCE = FfiConverterSequenceBolt12OfferBlindedPath{}
+
+func (c FfiConverterSequenceBolt12OfferBlindedPath) Lift(rb RustBufferI) []Bolt12OfferBlindedPath {
+	return LiftFromRustBuffer[[]Bolt12OfferBlindedPath](c, rb)
+}
+
+func (c FfiConverterSequenceBolt12OfferBlindedPath) Read(reader io.Reader) []Bolt12OfferBlindedPath {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]Bolt12OfferBlindedPath, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterBolt12OfferBlindedPathINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceBolt12OfferBlindedPath) Lower(value []Bolt12OfferBlindedPath) C.RustBuffer {
+	return LowerIntoRustBuffer[[]Bolt12OfferBlindedPath](c, value)
+}
+
+func (c FfiConverterSequenceBolt12OfferBlindedPath) Write(writer io.Writer, value []Bolt12OfferBlindedPath) {
+	if len(value) > math.MaxInt32 {
+		panic("[]Bolt12OfferBlindedPath is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterBolt12OfferBlindedPathINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceBolt12OfferBlindedPath struct{}
+
+func (FfiDestroyerSequenceBolt12OfferBlindedPath) Destroy(sequence []Bolt12OfferBlindedPath) {
+	for _, value := range sequence {
+		FfiDestroyerBolt12OfferBlindedPath{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceDepositInfo struct{}
+
+var FfiConverterSequenceDepositInfoINSTANCE = FfiConverterSequenceDepositInfo{}
+
+func (c FfiConverterSequenceDepositInfo) Lift(rb RustBufferI) []DepositInfo {
+	return LiftFromRustBuffer[[]DepositInfo](c, rb)
+}
+
+func (c FfiConverterSequenceDepositInfo) Read(reader io.Reader) []DepositInfo {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]DepositInfo, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterDepositInfoINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceDepositInfo) Lower(value []DepositInfo) C.RustBuffer {
+	return LowerIntoRustBuffer[[]DepositInfo](c, value)
+}
+
+func (c FfiConverterSequenceDepositInfo) Write(writer io.Writer, value []DepositInfo) {
+	if len(value) > math.MaxInt32 {
+		panic("[]DepositInfo is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterDepositInfoINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceDepositInfo struct{}
+
+func (FfiDestroyerSequenceDepositInfo) Destroy(sequence []DepositInfo) {
+	for _, value := range sequence {
+		FfiDestroyerDepositInfo{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceExternalInputParser struct{}
+
+var FfiConverterSequenceExternalInputParserINSTANCE = FfiConverterSequenceExternalInputParser{}
+
+func (c FfiConverterSequenceExternalInputParser) Lift(rb RustBufferI) []ExternalInputParser {
+	return LiftFromRustBuffer[[]ExternalInputParser](c, rb)
+}
+
+func (c FfiConverterSequenceExternalInputParser) Read(reader io.Reader) []ExternalInputParser {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]ExternalInputParser, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterExternalInputParserINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceExternalInputParser) Lower(value []ExternalInputParser) C.RustBuffer {
+	return LowerIntoRustBuffer[[]ExternalInputParser](c, value)
+}
+
+func (c FfiConverterSequenceExternalInputParser) Write(writer io.Writer, value []ExternalInputParser) {
+	if len(value) > math.MaxInt32 {
+		panic("[]ExternalInputParser is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterExternalInputParserINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceExternalInputParser struct{}
+
+func (FfiDestroyerSequenceExternalInputParser) Destroy(sequence []ExternalInputParser) {
+	for _, value := range sequence {
+		FfiDestroyerExternalInputParser{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceFiatCurrency struct{}
+
+var FfiConverterSequenceFiatCurrencyINSTANCE = FfiConverterSequenceFiatCurrency{}
+
+func (c FfiConverterSequenceFiatCurrency) Lift(rb RustBufferI) []FiatCurrency {
+	return LiftFromRustBuffer[[]FiatCurrency](c, rb)
+}
+
+func (c FfiConverterSequenceFiatCurrency) Read(reader io.Reader) []FiatCurrency {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]FiatCurrency, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterFiatCurrencyINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceFiatCurrency) Lower(value []FiatCurrency) C.RustBuffer {
+	return LowerIntoRustBuffer[[]FiatCurrency](c, value)
+}
+
+func (c FfiConverterSequenceFiatCurrency) Write(writer io.Writer, value []FiatCurrency) {
+	if len(value) > math.MaxInt32 {
+		panic("[]FiatCurrency is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterFiatCurrencyINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceFiatCurrency struct{}
+
+func (FfiDestroyerSequenceFiatCurrency) Destroy(sequence []FiatCurrency) {
+	for _, value := range sequence {
+		FfiDestroyerFiatCurrency{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceIncomingChange struct{}
+
+var FfiConverterSequenceIncomingChangeINSTANCE = FfiConverterSequenceIncomingChange{}
+
+func (c FfiConverterSequenceIncomingChange) Lift(rb RustBufferI) []IncomingChange {
+	return LiftFromRustBuffer[[]IncomingChange](c, rb)
+}
+
+func (c FfiConverterSequenceIncomingChange) Read(reader io.Reader) []IncomingChange {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]IncomingChange, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterIncomingChangeINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceIncomingChange) Lower(value []IncomingChange) C.RustBuffer {
+	return LowerIntoRustBuffer[[]IncomingChange](c, value)
+}
+
+func (c FfiConverterSequenceIncomingChange) Write(writer io.Writer, value []IncomingChange) {
+	if len(value) > math.MaxInt32 {
+		panic("[]IncomingChange is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterIncomingChangeINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceIncomingChange struct{}
+
+func (FfiDestroyerSequenceIncomingChange) Destroy(sequence []IncomingChange) {
+	for _, value := range sequence {
+		FfiDestroyerIncomingChange{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceInputType struct{}
+
+var FfiConverterSequenceInputTypeINSTANCE = FfiConverterSequenceInputType{}
+
+func (c FfiConverterSequenceInputType) Lift(rb RustBufferI) []InputType {
+	return LiftFromRustBuffer[[]InputType](c, rb)
+}
+
+func (c FfiConverterSequenceInputType) Read(reader io.Reader) []InputType {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]InputType, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterInputTypeINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceInputType) Lower(value []InputType) C.RustBuffer {
+	return LowerIntoRustBuffer[[]InputType](c, value)
+}
+
+func (c FfiConverterSequenceInputType) Write(writer io.Writer, value []InputType) {
+	if len(value) > math.MaxInt32 {
+		panic("[]InputType is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterInputTypeINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceInputType struct{}
+
+func (FfiDestroyerSequenceInputType) Destroy(sequence []InputType) {
+	for _, value := range sequence {
+		FfiDestroyerInputType{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceLocaleOverrides struct{}
+
+var FfiConverterSequenceLocaleOverridesINSTANCE = FfiConverterSequenceLocaleOverrides{}
+
+func (c FfiConverterSequenceLocaleOverrides) Lift(rb RustBufferI) []LocaleOverrides {
+	return LiftFromRustBuffer[[]LocaleOverrides](c, rb)
+}
+
+func (c FfiConverterSequenceLocaleOverrides) Read(reader io.Reader) []LocaleOverrides {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]LocaleOverrides, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterLocaleOverridesINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceLocaleOverrides) Lower(value []LocaleOverrides) C.RustBuffer {
+	return LowerIntoRustBuffer[[]LocaleOverrides](c, value)
+}
+
+func (c FfiConverterSequenceLocaleOverrides) Write(writer io.Writer, value []LocaleOverrides) {
+	if len(value) > math.MaxInt32 {
+		panic("[]LocaleOverrides is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterLocaleOverridesINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceLocaleOverrides struct{}
+
+func (FfiDestroyerSequenceLocaleOverrides) Destroy(sequence []LocaleOverrides) {
+	for _, value := range sequence {
+		FfiDestroyerLocaleOverrides{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceLocalizedName struct{}
+
+var FfiConverterSequenceLocalizedNameINSTANCE = FfiConverterSequenceLocalizedName{}
+
+func (c FfiConverterSequenceLocalizedName) Lift(rb RustBufferI) []LocalizedName {
+	return LiftFromRustBuffer[[]LocalizedName](c, rb)
+}
+
+func (c FfiConverterSequenceLocalizedName) Read(reader io.Reader) []LocalizedName {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]LocalizedName, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterLocalizedNameINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceLocalizedName) Lower(value []LocalizedName) C.RustBuffer {
+	return LowerIntoRustBuffer[[]LocalizedName](c, value)
+}
+
+func (c FfiConverterSequenceLocalizedName) Write(writer io.Writer, value []LocalizedName) {
+	if len(value) > math.MaxInt32 {
+		panic("[]LocalizedName is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterLocalizedNameINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceLocalizedName struct{}
+
+func (FfiDestroyerSequenceLocalizedName) Destroy(sequence []LocalizedName) {
+	for _, value := range sequence {
+		FfiDestroyerLocalizedName{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceOutgoingChange struct{}
+
+var FfiConverterSequenceOutgoingChangeINSTANCE = FfiConverterSequenceOutgoingChange{}
+
+func (c FfiConverterSequenceOutgoingChange) Lift(rb RustBufferI) []OutgoingChange {
+	return LiftFromRustBuffer[[]OutgoingChange](c, rb)
+}
+
+func (c FfiConverterSequenceOutgoingChange) Read(reader io.Reader) []OutgoingChange {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]OutgoingChange, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterOutgoingChangeINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceOutgoingChange) Lower(value []OutgoingChange) C.RustBuffer {
+	return LowerIntoRustBuffer[[]OutgoingChange](c, value)
+}
+
+func (c FfiConverterSequenceOutgoingChange) Write(writer io.Writer, value []OutgoingChange) {
+	if len(value) > math.MaxInt32 {
+		panic("[]OutgoingChange is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterOutgoingChangeINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceOutgoingChange struct{}
+
+func (FfiDestroyerSequenceOutgoingChange) Destroy(sequence []OutgoingChange) {
+	for _, value := range sequence {
+		FfiDestroyerOutgoingChange{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequencePayment struct{}
+
+var FfiConverterSequencePaymentINSTANCE = FfiConverterSequencePayment{}
+
+func (c FfiConverterSequencePayment) Lift(rb RustBufferI) []Payment {
+	return LiftFromRustBuffer[[]Payment](c, rb)
+}
+
+func (c FfiConverterSequencePayment) Read(reader io.Reader) []Payment {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]Payment, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterPaymentINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequencePayment) Lower(value []Payment) C.RustBuffer {
+	return LowerIntoRustBuffer[[]Payment](c, value)
+}
+
+func (c FfiConverterSequencePayment) Write(writer io.Writer, value []Payment) {
+	if len(value) > math.MaxInt32 {
+		panic("[]Payment is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterPaymentINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequencePayment struct{}
+
+func (FfiDestroyerSequencePayment) Destroy(sequence []Payment) {
+	for _, value := range sequence {
+		FfiDestroyerPayment{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequencePaymentStatus struct{}
+
+var FfiConverterSequencePaymentStatusINSTANCE = FfiConverterSequencePaymentStatus{}
+
+func (c FfiConverterSequencePaymentStatus) Lift(rb RustBufferI) []PaymentStatus {
+	return LiftFromRustBuffer[[]PaymentStatus](c, rb)
+}
+
+func (c FfiConverterSequencePaymentStatus) Read(reader io.Reader) []PaymentStatus {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]PaymentStatus, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterPaymentStatusINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequencePaymentStatus) Lower(value []PaymentStatus) C.RustBuffer {
+	return LowerIntoRustBuffer[[]PaymentStatus](c, value)
+}
+
+func (c FfiConverterSequencePaymentStatus) Write(writer io.Writer, value []PaymentStatus) {
+	if len(value) > math.MaxInt32 {
+		panic("[]PaymentStatus is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterPaymentStatusINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequencePaymentStatus struct{}
+
+func (FfiDestroyerSequencePaymentStatus) Destroy(sequence []PaymentStatus) {
+	for _, value := range sequence {
+		FfiDestroyerPaymentStatus{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequencePaymentType struct{}
+
+var FfiConverterSequencePaymentTypeINSTANCE = FfiConverterSequencePaymentType{}
+
+func (c FfiConverterSequencePaymentType) Lift(rb RustBufferI) []PaymentType {
+	return LiftFromRustBuffer[[]PaymentType](c, rb)
+}
+
+func (c FfiConverterSequencePaymentType) Read(reader io.Reader) []PaymentType {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]PaymentType, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterPaymentTypeINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequencePaymentType) Lower(value []PaymentType) C.RustBuffer {
+	return LowerIntoRustBuffer[[]PaymentType](c, value)
+}
+
+func (c FfiConverterSequencePaymentType) Write(writer io.Writer, value []PaymentType) {
+	if len(value) > math.MaxInt32 {
+		panic("[]PaymentType is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterPaymentTypeINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequencePaymentType struct{}
+
+func (FfiDestroyerSequencePaymentType) Destroy(sequence []PaymentType) {
+	for _, value := range sequence {
+		FfiDestroyerPaymentType{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceProvisionalPayment struct{}
+
+var FfiConverterSequenceProvisionalPaymentINSTANCE = FfiConverterSequenceProvisionalPayment{}
+
+func (c FfiConverterSequenceProvisionalPayment) Lift(rb RustBufferI) []ProvisionalPayment {
+	return LiftFromRustBuffer[[]ProvisionalPayment](c, rb)
+}
+
+func (c FfiConverterSequenceProvisionalPayment) Read(reader io.Reader) []ProvisionalPayment {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]ProvisionalPayment, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterProvisionalPaymentINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceProvisionalPayment) Lower(value []ProvisionalPayment) C.RustBuffer {
+	return LowerIntoRustBuffer[[]ProvisionalPayment](c, value)
+}
+
+func (c FfiConverterSequenceProvisionalPayment) Write(writer io.Writer, value []ProvisionalPayment) {
+	if len(value) > math.MaxInt32 {
+		panic("[]ProvisionalPayment is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterProvisionalPaymentINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceProvisionalPayment struct{}
+
+func (FfiDestroyerSequenceProvisionalPayment) Destroy(sequence []ProvisionalPayment) {
+	for _, value := range sequence {
+		FfiDestroyerProvisionalPayment{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceRate struct{}
+
+var FfiConverterSequenceRateINSTANCE = FfiConverterSequenceRate{}
+
+func (c FfiConverterSequenceRate) Lift(rb RustBufferI) []Rate {
+	return LiftFromRustBuffer[[]Rate](c, rb)
+}
+
+func (c FfiConverterSequenceRate) Read(reader io.Reader) []Rate {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]Rate, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterRateINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceRate) Lower(value []Rate) C.RustBuffer {
+	return LowerIntoRustBuffer[[]Rate](c, value)
+}
+
+func (c FfiConverterSequenceRate) Write(writer io.Writer, value []Rate) {
+	if len(value) > math.MaxInt32 {
+		panic("[]Rate is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterRateINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceRate struct{}
+
+func (FfiDestroyerSequenceRate) Destroy(sequence []Rate) {
+	for _, value := range sequence {
+		FfiDestroyerRate{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceRecord struct{}
+
+var FfiConverterSequenceRecordINSTANCE = FfiConverterSequenceRecord{}
+
+func (c FfiConverterSequenceRecord) Lift(rb RustBufferI) []Record {
+	return LiftFromRustBuffer[[]Record](c, rb)
+}
+
+func (c FfiConverterSequenceRecord) Read(reader io.Reader) []Record {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]Record, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterRecordINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceRecord) Lower(value []Record) C.RustBuffer {
+	return LowerIntoRustBuffer[[]Record](c, value)
+}
+
+func (c FfiConverterSequenceRecord) Write(writer io.Writer, value []Record) {
+	if len(value) > math.MaxInt32 {
+		panic("[]Record is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterRecordINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceRecord struct{}
+
+func (FfiDestroyerSequenceRecord) Destroy(sequence []Record) {
+	for _, value := range sequence {
+		FfiDestroyerRecord{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceSetLnurlMetadataItem struct{}
+
+var FfiConverterSequenceSetLnurlMetadataItemINSTANCE = FfiConverterSequenceSetLnurlMetadataItem{}
+
+func (c FfiConverterSequenceSetLnurlMetadataItem) Lift(rb RustBufferI) []SetLnurlMetadataItem {
+	return LiftFromRustBuffer[[]SetLnurlMetadataItem](c, rb)
+}
+
+func (c FfiConverterSequenceSetLnurlMetadataItem) Read(reader io.Reader) []SetLnurlMetadataItem {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]SetLnurlMetadataItem, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterSetLnurlMetadataItemINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceSetLnurlMetadataItem) Lower(value []SetLnurlMetadataItem) C.RustBuffer {
+	return LowerIntoRustBuffer[[]SetLnurlMetadataItem](c, value)
+}
+
+func (c FfiConverterSequenceSetLnurlMetadataItem) Write(writer io.Writer, value []SetLnurlMetadataItem) {
+	if len(value) > math.MaxInt32 {
+		panic("[]SetLnurlMetadataItem is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterSetLnurlMetadataItemINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceSetLnurlMetadataItem struct{}
+
+func (FfiDestroyerSequenceSetLnurlMetadataItem) Destroy(sequence []SetLnurlMetadataItem) {
+	for _, value := range sequence {
+		FfiDestroyerSetLnurlMetadataItem{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceSparkHtlcStatus struct{}
+
+var FfiConverterSequenceSparkHtlcStatusINSTANCE = FfiConverterSequenceSparkHtlcStatus{}
+
+func (c FfiConverterSequenceSparkHtlcStatus) Lift(rb RustBufferI) []SparkHtlcStatus {
+	return LiftFromRustBuffer[[]SparkHtlcStatus](c, rb)
+}
+
+func (c FfiConverterSequenceSparkHtlcStatus) Read(reader io.Reader) []SparkHtlcStatus {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]SparkHtlcStatus, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterSparkHtlcStatusINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceSparkHtlcStatus) Lower(value []SparkHtlcStatus) C.RustBuffer {
+	return LowerIntoRustBuffer[[]SparkHtlcStatus](c, value)
+}
+
+func (c FfiConverterSequenceSparkHtlcStatus) Write(writer io.Writer, value []SparkHtlcStatus) {
+	if len(value) > math.MaxInt32 {
+		panic("[]SparkHtlcStatus is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterSparkHtlcStatusINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceSparkHtlcStatus struct{}
+
+func (FfiDestroyerSequenceSparkHtlcStatus) Destroy(sequence []SparkHtlcStatus) {
+	for _, value := range sequence {
+		FfiDestroyerSparkHtlcStatus{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceTokenMetadata struct{}
+
+var FfiConverterSequenceTokenMetadataINSTANCE = FfiConverterSequenceTokenMetadata{}
+
+func (c FfiConverterSequenceTokenMetadata) Lift(rb RustBufferI) []TokenMetadata {
+	return LiftFromRustBuffer[[]TokenMetadata](c, rb)
+}
+
+func (c FfiConverterSequenceTokenMetadata) Read(reader io.Reader) []TokenMetadata {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]TokenMetadata, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterTokenMetadataINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceTokenMetadata) Lower(value []TokenMetadata) C.RustBuffer {
+	return LowerIntoRustBuffer[[]TokenMetadata](c, value)
+}
+
+func (c FfiConverterSequenceTokenMetadata) Write(writer io.Writer, value []TokenMetadata) {
+	if len(value) > math.MaxInt32 {
+		panic("[]TokenMetadata is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterTokenMetadataINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceTokenMetadata struct{}
+
+func (FfiDestroyerSequenceTokenMetadata) Destroy(sequence []TokenMetadata) {
+	for _, value := range sequence {
+		FfiDestroyerTokenMetadata{}.Destroy(value)
+	}
+}
+
+type FfiConverterSequenceUtxo struct{}
+
+var FfiConverterSequenceUtxoINSTANCE = FfiConverterSequenceUtxo{}
+
+func (c FfiConverterSequenceUtxo) Lift(rb RustBufferI) []Utxo {
+	return LiftFromRustBuffer[[]Utxo](c, rb)
+}
+
+func (c FfiConverterSequenceUtxo) Read(reader io.Reader) []Utxo {
+	length := readInt32(reader)
+	if length == 0 {
+		return nil
+	}
+	result := make([]Utxo, 0, length)
+	for i := int32(0); i < length; i++ {
+		result = append(result, FfiConverterUtxoINSTANCE.Read(reader))
+	}
+	return result
+}
+
+func (c FfiConverterSequenceUtxo) Lower(value []Utxo) C.RustBuffer {
+	return LowerIntoRustBuffer[[]Utxo](c, value)
+}
+
+func (c FfiConverterSequenceUtxo) Write(writer io.Writer, value []Utxo) {
+	if len(value) > math.MaxInt32 {
+		panic("[]Utxo is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(value)))
+	for _, item := range value {
+		FfiConverterUtxoINSTANCE.Write(writer, item)
+	}
+}
+
+type FfiDestroyerSequenceUtxo struct{}
+
+func (FfiDestroyerSequenceUtxo) Destroy(sequence []Utxo) {
+	for _, value := range sequence {
+		FfiDestroyerUtxo{}.Destroy(value)
+	}
+}
+
+type FfiConverterMapStringString struct{}
+
+var FfiConverterMapStringStringINSTANCE = FfiConverterMapStringString{}
+
+func (c FfiConverterMapStringString) Lift(rb RustBufferI) map[string]string {
+	return LiftFromRustBuffer[map[string]string](c, rb)
+}
+
+func (_ FfiConverterMapStringString) Read(reader io.Reader) map[string]string {
+	result := make(map[string]string)
+	length := readInt32(reader)
+	for i := int32(0); i < length; i++ {
+		key := FfiConverterStringINSTANCE.Read(reader)
+		value := FfiConverterStringINSTANCE.Read(reader)
+		result[key] = value
+	}
+	return result
+}
+
+func (c FfiConverterMapStringString) Lower(value map[string]string) C.RustBuffer {
+	return LowerIntoRustBuffer[map[string]string](c, value)
+}
+
+func (_ FfiConverterMapStringString) Write(writer io.Writer, mapValue map[string]string) {
+	if len(mapValue) > math.MaxInt32 {
+		panic("map[string]string is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(mapValue)))
+	for key, value := range mapValue {
+		FfiConverterStringINSTANCE.Write(writer, key)
+		FfiConverterStringINSTANCE.Write(writer, value)
+	}
+}
+
+type FfiDestroyerMapStringString struct{}
+
+func (_ FfiDestroyerMapStringString) Destroy(mapValue map[string]string) {
+	for key, value := range mapValue {
+		FfiDestroyerString{}.Destroy(key)
+		FfiDestroyerString{}.Destroy(value)
+	}
+}
+
+type FfiConverterMapStringTokenBalance struct{}
+
+var FfiConverterMapStringTokenBalanceINSTANCE = FfiConverterMapStringTokenBalance{}
+
+func (c FfiConverterMapStringTokenBalance) Lift(rb RustBufferI) map[string]TokenBalance {
+	return LiftFromRustBuffer[map[string]TokenBalance](c, rb)
+}
+
+func (_ FfiConverterMapStringTokenBalance) Read(reader io.Reader) map[string]TokenBalance {
+	result := make(map[string]TokenBalance)
+	length := readInt32(reader)
+	for i := int32(0); i < length; i++ {
+		key := FfiConverterStringINSTANCE.Read(reader)
+		value := FfiConverterTokenBalanceINSTANCE.Read(reader)
+		result[key] = value
+	}
+	return result
+}
+
+func (c FfiConverterMapStringTokenBalance) Lower(value map[string]TokenBalance) C.RustBuffer {
+	return LowerIntoRustBuffer[map[string]TokenBalance](c, value)
+}
+
+func (_ FfiConverterMapStringTokenBalance) Write(writer io.Writer, mapValue map[string]TokenBalance) {
+	if len(mapValue) > math.MaxInt32 {
+		panic("map[string]TokenBalance is too large to fit into Int32")
+	}
+
+	writeInt32(writer, int32(len(mapValue)))
+	for key, value := range mapValue {
+		FfiConverterStringINSTANCE.Write(writer, key)
+		FfiConverterTokenBalanceINSTANCE.Write(writer, value)
+	}
+}
+
+type FfiDestroyerMapStringTokenBalance struct{}
+
+func (_ FfiDestroyerMapStringTokenBalance) Destroy(mapValue map[string]TokenBalance) {
+	for key, value := range mapValue {
+		FfiDestroyerString{}.Destroy(key)
+		FfiDestroyerTokenBalance{}.Destroy(value)
+	}
+}
+
+/**
+ * Typealias from the type name used in the UDL file to the custom type.  This
+ * is needed because the UDL type name is used in function/method signatures.
+ */
+type u128 = *big.Int
+type FfiConverterTypeu128 struct{}
+
+var FfiConverterTypeu128INSTANCE = FfiConverterTypeu128{}
+
+func (FfiConverterTypeu128) Lift(value RustBufferI) u128 {
+	builtinValue := FfiConverterStringINSTANCE.Lift(value)
+	return func() *big.Int { res, _ := new(big.Int).SetString(builtinValue, 10); return res }()
+}
+
+func (FfiConverterTypeu128) Read(reader io.Reader) u128 {
+	builtinValue := FfiConverterStringINSTANCE.Read(reader)
+	return func() *big.Int { res, _ := new(big.Int).SetString(builtinValue, 10); return res }()
+}
+
+func (FfiConverterTypeu128) Lower(value u128) C.RustBuffer {
+	builtinValue := value.String()
+	return FfiConverterStringINSTANCE.Lower(builtinValue)
+}
+
+func (FfiConverterTypeu128) Write(writer io.Writer, value u128) {
+	builtinValue := value.String()
+	FfiConverterStringINSTANCE.Write(writer, builtinValue)
+}
+
+type FfiDestroyerTypeu128 struct{}
+
+func (FfiDestroyerTypeu128) Destroy(value u128) {}
+
+const (
+	uniffiRustFuturePollReady      int8 = 0
+	uniffiRustFuturePollMaybeReady int8 = 1
+)
+
+type rustFuturePollFunc func(C.uint64_t, C.UniffiRustFutureContinuationCallback, C.uint64_t)
+type rustFutureCompleteFunc[T any] func(C.uint64_t, *C.RustCallStatus) T
+type rustFutureFreeFunc func(C.uint64_t)
+
+//export breez_sdk_spark_uniffiFutureContinuationCallback
+func breez_sdk_spark_uniffiFutureContinuationCallback(data C.uint64_t, pollResult C.int8_t) {
+	h := cgo.Handle(uintptr(data))
+	waiter := h.Value().(chan int8)
+	waiter <- int8(pollResult)
+}
+
+func uniffiRustCallAsync[E any, T any, F any](
+	errConverter BufReader[*E],
+	completeFunc rustFutureCompleteFunc[F],
+	liftFunc func(F) T,
+	rustFuture C.uint64_t,
+	pollFunc rustFuturePollFunc,
+	freeFunc rustFutureFreeFunc,
+) (T, error) {
+	defer freeFunc(rustFuture)
+
+	pollResult := int8(-1)
+	waiter := make(chan int8, 1)
+
+	chanHandle := cgo.NewHandle(waiter)
+	defer chanHandle.Delete()
+
+	for pollResult != uniffiRustFuturePollReady {
+		pollFunc(
+			rustFuture,
+			(C.UniffiRustFutureContinuationCallback)(C.breez_sdk_spark_uniffiFutureContinuationCallback),
+			C.uint64_t(chanHandle),
+		)
+		pollResult = <-waiter
+	}
+
+	var goValue T
+	var ffiValue F
+	var err *E
+
+	ffiValue, err = rustCallWithError(errConverter, func(status *C.RustCallStatus) F {
+		return completeFunc(rustFuture, status)
+	})
+	if err != nil {
+		return goValue, any(err).(NativeError).AsError()
+	}
+	return liftFunc(ffiValue), nil
+}
+
+//export breez_sdk_spark_uniffiFreeGorutine
+func breez_sdk_spark_uniffiFreeGorutine(data C.uint64_t) {
+	handle := cgo.Handle(uintptr(data))
+	defer handle.Delete()
+
+	guard := handle.Value().(chan struct{})
+	guard <- struct{}{}
+}
+
+// Connects to the Spark network using the provided configuration and mnemonic.
+//
+// # Arguments
+//
+// * `request` - The connection request object
+//
+// # Returns
+//
+// Result containing either the initialized `BreezSdk` or an `SdkError`
+func Connect(request ConnectRequest) (*BreezSdk, error) {
+	res, err := uniffiRustCallAsync[SdkError](
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) unsafe.Pointer {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_pointer(handle, status)
+			return res
+		},
+		// liftFn
+		func(ffi unsafe.Pointer) *BreezSdk {
+			return FfiConverterBreezSdkINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_func_connect(FfiConverterConnectRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_pointer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_pointer(handle)
+		},
+	)
+
+	return res, err
+}
+
+func DefaultConfig(network Network) Config {
+	return FfiConverterConfigINSTANCE.Lift(rustCall(func(_uniffiStatus *C.RustCallStatus) RustBufferI {
+		return GoRustBuffer{
+			inner: C.uniffi_breez_sdk_spark_fn_func_default_config(FfiConverterNetworkINSTANCE.Lower(network), _uniffiStatus),
+		}
+	}))
+}
+
+func InitLogging(logDir *string, appLogger *Logger, logFilter *string) error {
+	_, _uniffiErr := rustCallWithError[SdkError](FfiConverterSdkError{}, func(_uniffiStatus *C.RustCallStatus) bool {
+		C.uniffi_breez_sdk_spark_fn_func_init_logging(FfiConverterOptionalStringINSTANCE.Lower(logDir), FfiConverterOptionalCallbackInterfaceLoggerINSTANCE.Lower(appLogger), FfiConverterOptionalStringINSTANCE.Lower(logFilter), _uniffiStatus)
+		return false
+	})
+	return _uniffiErr.AsError()
+}
@@ -0,0 +1,371 @@
+package breez_sdk_spark
+
+import "context"
+
+// BitcoinChainServiceCtx is the context-aware counterpart of
+// [BitcoinChainService]. Wrap an implementation with
+// [NewBitcoinChainServiceFromCtx] before handing it to the SDK.
+type BitcoinChainServiceCtx interface {
+	GetAddressUtxos(ctx context.Context, address string) ([]Utxo, error)
+	GetTransactionStatus(ctx context.Context, txid string) (TxStatus, error)
+	GetTransactionHex(ctx context.Context, txid string) (string, error)
+	BroadcastTransaction(ctx context.Context, tx string) error
+	RecommendedFees(ctx context.Context) (RecommendedFees, error)
+}
+
+// NewBitcoinChainServiceFromCtx adapts s to [BitcoinChainService]. Calls
+// coming from the SDK get a context that is cancelled when the SDK abandons
+// them; direct Go calls through the returned value use context.Background().
+func NewBitcoinChainServiceFromCtx(s BitcoinChainServiceCtx) BitcoinChainService {
+	return bitcoinChainServiceCtxAdapter{s}
+}
+
+type bitcoinChainServiceCtxAdapter struct {
+	inner BitcoinChainServiceCtx
+}
+
+func (a bitcoinChainServiceCtxAdapter) GetAddressUtxos(address string) ([]Utxo, error) {
+	return a.inner.GetAddressUtxos(context.Background(), address)
+}
+
+func (a bitcoinChainServiceCtxAdapter) GetTransactionStatus(txid string) (TxStatus, error) {
+	return a.inner.GetTransactionStatus(context.Background(), txid)
+}
+
+func (a bitcoinChainServiceCtxAdapter) GetTransactionHex(txid string) (string, error) {
+	return a.inner.GetTransactionHex(context.Background(), txid)
+}
+
+func (a bitcoinChainServiceCtxAdapter) BroadcastTransaction(tx string) error {
+	return a.inner.BroadcastTransaction(context.Background(), tx)
+}
+
+func (a bitcoinChainServiceCtxAdapter) RecommendedFees() (RecommendedFees, error) {
+	return a.inner.RecommendedFees(context.Background())
+}
+
+type bitcoinChainServiceIgnoringCtx struct {
+	inner BitcoinChainService
+}
+
+func (s bitcoinChainServiceIgnoringCtx) GetAddressUtxos(_ context.Context, address string) ([]Utxo, error) {
+	return s.inner.GetAddressUtxos(address)
+}
+
+func (s bitcoinChainServiceIgnoringCtx) GetTransactionStatus(_ context.Context, txid string) (TxStatus, error) {
+	return s.inner.GetTransactionStatus(txid)
+}
+
+func (s bitcoinChainServiceIgnoringCtx) GetTransactionHex(_ context.Context, txid string) (string, error) {
+	return s.inner.GetTransactionHex(txid)
+}
+
+func (s bitcoinChainServiceIgnoringCtx) BroadcastTransaction(_ context.Context, tx string) error {
+	return s.inner.BroadcastTransaction(tx)
+}
+
+func (s bitcoinChainServiceIgnoringCtx) RecommendedFees(_ context.Context) (RecommendedFees, error) {
+	return s.inner.RecommendedFees()
+}
+
+// AsBitcoinChainServiceCtx is the reverse of [NewBitcoinChainServiceFromCtx],
+// for decorators that accept either kind of service. A value returned by
+// NewBitcoinChainServiceFromCtx is unwrapped; any other s ignores the
+// context it is given.
+func AsBitcoinChainServiceCtx(s BitcoinChainService) BitcoinChainServiceCtx {
+	return bitcoinChainServiceWithCtx(s)
+}
+
+// bitcoinChainServiceWithCtx returns the view of s used by the callback dispatchers.
+func bitcoinChainServiceWithCtx(s BitcoinChainService) BitcoinChainServiceCtx {
+	if a, ok := s.(bitcoinChainServiceCtxAdapter); ok {
+		return a.inner
+	}
+	return bitcoinChainServiceIgnoringCtx{s}
+}
+
+// FiatServiceCtx is the context-aware counterpart of [FiatService]. Wrap an
+// implementation with [NewFiatServiceFromCtx] before handing it to the SDK.
+type FiatServiceCtx interface {
+	FetchFiatCurrencies(ctx context.Context) ([]FiatCurrency, error)
+	FetchFiatRates(ctx context.Context) ([]Rate, error)
+}
+
+// NewFiatServiceFromCtx adapts s to [FiatService]. Calls coming from the SDK
+// get a context that is cancelled when the SDK abandons them; direct Go
+// calls through the returned value use context.Background().
+func NewFiatServiceFromCtx(s FiatServiceCtx) FiatService {
+	return fiatServiceCtxAdapter{s}
+}
+
+type fiatServiceCtxAdapter struct {
+	inner FiatServiceCtx
+}
+
+func (a fiatServiceCtxAdapter) FetchFiatCurrencies() ([]FiatCurrency, error) {
+	return a.inner.FetchFiatCurrencies(context.Background())
+}
+
+func (a fiatServiceCtxAdapter) FetchFiatRates() ([]Rate, error) {
+	return a.inner.FetchFiatRates(context.Background())
+}
+
+type fiatServiceIgnoringCtx struct {
+	inner FiatService
+}
+
+func (s fiatServiceIgnoringCtx) FetchFiatCurrencies(_ context.Context) ([]FiatCurrency, error) {
+	return s.inner.FetchFiatCurrencies()
+}
+
+func (s fiatServiceIgnoringCtx) FetchFiatRates(_ context.Context) ([]Rate, error) {
+	return s.inner.FetchFiatRates()
+}
+
+// AsFiatServiceCtx is the reverse of [NewFiatServiceFromCtx], for
+// decorators that accept either kind of service. A value returned by
+// NewFiatServiceFromCtx is unwrapped; any other s ignores the context it is
+// given.
+func AsFiatServiceCtx(s FiatService) FiatServiceCtx {
+	return fiatServiceWithCtx(s)
+}
+
+// fiatServiceWithCtx returns the view of s used by the callback dispatchers.
+func fiatServiceWithCtx(s FiatService) FiatServiceCtx {
+	if a, ok := s.(fiatServiceCtxAdapter); ok {
+		return a.inner
+	}
+	return fiatServiceIgnoringCtx{s}
+}
+
+// RestClientCtx is the context-aware counterpart of [RestClient]. Wrap an
+// implementation with [NewRestClientFromCtx] before handing it to the SDK.
+type RestClientCtx interface {
+	GetRequest(ctx context.Context, url string, headers *map[string]string) (RestResponse, error)
+	PostRequest(ctx context.Context, url string, headers *map[string]string, body *string) (RestResponse, error)
+	DeleteRequest(ctx context.Context, url string, headers *map[string]string, body *string) (RestResponse, error)
+}
+
+// NewRestClientFromCtx adapts s to [RestClient]. Calls coming from the SDK
+// get a context that is cancelled when the SDK abandons them; direct Go
+// calls through the returned value use context.Background().
+func NewRestClientFromCtx(s RestClientCtx) RestClient {
+	return restClientCtxAdapter{s}
+}
+
+type restClientCtxAdapter struct {
+	inner RestClientCtx
+}
+
+func (a restClientCtxAdapter) GetRequest(url string, headers *map[string]string) (RestResponse, error) {
+	return a.inner.GetRequest(context.Background(), url, headers)
+}
+
+func (a restClientCtxAdapter) PostRequest(url string, headers *map[string]string, body *string) (RestResponse, error) {
+	return a.inner.PostRequest(context.Background(), url, headers, body)
+}
+
+func (a restClientCtxAdapter) DeleteRequest(url string, headers *map[string]string, body *string) (RestResponse, error) {
+	return a.inner.DeleteRequest(context.Background(), url, headers, body)
+}
+
+type restClientIgnoringCtx struct {
+	inner RestClient
+}
+
+func (s restClientIgnoringCtx) GetRequest(_ context.Context, url string, headers *map[string]string) (RestResponse, error) {
+	return s.inner.GetRequest(url, headers)
+}
+
+func (s restClientIgnoringCtx) PostRequest(_ context.Context, url string, headers *map[string]string, body *string) (RestResponse, error) {
+	return s.inner.PostRequest(url, headers, body)
+}
+
+func (s restClientIgnoringCtx) DeleteRequest(_ context.Context, url string, headers *map[string]string, body *string) (RestResponse, error) {
+	return s.inner.DeleteRequest(url, headers, body)
+}
+
+// AsRestClientCtx is the reverse of [NewRestClientFromCtx], for decorators
+// that accept either kind of client. A value returned by
+// NewRestClientFromCtx is unwrapped; any other s ignores the context it is
+// given.
+func AsRestClientCtx(s RestClient) RestClientCtx {
+	return restClientWithCtx(s)
+}
+
+// restClientWithCtx returns the view of s used by the callback dispatchers.
+func restClientWithCtx(s RestClient) RestClientCtx {
+	if a, ok := s.(restClientCtxAdapter); ok {
+		return a.inner
+	}
+	return restClientIgnoringCtx{s}
+}
+
+// StorageCtx is the context-aware counterpart of [Storage]. Wrap an
+// implementation with [NewStorageFromCtx] before handing it to the SDK.
+type StorageCtx interface {
+	DeleteCachedItem(ctx context.Context, key string) error
+	GetCachedItem(ctx context.Context, key string) (*string, error)
+	SetCachedItem(ctx context.Context, key string, value string) error
+	ListPayments(ctx context.Context, request ListPaymentsRequest) ([]Payment, error)
+	InsertPayment(ctx context.Context, payment Payment) error
+	SetPaymentMetadata(ctx context.Context, paymentId string, metadata PaymentMetadata) error
+	GetPaymentById(ctx context.Context, id string) (Payment, error)
+	GetPaymentByInvoice(ctx context.Context, invoice string) (*Payment, error)
+	AddDeposit(ctx context.Context, txid string, vout uint32, amountSats uint64) error
+	DeleteDeposit(ctx context.Context, txid string, vout uint32) error
+	ListDeposits(ctx context.Context) ([]DepositInfo, error)
+	UpdateDeposit(ctx context.Context, txid string, vout uint32, payload UpdateDepositPayload) error
+	SetLnurlMetadata(ctx context.Context, metadata []SetLnurlMetadataItem) error
+}
+
+// NewStorageFromCtx adapts s to [Storage]. Calls coming from the SDK get a
+// context that is cancelled when the SDK abandons them; direct Go calls
+// through the returned value use context.Background().
+func NewStorageFromCtx(s StorageCtx) Storage {
+	return storageCtxAdapter{s}
+}
+
+type storageCtxAdapter struct {
+	inner StorageCtx
+}
+
+func (a storageCtxAdapter) DeleteCachedItem(key string) error {
+	return a.inner.DeleteCachedItem(context.Background(), key)
+}
+
+func (a storageCtxAdapter) GetCachedItem(key string) (*string, error) {
+	return a.inner.GetCachedItem(context.Background(), key)
+}
+
+func (a storageCtxAdapter) SetCachedItem(key string, value string) error {
+	return a.inner.SetCachedItem(context.Background(), key, value)
+}
+
+func (a storageCtxAdapter) ListPayments(request ListPaymentsRequest) ([]Payment, error) {
+	return a.inner.ListPayments(context.Background(), request)
+}
+
+func (a storageCtxAdapter) InsertPayment(payment Payment) error {
+	return a.inner.InsertPayment(context.Background(), payment)
+}
+
+func (a storageCtxAdapter) SetPaymentMetadata(paymentId string, metadata PaymentMetadata) error {
+	return a.inner.SetPaymentMetadata(context.Background(), paymentId, metadata)
+}
+
+func (a storageCtxAdapter) GetPaymentById(id string) (Payment, error) {
+	return a.inner.GetPaymentById(context.Background(), id)
+}
+
+func (a storageCtxAdapter) GetPaymentByInvoice(invoice string) (*Payment, error) {
+	return a.inner.GetPaymentByInvoice(context.Background(), invoice)
+}
+
+func (a storageCtxAdapter) AddDeposit(txid string, vout uint32, amountSats uint64) error {
+	return a.inner.AddDeposit(context.Background(), txid, vout, amountSats)
+}
+
+func (a storageCtxAdapter) DeleteDeposit(txid string, vout uint32) error {
+	return a.inner.DeleteDeposit(context.Background(), txid, vout)
+}
+
+func (a storageCtxAdapter) ListDeposits() ([]DepositInfo, error) {
+	return a.inner.ListDeposits(context.Background())
+}
+
+func (a storageCtxAdapter) UpdateDeposit(txid string, vout uint32, payload UpdateDepositPayload) error {
+	return a.inner.UpdateDeposit(context.Background(), txid, vout, payload)
+}
+
+func (a storageCtxAdapter) SetLnurlMetadata(metadata []SetLnurlMetadataItem) error {
+	return a.inner.SetLnurlMetadata(context.Background(), metadata)
+}
+
+type storageIgnoringCtx struct {
+	inner Storage
+}
+
+func (s storageIgnoringCtx) DeleteCachedItem(_ context.Context, key string) error {
+	return s.inner.DeleteCachedItem(key)
+}
+
+func (s storageIgnoringCtx) GetCachedItem(_ context.Context, key string) (*string, error) {
+	return s.inner.GetCachedItem(key)
+}
+
+func (s storageIgnoringCtx) SetCachedItem(_ context.Context, key string, value string) error {
+	return s.inner.SetCachedItem(key, value)
+}
+
+func (s storageIgnoringCtx) ListPayments(_ context.Context, request ListPaymentsRequest) ([]Payment, error) {
+	return s.inner.ListPayments(request)
+}
+
+func (s storageIgnoringCtx) InsertPayment(_ context.Context, payment Payment) error {
+	return s.inner.InsertPayment(payment)
+}
+
+func (s storageIgnoringCtx) SetPaymentMetadata(_ context.Context, paymentId string, metadata PaymentMetadata) error {
+	return s.inner.SetPaymentMetadata(paymentId, metadata)
+}
+
+func (s storageIgnoringCtx) GetPaymentById(_ context.Context, id string) (Payment, error) {
+	return s.inner.GetPaymentById(id)
+}
+
+func (s storageIgnoringCtx) GetPaymentByInvoice(_ context.Context, invoice string) (*Payment, error) {
+	return s.inner.GetPaymentByInvoice(invoice)
+}
+
+func (s storageIgnoringCtx) AddDeposit(_ context.Context, txid string, vout uint32, amountSats uint64) error {
+	return s.inner.AddDeposit(txid, vout, amountSats)
+}
+
+func (s storageIgnoringCtx) DeleteDeposit(_ context.Context, txid string, vout uint32) error {
+	return s.inner.DeleteDeposit(txid, vout)
+}
+
+func (s storageIgnoringCtx) ListDeposits(_ context.Context) ([]DepositInfo, error) {
+	return s.inner.ListDeposits()
+}
+
+func (s storageIgnoringCtx) UpdateDeposit(_ context.Context, txid string, vout uint32, payload UpdateDepositPayload) error {
+	return s.inner.UpdateDeposit(txid, vout, payload)
+}
+
+func (s storageIgnoringCtx) SetLnurlMetadata(_ context.Context, metadata []SetLnurlMetadataItem) error {
+	return s.inner.SetLnurlMetadata(metadata)
+}
+
+// AsStorageCtx is the reverse of [NewStorageFromCtx], for decorators that
+// accept either kind of storage. A value returned by NewStorageFromCtx is
+// unwrapped; any other s ignores the context it is given.
+func AsStorageCtx(s Storage) StorageCtx {
+	return storageWithCtx(s)
+}
+
+// storageWithCtx returns the view of s used by the callback dispatchers.
+func storageWithCtx(s Storage) StorageCtx {
+	if a, ok := s.(storageCtxAdapter); ok {
+		return a.inner
+	}
+	return storageIgnoringCtx{s}
+}
+
+// uniffiForeignFutureCtx returns the context of a callback dispatched for a
+// foreign future. It waits for the callback's result, which it hands to
+// complete, or for Rust to free the future, signalled on cancel; the
+// context is cancelled when either happens.
+func uniffiForeignFutureCtx[R any](cancel <-chan struct{}, result <-chan R, complete func(R)) context.Context {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	go func() {
+		defer cancelCtx()
+		select {
+		case <-cancel:
+		case res := <-result:
+			complete(res)
+		}
+	}()
+	return ctx
+}
@@ -0,0 +1,116 @@
+package breez_sdk_spark
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fiatServiceCtx blocks until its context is done.
+type fiatServiceCtx struct {
+	called chan struct{}
+}
+
+func (s fiatServiceCtx) FetchFiatCurrencies(ctx context.Context) ([]FiatCurrency, error) {
+	close(s.called)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s fiatServiceCtx) FetchFiatRates(context.Context) ([]Rate, error) {
+	return []Rate{{Coin: "USD", Value: 100_000}}, nil
+}
+
+// fiatService ignores contexts.
+type fiatService struct{}
+
+func (fiatService) FetchFiatCurrencies() ([]FiatCurrency, error) { return nil, nil }
+
+func (fiatService) FetchFiatRates() ([]Rate, error) {
+	return []Rate{{Coin: "EUR", Value: 90_000}}, nil
+}
+
+// dispatch runs call the way the callback dispatchers do, returning the
+// channel the result is completed on and the cancel channel that freeing
+// the foreign future signals.
+func dispatch[R any](call func(ctx context.Context) R) (<-chan R, chan<- struct{}) {
+	result := make(chan R, 1)
+	cancel := make(chan struct{}, 1)
+	completed := make(chan R, 1)
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res R) { completed <- res })
+	go func() { result <- call(ctx) }()
+	return completed, cancel
+}
+
+func TestForeignFutureCancelledCancelsCtx(t *testing.T) {
+	s := fiatServiceCtx{called: make(chan struct{})}
+	errs := make(chan error, 1)
+	completed, cancel := dispatch(func(ctx context.Context) error {
+		_, err := fiatServiceWithCtx(NewFiatServiceFromCtx(s)).FetchFiatCurrencies(ctx)
+		errs <- err
+		return err
+	})
+	<-s.called
+	// Rust frees the future it no longer waits for.
+	cancel <- struct{}{}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("callback context not cancelled")
+	}
+	select {
+	case <-completed:
+		t.Fatal("completed a freed future")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestForeignFutureCompleted(t *testing.T) {
+	var callCtx context.Context
+	completed, _ := dispatch(func(ctx context.Context) []Rate {
+		callCtx = ctx
+		rates, _ := fiatServiceWithCtx(NewFiatServiceFromCtx(fiatServiceCtx{})).FetchFiatRates(ctx)
+		return rates
+	})
+	if rates := <-completed; len(rates) != 1 || rates[0].Coin != "USD" {
+		t.Fatalf("got %v", rates)
+	}
+	// The context ends with the call.
+	select {
+	case <-callCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("callback context outlived the call")
+	}
+}
+
+func TestCtxAdapters(t *testing.T) {
+	// Services made from ctx-aware ones are unwrapped...
+	inner := fiatServiceCtx{}
+	if got := AsFiatServiceCtx(NewFiatServiceFromCtx(inner)); got != FiatServiceCtx(inner) {
+		t.Fatalf("got %#v", got)
+	}
+	// ...while the others ignore the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if rates, err := AsFiatServiceCtx(fiatService{}).FetchFiatRates(ctx); err != nil || rates[0].Coin != "EUR" {
+		t.Fatalf("got %v, %v", rates, err)
+	}
+	// Direct Go calls through an adapter use a live context.
+	if rates, err := NewFiatServiceFromCtx(inner).FetchFiatRates(); err != nil || len(rates) != 1 {
+		t.Fatalf("got %v, %v", rates, err)
+	}
+
+	if _, ok := AsBitcoinChainServiceCtx(NewBitcoinChainServiceFromCtx(nil)).(bitcoinChainServiceIgnoringCtx); ok {
+		t.Fatal("chain service not unwrapped")
+	}
+	if _, ok := AsRestClientCtx(NewRestClientFromCtx(nil)).(restClientIgnoringCtx); ok {
+		t.Fatal("rest client not unwrapped")
+	}
+	if _, ok := AsStorageCtx(NewStorageFromCtx(nil)).(storageIgnoringCtx); ok {
+		t.Fatal("storage not unwrapped")
+	}
+}
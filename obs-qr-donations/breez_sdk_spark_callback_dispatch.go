@@ -0,0 +1,1469 @@
+package breez_sdk_spark
+
+// #include <breez_sdk_spark.h>
+//
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod0(uint64_t uniffi_handle, RustBuffer address, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod1(uint64_t uniffi_handle, RustBuffer txid, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod2(uint64_t uniffi_handle, RustBuffer txid, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod3(uint64_t uniffi_handle, RustBuffer tx, UniffiForeignFutureCompleteVoid uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod4(uint64_t uniffi_handle, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceFiatServiceMethod0(uint64_t uniffi_handle, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceFiatServiceMethod1(uint64_t uniffi_handle, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod0(uint64_t uniffi_handle, RustBuffer url, RustBuffer headers, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod1(uint64_t uniffi_handle, RustBuffer url, RustBuffer headers, RustBuffer body, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod2(uint64_t uniffi_handle, RustBuffer url, RustBuffer headers, RustBuffer body, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod0(uint64_t uniffi_handle, RustBuffer key, UniffiForeignFutureCompleteVoid uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod1(uint64_t uniffi_handle, RustBuffer key, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod2(uint64_t uniffi_handle, RustBuffer key, RustBuffer value, UniffiForeignFutureCompleteVoid uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod3(uint64_t uniffi_handle, RustBuffer request, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod4(uint64_t uniffi_handle, RustBuffer payment, UniffiForeignFutureCompleteVoid uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod5(uint64_t uniffi_handle, RustBuffer payment_id, RustBuffer metadata, UniffiForeignFutureCompleteVoid uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod6(uint64_t uniffi_handle, RustBuffer id, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod7(uint64_t uniffi_handle, RustBuffer invoice, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod8(uint64_t uniffi_handle, RustBuffer txid, uint32_t vout, uint64_t amount_sats, UniffiForeignFutureCompleteVoid uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod9(uint64_t uniffi_handle, RustBuffer txid, uint32_t vout, UniffiForeignFutureCompleteVoid uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod10(uint64_t uniffi_handle, UniffiForeignFutureCompleteRustBuffer uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod11(uint64_t uniffi_handle, RustBuffer txid, uint32_t vout, RustBuffer payload, UniffiForeignFutureCompleteVoid uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+// void breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod12(uint64_t uniffi_handle, RustBuffer metadata, UniffiForeignFutureCompleteVoid uniffi_future_callback, uint64_t uniffi_callback_data, UniffiForeignFuture* uniffi_out_return);
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime/cgo"
+)
+
+// The vtables in breez_sdk_spark.go point at the generated dispatchers,
+// which call the plain interfaces. installCtxDispatchers points the methods
+// of the services that have a Ctx counterpart at the dispatchers below
+// instead, which hand the callback a context cancelled when Rust abandons
+// the future. It runs as a variable initializer so that it happens before
+// the generated init registers the vtables.
+var _ = installCtxDispatchers()
+
+func installCtxDispatchers() bool {
+	chain := &UniffiVTableCallbackInterfaceBitcoinChainServiceINSTANCE
+	chain.getAddressUtxos = (C.UniffiCallbackInterfaceBitcoinChainServiceMethod0)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod0)
+	chain.getTransactionStatus = (C.UniffiCallbackInterfaceBitcoinChainServiceMethod1)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod1)
+	chain.getTransactionHex = (C.UniffiCallbackInterfaceBitcoinChainServiceMethod2)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod2)
+	chain.broadcastTransaction = (C.UniffiCallbackInterfaceBitcoinChainServiceMethod3)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod3)
+	chain.recommendedFees = (C.UniffiCallbackInterfaceBitcoinChainServiceMethod4)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod4)
+
+	fiat := &UniffiVTableCallbackInterfaceFiatServiceINSTANCE
+	fiat.fetchFiatCurrencies = (C.UniffiCallbackInterfaceFiatServiceMethod0)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceFiatServiceMethod0)
+	fiat.fetchFiatRates = (C.UniffiCallbackInterfaceFiatServiceMethod1)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceFiatServiceMethod1)
+
+	rest := &UniffiVTableCallbackInterfaceRestClientINSTANCE
+	rest.getRequest = (C.UniffiCallbackInterfaceRestClientMethod0)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod0)
+	rest.postRequest = (C.UniffiCallbackInterfaceRestClientMethod1)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod1)
+	rest.deleteRequest = (C.UniffiCallbackInterfaceRestClientMethod2)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod2)
+
+	storage := &UniffiVTableCallbackInterfaceStorageINSTANCE
+	storage.deleteCachedItem = (C.UniffiCallbackInterfaceStorageMethod0)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod0)
+	storage.getCachedItem = (C.UniffiCallbackInterfaceStorageMethod1)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod1)
+	storage.setCachedItem = (C.UniffiCallbackInterfaceStorageMethod2)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod2)
+	storage.listPayments = (C.UniffiCallbackInterfaceStorageMethod3)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod3)
+	storage.insertPayment = (C.UniffiCallbackInterfaceStorageMethod4)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod4)
+	storage.setPaymentMetadata = (C.UniffiCallbackInterfaceStorageMethod5)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod5)
+	storage.getPaymentById = (C.UniffiCallbackInterfaceStorageMethod6)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod6)
+	storage.getPaymentByInvoice = (C.UniffiCallbackInterfaceStorageMethod7)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod7)
+	storage.addDeposit = (C.UniffiCallbackInterfaceStorageMethod8)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod8)
+	storage.deleteDeposit = (C.UniffiCallbackInterfaceStorageMethod9)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod9)
+	storage.listDeposits = (C.UniffiCallbackInterfaceStorageMethod10)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod10)
+	storage.updateDeposit = (C.UniffiCallbackInterfaceStorageMethod11)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod11)
+	storage.setLnurlMetadata = (C.UniffiCallbackInterfaceStorageMethod12)(C.breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod12)
+	return true
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod0
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod0(uniffiHandle C.uint64_t, address C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterBitcoinChainServiceINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := bitcoinChainServiceWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.GetAddressUtxos(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: address,
+				}),
+			)
+
+		if err != nil {
+			var actualError *ChainServiceError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterChainServiceErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterSequenceUtxoINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod1
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod1(uniffiHandle C.uint64_t, txid C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterBitcoinChainServiceINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := bitcoinChainServiceWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.GetTransactionStatus(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: txid,
+				}),
+			)
+
+		if err != nil {
+			var actualError *ChainServiceError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterChainServiceErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterTxStatusINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod2
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod2(uniffiHandle C.uint64_t, txid C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterBitcoinChainServiceINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := bitcoinChainServiceWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.GetTransactionHex(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: txid,
+				}),
+			)
+
+		if err != nil {
+			var actualError *ChainServiceError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterChainServiceErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterStringINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod3
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod3(uniffiHandle C.uint64_t, tx C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterBitcoinChainServiceINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := bitcoinChainServiceWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructVoid) {
+		C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		err :=
+			uniffiObjCtx.BroadcastTransaction(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: tx,
+				}),
+			)
+
+		if err != nil {
+			var actualError *ChainServiceError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterChainServiceErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod4
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceBitcoinChainServiceMethod4(uniffiHandle C.uint64_t, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterBitcoinChainServiceINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := bitcoinChainServiceWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.RecommendedFees(ctx)
+
+		if err != nil {
+			var actualError *ChainServiceError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterChainServiceErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterRecommendedFeesINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceFiatServiceMethod0
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceFiatServiceMethod0(uniffiHandle C.uint64_t, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterFiatServiceINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := fiatServiceWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.FetchFiatCurrencies(ctx)
+
+		if err != nil {
+			var actualError *ServiceConnectivityError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterServiceConnectivityErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterSequenceFiatCurrencyINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceFiatServiceMethod1
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceFiatServiceMethod1(uniffiHandle C.uint64_t, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterFiatServiceINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := fiatServiceWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.FetchFiatRates(ctx)
+
+		if err != nil {
+			var actualError *ServiceConnectivityError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterServiceConnectivityErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterSequenceRateINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod0
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod0(uniffiHandle C.uint64_t, url C.RustBuffer, headers C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterRestClientINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := restClientWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.GetRequest(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: url,
+				}),
+				FfiConverterOptionalMapStringStringINSTANCE.Lift(GoRustBuffer{
+					inner: headers,
+				}),
+			)
+
+		if err != nil {
+			var actualError *ServiceConnectivityError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterServiceConnectivityErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterRestResponseINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod1
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod1(uniffiHandle C.uint64_t, url C.RustBuffer, headers C.RustBuffer, body C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterRestClientINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := restClientWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.PostRequest(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: url,
+				}),
+				FfiConverterOptionalMapStringStringINSTANCE.Lift(GoRustBuffer{
+					inner: headers,
+				}),
+				FfiConverterOptionalStringINSTANCE.Lift(GoRustBuffer{
+					inner: body,
+				}),
+			)
+
+		if err != nil {
+			var actualError *ServiceConnectivityError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterServiceConnectivityErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterRestResponseINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod2
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceRestClientMethod2(uniffiHandle C.uint64_t, url C.RustBuffer, headers C.RustBuffer, body C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterRestClientINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := restClientWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.DeleteRequest(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: url,
+				}),
+				FfiConverterOptionalMapStringStringINSTANCE.Lift(GoRustBuffer{
+					inner: headers,
+				}),
+				FfiConverterOptionalStringINSTANCE.Lift(GoRustBuffer{
+					inner: body,
+				}),
+			)
+
+		if err != nil {
+			var actualError *ServiceConnectivityError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterServiceConnectivityErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterRestResponseINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod0
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod0(uniffiHandle C.uint64_t, key C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructVoid) {
+		C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		err :=
+			uniffiObjCtx.DeleteCachedItem(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: key,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod1
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod1(uniffiHandle C.uint64_t, key C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.GetCachedItem(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: key,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterOptionalStringINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod2
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod2(uniffiHandle C.uint64_t, key C.RustBuffer, value C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructVoid) {
+		C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		err :=
+			uniffiObjCtx.SetCachedItem(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: key,
+				}),
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: value,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod3
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod3(uniffiHandle C.uint64_t, request C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.ListPayments(
+				ctx,
+				FfiConverterListPaymentsRequestINSTANCE.Lift(GoRustBuffer{
+					inner: request,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterSequencePaymentINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod4
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod4(uniffiHandle C.uint64_t, payment C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructVoid) {
+		C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		err :=
+			uniffiObjCtx.InsertPayment(
+				ctx,
+				FfiConverterPaymentINSTANCE.Lift(GoRustBuffer{
+					inner: payment,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod5
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod5(uniffiHandle C.uint64_t, paymentId C.RustBuffer, metadata C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructVoid) {
+		C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		err :=
+			uniffiObjCtx.SetPaymentMetadata(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: paymentId,
+				}),
+				FfiConverterPaymentMetadataINSTANCE.Lift(GoRustBuffer{
+					inner: metadata,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod6
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod6(uniffiHandle C.uint64_t, id C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.GetPaymentById(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: id,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterPaymentINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod7
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod7(uniffiHandle C.uint64_t, invoice C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.GetPaymentByInvoice(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: invoice,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterOptionalPaymentINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod8
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod8(uniffiHandle C.uint64_t, txid C.RustBuffer, vout C.uint32_t, amountSats C.uint64_t, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructVoid) {
+		C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		err :=
+			uniffiObjCtx.AddDeposit(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: txid,
+				}),
+				FfiConverterUint32INSTANCE.Lift(vout),
+				FfiConverterUint64INSTANCE.Lift(amountSats),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod9
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod9(uniffiHandle C.uint64_t, txid C.RustBuffer, vout C.uint32_t, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructVoid) {
+		C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		err :=
+			uniffiObjCtx.DeleteDeposit(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: txid,
+				}),
+				FfiConverterUint32INSTANCE.Lift(vout),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod10
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod10(uniffiHandle C.uint64_t, uniffiFutureCallback C.UniffiForeignFutureCompleteRustBuffer, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructRustBuffer, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructRustBuffer) {
+		C.call_UniffiForeignFutureCompleteRustBuffer(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructRustBuffer{}
+		uniffiOutReturn := &asyncResult.returnValue
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		res, err :=
+			uniffiObjCtx.ListDeposits(ctx)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+		*uniffiOutReturn = FfiConverterSequenceDepositInfoINSTANCE.Lower(res)
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod11
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod11(uniffiHandle C.uint64_t, txid C.RustBuffer, vout C.uint32_t, payload C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructVoid) {
+		C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		err :=
+			uniffiObjCtx.UpdateDeposit(
+				ctx,
+				FfiConverterStringINSTANCE.Lift(GoRustBuffer{
+					inner: txid,
+				}),
+				FfiConverterUint32INSTANCE.Lift(vout),
+				FfiConverterUpdateDepositPayloadINSTANCE.Lift(GoRustBuffer{
+					inner: payload,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+	}()
+}
+
+//export breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod12
+func breez_sdk_spark_ctx_dispatchCallbackInterfaceStorageMethod12(uniffiHandle C.uint64_t, metadata C.RustBuffer, uniffiFutureCallback C.UniffiForeignFutureCompleteVoid, uniffiCallbackData C.uint64_t, uniffiOutReturn *C.UniffiForeignFuture) {
+	handle := uint64(uniffiHandle)
+	uniffiObj, ok := FfiConverterStorageINSTANCE.handleMap.tryGet(handle)
+	if !ok {
+		panic(fmt.Errorf("no callback in handle map: %d", handle))
+	}
+	uniffiObjCtx := storageWithCtx(uniffiObj)
+
+	result := make(chan C.UniffiForeignFutureStructVoid, 1)
+	cancel := make(chan struct{}, 1)
+	guardHandle := cgo.NewHandle(cancel)
+	*uniffiOutReturn = C.UniffiForeignFuture{
+		handle: C.uint64_t(guardHandle),
+		free:   C.UniffiForeignFutureFree(C.breez_sdk_spark_uniffiFreeGorutine),
+	}
+
+	// Wait for completion or cancel; ctx is cancelled when either happens
+	ctx := uniffiForeignFutureCtx(cancel, result, func(res C.UniffiForeignFutureStructVoid) {
+		C.call_UniffiForeignFutureCompleteVoid(uniffiFutureCallback, uniffiCallbackData, res)
+	})
+
+	// Eval callback asynchronously
+	go func() {
+		asyncResult := &C.UniffiForeignFutureStructVoid{}
+		callStatus := &asyncResult.callStatus
+		defer func() {
+			result <- *asyncResult
+		}()
+
+		err :=
+			uniffiObjCtx.SetLnurlMetadata(
+				ctx,
+				FfiConverterSequenceSetLnurlMetadataItemINSTANCE.Lift(GoRustBuffer{
+					inner: metadata,
+				}),
+			)
+
+		if err != nil {
+			var actualError *StorageError
+			if errors.As(err, &actualError) {
+				if actualError != nil {
+					*callStatus = C.RustCallStatus{
+						code:     C.int8_t(uniffiCallbackResultError),
+						errorBuf: FfiConverterStorageErrorINSTANCE.Lower(actualError),
+					}
+					return
+				}
+			} else {
+				*callStatus = C.RustCallStatus{
+					code: C.int8_t(uniffiCallbackUnexpectedResultError),
+				}
+				return
+			}
+		}
+
+	}()
+}
@@ -0,0 +1,7 @@
+package breez_sdk_spark
+
+// The generated bindings include breez_sdk_spark.h; it ships in third_party
+// so that the package builds without CGO_CFLAGS.
+
+// #cgo CFLAGS: -I${SRCDIR}/third_party/breez_sdk/include/breez_sdk
+import "C"
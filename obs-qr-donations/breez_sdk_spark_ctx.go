@@ -0,0 +1,1253 @@
+package breez_sdk_spark
+
+// #include <breez_sdk_spark.h>
+import "C"
+
+import (
+	"context"
+	"runtime/cgo"
+	"unsafe"
+)
+
+type rustFutureCancelFunc func(C.uint64_t)
+
+// uniffiRustCallAsyncCtx drives a Rust future like uniffiRustCallAsync, but
+// stops waiting as soon as ctx is done. In that case the future is cancelled
+// through cancelFunc, the continuation Rust still owes us is drained so the
+// waiter handle is not deleted underneath it, the future is freed and
+// ctx.Err() is returned.
+func uniffiRustCallAsyncCtx[E any, T any, F any](
+	ctx context.Context,
+	errConverter BufReader[*E],
+	completeFunc rustFutureCompleteFunc[F],
+	liftFunc func(F) T,
+	rustFuture C.uint64_t,
+	pollFunc rustFuturePollFunc,
+	freeFunc rustFutureFreeFunc,
+	cancelFunc rustFutureCancelFunc,
+) (T, error) {
+	return uniffiRustCallAsyncCtxUndo(ctx, errConverter, completeFunc, liftFunc, rustFuture, pollFunc, freeFunc, cancelFunc, nil)
+}
+
+// uniffiRustCallAsyncCtxUndo is uniffiRustCallAsyncCtx for calls with an
+// effect to take back when ctx ends the wait: cancelling only stops a
+// future that has not completed yet, so when the future turns out to have
+// completed anyway, its value is handed to undo.
+func uniffiRustCallAsyncCtxUndo[E any, T any, F any](
+	ctx context.Context,
+	errConverter BufReader[*E],
+	completeFunc rustFutureCompleteFunc[F],
+	liftFunc func(F) T,
+	rustFuture C.uint64_t,
+	pollFunc rustFuturePollFunc,
+	freeFunc rustFutureFreeFunc,
+	cancelFunc rustFutureCancelFunc,
+	undo func(T),
+) (T, error) {
+	defer freeFunc(rustFuture)
+
+	var goValue T
+	err := uniffiAwaitCtx(ctx,
+		func(waiter cgo.Handle) {
+			pollFunc(
+				rustFuture,
+				(C.UniffiRustFutureContinuationCallback)(C.breez_sdk_spark_uniffiFutureContinuationCallback),
+				C.uint64_t(waiter),
+			)
+		},
+		func() { cancelFunc(rustFuture) },
+	)
+	if err != nil {
+		if undo != nil {
+			var status C.RustCallStatus
+			ffiValue := completeFunc(rustFuture, &status)
+			switch status.code {
+			case 0:
+				undo(liftFunc(ffiValue))
+			case 1:
+				if errConverter != nil {
+					// Free the error buffer.
+					checkCallStatus(errConverter, status)
+				}
+			}
+		}
+		return goValue, err
+	}
+
+	ffiValue, callErr := rustCallWithError(errConverter, func(status *C.RustCallStatus) F {
+		return completeFunc(rustFuture, status)
+	})
+	if callErr != nil {
+		return goValue, any(callErr).(NativeError).AsError()
+	}
+	return liftFunc(ffiValue), nil
+}
+
+// uniffiAwaitCtx waits for a Rust future to be ready. poll asks for the
+// continuation to be sent to waiter, a handle to a chan int8. When ctx is
+// done first, the future is cancelled and the pending continuation, which
+// Rust calls even for a cancelled future, is drained before the handle is
+// deleted; ctx.Err() is returned.
+func uniffiAwaitCtx(ctx context.Context, poll func(waiter cgo.Handle), cancel func()) error {
+	waiter := make(chan int8, 1)
+	chanHandle := cgo.NewHandle(waiter)
+	defer chanHandle.Delete()
+
+	for pollResult := int8(-1); pollResult != uniffiRustFuturePollReady; {
+		poll(chanHandle)
+		select {
+		case pollResult = <-waiter:
+		case <-ctx.Done():
+			cancel()
+			<-waiter
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// ConnectCtx is like [Connect] but gives up when ctx is done.
+// An SDK connected by the time ctx is done is disconnected again.
+func ConnectCtx(ctx context.Context, request ConnectRequest) (*BreezSdk, error) {
+	res, err := uniffiRustCallAsyncCtxUndo[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) unsafe.Pointer {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_pointer(handle, status)
+			return res
+		},
+		// liftFn
+		func(ffi unsafe.Pointer) *BreezSdk {
+			return FfiConverterBreezSdkINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_func_connect(FfiConverterConnectRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_pointer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_pointer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_pointer(handle)
+		},
+		// The SDK connected before ctx ended the wait.
+		func(sdk *BreezSdk) {
+			disconnectAbandoned(sdk)
+		},
+	)
+
+	return res, err
+}
+
+// disconnectAbandoned stops an SDK nobody was handed, rather than leave
+// its background tasks running until the finalizer frees it.
+func disconnectAbandoned(sdk interface {
+	Disconnect() error
+	Destroy()
+}) {
+	sdk.Disconnect()
+	sdk.Destroy()
+}
+
+// AddEventListenerCtx is like [BreezSdk.AddEventListener] but gives up when ctx is done.
+// A listener registered by the time ctx is done is removed again.
+func (_self *BreezSdk) AddEventListenerCtx(ctx context.Context, listener EventListener) (string, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtxUndo[error](
+		ctx,
+		nil,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) string {
+			return FfiConverterStringINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_add_event_listener(
+			_pointer, FfiConverterCallbackInterfaceEventListenerINSTANCE.Lower(listener)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+		// The listener was registered before ctx ended the wait.
+		func(id string) {
+			_self.RemoveEventListener(id)
+		},
+	)
+
+	return res, err
+}
+
+// CheckLightningAddressAvailableCtx is like [BreezSdk.CheckLightningAddressAvailable] but gives up when ctx is done.
+func (_self *BreezSdk) CheckLightningAddressAvailableCtx(ctx context.Context, req CheckLightningAddressRequest) (bool, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) C.int8_t {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_i8(handle, status)
+			return res
+		},
+		// liftFn
+		func(ffi C.int8_t) bool {
+			return FfiConverterBoolINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_check_lightning_address_available(
+			_pointer, FfiConverterCheckLightningAddressRequestINSTANCE.Lower(req)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_i8(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_i8(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_i8(handle)
+		},
+	)
+
+	return res, err
+}
+
+// CheckMessageCtx is like [BreezSdk.CheckMessage] but gives up when ctx is done.
+func (_self *BreezSdk) CheckMessageCtx(ctx context.Context, request CheckMessageRequest) (CheckMessageResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) CheckMessageResponse {
+			return FfiConverterCheckMessageResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_check_message(
+			_pointer, FfiConverterCheckMessageRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// ClaimDepositCtx is like [BreezSdk.ClaimDeposit] but gives up when ctx is done.
+func (_self *BreezSdk) ClaimDepositCtx(ctx context.Context, request ClaimDepositRequest) (ClaimDepositResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) ClaimDepositResponse {
+			return FfiConverterClaimDepositResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_claim_deposit(
+			_pointer, FfiConverterClaimDepositRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// ClaimHtlcPaymentCtx is like [BreezSdk.ClaimHtlcPayment] but gives up when ctx is done.
+func (_self *BreezSdk) ClaimHtlcPaymentCtx(ctx context.Context, request ClaimHtlcPaymentRequest) (ClaimHtlcPaymentResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) ClaimHtlcPaymentResponse {
+			return FfiConverterClaimHtlcPaymentResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_claim_htlc_payment(
+			_pointer, FfiConverterClaimHtlcPaymentRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// DeleteLightningAddressCtx is like [BreezSdk.DeleteLightningAddress] but gives up when ctx is done.
+func (_self *BreezSdk) DeleteLightningAddressCtx(ctx context.Context) error {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	_, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) struct{} {
+			C.ffi_breez_sdk_spark_rust_future_complete_void(handle, status)
+			return struct{}{}
+		},
+		// liftFn
+		func(_ struct{}) struct{} { return struct{}{} },
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_delete_lightning_address(
+			_pointer),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_void(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_void(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_void(handle)
+		},
+	)
+
+	return err
+}
+
+// DisconnectCtx is like [BreezSdk.Disconnect] but gives up when ctx is done.
+func (_self *BreezSdk) DisconnectCtx(ctx context.Context) error {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	_, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) struct{} {
+			C.ffi_breez_sdk_spark_rust_future_complete_void(handle, status)
+			return struct{}{}
+		},
+		// liftFn
+		func(_ struct{}) struct{} { return struct{}{} },
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_disconnect(
+			_pointer),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_void(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_void(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_void(handle)
+		},
+	)
+
+	return err
+}
+
+// GetInfoCtx is like [BreezSdk.GetInfo] but gives up when ctx is done.
+func (_self *BreezSdk) GetInfoCtx(ctx context.Context, request GetInfoRequest) (GetInfoResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) GetInfoResponse {
+			return FfiConverterGetInfoResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_get_info(
+			_pointer, FfiConverterGetInfoRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// GetLightningAddressCtx is like [BreezSdk.GetLightningAddress] but gives up when ctx is done.
+func (_self *BreezSdk) GetLightningAddressCtx(ctx context.Context) (*LightningAddressInfo, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) *LightningAddressInfo {
+			return FfiConverterOptionalLightningAddressInfoINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_get_lightning_address(
+			_pointer),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// GetPaymentCtx is like [BreezSdk.GetPayment] but gives up when ctx is done.
+func (_self *BreezSdk) GetPaymentCtx(ctx context.Context, request GetPaymentRequest) (GetPaymentResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) GetPaymentResponse {
+			return FfiConverterGetPaymentResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_get_payment(
+			_pointer, FfiConverterGetPaymentRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// GetTokensMetadataCtx is like [BreezSdk.GetTokensMetadata] but gives up when ctx is done.
+func (_self *BreezSdk) GetTokensMetadataCtx(ctx context.Context, request GetTokensMetadataRequest) (GetTokensMetadataResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) GetTokensMetadataResponse {
+			return FfiConverterGetTokensMetadataResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_get_tokens_metadata(
+			_pointer, FfiConverterGetTokensMetadataRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// GetUserSettingsCtx is like [BreezSdk.GetUserSettings] but gives up when ctx is done.
+func (_self *BreezSdk) GetUserSettingsCtx(ctx context.Context) (UserSettings, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) UserSettings {
+			return FfiConverterUserSettingsINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_get_user_settings(
+			_pointer),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// ListFiatCurrenciesCtx is like [BreezSdk.ListFiatCurrencies] but gives up when ctx is done.
+func (_self *BreezSdk) ListFiatCurrenciesCtx(ctx context.Context) (ListFiatCurrenciesResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) ListFiatCurrenciesResponse {
+			return FfiConverterListFiatCurrenciesResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_list_fiat_currencies(
+			_pointer),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// ListFiatRatesCtx is like [BreezSdk.ListFiatRates] but gives up when ctx is done.
+func (_self *BreezSdk) ListFiatRatesCtx(ctx context.Context) (ListFiatRatesResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) ListFiatRatesResponse {
+			return FfiConverterListFiatRatesResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_list_fiat_rates(
+			_pointer),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// ListPaymentsCtx is like [BreezSdk.ListPayments] but gives up when ctx is done.
+func (_self *BreezSdk) ListPaymentsCtx(ctx context.Context, request ListPaymentsRequest) (ListPaymentsResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) ListPaymentsResponse {
+			return FfiConverterListPaymentsResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_list_payments(
+			_pointer, FfiConverterListPaymentsRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// ListUnclaimedDepositsCtx is like [BreezSdk.ListUnclaimedDeposits] but gives up when ctx is done.
+func (_self *BreezSdk) ListUnclaimedDepositsCtx(ctx context.Context, request ListUnclaimedDepositsRequest) (ListUnclaimedDepositsResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) ListUnclaimedDepositsResponse {
+			return FfiConverterListUnclaimedDepositsResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_list_unclaimed_deposits(
+			_pointer, FfiConverterListUnclaimedDepositsRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// LnurlPayCtx is like [BreezSdk.LnurlPay] but gives up when ctx is done.
+func (_self *BreezSdk) LnurlPayCtx(ctx context.Context, request LnurlPayRequest) (LnurlPayResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) LnurlPayResponse {
+			return FfiConverterLnurlPayResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_lnurl_pay(
+			_pointer, FfiConverterLnurlPayRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// LnurlWithdrawCtx is like [BreezSdk.LnurlWithdraw] but gives up when ctx is done.
+func (_self *BreezSdk) LnurlWithdrawCtx(ctx context.Context, request LnurlWithdrawRequest) (LnurlWithdrawResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) LnurlWithdrawResponse {
+			return FfiConverterLnurlWithdrawResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_lnurl_withdraw(
+			_pointer, FfiConverterLnurlWithdrawRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// ParseCtx is like [BreezSdk.Parse] but gives up when ctx is done.
+func (_self *BreezSdk) ParseCtx(ctx context.Context, input string) (InputType, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) InputType {
+			return FfiConverterInputTypeINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_parse(
+			_pointer, FfiConverterStringINSTANCE.Lower(input)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// PrepareLnurlPayCtx is like [BreezSdk.PrepareLnurlPay] but gives up when ctx is done.
+func (_self *BreezSdk) PrepareLnurlPayCtx(ctx context.Context, request PrepareLnurlPayRequest) (PrepareLnurlPayResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) PrepareLnurlPayResponse {
+			return FfiConverterPrepareLnurlPayResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_prepare_lnurl_pay(
+			_pointer, FfiConverterPrepareLnurlPayRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// PrepareSendPaymentCtx is like [BreezSdk.PrepareSendPayment] but gives up when ctx is done.
+func (_self *BreezSdk) PrepareSendPaymentCtx(ctx context.Context, request PrepareSendPaymentRequest) (PrepareSendPaymentResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) PrepareSendPaymentResponse {
+			return FfiConverterPrepareSendPaymentResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_prepare_send_payment(
+			_pointer, FfiConverterPrepareSendPaymentRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// ReceivePaymentCtx is like [BreezSdk.ReceivePayment] but gives up when ctx is done.
+func (_self *BreezSdk) ReceivePaymentCtx(ctx context.Context, request ReceivePaymentRequest) (ReceivePaymentResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) ReceivePaymentResponse {
+			return FfiConverterReceivePaymentResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_receive_payment(
+			_pointer, FfiConverterReceivePaymentRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// RecommendedFeesCtx is like [BreezSdk.RecommendedFees] but gives up when ctx is done.
+func (_self *BreezSdk) RecommendedFeesCtx(ctx context.Context) (RecommendedFees, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) RecommendedFees {
+			return FfiConverterRecommendedFeesINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_recommended_fees(
+			_pointer),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// RefundDepositCtx is like [BreezSdk.RefundDeposit] but gives up when ctx is done.
+func (_self *BreezSdk) RefundDepositCtx(ctx context.Context, request RefundDepositRequest) (RefundDepositResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) RefundDepositResponse {
+			return FfiConverterRefundDepositResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_refund_deposit(
+			_pointer, FfiConverterRefundDepositRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// RegisterLightningAddressCtx is like [BreezSdk.RegisterLightningAddress] but gives up when ctx is done.
+func (_self *BreezSdk) RegisterLightningAddressCtx(ctx context.Context, request RegisterLightningAddressRequest) (LightningAddressInfo, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) LightningAddressInfo {
+			return FfiConverterLightningAddressInfoINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_register_lightning_address(
+			_pointer, FfiConverterRegisterLightningAddressRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// RemoveEventListenerCtx is like [BreezSdk.RemoveEventListener] but gives up when ctx is done.
+func (_self *BreezSdk) RemoveEventListenerCtx(ctx context.Context, id string) (bool, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[error](
+		ctx,
+		nil,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) C.int8_t {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_i8(handle, status)
+			return res
+		},
+		// liftFn
+		func(ffi C.int8_t) bool {
+			return FfiConverterBoolINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_remove_event_listener(
+			_pointer, FfiConverterStringINSTANCE.Lower(id)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_i8(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_i8(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_i8(handle)
+		},
+	)
+
+	return res, err
+}
+
+// SendPaymentCtx is like [BreezSdk.SendPayment] but gives up when ctx is done.
+func (_self *BreezSdk) SendPaymentCtx(ctx context.Context, request SendPaymentRequest) (SendPaymentResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) SendPaymentResponse {
+			return FfiConverterSendPaymentResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_send_payment(
+			_pointer, FfiConverterSendPaymentRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// SignMessageCtx is like [BreezSdk.SignMessage] but gives up when ctx is done.
+func (_self *BreezSdk) SignMessageCtx(ctx context.Context, request SignMessageRequest) (SignMessageResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) SignMessageResponse {
+			return FfiConverterSignMessageResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_sign_message(
+			_pointer, FfiConverterSignMessageRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// SyncWalletCtx is like [BreezSdk.SyncWallet] but gives up when ctx is done.
+func (_self *BreezSdk) SyncWalletCtx(ctx context.Context, request SyncWalletRequest) (SyncWalletResponse, error) {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	res, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) RustBufferI {
+			res := C.ffi_breez_sdk_spark_rust_future_complete_rust_buffer(handle, status)
+			return GoRustBuffer{
+				inner: res,
+			}
+		},
+		// liftFn
+		func(ffi RustBufferI) SyncWalletResponse {
+			return FfiConverterSyncWalletResponseINSTANCE.Lift(ffi)
+		},
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_sync_wallet(
+			_pointer, FfiConverterSyncWalletRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_rust_buffer(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_rust_buffer(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_rust_buffer(handle)
+		},
+	)
+
+	return res, err
+}
+
+// UpdateUserSettingsCtx is like [BreezSdk.UpdateUserSettings] but gives up when ctx is done.
+func (_self *BreezSdk) UpdateUserSettingsCtx(ctx context.Context, request UpdateUserSettingsRequest) error {
+	_pointer := _self.ffiObject.incrementPointer("*BreezSdk")
+	defer _self.ffiObject.decrementPointer()
+	_, err := uniffiRustCallAsyncCtx[SdkError](
+		ctx,
+		FfiConverterSdkErrorINSTANCE,
+		// completeFn
+		func(handle C.uint64_t, status *C.RustCallStatus) struct{} {
+			C.ffi_breez_sdk_spark_rust_future_complete_void(handle, status)
+			return struct{}{}
+		},
+		// liftFn
+		func(_ struct{}) struct{} { return struct{}{} },
+		C.uniffi_breez_sdk_spark_fn_method_breezsdk_update_user_settings(
+			_pointer, FfiConverterUpdateUserSettingsRequestINSTANCE.Lower(request)),
+		// pollFn
+		func(handle C.uint64_t, continuation C.UniffiRustFutureContinuationCallback, data C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_poll_void(handle, continuation, data)
+		},
+		// freeFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_free_void(handle)
+		},
+		// cancelFn
+		func(handle C.uint64_t) {
+			C.ffi_breez_sdk_spark_rust_future_cancel_void(handle)
+		},
+	)
+
+	return err
+}
@@ -0,0 +1,135 @@
+package breez_sdk_spark
+
+import (
+	"context"
+	"errors"
+	"runtime/cgo"
+	"sync"
+	"testing"
+	"time"
+)
+
+// future stands in for a Rust future: it calls back the waiter of the
+// last poll from another goroutine, as Rust does, once woken.
+type future struct {
+	mu        sync.Mutex
+	ready     bool
+	cancelled int
+	polls     int
+	pending   *cgo.Handle
+}
+
+func (f *future) poll(waiter cgo.Handle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.polls++
+	if f.ready {
+		continuation(waiter, uniffiRustFuturePollReady)
+		return
+	}
+	f.pending = &waiter
+}
+
+// wake calls the pending continuation with result.
+func (f *future) wake(result int8) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ready = f.ready || result == uniffiRustFuturePollReady
+	if f.pending != nil {
+		continuation(*f.pending, result)
+		f.pending = nil
+	}
+}
+
+// cancel wakes the pending continuation, like the Rust scheduler does.
+func (f *future) cancel() {
+	f.mu.Lock()
+	f.cancelled++
+	f.mu.Unlock()
+	f.wake(uniffiRustFuturePollReady)
+}
+
+// continuation sends result a little later, so a handle deleted before
+// the continuation ran panics.
+func continuation(waiter cgo.Handle, result int8) {
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		waiter.Value().(chan int8) <- result
+	}()
+}
+
+func TestAwaitCtx(t *testing.T) {
+	f := &future{}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		f.wake(uniffiRustFuturePollMaybeReady)
+		time.Sleep(10 * time.Millisecond)
+		f.wake(uniffiRustFuturePollReady)
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := uniffiAwaitCtx(ctx, f.poll, f.cancel); err != nil {
+		t.Fatal(err)
+	}
+	// Cancelling after completion has no effect.
+	cancel()
+	if f.polls != 2 || f.cancelled != 0 {
+		t.Fatalf("polled %d times, cancelled %d", f.polls, f.cancelled)
+	}
+}
+
+func TestAwaitCtxCancelled(t *testing.T) {
+	for name, cancelAfter := range map[string]time.Duration{"before": 0, "during": 20 * time.Millisecond} {
+		t.Run(name, func(t *testing.T) {
+			f := &future{}
+			ctx, cancel := context.WithCancel(context.Background())
+			if cancelAfter == 0 {
+				cancel()
+			} else {
+				time.AfterFunc(cancelAfter, cancel)
+			}
+			start := time.Now()
+			if err := uniffiAwaitCtx(ctx, f.poll, f.cancel); !errors.Is(err, context.Canceled) {
+				t.Fatalf("got %v", err)
+			}
+			if f.cancelled != 1 {
+				t.Fatalf("cancelled %d times", f.cancelled)
+			}
+			if time.Since(start) > time.Second {
+				t.Fatal("did not stop waiting")
+			}
+		})
+	}
+}
+
+func TestAwaitCtxDeadline(t *testing.T) {
+	f := &future{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := uniffiAwaitCtx(ctx, f.poll, f.cancel); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+// abandoned records the calls disconnectAbandoned makes.
+type abandoned struct {
+	calls []string
+}
+
+func (a *abandoned) Disconnect() error {
+	a.calls = append(a.calls, "Disconnect")
+	return errors.New("already stopped")
+}
+
+func (a *abandoned) Destroy() {
+	a.calls = append(a.calls, "Destroy")
+}
+
+func TestDisconnectAbandoned(t *testing.T) {
+	// An SDK ConnectCtx gave up on is stopped and freed, even when
+	// stopping fails.
+	sdk := &abandoned{}
+	disconnectAbandoned(sdk)
+	if len(sdk.calls) != 2 || sdk.calls[0] != "Disconnect" || sdk.calls[1] != "Destroy" {
+		t.Fatalf("calls %v", sdk.calls)
+	}
+}
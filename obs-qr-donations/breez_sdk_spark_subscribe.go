@@ -0,0 +1,210 @@
+package breez_sdk_spark
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/eventsub"
+)
+
+// SdkEventKind identifies the variants of [SdkEvent].
+type SdkEventKind int
+
+const (
+	SdkEventKindUnknown SdkEventKind = iota
+	SdkEventKindSynced
+	SdkEventKindDataSynced
+	SdkEventKindUnclaimedDeposits
+	SdkEventKindClaimedDeposits
+	SdkEventKindPaymentSucceeded
+	SdkEventKindPaymentPending
+	SdkEventKindPaymentFailed
+)
+
+var sdkEventKindNames = [...]string{
+	SdkEventKindUnknown:           "unknown",
+	SdkEventKindSynced:            "synced",
+	SdkEventKindDataSynced:        "data_synced",
+	SdkEventKindUnclaimedDeposits: "unclaimed_deposits",
+	SdkEventKindClaimedDeposits:   "claimed_deposits",
+	SdkEventKindPaymentSucceeded:  "payment_succeeded",
+	SdkEventKindPaymentPending:    "payment_pending",
+	SdkEventKindPaymentFailed:     "payment_failed",
+}
+
+// String returns the snake_case name of k, e.g. "payment_succeeded".
+func (k SdkEventKind) String() string {
+	if k < 0 || int(k) >= len(sdkEventKindNames) {
+		return sdkEventKindNames[SdkEventKindUnknown]
+	}
+	return sdkEventKindNames[k]
+}
+
+// SdkEventKindOf returns the kind of e.
+func SdkEventKindOf(e SdkEvent) SdkEventKind {
+	switch e.(type) {
+	case SdkEventSynced:
+		return SdkEventKindSynced
+	case SdkEventDataSynced:
+		return SdkEventKindDataSynced
+	case SdkEventUnclaimedDeposits:
+		return SdkEventKindUnclaimedDeposits
+	case SdkEventClaimedDeposits:
+		return SdkEventKindClaimedDeposits
+	case SdkEventPaymentSucceeded:
+		return SdkEventKindPaymentSucceeded
+	case SdkEventPaymentPending:
+		return SdkEventKindPaymentPending
+	case SdkEventPaymentFailed:
+		return SdkEventKindPaymentFailed
+	default:
+		return SdkEventKindUnknown
+	}
+}
+
+// OverflowPolicy decides what happens to events arriving while the buffer
+// of a subscription is full.
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest discards the oldest buffered event to make room.
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowBlock holds up the SDK's event delivery, to every listener,
+	// until there is room. Only use it with consumers that keep up.
+	OverflowBlock
+	// OverflowError ends the subscription: its channel is closed once the
+	// buffered events are read, while ctx is still not done, and the Err
+	// of its Subscription is ErrSubscriptionOverflow.
+	OverflowError
+)
+
+// ErrSubscriptionOverflow is the Err of a subscription ended by
+// OverflowError.
+var ErrSubscriptionOverflow = eventsub.ErrOverflow
+
+// DefaultSubscribeBufferSize is the buffer of subscriptions that do not
+// set one.
+const DefaultSubscribeBufferSize = 64
+
+// SubscribeFilter selects the events of a subscription and sets its
+// buffering.
+type SubscribeFilter struct {
+	// Kinds, when not empty, are the only kinds of events delivered.
+	Kinds []SdkEventKind
+	// PaymentTypes, when not empty, restricts delivery to payment events
+	// of these types; other events are then not delivered.
+	PaymentTypes []PaymentType
+	// BufferSize defaults to DefaultSubscribeBufferSize.
+	BufferSize int
+	Overflow   OverflowPolicy
+}
+
+// Matches tells whether e is selected by the filter.
+func (f SubscribeFilter) Matches(e SdkEvent) bool {
+	if len(f.Kinds) > 0 && !slices.Contains(f.Kinds, SdkEventKindOf(e)) {
+		return false
+	}
+	if len(f.PaymentTypes) == 0 {
+		return true
+	}
+	var p Payment
+	switch e := e.(type) {
+	case SdkEventPaymentSucceeded:
+		p = e.Payment
+	case SdkEventPaymentPending:
+		p = e.Payment
+	case SdkEventPaymentFailed:
+		p = e.Payment
+	default:
+		return false
+	}
+	return slices.Contains(f.PaymentTypes, p.PaymentType)
+}
+
+// Subscription delivers the events of [BreezSdk.NewSubscription].
+type Subscription struct {
+	filter SubscribeFilter
+	sink   *eventsub.Sink[SdkEvent]
+}
+
+// C returns the channel events are delivered on. It is closed when the
+// subscription ends, after which the events still buffered can be read.
+func (s *Subscription) C() <-chan SdkEvent {
+	return s.sink.C()
+}
+
+// Err returns why the subscription ended: ErrSubscriptionOverflow, or the
+// cause of the context it was made with, such as context.Canceled. It
+// returns nil while the subscription is live, and is set by the time C is
+// closed.
+func (s *Subscription) Err() error {
+	return s.sink.Err()
+}
+
+// subscriptionListener is the EventListener feeding a Subscription.
+type subscriptionListener struct {
+	*Subscription
+}
+
+func (l subscriptionListener) OnEvent(e SdkEvent) {
+	if l.filter.Matches(e) {
+		l.sink.Send(e)
+	}
+}
+
+// Subscribe delivers the events selected by filter on a channel, without
+// an [EventListener] to implement or an id to keep. The subscription ends
+// when ctx is done or on overflow under OverflowError: the listener is
+// removed and the channel closed. Use NewSubscription to learn which.
+func (_self *BreezSdk) Subscribe(ctx context.Context, filter SubscribeFilter) (<-chan SdkEvent, error) {
+	sub, err := _self.NewSubscription(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return sub.C(), nil
+}
+
+// NewSubscription is Subscribe returning the [Subscription], whose Err
+// tells why it ended.
+func (_self *BreezSdk) NewSubscription(ctx context.Context, filter SubscribeFilter) (*Subscription, error) {
+	sub, err := newSubscription(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	id, err := _self.AddEventListenerCtx(ctx, subscriptionListener{sub})
+	if err != nil {
+		sub.sink.Close()
+		return nil, err
+	}
+	go func() {
+		<-sub.sink.Done()
+		_self.RemoveEventListener(id)
+	}()
+	return sub, nil
+}
+
+// newSubscription checks filter and returns the subscription for it.
+func newSubscription(ctx context.Context, filter SubscribeFilter) (*Subscription, error) {
+	if filter.BufferSize < 0 {
+		return nil, errors.New("negative subscription buffer size")
+	}
+	if filter.BufferSize == 0 {
+		filter.BufferSize = DefaultSubscribeBufferSize
+	}
+	var policy eventsub.Policy
+	switch filter.Overflow {
+	case OverflowDropOldest:
+		policy = eventsub.DropOldest
+	case OverflowBlock:
+		policy = eventsub.Block
+	case OverflowError:
+		policy = eventsub.Close
+	default:
+		return nil, errors.New("unknown subscription overflow policy")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &Subscription{filter: filter, sink: eventsub.New[SdkEvent](ctx, filter.BufferSize, policy)}, nil
+}
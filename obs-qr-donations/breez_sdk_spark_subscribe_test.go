@@ -0,0 +1,143 @@
+package breez_sdk_spark
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func paymentEvent(kind SdkEventKind, typ PaymentType) SdkEvent {
+	p := Payment{Id: "p1", PaymentType: typ}
+	switch kind {
+	case SdkEventKindPaymentSucceeded:
+		return SdkEventPaymentSucceeded{Payment: p}
+	case SdkEventKindPaymentPending:
+		return SdkEventPaymentPending{Payment: p}
+	default:
+		return SdkEventPaymentFailed{Payment: p}
+	}
+}
+
+func TestSdkEventKind(t *testing.T) {
+	if k := SdkEventKindOf(SdkEventSynced{}); k != SdkEventKindSynced || k.String() != "synced" {
+		t.Fatalf("got %v", k)
+	}
+	if k := SdkEventKindOf(paymentEvent(SdkEventKindPaymentFailed, PaymentTypeSend)); k.String() != "payment_failed" {
+		t.Fatalf("got %v", k)
+	}
+	if s := SdkEventKind(99).String(); s != "unknown" {
+		t.Fatalf("out of range kind: %q", s)
+	}
+}
+
+func TestSubscribeFilterMatches(t *testing.T) {
+	received := paymentEvent(SdkEventKindPaymentSucceeded, PaymentTypeReceive)
+	sent := paymentEvent(SdkEventKindPaymentFailed, PaymentTypeSend)
+	for _, tc := range []struct {
+		name   string
+		filter SubscribeFilter
+		event  SdkEvent
+		want   bool
+	}{
+		{"no filter", SubscribeFilter{}, SdkEventSynced{}, true},
+		{"kind", SubscribeFilter{Kinds: []SdkEventKind{SdkEventKindPaymentSucceeded}}, received, true},
+		{"other kind", SubscribeFilter{Kinds: []SdkEventKind{SdkEventKindPaymentSucceeded}}, sent, false},
+		{"payment type", SubscribeFilter{PaymentTypes: []PaymentType{PaymentTypeReceive}}, received, true},
+		{"other payment type", SubscribeFilter{PaymentTypes: []PaymentType{PaymentTypeReceive}}, sent, false},
+		{"payment type on other events", SubscribeFilter{PaymentTypes: []PaymentType{PaymentTypeReceive}}, SdkEventSynced{}, false},
+		{"both", SubscribeFilter{
+			Kinds:        []SdkEventKind{SdkEventKindPaymentFailed},
+			PaymentTypes: []PaymentType{PaymentTypeSend},
+		}, sent, true},
+	} {
+		if got := tc.filter.Matches(tc.event); got != tc.want {
+			t.Errorf("%s: got %v", tc.name, got)
+		}
+	}
+}
+
+func TestSubscriptionPolicies(t *testing.T) {
+	ctx := context.Background()
+	for name, filter := range map[string]SubscribeFilter{
+		"negative buffer": {BufferSize: -1},
+		"unknown policy":  {Overflow: OverflowPolicy(42)},
+	} {
+		if _, err := newSubscription(ctx, filter); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := newSubscription(cancelled, SubscribeFilter{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled context: %v", err)
+	}
+
+	// The default buffer holds DefaultSubscribeBufferSize events.
+	sub, err := newSubscription(ctx, SubscribeFilter{Overflow: OverflowError})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range DefaultSubscribeBufferSize {
+		subscriptionListener{sub}.OnEvent(SdkEventSynced{})
+	}
+	if sub.Err() != nil {
+		t.Fatalf("full subscription ended: %v", sub.Err())
+	}
+}
+
+func TestSubscriptionOverflow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub, err := newSubscription(ctx, SubscribeFilter{
+		Kinds:      []SdkEventKind{SdkEventKindPaymentSucceeded},
+		BufferSize: 2,
+		Overflow:   OverflowError,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := subscriptionListener{sub}
+	for range 3 {
+		l.OnEvent(SdkEventSynced{}) // filtered out
+		l.OnEvent(paymentEvent(SdkEventKindPaymentSucceeded, PaymentTypeReceive))
+	}
+	n := 0
+	for range sub.C() {
+		n++
+	}
+	if n != 2 {
+		t.Fatalf("read %d buffered events", n)
+	}
+	// An overflow is told apart from the context ending.
+	if !errors.Is(sub.Err(), ErrSubscriptionOverflow) || ctx.Err() != nil {
+		t.Fatalf("err %v", sub.Err())
+	}
+}
+
+func TestSubscriptionCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := newSubscription(ctx, SubscribeFilter{BufferSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := subscriptionListener{sub}
+	l.OnEvent(paymentEvent(SdkEventKindPaymentPending, PaymentTypeSend))
+	l.OnEvent(paymentEvent(SdkEventKindPaymentSucceeded, PaymentTypeSend))
+	cancel()
+	select {
+	case <-sub.sink.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscription outlived its context")
+	}
+	// DropOldest kept the latest event.
+	if e := <-sub.C(); SdkEventKindOf(e) != SdkEventKindPaymentSucceeded {
+		t.Fatalf("got %v", SdkEventKindOf(e))
+	}
+	if _, ok := <-sub.C(); ok {
+		t.Fatal("channel not closed")
+	}
+	if !errors.Is(sub.Err(), context.Canceled) {
+		t.Fatalf("err %v", sub.Err())
+	}
+}
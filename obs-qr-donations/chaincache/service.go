@@ -0,0 +1,189 @@
+// Package chaincache caches BitcoinChainService answers. The SDK polls the
+// chain service often while it syncs, and public block explorers rate
+// limit clients that ask the same questions over and over.
+//
+// What is cached and for how long follows from what can change:
+//
+//   - GetTransactionHex answers and confirmed GetTransactionStatus answers
+//     are kept until they are evicted to make room;
+//   - unconfirmed statuses, address UTXOs and RecommendedFees are kept for
+//     a short TTL;
+//   - every BroadcastTransaction drops the cached UTXOs, since it spends
+//     some and creates others.
+//
+// Concurrent calls asking the same question share a single request to the
+// wrapped service. Errors are never cached.
+//
+//	c := chaincache.New(esploraClient, chaincache.Config{})
+//	builder.WithChainService(breez_sdk_spark.NewBitcoinChainServiceFromCtx(c))
+package chaincache
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// Defaults for the zero Config.
+const (
+	DefaultSize       = 10_000
+	DefaultPendingTTL = 10 * time.Second
+	DefaultUtxosTTL   = 30 * time.Second
+	DefaultFeesTTL    = time.Minute
+)
+
+// Config configures a Service. Zero fields get the package defaults; a
+// negative TTL turns caching of that answer off while still collapsing
+// concurrent calls.
+type Config struct {
+	// Size is the maximum number of cached answers. Defaults to DefaultSize.
+	Size int
+	// PendingTTL is how long the status of an unconfirmed transaction is
+	// reused. Defaults to DefaultPendingTTL.
+	PendingTTL time.Duration
+	// UtxosTTL is how long the UTXOs of an address are reused. Defaults to
+	// DefaultUtxosTTL.
+	UtxosTTL time.Duration
+	// FeesTTL is how long RecommendedFees are reused. Defaults to
+	// DefaultFeesTTL.
+	FeesTTL time.Duration
+}
+
+// Service is a caching breez_sdk_spark.BitcoinChainServiceCtx. Plain
+// breez_sdk_spark.BitcoinChainService values can be wrapped through
+// breez_sdk_spark.AsBitcoinChainServiceCtx.
+type Service struct {
+	inner      breez_sdk_spark.BitcoinChainServiceCtx
+	cache      *lru
+	calls      group
+	pendingTTL time.Duration
+	utxosTTL   time.Duration
+	feesTTL    time.Duration
+	now        func() time.Time
+
+	// utxoGen is bumped by every broadcast. It is part of the UTXO cache
+	// keys, so that answers from before the broadcast are no longer found,
+	// including those of lookups still running when it happened.
+	utxoGen atomic.Uint64
+}
+
+var _ breez_sdk_spark.BitcoinChainServiceCtx = (*Service)(nil)
+
+// New returns a Service caching the answers of inner.
+func New(inner breez_sdk_spark.BitcoinChainServiceCtx, cfg Config) *Service {
+	s := &Service{
+		inner:      inner,
+		pendingTTL: orDefault(cfg.PendingTTL, DefaultPendingTTL),
+		utxosTTL:   orDefault(cfg.UtxosTTL, DefaultUtxosTTL),
+		feesTTL:    orDefault(cfg.FeesTTL, DefaultFeesTTL),
+		now:        time.Now,
+	}
+	size := cfg.Size
+	if size <= 0 {
+		size = DefaultSize
+	}
+	s.cache = newLRU(size)
+	return s
+}
+
+func orDefault(ttl, def time.Duration) time.Duration {
+	if ttl == 0 {
+		return def
+	}
+	return ttl
+}
+
+// GetAddressUtxos returns the cached UTXOs of address if they are recent
+// and no transaction was broadcast since they were fetched.
+func (s *Service) GetAddressUtxos(ctx context.Context, address string) ([]breez_sdk_spark.Utxo, error) {
+	key := "utxos:" + strconv.FormatUint(s.utxoGen.Load(), 10) + ":" + address
+	v, err := s.cached(ctx, key, func(ctx context.Context) (any, time.Duration, error) {
+		utxos, err := s.inner.GetAddressUtxos(ctx, address)
+		return utxos, s.utxosTTL, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(v.([]breez_sdk_spark.Utxo)), nil
+}
+
+// GetTransactionStatus returns the cached status of txid. Once a
+// transaction is confirmed its status is never fetched again.
+func (s *Service) GetTransactionStatus(ctx context.Context, txid string) (breez_sdk_spark.TxStatus, error) {
+	v, err := s.cached(ctx, "status:"+txid, func(ctx context.Context) (any, time.Duration, error) {
+		status, err := s.inner.GetTransactionStatus(ctx, txid)
+		if status.Confirmed {
+			return status, 0, err
+		}
+		return status, s.pendingTTL, err
+	})
+	if err != nil {
+		return breez_sdk_spark.TxStatus{}, err
+	}
+	return v.(breez_sdk_spark.TxStatus), nil
+}
+
+// GetTransactionHex returns the cached raw transaction txid.
+func (s *Service) GetTransactionHex(ctx context.Context, txid string) (string, error) {
+	v, err := s.cached(ctx, "hex:"+txid, func(ctx context.Context) (any, time.Duration, error) {
+		hex, err := s.inner.GetTransactionHex(ctx, txid)
+		return hex, 0, err
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// BroadcastTransaction always goes to the wrapped service, and drops the
+// cached UTXOs whatever the outcome: a broadcast that timed out may still
+// have gone through.
+func (s *Service) BroadcastTransaction(ctx context.Context, tx string) error {
+	defer s.utxoGen.Add(1)
+	return s.inner.BroadcastTransaction(ctx, tx)
+}
+
+// RecommendedFees returns the cached fees if they are recent.
+func (s *Service) RecommendedFees(ctx context.Context) (breez_sdk_spark.RecommendedFees, error) {
+	v, err := s.cached(ctx, "fees", func(ctx context.Context) (any, time.Duration, error) {
+		fees, err := s.inner.RecommendedFees(ctx)
+		return fees, s.feesTTL, err
+	})
+	if err != nil {
+		return breez_sdk_spark.RecommendedFees{}, err
+	}
+	return v.(breez_sdk_spark.RecommendedFees), nil
+}
+
+// cached returns the cached value for key or fetches it, sharing the fetch
+// with concurrent callers. fetch also says how long its answer is good
+// for: 0 for as long as it stays in the cache, a negative duration not to
+// cache it.
+func (s *Service) cached(ctx context.Context, key string, fetch func(context.Context) (any, time.Duration, error)) (any, error) {
+	if v, ok := s.cache.get(key, s.now()); ok {
+		return v, nil
+	}
+	v, err := s.calls.do(ctx, key, func(ctx context.Context) (any, error) {
+		v, ttl, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case ttl == 0:
+			s.cache.add(key, v, time.Time{})
+		case ttl > 0:
+			s.cache.add(key, v, s.now().Add(ttl))
+		}
+		return v, nil
+	})
+	if err != nil && err == ctx.Err() {
+		// The caller gave up waiting; report it the way backends report
+		// a cancelled request.
+		return nil, breez_sdk_spark.NewChainServiceErrorServiceConnectivity(err.Error())
+	}
+	return v, err
+}
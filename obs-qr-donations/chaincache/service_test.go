@@ -0,0 +1,359 @@
+package chaincache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// fakeChain counts calls per method. While gate is non-nil, calls block
+// until it is closed or their context ends.
+type fakeChain struct {
+	mu        sync.Mutex
+	calls     map[string]int
+	confirmed bool
+	utxos     []breez_sdk_spark.Utxo
+	fee       uint64
+	fail      error
+	gate      chan struct{}
+}
+
+func newFake() *fakeChain {
+	return &fakeChain{calls: map[string]int{}, fee: 10}
+}
+
+func (f *fakeChain) enter(ctx context.Context, method string) error {
+	f.mu.Lock()
+	f.calls[method]++
+	gate, fail := f.gate, f.fail
+	f.mu.Unlock()
+	if gate != nil {
+		select {
+		case <-gate:
+		case <-ctx.Done():
+			return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(ctx.Err().Error())
+		}
+	}
+	return fail
+}
+
+func (f *fakeChain) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func (f *fakeChain) set(fn func(f *fakeChain)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(f)
+}
+
+func (f *fakeChain) GetAddressUtxos(ctx context.Context, _ string) ([]breez_sdk_spark.Utxo, error) {
+	if err := f.enter(ctx, "utxos"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.utxos, nil
+}
+
+func (f *fakeChain) GetTransactionStatus(ctx context.Context, _ string) (breez_sdk_spark.TxStatus, error) {
+	if err := f.enter(ctx, "status"); err != nil {
+		return breez_sdk_spark.TxStatus{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.confirmed {
+		return breez_sdk_spark.TxStatus{}, nil
+	}
+	height := uint32(800_000)
+	return breez_sdk_spark.TxStatus{Confirmed: true, BlockHeight: &height}, nil
+}
+
+func (f *fakeChain) GetTransactionHex(ctx context.Context, txid string) (string, error) {
+	if err := f.enter(ctx, "hex"); err != nil {
+		return "", err
+	}
+	return "02" + txid, nil
+}
+
+func (f *fakeChain) BroadcastTransaction(ctx context.Context, _ string) error {
+	return f.enter(ctx, "broadcast")
+}
+
+func (f *fakeChain) RecommendedFees(ctx context.Context) (breez_sdk_spark.RecommendedFees, error) {
+	if err := f.enter(ctx, "fees"); err != nil {
+		return breez_sdk_spark.RecommendedFees{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return breez_sdk_spark.RecommendedFees{FastestFee: f.fee}, nil
+}
+
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func newService(f *fakeChain, cfg Config) (*Service, *clock) {
+	s := New(f, cfg)
+	c := &clock{now: time.Unix(1_700_000_000, 0)}
+	s.now = c.Now
+	return s, c
+}
+
+func TestTransactionStatus(t *testing.T) {
+	f := newFake()
+	s, clk := newService(f, Config{})
+	ctx := context.Background()
+
+	for range 3 {
+		if status, err := s.GetTransactionStatus(ctx, "aa"); err != nil || status.Confirmed {
+			t.Fatalf("status = %+v, %v", status, err)
+		}
+	}
+	if n := f.count("status"); n != 1 {
+		t.Fatalf("unconfirmed status fetched %d times within its TTL", n)
+	}
+
+	f.set(func(f *fakeChain) { f.confirmed = true })
+	s.GetTransactionStatus(ctx, "aa")
+	if n := f.count("status"); n != 1 {
+		t.Fatalf("status fetched %d times", n)
+	}
+	clk.advance(DefaultPendingTTL)
+	if status, _ := s.GetTransactionStatus(ctx, "aa"); !status.Confirmed {
+		t.Fatal("unconfirmed status outlived its TTL")
+	}
+
+	clk.advance(1000 * time.Hour)
+	if status, _ := s.GetTransactionStatus(ctx, "aa"); !status.Confirmed || *status.BlockHeight != 800_000 {
+		t.Fatalf("status = %+v", status)
+	}
+	if n := f.count("status"); n != 2 {
+		t.Fatalf("confirmed status fetched again: %d calls", n)
+	}
+}
+
+func TestTransactionHex(t *testing.T) {
+	f := newFake()
+	s, clk := newService(f, Config{})
+	for range 2 {
+		if hex, err := s.GetTransactionHex(context.Background(), "aa"); err != nil || hex != "02aa" {
+			t.Fatalf("hex = %q, %v", hex, err)
+		}
+		clk.advance(1000 * time.Hour)
+	}
+	s.GetTransactionHex(context.Background(), "bb")
+	if n := f.count("hex"); n != 2 {
+		t.Fatalf("hex fetched %d times, want once per txid", n)
+	}
+}
+
+func TestUtxosInvalidatedByBroadcast(t *testing.T) {
+	f := newFake()
+	f.utxos = []breez_sdk_spark.Utxo{{Txid: "aa", Value: 1000}}
+	s, clk := newService(f, Config{})
+	ctx := context.Background()
+
+	utxos, _ := s.GetAddressUtxos(ctx, "bc1qdonate")
+	utxos[0].Value = 1 // callers cannot corrupt the cache
+	if utxos, _ := s.GetAddressUtxos(ctx, "bc1qdonate"); len(utxos) != 1 || utxos[0].Value != 1000 {
+		t.Fatalf("utxos = %+v", utxos)
+	}
+	if n := f.count("utxos"); n != 1 {
+		t.Fatalf("utxos fetched %d times", n)
+	}
+
+	f.set(func(f *fakeChain) { f.utxos = nil })
+	if err := s.BroadcastTransaction(ctx, "0200"); err != nil {
+		t.Fatal(err)
+	}
+	if utxos, _ := s.GetAddressUtxos(ctx, "bc1qdonate"); len(utxos) != 0 {
+		t.Fatalf("stale utxos after broadcast: %+v", utxos)
+	}
+
+	// A failed broadcast may still have reached the network.
+	f.set(func(f *fakeChain) { f.fail = breez_sdk_spark.NewChainServiceErrorServiceConnectivity("timeout") })
+	if err := s.BroadcastTransaction(ctx, "0200"); err == nil {
+		t.Fatal("broadcast error swallowed")
+	}
+	f.set(func(f *fakeChain) { f.fail = nil })
+	s.GetAddressUtxos(ctx, "bc1qdonate")
+	if n := f.count("utxos"); n != 3 {
+		t.Fatalf("utxos fetched %d times, want 3", n)
+	}
+
+	clk.advance(DefaultUtxosTTL)
+	s.GetAddressUtxos(ctx, "bc1qdonate")
+	if n := f.count("utxos"); n != 4 {
+		t.Fatalf("utxos outlived their TTL: %d fetches", n)
+	}
+}
+
+func TestRecommendedFees(t *testing.T) {
+	f := newFake()
+	s, clk := newService(f, Config{FeesTTL: 5 * time.Second})
+	ctx := context.Background()
+
+	s.RecommendedFees(ctx)
+	f.set(func(f *fakeChain) { f.fee = 42 })
+	if fees, _ := s.RecommendedFees(ctx); fees.FastestFee != 10 {
+		t.Fatalf("fees = %+v, want the cached ones", fees)
+	}
+	clk.advance(5 * time.Second)
+	if fees, _ := s.RecommendedFees(ctx); fees.FastestFee != 42 {
+		t.Fatalf("fees = %+v, want fresh ones", fees)
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	f := newFake()
+	f.fail = breez_sdk_spark.NewChainServiceErrorGeneric("transaction not found")
+	s, _ := newService(f, Config{})
+	if _, err := s.GetTransactionHex(context.Background(), "aa"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) {
+		t.Fatalf("got %v", err)
+	}
+	f.set(func(f *fakeChain) { f.fail = nil })
+	if hex, err := s.GetTransactionHex(context.Background(), "aa"); err != nil || hex != "02aa" {
+		t.Fatalf("hex = %q, %v", hex, err)
+	}
+}
+
+func TestNegativeTTLDisablesCaching(t *testing.T) {
+	f := newFake()
+	s, _ := newService(f, Config{FeesTTL: -1})
+	s.RecommendedFees(context.Background())
+	s.RecommendedFees(context.Background())
+	if n := f.count("fees"); n != 2 {
+		t.Fatalf("fees fetched %d times", n)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	f := newFake()
+	s, _ := newService(f, Config{Size: 2})
+	ctx := context.Background()
+	s.GetTransactionHex(ctx, "aa")
+	s.GetTransactionHex(ctx, "bb")
+	s.GetTransactionHex(ctx, "aa") // bb is now the least recently used
+	s.GetTransactionHex(ctx, "cc")
+	if s.cache.len() != 2 {
+		t.Fatalf("cache holds %d entries", s.cache.len())
+	}
+	s.GetTransactionHex(ctx, "aa")
+	s.GetTransactionHex(ctx, "bb")
+	if n := f.count("hex"); n != 4 {
+		t.Fatalf("hex fetched %d times, want 4", n)
+	}
+}
+
+func TestConcurrentCallsCollapse(t *testing.T) {
+	f := newFake()
+	f.gate = make(chan struct{})
+	s, _ := newService(f, Config{})
+
+	const callers = 20
+	var wg sync.WaitGroup
+	var ok atomic.Int32
+	for range callers {
+		wg.Go(func() {
+			if hex, err := s.GetTransactionHex(context.Background(), "aa"); err == nil && hex == "02aa" {
+				ok.Add(1)
+			}
+		})
+	}
+	waitFor(t, func() bool { return s.waiting("hex:aa") == callers })
+	close(f.gate)
+	wg.Wait()
+	if ok.Load() != callers {
+		t.Fatalf("%d of %d callers got the answer", ok.Load(), callers)
+	}
+	if n := f.count("hex"); n != 1 {
+		t.Fatalf("hex fetched %d times", n)
+	}
+}
+
+func TestAbandonedCalls(t *testing.T) {
+	f := newFake()
+	f.gate = make(chan struct{})
+	s, _ := newService(f, Config{})
+
+	// A caller giving up does not cancel the request for the others.
+	first, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := s.GetTransactionHex(first, "aa")
+		firstErr <- err
+	}()
+	waitFor(t, func() bool { return s.waiting("hex:aa") == 1 })
+	second := make(chan error, 1)
+	go func() {
+		_, err := s.GetTransactionHex(context.Background(), "aa")
+		second <- err
+	}()
+	waitFor(t, func() bool { return s.waiting("hex:aa") == 2 })
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("abandoned call: got %v", err)
+	}
+	close(f.gate)
+	if err := <-second; err != nil {
+		t.Fatalf("remaining caller: got %v", err)
+	}
+
+	// Once every caller has gone the request is cancelled, and the next
+	// caller starts a new one.
+	f.set(func(f *fakeChain) { f.gate = make(chan struct{}) })
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.RecommendedFees(ctx); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("timed out call: got %v", err)
+	}
+	f.set(func(f *fakeChain) { close(f.gate); f.gate = nil })
+	if _, err := s.RecommendedFees(context.Background()); err != nil {
+		t.Fatalf("after abandoned call: %v", err)
+	}
+	if n := f.count("fees"); n != 2 {
+		t.Fatalf("fees fetched %d times, want 2", n)
+	}
+}
+
+// waiting reports how many callers are waiting on the call for key.
+func (s *Service) waiting(key string) int {
+	s.calls.mu.Lock()
+	defer s.calls.mu.Unlock()
+	if f, ok := s.calls.calls[key]; ok {
+		return f.waiters
+	}
+	return 0
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,262 @@
+package chainsim
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// params are the address prefixes of a network.
+type params struct {
+	hrp          string
+	pubKeyHashID byte
+	scriptHashID byte
+}
+
+func networkParams(n breez_sdk_spark.BitcoinNetwork) (params, error) {
+	switch n {
+	case breez_sdk_spark.BitcoinNetworkBitcoin:
+		return params{hrp: "bc", pubKeyHashID: 0x00, scriptHashID: 0x05}, nil
+	case breez_sdk_spark.BitcoinNetworkTestnet3, breez_sdk_spark.BitcoinNetworkTestnet4, breez_sdk_spark.BitcoinNetworkSignet:
+		return params{hrp: "tb", pubKeyHashID: 0x6f, scriptHashID: 0xc4}, nil
+	case breez_sdk_spark.BitcoinNetworkRegtest:
+		return params{hrp: "bcrt", pubKeyHashID: 0x6f, scriptHashID: 0xc4}, nil
+	}
+	return params{}, fmt.Errorf("unknown bitcoin network %d", n)
+}
+
+// scriptForAddress returns the output script paid by address.
+func (p params) scriptForAddress(address string) ([]byte, error) {
+	if hrp, version, program, err := decodeSegwit(address); err == nil {
+		if hrp != p.hrp {
+			return nil, fmt.Errorf("address %s is for another network", address)
+		}
+		op := byte(0x00)
+		if version > 0 {
+			op = 0x50 + version
+		}
+		return append([]byte{op, byte(len(program))}, program...), nil
+	}
+	payload, err := decodeBase58Check(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q", address)
+	}
+	if len(payload) != 21 {
+		return nil, fmt.Errorf("address %s has a %d byte payload", address, len(payload))
+	}
+	switch payload[0] {
+	case p.pubKeyHashID:
+		return append(append([]byte{0x76, 0xa9, 0x14}, payload[1:]...), 0x88, 0xac), nil
+	case p.scriptHashID:
+		return append(append([]byte{0xa9, 0x14}, payload[1:]...), 0x87), nil
+	}
+	return nil, fmt.Errorf("address %s is for another network", address)
+}
+
+// addressForScript is the reverse of scriptForAddress. It fails for
+// scripts that have no address, such as OP_RETURN outputs.
+func (p params) addressForScript(script []byte) (string, bool) {
+	switch {
+	case len(script) == 25 && script[0] == 0x76 && script[1] == 0xa9 && script[2] == 0x14 && script[23] == 0x88 && script[24] == 0xac:
+		return encodeBase58Check(append([]byte{p.pubKeyHashID}, script[3:23]...)), true
+	case len(script) == 23 && script[0] == 0xa9 && script[1] == 0x14 && script[22] == 0x87:
+		return encodeBase58Check(append([]byte{p.scriptHashID}, script[2:22]...)), true
+	case len(script) >= 4 && len(script) <= 42 && int(script[1]) == len(script)-2 && (script[0] == 0x00 || script[0] >= 0x51 && script[0] <= 0x60):
+		version := byte(0)
+		if script[0] != 0x00 {
+			version = script[0] - 0x50
+		}
+		address, err := encodeSegwit(p.hrp, version, script[2:])
+		return address, err == nil
+	}
+	return "", false
+}
+
+// Segwit addresses, BIP 173 and BIP 350.
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range gen {
+			if (top>>i)&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits regroups data from groups of from bits to groups of to bits.
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+	maxv := uint(1)<<to - 1
+	var out []byte
+	for _, v := range data {
+		if uint(v)>>from != 0 {
+			return nil, errors.New("invalid data range")
+		}
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
+
+func encodeSegwit(hrp string, version byte, program []byte) (string, error) {
+	if version > 16 || len(program) < 2 || len(program) > 40 || version == 0 && len(program) != 20 && len(program) != 32 {
+		return "", errors.New("invalid witness program")
+	}
+	conv, _ := convertBits(program, 8, 5, true)
+	data := append([]byte{version}, conv...)
+	constant := uint32(bech32Const)
+	if version > 0 {
+		constant = bech32mConst
+	}
+	values := append(bech32HRPExpand(hrp), data...)
+	mod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ constant
+	var b strings.Builder
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, d := range data {
+		b.WriteByte(bech32Charset[d])
+	}
+	for i := range 6 {
+		b.WriteByte(bech32Charset[mod>>(5*(5-i))&31])
+	}
+	return b.String(), nil
+}
+
+func decodeSegwit(address string) (hrp string, version byte, program []byte, err error) {
+	if len(address) > 90 {
+		return "", 0, nil, errors.New("address too long")
+	}
+	lower := strings.ToLower(address)
+	if lower != address && strings.ToUpper(address) != address {
+		return "", 0, nil, errors.New("mixed case address")
+	}
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+7 > len(lower) {
+		return "", 0, nil, errors.New("not a bech32 address")
+	}
+	hrp = lower[:sep]
+	data := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		d := strings.IndexByte(bech32Charset, lower[i])
+		if d < 0 {
+			return "", 0, nil, fmt.Errorf("invalid bech32 character %q", lower[i])
+		}
+		data = append(data, byte(d))
+	}
+	if len(data) < 7 {
+		return "", 0, nil, errors.New("bech32 data too short")
+	}
+	version = data[0]
+	constant := uint32(bech32Const)
+	if version > 0 {
+		constant = bech32mConst
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != constant {
+		return "", 0, nil, errors.New("bad bech32 checksum")
+	}
+	program, err = convertBits(data[1:len(data)-6], 5, 8, false)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if _, err := encodeSegwit(hrp, version, program); err != nil {
+		return "", 0, nil, err
+	}
+	return hrp, version, program, nil
+}
+
+// Legacy addresses.
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func checksum(payload []byte) []byte {
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+func encodeBase58Check(payload []byte) string {
+	data := append(bytes.Clone(payload), checksum(payload)...)
+	n := new(big.Int).SetBytes(data)
+	radix, mod := big.NewInt(58), new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, '1')
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func decodeBase58Check(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(base58Alphabet, s[i])
+		if d < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", s[i])
+		}
+		n.Mul(n, radix).Add(n, big.NewInt(int64(d)))
+	}
+	data := append(make([]byte, zeros), n.Bytes()...)
+	if len(data) < 5 {
+		return nil, errors.New("base58 data too short")
+	}
+	payload, sum := data[:len(data)-4], data[len(data)-4:]
+	if !bytes.Equal(checksum(payload), sum) {
+		return nil, errors.New("bad base58 checksum")
+	}
+	return payload, nil
+}
@@ -0,0 +1,527 @@
+// Package chainsim is a deterministic, in-process Bitcoin chain for tests
+// that cannot reach a real network. Chain implements the SDK
+// BitcoinChainService, and the test controls everything the service
+// reports: which outputs pay which address, when blocks are mined and what
+// they contain, the fee rates over time and injected failures.
+//
+// A deposit test typically funds the address the SDK handed out, mines
+// blocks until the deposit is claimable, and inspects what the SDK
+// broadcast:
+//
+//	chain, _ := chainsim.New(chainsim.Config{})
+//	builder.WithChainService(chain)
+//	...
+//	deposit, _ := chain.Fund(depositAddress, 50_000)
+//	chain.Mine(3)
+//	// The deposit is now confirmed; drive ClaimDeposit or RefundDeposit.
+//	// Raising the fees with ScheduleFees makes claims fail with
+//	// DepositClaimErrorMaxDepositClaimFeeExceeded, and spending the
+//	// deposit elsewhere with DepositClaimErrorMissingUtxo.
+//
+// Transactions are real consensus serializations with real txids, and the
+// mempool applies the checks that do not need scripts: inputs must exist
+// and be unspent, outputs may not exceed inputs and the fee must meet the
+// current MinimumFee. Signatures and scripts are not validated.
+//
+// Block N is timestamped GenesisTime + N*BlockInterval and block hashes
+// derive from their contents, so the same sequence of calls always gives
+// the same chain.
+package chainsim
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// Defaults for the zero Config.
+const (
+	DefaultGenesisTime   = 1_700_000_000
+	DefaultBlockInterval = 600
+)
+
+// Config configures a Chain.
+type Config struct {
+	// Network selects the address format. Defaults to regtest.
+	Network breez_sdk_spark.BitcoinNetwork
+	// GenesisTime is the timestamp of block 0 in Unix seconds. Defaults to
+	// DefaultGenesisTime.
+	GenesisTime uint64
+	// BlockInterval is the number of seconds between blocks. Defaults to
+	// DefaultBlockInterval.
+	BlockInterval uint64
+	// Fees are reported until ScheduleFees changes them. Defaults to
+	// 1 sat/vB for every target.
+	Fees breez_sdk_spark.RecommendedFees
+}
+
+// Output is an amount paid to an address.
+type Output struct {
+	Address string
+	Sats    uint64
+}
+
+// Chain is a simulated chain and mempool. It is safe for concurrent use.
+type Chain struct {
+	mu       sync.Mutex
+	params   params
+	genesis  uint64
+	interval uint64
+
+	blocks  []block // blocks[0] is the empty genesis block
+	txs     map[string]*txEntry
+	arrival []string // every known txid, in the order it was first seen
+	mempool []string
+	spentBy map[OutPoint]string
+	fees    []feeStep // sorted by height
+	funded  uint64    // number of transactions made by Pay
+	fault   func(method string) error
+}
+
+type block struct {
+	hash  string
+	txids []string
+}
+
+type txEntry struct {
+	tx  *tx
+	raw string
+	// height of the block containing the transaction, 0 while it is in
+	// the mempool.
+	height uint32
+}
+
+type feeStep struct {
+	height uint32
+	fees   breez_sdk_spark.RecommendedFees
+}
+
+var _ breez_sdk_spark.BitcoinChainService = (*Chain)(nil)
+
+// New returns a Chain holding only the genesis block.
+func New(cfg Config) (*Chain, error) {
+	if cfg.Network == 0 {
+		cfg.Network = breez_sdk_spark.BitcoinNetworkRegtest
+	}
+	p, err := networkParams(cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("chainsim: %w", err)
+	}
+	if cfg.GenesisTime == 0 {
+		cfg.GenesisTime = DefaultGenesisTime
+	}
+	if cfg.BlockInterval == 0 {
+		cfg.BlockInterval = DefaultBlockInterval
+	}
+	if cfg.Fees == (breez_sdk_spark.RecommendedFees{}) {
+		cfg.Fees = breez_sdk_spark.RecommendedFees{FastestFee: 1, HalfHourFee: 1, HourFee: 1, EconomyFee: 1, MinimumFee: 1}
+	}
+	c := &Chain{
+		params:   p,
+		genesis:  cfg.GenesisTime,
+		interval: cfg.BlockInterval,
+		txs:      make(map[string]*txEntry),
+		spentBy:  make(map[OutPoint]string),
+		fees:     []feeStep{{height: 0, fees: cfg.Fees}},
+	}
+	c.blocks = []block{{hash: blockHash("", 0, nil)}}
+	return c, nil
+}
+
+// SetFault makes every BitcoinChainService method first call fn with the
+// method name, e.g. "BroadcastTransaction", and fail with the error it
+// returns, if any. The error should be a *breez_sdk_spark.ChainServiceError
+// for the SDK to understand it. fn is called without the chain locked, so it
+// may mine blocks or broadcast transactions itself. A nil fn removes the
+// fault.
+func (c *Chain) SetFault(fn func(method string) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fault = fn
+}
+
+// checkFault runs the fault set by SetFault. It must be called without
+// holding c.mu, so the fault can use the chain itself.
+func (c *Chain) checkFault(method string) error {
+	c.mu.Lock()
+	fault := c.fault
+	c.mu.Unlock()
+	if fault == nil {
+		return nil
+	}
+	return fault(method)
+}
+
+// GetAddressUtxos returns the outputs paying address that no known
+// transaction spends, in the order they appeared. Outputs of mempool
+// transactions are included as unconfirmed.
+func (c *Chain) GetAddressUtxos(address string) ([]breez_sdk_spark.Utxo, error) {
+	if err := c.checkFault("GetAddressUtxos"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	script, err := c.params.scriptForAddress(address)
+	if err != nil {
+		return nil, breez_sdk_spark.NewChainServiceErrorInvalidAddress("chainsim: " + err.Error())
+	}
+	utxos := []breez_sdk_spark.Utxo{}
+	for _, txid := range c.arrival {
+		e := c.txs[txid]
+		for vout, out := range e.tx.outputs {
+			if !slices.Equal(out.script, script) {
+				continue
+			}
+			if _, spent := c.spentBy[OutPoint{txid, uint32(vout)}]; spent {
+				continue
+			}
+			utxos = append(utxos, breez_sdk_spark.Utxo{Txid: txid, Vout: uint32(vout), Value: out.value, Status: c.status(e)})
+		}
+	}
+	return utxos, nil
+}
+
+// GetTransactionStatus reports whether txid is confirmed and in which
+// block.
+func (c *Chain) GetTransactionStatus(txid string) (breez_sdk_spark.TxStatus, error) {
+	if err := c.checkFault("GetTransactionStatus"); err != nil {
+		return breez_sdk_spark.TxStatus{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, err := c.lookup(txid)
+	if err != nil {
+		return breez_sdk_spark.TxStatus{}, err
+	}
+	return c.status(e), nil
+}
+
+// GetTransactionHex returns txid in the serialization it was broadcast in.
+func (c *Chain) GetTransactionHex(txid string) (string, error) {
+	if err := c.checkFault("GetTransactionHex"); err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, err := c.lookup(txid)
+	if err != nil {
+		return "", err
+	}
+	return e.raw, nil
+}
+
+// BroadcastTransaction adds tx to the mempool. Resending a known
+// transaction succeeds without effect. Rejections are Generic errors
+// carrying the reason bitcoind would give.
+func (c *Chain) BroadcastTransaction(rawHex string) error {
+	if err := c.checkFault("BroadcastTransaction"); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rawHex = strings.ToLower(strings.TrimSpace(rawHex))
+	t, err := parseTx(rawHex)
+	if err != nil {
+		return reject(err.Error())
+	}
+	txid := t.txid()
+	if _, ok := c.txs[txid]; ok {
+		return nil
+	}
+	if len(t.outputs) == 0 {
+		return reject("bad-txns-vout-empty")
+	}
+	var in, out uint64
+	seen := make(map[OutPoint]bool, len(t.inputs))
+	for _, input := range t.inputs {
+		if seen[input.prev] {
+			return reject("bad-txns-inputs-duplicate")
+		}
+		seen[input.prev] = true
+		parent, ok := c.txs[input.prev.Txid]
+		if !ok || int(input.prev.Vout) >= len(parent.tx.outputs) {
+			return reject("bad-txns-inputs-missingorspent")
+		}
+		if spender, spent := c.spentBy[input.prev]; spent {
+			if c.txs[spender].height == 0 {
+				return reject("txn-mempool-conflict")
+			}
+			return reject("bad-txns-inputs-missingorspent")
+		}
+		in += parent.tx.outputs[input.prev.Vout].value
+	}
+	for _, o := range t.outputs {
+		out += o.value
+	}
+	if out > in {
+		return reject("bad-txns-in-belowout")
+	}
+	if minFee := c.currentFees().MinimumFee * t.vsize(); in-out < minFee {
+		return reject(fmt.Sprintf("min relay fee not met, %d < %d", in-out, minFee))
+	}
+	c.add(txid, t, rawHex)
+	return nil
+}
+
+func reject(reason string) error {
+	return breez_sdk_spark.NewChainServiceErrorGeneric("chainsim: " + reason)
+}
+
+// RecommendedFees returns the fees scheduled for the current height.
+func (c *Chain) RecommendedFees() (breez_sdk_spark.RecommendedFees, error) {
+	if err := c.checkFault("RecommendedFees"); err != nil {
+		return breez_sdk_spark.RecommendedFees{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.currentFees(), nil
+}
+
+// Fund sends sats to address in a new mempool transaction and returns the
+// output. It is Pay for a single output.
+func (c *Chain) Fund(address string, sats uint64) (OutPoint, error) {
+	txid, err := c.Pay(Output{Address: address, Sats: sats})
+	return OutPoint{Txid: txid, Vout: 0}, err
+}
+
+// Pay puts a transaction paying outputs, in that order, in the mempool and
+// returns its txid. Its single input comes from nowhere, so it can pay
+// any amount.
+func (c *Chain) Pay(outputs ...Output) (string, error) {
+	if len(outputs) == 0 {
+		return "", fmt.Errorf("chainsim: no outputs")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &tx{version: 2}
+	for _, o := range outputs {
+		script, err := c.params.scriptForAddress(o.Address)
+		if err != nil {
+			return "", fmt.Errorf("chainsim: %w", err)
+		}
+		t.outputs = append(t.outputs, txOut{value: o.Sats, script: script})
+	}
+	c.funded++
+	source := sha256.Sum256(binary.BigEndian.AppendUint64([]byte("chainsim funding"), c.funded))
+	t.inputs = []txIn{{prev: OutPoint{Txid: hex.EncodeToString(source[:])}, sequence: 0xffffffff}}
+	txid := t.txid()
+	c.add(txid, t, hex.EncodeToString(t.serialize(true)))
+	return txid, nil
+}
+
+func (c *Chain) add(txid string, t *tx, raw string) {
+	c.txs[txid] = &txEntry{tx: t, raw: raw}
+	c.arrival = append(c.arrival, txid)
+	c.mempool = append(c.mempool, txid)
+	for _, in := range t.inputs {
+		if _, ok := c.txs[in.prev.Txid]; ok {
+			c.spentBy[in.prev] = txid
+		}
+	}
+}
+
+// Mine mines n blocks and returns their hashes. The first one confirms
+// every mempool transaction.
+func (c *Chain) Mine(n int) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hashes := make([]string, 0, n)
+	for range n {
+		hashes = append(hashes, c.mine(c.mempool))
+	}
+	return hashes
+}
+
+// MineWith mines one block confirming only the given mempool transactions
+// and the unconfirmed transactions they spend from. The rest stay in the
+// mempool.
+func (c *Chain) MineWith(txids ...string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	include := make(map[string]bool)
+	var visit func(txid string)
+	visit = func(txid string) {
+		if include[txid] {
+			return
+		}
+		include[txid] = true
+		for _, in := range c.txs[txid].tx.inputs {
+			if parent, ok := c.txs[in.prev.Txid]; ok && parent.height == 0 {
+				visit(in.prev.Txid)
+			}
+		}
+	}
+	for _, txid := range txids {
+		if e, ok := c.txs[txid]; !ok || e.height != 0 {
+			return "", fmt.Errorf("chainsim: %s is not in the mempool", txid)
+		}
+		visit(txid)
+	}
+	// Mempool order puts parents before children.
+	var confirmed []string
+	for _, txid := range c.mempool {
+		if include[txid] {
+			confirmed = append(confirmed, txid)
+		}
+	}
+	return c.mine(confirmed), nil
+}
+
+func (c *Chain) mine(txids []string) string {
+	height := uint32(len(c.blocks))
+	txids = slices.Clone(txids)
+	hash := blockHash(c.blocks[height-1].hash, height, txids)
+	c.blocks = append(c.blocks, block{hash: hash, txids: txids})
+	for _, txid := range txids {
+		c.txs[txid].height = height
+	}
+	c.mempool = slices.DeleteFunc(c.mempool, func(txid string) bool { return c.txs[txid].height != 0 })
+	return hash
+}
+
+// Reorg disconnects the last depth blocks. Their transactions go back to
+// the mempool, ahead of those already there, to be mined again or not.
+func (c *Chain) Reorg(depth int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if depth < 0 || depth >= len(c.blocks) {
+		return fmt.Errorf("chainsim: cannot disconnect %d of %d blocks", depth, len(c.blocks)-1)
+	}
+	var returned []string
+	for _, b := range c.blocks[len(c.blocks)-depth:] {
+		for _, txid := range b.txids {
+			c.txs[txid].height = 0
+			returned = append(returned, txid)
+		}
+	}
+	c.blocks = c.blocks[:len(c.blocks)-depth]
+	c.mempool = append(returned, c.mempool...)
+	return nil
+}
+
+// Height returns the height of the chain tip.
+func (c *Chain) Height() uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint32(len(c.blocks) - 1)
+}
+
+// BlockHash returns the hash of the block at height.
+func (c *Chain) BlockHash(height uint32) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if int(height) >= len(c.blocks) {
+		return "", false
+	}
+	return c.blocks[height].hash, true
+}
+
+// Confirmations returns the number of blocks confirming txid, 0 for
+// mempool and unknown transactions.
+func (c *Chain) Confirmations(txid string) uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.txs[txid]
+	if !ok || e.height == 0 {
+		return 0
+	}
+	return uint32(len(c.blocks)) - e.height
+}
+
+// Mempool returns the unconfirmed transactions.
+func (c *Chain) Mempool() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.mempool)
+}
+
+// SpentBy returns the transaction spending the given output, confirmed or
+// not.
+func (c *Chain) SpentBy(op OutPoint) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	txid, ok := c.spentBy[op]
+	return txid, ok
+}
+
+// Outputs decodes the outputs of txid, to check where a transaction the
+// SDK broadcast sends its funds. Outputs without an address, such as
+// OP_RETURN, have an empty Address.
+func (c *Chain) Outputs(txid string) ([]Output, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.txs[txid]
+	if !ok {
+		return nil, fmt.Errorf("chainsim: unknown transaction %s", txid)
+	}
+	outputs := make([]Output, len(e.tx.outputs))
+	for i, out := range e.tx.outputs {
+		address, _ := c.params.addressForScript(out.script)
+		outputs[i] = Output{Address: address, Sats: out.value}
+	}
+	return outputs, nil
+}
+
+// ScheduleFees makes RecommendedFees return fees once the tip reaches
+// height, until a later step takes over. Scheduling the current height
+// changes the fees immediately.
+func (c *Chain) ScheduleFees(height uint32, fees breez_sdk_spark.RecommendedFees) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i, found := slices.BinarySearchFunc(c.fees, height, func(s feeStep, h uint32) int { return int(s.height) - int(h) })
+	if found {
+		c.fees[i].fees = fees
+		return
+	}
+	c.fees = slices.Insert(c.fees, i, feeStep{height: height, fees: fees})
+}
+
+// SetFees changes the fees from the current height on.
+func (c *Chain) SetFees(fees breez_sdk_spark.RecommendedFees) {
+	c.ScheduleFees(c.Height(), fees)
+}
+
+func (c *Chain) currentFees() breez_sdk_spark.RecommendedFees {
+	height := uint32(len(c.blocks) - 1)
+	fees := c.fees[0].fees
+	for _, s := range c.fees {
+		if s.height > height {
+			break
+		}
+		fees = s.fees
+	}
+	return fees
+}
+
+func (c *Chain) lookup(txid string) (*txEntry, error) {
+	e, ok := c.txs[strings.ToLower(txid)]
+	if !ok {
+		return nil, breez_sdk_spark.NewChainServiceErrorGeneric("chainsim: transaction not found: " + txid)
+	}
+	return e, nil
+}
+
+func (c *Chain) status(e *txEntry) breez_sdk_spark.TxStatus {
+	if e.height == 0 {
+		return breez_sdk_spark.TxStatus{}
+	}
+	height := e.height
+	blockTime := c.genesis + uint64(height)*c.interval
+	return breez_sdk_spark.TxStatus{Confirmed: true, BlockHeight: &height, BlockTime: &blockTime}
+}
+
+func blockHash(prev string, height uint32, txids []string) string {
+	h := sha256.New()
+	h.Write([]byte(prev))
+	h.Write(binary.BigEndian.AppendUint32(nil, height))
+	for _, txid := range txids {
+		h.Write([]byte(txid))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
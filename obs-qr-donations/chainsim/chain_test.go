@@ -0,0 +1,354 @@
+package chainsim
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+func newChain(t *testing.T) *Chain {
+	t.Helper()
+	c, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// address returns a regtest P2WPKH address derived from name.
+func address(t *testing.T, name string) string {
+	t.Helper()
+	program := bytes.Repeat([]byte(name), 20)[:20]
+	a, err := encodeSegwit("bcrt", 0, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// spend builds a signed-looking segwit transaction spending prev and
+// paying outputs.
+func spend(t *testing.T, c *Chain, prev []OutPoint, outputs ...Output) (string, string) {
+	t.Helper()
+	tx := &tx{version: 2}
+	for _, p := range prev {
+		tx.inputs = append(tx.inputs, txIn{prev: p, sequence: 0xfffffffd, witness: [][]byte{bytes.Repeat([]byte{0x30}, 71), bytes.Repeat([]byte{0x02}, 33)}})
+	}
+	for _, o := range outputs {
+		script, err := c.params.scriptForAddress(o.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.outputs = append(tx.outputs, txOut{value: o.Sats, script: script})
+	}
+	return hex.EncodeToString(tx.serialize(true)), tx.txid()
+}
+
+func TestAddresses(t *testing.T) {
+	mainnet, _ := networkParams(breez_sdk_spark.BitcoinNetworkBitcoin)
+	for address, script := range map[string]string{
+		"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4":                                 "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+		"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y": "5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6",
+		"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2":                                         "76a91477bff20c60e522dfaa3350c39b030a5d004e839a88ac",
+		"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy":                                         "a914b472a266d0bd89c13706a4132ccfb16f7c3b9fcb87",
+	} {
+		got, err := mainnet.scriptForAddress(address)
+		if err != nil || hex.EncodeToString(got) != script {
+			t.Errorf("scriptForAddress(%s) = %x, %v; want %s", address, got, err, script)
+			continue
+		}
+		if back, ok := mainnet.addressForScript(got); !ok || back != strings.ToLower(address) && back != address {
+			t.Errorf("addressForScript(%s) = %q", script, back)
+		}
+	}
+
+	regtest, _ := networkParams(breez_sdk_spark.BitcoinNetworkRegtest)
+	for _, bad := range []string{
+		"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",   // mainnet
+		"bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", // bad checksum
+		"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",           // mainnet
+		"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3",           // bad checksum
+		"",
+		"not an address",
+	} {
+		if _, err := regtest.scriptForAddress(bad); err == nil {
+			t.Errorf("regtest accepted %q", bad)
+		}
+	}
+}
+
+func TestTxRoundTrip(t *testing.T) {
+	c := newChain(t)
+	raw, txid := spend(t, c, []OutPoint{{Txid: strings.Repeat("ab", 32), Vout: 3}}, Output{Address: address(t, "a"), Sats: 1234})
+	parsed, err := parseTx(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(parsed.serialize(true)); got != raw {
+		t.Fatalf("serialization changed:\n%s\n%s", got, raw)
+	}
+	if parsed.txid() != txid || parsed.inputs[0].prev.Txid != strings.Repeat("ab", 32) || parsed.inputs[0].prev.Vout != 3 {
+		t.Fatalf("parsed = %+v", parsed)
+	}
+	// The txid does not commit to witness data.
+	parsed.inputs[0].witness = nil
+	if parsed.txid() != txid {
+		t.Fatal("txid depends on the witness")
+	}
+	if parsed.vsize() >= uint64(len(raw)/2) {
+		t.Fatalf("vsize %d does not discount the witness", parsed.vsize())
+	}
+
+	for _, bad := range []string{"", "zz", raw[:len(raw)-2], raw + "00"} {
+		if _, err := parseTx(bad); err == nil {
+			t.Errorf("parseTx(%.16q...) succeeded", bad)
+		}
+	}
+}
+
+func TestDepositLifecycle(t *testing.T) {
+	c := newChain(t)
+	c.Mine(100)
+	depositAddress := address(t, "deposit")
+
+	deposit, err := c.Fund(depositAddress, 50_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxos, err := c.GetAddressUtxos(depositAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 || utxos[0].Txid != deposit.Txid || utxos[0].Vout != 0 || utxos[0].Value != 50_000 || utxos[0].Status.Confirmed {
+		t.Fatalf("utxos = %+v", utxos)
+	}
+
+	c.Mine(3)
+	if n := c.Confirmations(deposit.Txid); n != 3 {
+		t.Fatalf("confirmations = %d", n)
+	}
+	status, err := c.GetTransactionStatus(deposit.Txid)
+	if err != nil || !status.Confirmed || *status.BlockHeight != 101 || *status.BlockTime != DefaultGenesisTime+101*DefaultBlockInterval {
+		t.Fatalf("status = %+v, %v", status, err)
+	}
+
+	// The SDK reads the deposit back to claim it.
+	raw, err := c.GetTransactionHex(deposit.Txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseTx(raw)
+	if err != nil || parsed.txid() != deposit.Txid {
+		t.Fatalf("deposit tx does not parse back: %v", err)
+	}
+	outputs, _ := c.Outputs(deposit.Txid)
+	if len(outputs) != 1 || outputs[0] != (Output{Address: depositAddress, Sats: 50_000}) {
+		t.Fatalf("outputs = %+v", outputs)
+	}
+}
+
+func TestRefund(t *testing.T) {
+	c := newChain(t)
+	depositAddress, refundAddress := address(t, "deposit"), address(t, "refund")
+	deposit, _ := c.Fund(depositAddress, 50_000)
+	c.Mine(1)
+
+	refundTx, refundTxid := spend(t, c, []OutPoint{deposit}, Output{Address: refundAddress, Sats: 49_000})
+	if err := c.BroadcastTransaction(refundTx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BroadcastTransaction(refundTx); err != nil {
+		t.Fatalf("rebroadcast: %v", err)
+	}
+	if utxos, _ := c.GetAddressUtxos(depositAddress); len(utxos) != 0 {
+		t.Fatalf("deposit still unspent: %+v", utxos)
+	}
+	if spender, ok := c.SpentBy(deposit); !ok || spender != refundTxid {
+		t.Fatalf("deposit spent by %q", spender)
+	}
+	if got := c.Mempool(); len(got) != 1 || got[0] != refundTxid {
+		t.Fatalf("mempool = %v", got)
+	}
+
+	c.Mine(1)
+	utxos, _ := c.GetAddressUtxos(refundAddress)
+	if len(utxos) != 1 || utxos[0].Value != 49_000 || !utxos[0].Status.Confirmed {
+		t.Fatalf("refund utxos = %+v", utxos)
+	}
+	if err := c.BroadcastTransaction(refundTx); err != nil {
+		t.Fatalf("rebroadcast after mining: %v", err)
+	}
+}
+
+func TestMempoolPolicy(t *testing.T) {
+	c := newChain(t)
+	a, b := address(t, "a"), address(t, "b")
+	funding, _ := c.Fund(a, 10_000)
+	first, _ := spend(t, c, []OutPoint{funding}, Output{Address: b, Sats: 9_000})
+	if err := c.BroadcastTransaction(first); err != nil {
+		t.Fatal(err)
+	}
+
+	conflict, _ := spend(t, c, []OutPoint{funding}, Output{Address: a, Sats: 8_000})
+	missing, _ := spend(t, c, []OutPoint{{Txid: strings.Repeat("00", 32)}}, Output{Address: a, Sats: 1})
+	second, _ := c.Fund(a, 10_000)
+	belowOut, _ := spend(t, c, []OutPoint{second}, Output{Address: b, Sats: 10_001})
+	cheap, _ := spend(t, c, []OutPoint{second}, Output{Address: b, Sats: 9_990})
+	duplicate, _ := spend(t, c, []OutPoint{second, second}, Output{Address: b, Sats: 1_000})
+	for tx, reason := range map[string]string{
+		conflict:  "txn-mempool-conflict",
+		missing:   "bad-txns-inputs-missingorspent",
+		belowOut:  "bad-txns-in-belowout",
+		cheap:     "min relay fee not met",
+		duplicate: "bad-txns-inputs-duplicate",
+		"0200":    "TX decode failed",
+	} {
+		err := c.BroadcastTransaction(tx)
+		if !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) || !strings.Contains(err.Error(), reason) {
+			t.Errorf("%s: got %v", reason, err)
+		}
+	}
+
+	c.Mine(1)
+	if err := c.BroadcastTransaction(conflict); err == nil || !strings.Contains(err.Error(), "bad-txns-inputs-missingorspent") {
+		t.Fatalf("spending a confirmed spent output: got %v", err)
+	}
+}
+
+func TestFeeSchedule(t *testing.T) {
+	c := newChain(t)
+	high := breez_sdk_spark.RecommendedFees{FastestFee: 200, HalfHourFee: 150, HourFee: 100, EconomyFee: 50, MinimumFee: 10}
+	c.ScheduleFees(5, high)
+
+	if fees, _ := c.RecommendedFees(); fees.FastestFee != 1 {
+		t.Fatalf("fees before the spike = %+v", fees)
+	}
+	c.Mine(5)
+	if fees, _ := c.RecommendedFees(); fees != high {
+		t.Fatalf("fees at height 5 = %+v", fees)
+	}
+
+	// The mempool follows the current minimum fee.
+	funding, _ := c.Fund(address(t, "a"), 10_000)
+	cheap, _ := spend(t, c, []OutPoint{funding}, Output{Address: address(t, "b"), Sats: 9_500})
+	if err := c.BroadcastTransaction(cheap); err == nil {
+		t.Fatal("transaction below the scheduled minimum fee accepted")
+	}
+
+	c.SetFees(breez_sdk_spark.RecommendedFees{FastestFee: 3, HalfHourFee: 2, HourFee: 2, EconomyFee: 1, MinimumFee: 1})
+	if err := c.BroadcastTransaction(cheap); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reorg(1); err != nil {
+		t.Fatal(err)
+	}
+	if fees, _ := c.RecommendedFees(); fees.FastestFee != 1 {
+		t.Fatalf("fees after reorg below the spike = %+v", fees)
+	}
+}
+
+func TestMineWithAndReorg(t *testing.T) {
+	c := newChain(t)
+	a, b := address(t, "a"), address(t, "b")
+	parent, _ := c.Fund(a, 10_000)
+	childTx, child := spend(t, c, []OutPoint{parent}, Output{Address: b, Sats: 9_000})
+	if err := c.BroadcastTransaction(childTx); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := c.Fund(b, 5_000)
+
+	// Confirming the child also confirms its unconfirmed parent.
+	if _, err := c.MineWith(child); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Mempool(); len(got) != 1 || got[0] != other.Txid {
+		t.Fatalf("mempool = %v", got)
+	}
+	if c.Confirmations(parent.Txid) != 1 || c.Confirmations(child) != 1 {
+		t.Fatal("parent and child not confirmed together")
+	}
+	if _, err := c.MineWith(child); err == nil {
+		t.Fatal("mined a confirmed transaction again")
+	}
+
+	c.Mine(2)
+	hash, _ := c.BlockHash(1)
+	if err := c.Reorg(3); err != nil {
+		t.Fatal(err)
+	}
+	if c.Height() != 0 || len(c.Mempool()) != 3 {
+		t.Fatalf("height %d, mempool %v", c.Height(), c.Mempool())
+	}
+	if status, _ := c.GetTransactionStatus(child); status.Confirmed {
+		t.Fatal("reorged transaction still confirmed")
+	}
+	c.MineWith(other.Txid)
+	if again, _ := c.BlockHash(1); again == hash {
+		t.Fatal("different block got the same hash")
+	}
+	if err := c.Reorg(5); err == nil {
+		t.Fatal("reorged past genesis")
+	}
+}
+
+func TestDeterminism(t *testing.T) {
+	run := func() (string, string) {
+		c := newChain(t)
+		c.Mine(10)
+		deposit, _ := c.Fund(address(t, "deposit"), 21_000)
+		c.Mine(2)
+		hash, _ := c.BlockHash(12)
+		return deposit.Txid, hash
+	}
+	txid1, hash1 := run()
+	txid2, hash2 := run()
+	if txid1 != txid2 || hash1 != hash2 {
+		t.Fatal("identical runs produced different chains")
+	}
+}
+
+func TestFaultsAndErrors(t *testing.T) {
+	c := newChain(t)
+	down := breez_sdk_spark.NewChainServiceErrorServiceConnectivity("explorer down")
+	c.SetFault(func(method string) error {
+		if method == "GetAddressUtxos" {
+			return down
+		}
+		return nil
+	})
+	if _, err := c.GetAddressUtxos(address(t, "a")); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("injected fault: got %v", err)
+	}
+	if _, err := c.RecommendedFees(); err != nil {
+		t.Fatalf("other methods: %v", err)
+	}
+	// A fault may drive the chain, e.g. to mine a block before a query.
+	c.SetFault(func(method string) error {
+		if method == "GetTransactionStatus" {
+			c.Mine(1)
+		}
+		return nil
+	})
+	deposit, _ := c.Fund(address(t, "a"), 1_000)
+	if status, err := c.GetTransactionStatus(deposit.Txid); err != nil || !status.Confirmed {
+		t.Fatalf("fault mining a block: got %+v, %v", status, err)
+	}
+	c.SetFault(nil)
+
+	if _, err := c.GetAddressUtxos("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorInvalidAddress) {
+		t.Fatalf("mainnet address on regtest: got %v", err)
+	}
+	if _, err := c.GetTransactionHex(strings.Repeat("11", 32)); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) {
+		t.Fatalf("unknown tx: got %v", err)
+	}
+	if _, err := c.Fund("nope", 1); err == nil {
+		t.Fatal("funded an invalid address")
+	}
+	if _, err := New(Config{Network: 42}); err == nil {
+		t.Fatal("unknown network accepted")
+	}
+}
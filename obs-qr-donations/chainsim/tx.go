@@ -0,0 +1,227 @@
+package chainsim
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// OutPoint identifies a transaction output.
+type OutPoint struct {
+	Txid string
+	Vout uint32
+}
+
+type txIn struct {
+	prev     OutPoint
+	script   []byte
+	sequence uint32
+	witness  [][]byte
+}
+
+type txOut struct {
+	value  uint64
+	script []byte
+}
+
+// tx is a Bitcoin transaction in the consensus serialization, with or
+// without witness data. Scripts are carried but never executed.
+type tx struct {
+	version  uint32
+	inputs   []txIn
+	outputs  []txOut
+	lockTime uint32
+}
+
+func (t *tx) hasWitness() bool {
+	return slices.ContainsFunc(t.inputs, func(in txIn) bool { return len(in.witness) > 0 })
+}
+
+func (t *tx) serialize(withWitness bool) []byte {
+	withWitness = withWitness && t.hasWitness()
+	var b bytes.Buffer
+	b.Write(binary.LittleEndian.AppendUint32(nil, t.version))
+	if withWitness {
+		b.Write([]byte{0x00, 0x01})
+	}
+	writeVarInt(&b, uint64(len(t.inputs)))
+	for _, in := range t.inputs {
+		hash, _ := hex.DecodeString(in.prev.Txid)
+		slices.Reverse(hash)
+		b.Write(hash)
+		b.Write(binary.LittleEndian.AppendUint32(nil, in.prev.Vout))
+		writeBytes(&b, in.script)
+		b.Write(binary.LittleEndian.AppendUint32(nil, in.sequence))
+	}
+	writeVarInt(&b, uint64(len(t.outputs)))
+	for _, out := range t.outputs {
+		b.Write(binary.LittleEndian.AppendUint64(nil, out.value))
+		writeBytes(&b, out.script)
+	}
+	if withWitness {
+		for _, in := range t.inputs {
+			writeVarInt(&b, uint64(len(in.witness)))
+			for _, item := range in.witness {
+				writeBytes(&b, item)
+			}
+		}
+	}
+	b.Write(binary.LittleEndian.AppendUint32(nil, t.lockTime))
+	return b.Bytes()
+}
+
+// txid is the hash of the serialization without witness, in the usual
+// byte reversed hex.
+func (t *tx) txid() string {
+	first := sha256.Sum256(t.serialize(false))
+	second := sha256.Sum256(first[:])
+	slices.Reverse(second[:])
+	return hex.EncodeToString(second[:])
+}
+
+// vsize is the virtual size in vbytes, as used for fee rates.
+func (t *tx) vsize() uint64 {
+	weight := uint64(len(t.serialize(false)))*3 + uint64(len(t.serialize(true)))
+	return (weight + 3) / 4
+}
+
+func parseTx(rawHex string) (*tx, error) {
+	raw, err := hex.DecodeString(rawHex)
+	if err != nil {
+		return nil, fmt.Errorf("TX decode failed: %v", err)
+	}
+	r := &reader{data: raw}
+	t := &tx{version: r.uint32()}
+	segwit := false
+	if len(r.data)-r.pos >= 2 && r.data[r.pos] == 0x00 && r.data[r.pos+1] == 0x01 {
+		segwit = true
+		r.pos += 2
+	}
+	nIn := r.varInt()
+	if nIn == 0 && r.err == nil {
+		return nil, errors.New("TX decode failed: no inputs")
+	}
+	for i := uint64(0); i < nIn && r.err == nil; i++ {
+		hash := slices.Clone(r.bytes(32))
+		slices.Reverse(hash)
+		t.inputs = append(t.inputs, txIn{
+			prev:     OutPoint{Txid: hex.EncodeToString(hash), Vout: r.uint32()},
+			script:   r.varBytes(),
+			sequence: r.uint32(),
+		})
+	}
+	nOut := r.varInt()
+	for i := uint64(0); i < nOut && r.err == nil; i++ {
+		t.outputs = append(t.outputs, txOut{value: r.uint64(), script: r.varBytes()})
+	}
+	if segwit {
+		for i := range t.inputs {
+			items := r.varInt()
+			for j := uint64(0); j < items && r.err == nil; j++ {
+				t.inputs[i].witness = append(t.inputs[i].witness, r.varBytes())
+			}
+		}
+		if r.err == nil && !t.hasWitness() {
+			return nil, errors.New("TX decode failed: witness flag without witness data")
+		}
+	}
+	t.lockTime = r.uint32()
+	if r.err == nil && r.pos != len(raw) {
+		r.err = fmt.Errorf("%d trailing bytes", len(raw)-r.pos)
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("TX decode failed: %v", r.err)
+	}
+	return t, nil
+}
+
+func writeVarInt(b *bytes.Buffer, n uint64) {
+	switch {
+	case n < 0xfd:
+		b.WriteByte(byte(n))
+	case n <= 0xffff:
+		b.WriteByte(0xfd)
+		b.Write(binary.LittleEndian.AppendUint16(nil, uint16(n)))
+	case n <= 0xffffffff:
+		b.WriteByte(0xfe)
+		b.Write(binary.LittleEndian.AppendUint32(nil, uint32(n)))
+	default:
+		b.WriteByte(0xff)
+		b.Write(binary.LittleEndian.AppendUint64(nil, n))
+	}
+}
+
+func writeBytes(b *bytes.Buffer, data []byte) {
+	writeVarInt(b, uint64(len(data)))
+	b.Write(data)
+}
+
+// reader decodes the serialization. After the first error every read
+// returns zero values and err is kept.
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data)-r.pos < n {
+		r.err = errors.New("unexpected end of data")
+		return nil
+	}
+	out := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return out
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *reader) varInt() uint64 {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	switch b[0] {
+	case 0xfd:
+		if b := r.bytes(2); b != nil {
+			return uint64(binary.LittleEndian.Uint16(b))
+		}
+	case 0xfe:
+		return uint64(r.uint32())
+	case 0xff:
+		return r.uint64()
+	default:
+		return uint64(b[0])
+	}
+	return 0
+}
+
+func (r *reader) varBytes() []byte {
+	n := r.varInt()
+	if n > uint64(len(r.data)) {
+		if r.err == nil {
+			r.err = errors.New("length out of range")
+		}
+		return nil
+	}
+	return slices.Clone(r.bytes(int(n)))
+}
@@ -0,0 +1,69 @@
+package encstorage
+
+import (
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// Key is an AES-256 key.
+type Key [32]byte
+
+// passphraseIterations follows the OWASP recommendation for
+// PBKDF2-HMAC-SHA256.
+const passphraseIterations = 600_000
+
+// seedKeyInfo separates the storage key from anything else derived from the
+// wallet seed.
+const seedKeyInfo = "obs-qr-donations/encstorage/v1"
+
+// KeyFromPassphrase stretches passphrase with PBKDF2-HMAC-SHA256. The salt
+// must be random and stored alongside the data; NewWithPassphrase keeps it
+// in the wrapped storage.
+func KeyFromPassphrase(passphrase string, salt []byte) (Key, error) {
+	var key Key
+	if passphrase == "" {
+		return key, errors.New("empty passphrase")
+	}
+	if len(salt) < 16 {
+		return key, fmt.Errorf("salt must be at least 16 bytes, got %d", len(salt))
+	}
+	b, err := pbkdf2.Key(sha256.New, passphrase, salt, passphraseIterations, len(key))
+	if err != nil {
+		return key, err
+	}
+	copy(key[:], b)
+	return key, nil
+}
+
+// KeyFromSeed derives the key from the wallet seed with HKDF-SHA256, so the
+// storage opens with the same secret as the wallet and needs no extra
+// passphrase.
+func KeyFromSeed(seed breez_sdk_spark.Seed) (Key, error) {
+	var key Key
+	var secret, salt []byte
+	switch s := seed.(type) {
+	case breez_sdk_spark.SeedMnemonic:
+		secret = []byte(s.Mnemonic)
+		if s.Passphrase != nil {
+			salt = []byte(*s.Passphrase)
+		}
+	case breez_sdk_spark.SeedEntropy:
+		secret = s.Field0
+	default:
+		return key, fmt.Errorf("unsupported seed %T", seed)
+	}
+	if len(secret) == 0 {
+		return key, errors.New("empty seed")
+	}
+	b, err := hkdf.Key(sha256.New, secret, salt, seedKeyInfo, len(key))
+	if err != nil {
+		return key, err
+	}
+	copy(key[:], b)
+	return key, nil
+}
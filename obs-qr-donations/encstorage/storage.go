@@ -0,0 +1,632 @@
+// Package encstorage wraps any SDK Storage so that personal data is
+// encrypted at rest with AES-256-GCM.
+//
+// Payment descriptions, LNURL pay and withdraw metadata, LNURL receive
+// metadata (sender comments and zap requests/receipts) and cached item
+// values are sealed before they reach the wrapped storage. Everything
+// ListPaymentsRequest filters on (type, status, timestamp, asset, HTLC
+// status) stays in clear so the wrapped storage can still query it, as does
+// the payment hash LNURL metadata is keyed on. Each ciphertext is bound to
+// the payment id, payment hash or cache key it belongs to, so values cannot
+// be swapped between records.
+//
+// Lightning and Spark invoices are sealed deterministically: the nonce is a
+// keyed HMAC of the invoice, so the same invoice always seals to the same
+// value and GetPaymentByInvoice still runs on the wrapped storage without
+// revealing the invoice to it.
+//
+// Values written before encryption was enabled are rejected unless
+// Config.AllowPlaintext is set.
+//
+// Contexts reach the wrapped storage when it was made by
+// breez_sdk_spark.NewStorageFromCtx and the SDK is handed the Ctx view:
+//
+//	s, err := encstorage.NewWithSeed(breez_sdk_spark.NewStorageFromCtx(db.Ctx()), seed, encstorage.Config{})
+//	...
+//	builder.WithStorage(breez_sdk_spark.NewStorageFromCtx(s.Ctx()))
+package encstorage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/storageutil"
+)
+
+// sealedPrefix marks a sealed value: "enc1:" followed by the base64 of the
+// nonce and the AES-GCM ciphertext.
+const sealedPrefix = "enc1:"
+
+// invoicePrefix marks a deterministically sealed invoice: "encinv1:"
+// followed by the base64 of the HMAC nonce and the AES-GCM ciphertext.
+const invoicePrefix = "encinv1:"
+
+// invoiceMACInfo derives the invoice HMAC key from the storage key.
+const invoiceMACInfo = "obs-qr-donations/encstorage/invoice"
+
+const (
+	// saltKey is the cached item holding the passphrase salt.
+	saltKey = "encstorage_salt"
+	// keyCheckKey is the cached item used to detect a wrong key on open.
+	keyCheckKey  = "encstorage_key_check"
+	keyCheckText = "obs-qr-donations"
+)
+
+// errPlaintext is returned for a value that is not sealed when
+// Config.AllowPlaintext is off.
+var errPlaintext = errors.New("value is not encrypted")
+
+// Config holds the options of an EncryptedStorage.
+type Config struct {
+	// AllowPlaintext reads values that carry no sealed prefix back
+	// unchanged. Set it only to migrate a storage that was written before
+	// encryption was enabled: with it on, anyone who can write to the
+	// wrapped storage can plant values that are read back as genuine. The
+	// key check value is never accepted in clear.
+	AllowPlaintext bool
+}
+
+// EncryptedStorage is a breez_sdk_spark.Storage that encrypts personal data
+// before handing it to another Storage.
+type EncryptedStorage struct {
+	inner          breez_sdk_spark.StorageCtx
+	aead           cipher.AEAD
+	invoiceMAC     []byte
+	allowPlaintext bool
+}
+
+var _ breez_sdk_spark.Storage = (*EncryptedStorage)(nil)
+
+// storageCtx implements the methods of an EncryptedStorage.
+type storageCtx struct {
+	*EncryptedStorage
+}
+
+var _ breez_sdk_spark.StorageCtx = storageCtx{}
+
+// New wraps inner with key. The first open stores a key check value in
+// inner; later opens with a different key fail with an InitializationError.
+func New(inner breez_sdk_spark.Storage, key Key, cfg Config) (*EncryptedStorage, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+	}
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write([]byte(invoiceMACInfo))
+	s := &EncryptedStorage{
+		inner:          breez_sdk_spark.AsStorageCtx(inner),
+		aead:           aead,
+		invoiceMAC:     mac.Sum(nil),
+		allowPlaintext: cfg.AllowPlaintext,
+	}
+	check, err := s.inner.GetCachedItem(context.Background(), keyCheckKey)
+	if err != nil {
+		return nil, err
+	}
+	if check == nil {
+		sealed, err := s.seal([]byte(keyCheckText), keyCheckKey)
+		if err != nil {
+			return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+		}
+		if err := s.inner.SetCachedItem(context.Background(), keyCheckKey, sealed); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	if plain, err := s.openSealed(*check, keyCheckKey); err != nil || string(plain) != keyCheckText {
+		return nil, breez_sdk_spark.NewStorageErrorInitializationError("wrong encryption key")
+	}
+	return s, nil
+}
+
+// NewWithPassphrase wraps inner with a key stretched from passphrase. The
+// random salt is created on first use and kept as a cached item in inner.
+func NewWithPassphrase(inner breez_sdk_spark.Storage, passphrase string, cfg Config) (*EncryptedStorage, error) {
+	var salt []byte
+	stored, err := inner.GetCachedItem(saltKey)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		if salt, err = base64.StdEncoding.DecodeString(*stored); err != nil {
+			return nil, breez_sdk_spark.NewStorageErrorInitializationError(fmt.Sprintf("corrupt salt: %v", err))
+		}
+	} else {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+		}
+		if err := inner.SetCachedItem(saltKey, base64.StdEncoding.EncodeToString(salt)); err != nil {
+			return nil, err
+		}
+	}
+	key, err := KeyFromPassphrase(passphrase, salt)
+	if err != nil {
+		return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+	}
+	return New(inner, key, cfg)
+}
+
+// NewWithSeed wraps inner with a key derived from the wallet seed.
+func NewWithSeed(inner breez_sdk_spark.Storage, seed breez_sdk_spark.Seed, cfg Config) (*EncryptedStorage, error) {
+	key, err := KeyFromSeed(seed)
+	if err != nil {
+		return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+	}
+	return New(inner, key, cfg)
+}
+
+// Ctx returns the view of s that hands contexts on to the wrapped storage,
+// so that its queries stop when the SDK gives up on a call.
+func (s *EncryptedStorage) Ctx() breez_sdk_spark.StorageCtx {
+	return storageCtx{s}
+}
+
+func (s *EncryptedStorage) seal(plain []byte, aad string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, plain, []byte(aad))
+	return sealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// open reverses seal. Values without the sealed prefix were written before
+// encryption was enabled and are returned as they are if the config allows.
+func (s *EncryptedStorage) open(value, aad string) ([]byte, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		if !s.allowPlaintext {
+			return nil, errPlaintext
+		}
+		return []byte(value), nil
+	}
+	return s.openSealed(value, aad)
+}
+
+// openSealed is open without the plaintext fallback.
+func (s *EncryptedStorage) openSealed(value, aad string) ([]byte, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		return nil, errPlaintext
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(value[len(sealedPrefix):])
+	if err != nil {
+		return nil, err
+	}
+	n := s.aead.NonceSize()
+	if len(raw) < n {
+		return nil, errors.New("sealed value too short")
+	}
+	return s.aead.Open(nil, raw[:n], raw[n:], []byte(aad))
+}
+
+// sealInvoice seals invoice under a nonce derived from it, so lookups can
+// seal the invoice they are given and compare.
+func (s *EncryptedStorage) sealInvoice(invoice string) string {
+	mac := hmac.New(sha256.New, s.invoiceMAC)
+	mac.Write([]byte(invoice))
+	nonce := mac.Sum(nil)[:s.aead.NonceSize()]
+	sealed := s.aead.Seal(nonce, nonce, []byte(invoice), []byte(invoiceAAD))
+	return invoicePrefix + base64.RawStdEncoding.EncodeToString(sealed)
+}
+
+func (s *EncryptedStorage) openInvoice(value string) (string, error) {
+	if !strings.HasPrefix(value, invoicePrefix) {
+		if !s.allowPlaintext {
+			return "", fmt.Errorf("decrypting invoice: %w", errPlaintext)
+		}
+		return value, nil
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(value[len(invoicePrefix):])
+	if err != nil {
+		return "", fmt.Errorf("decrypting invoice: %w", err)
+	}
+	n := s.aead.NonceSize()
+	if len(raw) < n {
+		return "", errors.New("decrypting invoice: sealed value too short")
+	}
+	plain, err := s.aead.Open(nil, raw[:n], raw[n:], []byte(invoiceAAD))
+	if err != nil {
+		return "", fmt.Errorf("decrypting invoice: %w", err)
+	}
+	return string(plain), nil
+}
+
+func (s *EncryptedStorage) sealString(p *string, aad string) (*string, error) {
+	if p == nil {
+		return nil, nil
+	}
+	sealed, err := s.seal([]byte(*p), aad)
+	if err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+func (s *EncryptedStorage) openString(p *string, aad string) (*string, error) {
+	if p == nil {
+		return nil, nil
+	}
+	plain, err := s.open(*p, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting %s: %w", aad, err)
+	}
+	out := string(plain)
+	return &out, nil
+}
+
+func descriptionAAD(paymentId string) string { return "payment/" + paymentId + "/description" }
+func payInfoAAD(paymentId string) string     { return "payment/" + paymentId + "/lnurl_pay_info" }
+func withdrawAAD(paymentId string) string    { return "payment/" + paymentId + "/lnurl_withdraw_url" }
+func receiveAAD(paymentHash, field string) string {
+	return "lnurl/" + paymentHash + "/" + field
+}
+func cacheAAD(key string) string { return "cache/" + key }
+
+// invoiceAAD cannot name the payment, since lookups only know the invoice.
+const invoiceAAD = "invoice"
+
+// sealPayInfo packs the whole LnurlPayInfo into the Metadata field of an
+// otherwise empty one, since the success actions nest further strings.
+func (s *EncryptedStorage) sealPayInfo(info *breez_sdk_spark.LnurlPayInfo, paymentId string) (*breez_sdk_spark.LnurlPayInfo, error) {
+	if info == nil {
+		return nil, nil
+	}
+	sealed, err := s.seal(storageutil.EncodeOptionalLnurlPayInfo(info), payInfoAAD(paymentId))
+	if err != nil {
+		return nil, err
+	}
+	return &breez_sdk_spark.LnurlPayInfo{Metadata: &sealed}, nil
+}
+
+func (s *EncryptedStorage) openPayInfo(info *breez_sdk_spark.LnurlPayInfo, paymentId string) (*breez_sdk_spark.LnurlPayInfo, error) {
+	if info == nil {
+		return nil, nil
+	}
+	if info.Metadata == nil || !strings.HasPrefix(*info.Metadata, sealedPrefix) ||
+		info.LnAddress != nil || info.Comment != nil || info.Domain != nil ||
+		info.ProcessedSuccessAction != nil || info.RawSuccessAction != nil {
+		if !s.allowPlaintext {
+			return nil, fmt.Errorf("decrypting LNURL pay info of %s: %w", paymentId, errPlaintext)
+		}
+		return info, nil
+	}
+	plain, err := s.open(*info.Metadata, payInfoAAD(paymentId))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting LNURL pay info of %s: %w", paymentId, err)
+	}
+	return storageutil.DecodeOptionalLnurlPayInfo(plain)
+}
+
+func (s *EncryptedStorage) sealWithdrawInfo(info *breez_sdk_spark.LnurlWithdrawInfo, paymentId string) (*breez_sdk_spark.LnurlWithdrawInfo, error) {
+	if info == nil {
+		return nil, nil
+	}
+	url, err := s.sealString(&info.WithdrawUrl, withdrawAAD(paymentId))
+	if err != nil {
+		return nil, err
+	}
+	return &breez_sdk_spark.LnurlWithdrawInfo{WithdrawUrl: *url}, nil
+}
+
+func (s *EncryptedStorage) openWithdrawInfo(info *breez_sdk_spark.LnurlWithdrawInfo, paymentId string) (*breez_sdk_spark.LnurlWithdrawInfo, error) {
+	if info == nil {
+		return nil, nil
+	}
+	url, err := s.openString(&info.WithdrawUrl, withdrawAAD(paymentId))
+	if err != nil {
+		return nil, err
+	}
+	return &breez_sdk_spark.LnurlWithdrawInfo{WithdrawUrl: *url}, nil
+}
+
+// transformReceive applies fn to each field of m, keyed by the payment hash.
+func transformReceive(m *breez_sdk_spark.LnurlReceiveMetadata, paymentHash string, fn func(*string, string) (*string, error)) (*breez_sdk_spark.LnurlReceiveMetadata, error) {
+	if m == nil {
+		return nil, nil
+	}
+	var out breez_sdk_spark.LnurlReceiveMetadata
+	var err error
+	if out.NostrZapRequest, err = fn(m.NostrZapRequest, receiveAAD(paymentHash, "nostr_zap_request")); err != nil {
+		return nil, err
+	}
+	if out.NostrZapReceipt, err = fn(m.NostrZapReceipt, receiveAAD(paymentHash, "nostr_zap_receipt")); err != nil {
+		return nil, err
+	}
+	if out.SenderComment, err = fn(m.SenderComment, receiveAAD(paymentHash, "sender_comment")); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *EncryptedStorage) sealInvoiceDetails(d *breez_sdk_spark.SparkInvoicePaymentDetails, paymentId string) (*breez_sdk_spark.SparkInvoicePaymentDetails, error) {
+	if d == nil {
+		return nil, nil
+	}
+	out := *d
+	var err error
+	if out.Description, err = s.sealString(d.Description, descriptionAAD(paymentId)); err != nil {
+		return nil, err
+	}
+	out.Invoice = s.sealInvoice(d.Invoice)
+	return &out, nil
+}
+
+func (s *EncryptedStorage) openInvoiceDetails(d *breez_sdk_spark.SparkInvoicePaymentDetails, paymentId string) (*breez_sdk_spark.SparkInvoicePaymentDetails, error) {
+	if d == nil {
+		return nil, nil
+	}
+	out := *d
+	var err error
+	if out.Description, err = s.openString(d.Description, descriptionAAD(paymentId)); err != nil {
+		return nil, err
+	}
+	if out.Invoice, err = s.openInvoice(d.Invoice); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *EncryptedStorage) sealPayment(p breez_sdk_spark.Payment) (breez_sdk_spark.Payment, error) {
+	if p.Details == nil {
+		return p, nil
+	}
+	var details breez_sdk_spark.PaymentDetails
+	var err error
+	switch d := (*p.Details).(type) {
+	case breez_sdk_spark.PaymentDetailsLightning:
+		if d.Description, err = s.sealString(d.Description, descriptionAAD(p.Id)); err != nil {
+			return p, err
+		}
+		if d.LnurlPayInfo, err = s.sealPayInfo(d.LnurlPayInfo, p.Id); err != nil {
+			return p, err
+		}
+		if d.LnurlWithdrawInfo, err = s.sealWithdrawInfo(d.LnurlWithdrawInfo, p.Id); err != nil {
+			return p, err
+		}
+		if d.LnurlReceiveMetadata, err = transformReceive(d.LnurlReceiveMetadata, d.PaymentHash, s.sealString); err != nil {
+			return p, err
+		}
+		d.Invoice = s.sealInvoice(d.Invoice)
+		details = d
+	case breez_sdk_spark.PaymentDetailsSpark:
+		if d.InvoiceDetails, err = s.sealInvoiceDetails(d.InvoiceDetails, p.Id); err != nil {
+			return p, err
+		}
+		details = d
+	case breez_sdk_spark.PaymentDetailsToken:
+		if d.InvoiceDetails, err = s.sealInvoiceDetails(d.InvoiceDetails, p.Id); err != nil {
+			return p, err
+		}
+		details = d
+	default:
+		return p, nil
+	}
+	p.Details = &details
+	return p, nil
+}
+
+func (s *EncryptedStorage) openPayment(p breez_sdk_spark.Payment) (breez_sdk_spark.Payment, error) {
+	if p.Details == nil {
+		return p, nil
+	}
+	var details breez_sdk_spark.PaymentDetails
+	var err error
+	switch d := (*p.Details).(type) {
+	case breez_sdk_spark.PaymentDetailsLightning:
+		if d.Description, err = s.openString(d.Description, descriptionAAD(p.Id)); err != nil {
+			return p, err
+		}
+		if d.LnurlPayInfo, err = s.openPayInfo(d.LnurlPayInfo, p.Id); err != nil {
+			return p, err
+		}
+		if d.LnurlWithdrawInfo, err = s.openWithdrawInfo(d.LnurlWithdrawInfo, p.Id); err != nil {
+			return p, err
+		}
+		if d.LnurlReceiveMetadata, err = transformReceive(d.LnurlReceiveMetadata, d.PaymentHash, s.openString); err != nil {
+			return p, err
+		}
+		if d.Invoice, err = s.openInvoice(d.Invoice); err != nil {
+			return p, err
+		}
+		details = d
+	case breez_sdk_spark.PaymentDetailsSpark:
+		if d.InvoiceDetails, err = s.openInvoiceDetails(d.InvoiceDetails, p.Id); err != nil {
+			return p, err
+		}
+		details = d
+	case breez_sdk_spark.PaymentDetailsToken:
+		if d.InvoiceDetails, err = s.openInvoiceDetails(d.InvoiceDetails, p.Id); err != nil {
+			return p, err
+		}
+		details = d
+	default:
+		return p, nil
+	}
+	p.Details = &details
+	return p, nil
+}
+
+func (s *EncryptedStorage) DeleteCachedItem(key string) error {
+	return s.Ctx().DeleteCachedItem(context.Background(), key)
+}
+
+func (s *EncryptedStorage) GetCachedItem(key string) (*string, error) {
+	return s.Ctx().GetCachedItem(context.Background(), key)
+}
+
+func (s *EncryptedStorage) SetCachedItem(key string, value string) error {
+	return s.Ctx().SetCachedItem(context.Background(), key, value)
+}
+
+func (s *EncryptedStorage) ListPayments(request breez_sdk_spark.ListPaymentsRequest) ([]breez_sdk_spark.Payment, error) {
+	return s.Ctx().ListPayments(context.Background(), request)
+}
+
+func (s *EncryptedStorage) InsertPayment(payment breez_sdk_spark.Payment) error {
+	return s.Ctx().InsertPayment(context.Background(), payment)
+}
+
+func (s *EncryptedStorage) SetPaymentMetadata(paymentId string, metadata breez_sdk_spark.PaymentMetadata) error {
+	return s.Ctx().SetPaymentMetadata(context.Background(), paymentId, metadata)
+}
+
+func (s *EncryptedStorage) GetPaymentById(id string) (breez_sdk_spark.Payment, error) {
+	return s.Ctx().GetPaymentById(context.Background(), id)
+}
+
+func (s *EncryptedStorage) GetPaymentByInvoice(invoice string) (*breez_sdk_spark.Payment, error) {
+	return s.Ctx().GetPaymentByInvoice(context.Background(), invoice)
+}
+
+func (s *EncryptedStorage) AddDeposit(txid string, vout uint32, amountSats uint64) error {
+	return s.Ctx().AddDeposit(context.Background(), txid, vout, amountSats)
+}
+
+func (s *EncryptedStorage) DeleteDeposit(txid string, vout uint32) error {
+	return s.Ctx().DeleteDeposit(context.Background(), txid, vout)
+}
+
+func (s *EncryptedStorage) ListDeposits() ([]breez_sdk_spark.DepositInfo, error) {
+	return s.Ctx().ListDeposits(context.Background())
+}
+
+func (s *EncryptedStorage) UpdateDeposit(txid string, vout uint32, payload breez_sdk_spark.UpdateDepositPayload) error {
+	return s.Ctx().UpdateDeposit(context.Background(), txid, vout, payload)
+}
+
+func (s *EncryptedStorage) SetLnurlMetadata(metadata []breez_sdk_spark.SetLnurlMetadataItem) error {
+	return s.Ctx().SetLnurlMetadata(context.Background(), metadata)
+}
+
+func (s storageCtx) DeleteCachedItem(ctx context.Context, key string) error {
+	return s.inner.DeleteCachedItem(ctx, key)
+}
+
+func (s storageCtx) GetCachedItem(ctx context.Context, key string) (*string, error) {
+	value, err := s.inner.GetCachedItem(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	value, err = s.openString(value, cacheAAD(key))
+	return value, storageutil.StorageErr(err)
+}
+
+func (s storageCtx) SetCachedItem(ctx context.Context, key string, value string) error {
+	sealed, err := s.sealString(&value, cacheAAD(key))
+	if err != nil {
+		return storageutil.StorageErr(err)
+	}
+	return s.inner.SetCachedItem(ctx, key, *sealed)
+}
+
+func (s storageCtx) ListPayments(ctx context.Context, request breez_sdk_spark.ListPaymentsRequest) ([]breez_sdk_spark.Payment, error) {
+	payments, err := s.inner.ListPayments(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range payments {
+		if payments[i], err = s.openPayment(p); err != nil {
+			return nil, storageutil.StorageErr(err)
+		}
+	}
+	return payments, nil
+}
+
+func (s storageCtx) InsertPayment(ctx context.Context, payment breez_sdk_spark.Payment) error {
+	sealed, err := s.sealPayment(payment)
+	if err != nil {
+		return storageutil.StorageErr(err)
+	}
+	return s.inner.InsertPayment(ctx, sealed)
+}
+
+func (s storageCtx) SetPaymentMetadata(ctx context.Context, paymentId string, metadata breez_sdk_spark.PaymentMetadata) error {
+	var sealed breez_sdk_spark.PaymentMetadata
+	var err error
+	if sealed.LnurlPayInfo, err = s.sealPayInfo(metadata.LnurlPayInfo, paymentId); err != nil {
+		return storageutil.StorageErr(err)
+	}
+	if sealed.LnurlWithdrawInfo, err = s.sealWithdrawInfo(metadata.LnurlWithdrawInfo, paymentId); err != nil {
+		return storageutil.StorageErr(err)
+	}
+	// The storage shows the LNURL description as the payment description,
+	// so both are sealed under the same binding.
+	if sealed.LnurlDescription, err = s.sealString(metadata.LnurlDescription, descriptionAAD(paymentId)); err != nil {
+		return storageutil.StorageErr(err)
+	}
+	return s.inner.SetPaymentMetadata(ctx, paymentId, sealed)
+}
+
+func (s storageCtx) GetPaymentById(ctx context.Context, id string) (breez_sdk_spark.Payment, error) {
+	p, err := s.inner.GetPaymentById(ctx, id)
+	if err != nil {
+		return p, err
+	}
+	p, err = s.openPayment(p)
+	return p, storageutil.StorageErr(err)
+}
+
+func (s storageCtx) GetPaymentByInvoice(ctx context.Context, invoice string) (*breez_sdk_spark.Payment, error) {
+	p, err := s.inner.GetPaymentByInvoice(ctx, s.sealInvoice(invoice))
+	if err == nil && p == nil && s.allowPlaintext {
+		p, err = s.inner.GetPaymentByInvoice(ctx, invoice)
+	}
+	if err != nil || p == nil {
+		return p, err
+	}
+	opened, err := s.openPayment(*p)
+	if err != nil {
+		return nil, storageutil.StorageErr(err)
+	}
+	return &opened, nil
+}
+
+func (s storageCtx) AddDeposit(ctx context.Context, txid string, vout uint32, amountSats uint64) error {
+	return s.inner.AddDeposit(ctx, txid, vout, amountSats)
+}
+
+func (s storageCtx) DeleteDeposit(ctx context.Context, txid string, vout uint32) error {
+	return s.inner.DeleteDeposit(ctx, txid, vout)
+}
+
+func (s storageCtx) ListDeposits(ctx context.Context) ([]breez_sdk_spark.DepositInfo, error) {
+	return s.inner.ListDeposits(ctx)
+}
+
+func (s storageCtx) UpdateDeposit(ctx context.Context, txid string, vout uint32, payload breez_sdk_spark.UpdateDepositPayload) error {
+	return s.inner.UpdateDeposit(ctx, txid, vout, payload)
+}
+
+func (s storageCtx) SetLnurlMetadata(ctx context.Context, metadata []breez_sdk_spark.SetLnurlMetadataItem) error {
+	sealed := make([]breez_sdk_spark.SetLnurlMetadataItem, len(metadata))
+	for i, item := range metadata {
+		m, err := transformReceive(&breez_sdk_spark.LnurlReceiveMetadata{
+			NostrZapRequest: item.NostrZapRequest,
+			NostrZapReceipt: item.NostrZapReceipt,
+			SenderComment:   item.SenderComment,
+		}, item.PaymentHash, s.sealString)
+		if err != nil {
+			return storageutil.StorageErr(err)
+		}
+		sealed[i] = breez_sdk_spark.SetLnurlMetadataItem{
+			PaymentHash:     item.PaymentHash,
+			NostrZapRequest: m.NostrZapRequest,
+			NostrZapReceipt: m.NostrZapReceipt,
+			SenderComment:   m.SenderComment,
+		}
+	}
+	return s.inner.SetLnurlMetadata(ctx, sealed)
+}
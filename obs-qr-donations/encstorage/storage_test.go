@@ -0,0 +1,283 @@
+package encstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/storageutil"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/memstorage"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/sqlitestorage"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/storagetest"
+)
+
+var testSeed breez_sdk_spark.Seed = breez_sdk_spark.SeedEntropy{Field0: bytes.Repeat([]byte{7}, 32)}
+
+func ptr[T any](v T) *T { return &v }
+
+func newStorage(t *testing.T, inner breez_sdk_spark.Storage) *EncryptedStorage {
+	t.Helper()
+	s, err := NewWithSeed(inner, testSeed, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStorageSuite(t *testing.T) {
+	storagetest.RunStorageSuite(t, func(t *testing.T) breez_sdk_spark.Storage {
+		return newStorage(t, memstorage.NewStorage())
+	})
+}
+
+func sqlite(t *testing.T) *sqlitestorage.Storage {
+	t.Helper()
+	db, err := sqlitestorage.New(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestStorageSuiteCtx(t *testing.T) {
+	storagetest.RunStorageSuite(t, func(t *testing.T) breez_sdk_spark.Storage {
+		s := newStorage(t, breez_sdk_spark.NewStorageFromCtx(sqlite(t).Ctx()))
+		return breez_sdk_spark.NewStorageFromCtx(s.Ctx())
+	})
+}
+
+func TestCtxReachesInner(t *testing.T) {
+	s := newStorage(t, breez_sdk_spark.NewStorageFromCtx(sqlite(t).Ctx()))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Ctx().ListPayments(ctx, breez_sdk_spark.ListPaymentsRequest{}); err == nil {
+		t.Fatal("query ran with a cancelled context")
+	}
+	if err := s.Ctx().InsertPayment(ctx, donation()); err == nil {
+		t.Fatal("write ran with a cancelled context")
+	}
+	if _, err := s.GetPaymentById("p1"); err == nil {
+		t.Fatal("cancelled write stored the payment")
+	}
+}
+
+func donation() breez_sdk_spark.Payment {
+	var details breez_sdk_spark.PaymentDetails = breez_sdk_spark.PaymentDetailsLightning{
+		Description: ptr("secret description"),
+		Invoice:     "lnbc1secretinvoice",
+		PaymentHash: "hash1",
+	}
+	return breez_sdk_spark.Payment{
+		Id:          "p1",
+		PaymentType: breez_sdk_spark.PaymentTypeReceive,
+		Status:      breez_sdk_spark.PaymentStatusCompleted,
+		Amount:      big.NewInt(21000),
+		Fees:        big.NewInt(0),
+		Timestamp:   1700000000,
+		Method:      breez_sdk_spark.PaymentMethodLightning,
+		Details:     &details,
+	}
+}
+
+func TestNothingPersonalInClear(t *testing.T) {
+	inner := memstorage.NewStorage()
+	s := newStorage(t, inner)
+	if err := s.InsertPayment(donation()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetPaymentMetadata("p1", breez_sdk_spark.PaymentMetadata{
+		LnurlPayInfo:     &breez_sdk_spark.LnurlPayInfo{LnAddress: ptr("secret@example.com"), Comment: ptr("secret pay comment")},
+		LnurlDescription: ptr("secret lnurl description"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetLnurlMetadata([]breez_sdk_spark.SetLnurlMetadataItem{
+		{PaymentHash: "hash1", SenderComment: ptr("secret sender comment"), NostrZapRequest: ptr("secret zap")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCachedItem("settings", "secret cached value"); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := inner.GetPaymentById("p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc := storageutil.EncodePayment(raw); bytes.Contains(enc, []byte("secret")) {
+		t.Fatalf("wrapped storage holds personal data in clear: %q", enc)
+	}
+	if v, _ := inner.GetCachedItem("settings"); v == nil || strings.Contains(*v, "secret") {
+		t.Fatalf("cached value = %v", v)
+	}
+
+	// Filters still run on the wrapped storage.
+	got, err := inner.ListPayments(breez_sdk_spark.ListPaymentsRequest{
+		TypeFilter:    &[]breez_sdk_spark.PaymentType{breez_sdk_spark.PaymentTypeReceive},
+		StatusFilter:  &[]breez_sdk_spark.PaymentStatus{breez_sdk_spark.PaymentStatusCompleted},
+		FromTimestamp: ptr(uint64(1700000000)),
+	})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("ListPayments on wrapped storage = %d payments, %v", len(got), err)
+	}
+	if p, err := s.GetPaymentByInvoice("lnbc1secretinvoice"); err != nil || p == nil || p.Id != "p1" {
+		t.Fatalf("GetPaymentByInvoice = %v, %v", p, err)
+	}
+
+	p, err := s.GetPaymentById("p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := (*p.Details).(breez_sdk_spark.PaymentDetailsLightning)
+	if d.Invoice != "lnbc1secretinvoice" {
+		t.Fatalf("invoice = %q", d.Invoice)
+	}
+	if d.Description == nil || *d.Description != "secret lnurl description" {
+		t.Fatalf("description = %v", d.Description)
+	}
+	if d.LnurlPayInfo == nil || d.LnurlPayInfo.Comment == nil || *d.LnurlPayInfo.Comment != "secret pay comment" {
+		t.Fatalf("pay info = %+v", d.LnurlPayInfo)
+	}
+	if m := d.LnurlReceiveMetadata; m == nil || m.SenderComment == nil || *m.SenderComment != "secret sender comment" {
+		t.Fatalf("receive metadata = %+v", m)
+	}
+	if v, err := s.GetCachedItem("settings"); err != nil || v == nil || *v != "secret cached value" {
+		t.Fatalf("GetCachedItem = %v, %v", v, err)
+	}
+}
+
+func TestSparkInvoiceSealed(t *testing.T) {
+	inner := memstorage.NewStorage()
+	s := newStorage(t, inner)
+	var details breez_sdk_spark.PaymentDetails = breez_sdk_spark.PaymentDetailsSpark{
+		InvoiceDetails: &breez_sdk_spark.SparkInvoicePaymentDetails{
+			Description: ptr("secret spark description"),
+			Invoice:     "spark1secretinvoice",
+		},
+	}
+	p := donation()
+	p.Method = breez_sdk_spark.PaymentMethodSpark
+	p.Details = &details
+	if err := s.InsertPayment(p); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := inner.ListPayments(breez_sdk_spark.ListPaymentsRequest{})
+	if err != nil || len(all) != 1 {
+		t.Fatalf("ListPayments on wrapped storage = %d payments, %v", len(all), err)
+	}
+	if enc := storageutil.EncodePayment(all[0]); bytes.Contains(enc, []byte("secret")) {
+		t.Fatalf("wrapped storage holds personal data in clear: %q", enc)
+	}
+	if p, _ := inner.GetPaymentByInvoice("spark1secretinvoice"); p != nil {
+		t.Fatal("wrapped storage found the payment by its invoice in clear")
+	}
+
+	got, err := s.GetPaymentByInvoice("spark1secretinvoice")
+	if err != nil || got == nil {
+		t.Fatalf("GetPaymentByInvoice = %v, %v", got, err)
+	}
+	d := (*got.Details).(breez_sdk_spark.PaymentDetailsSpark).InvoiceDetails
+	if d.Invoice != "spark1secretinvoice" || d.Description == nil || *d.Description != "secret spark description" {
+		t.Fatalf("invoice details = %+v", d)
+	}
+}
+
+func TestWrongKey(t *testing.T) {
+	inner := memstorage.NewStorage()
+	newStorage(t, inner)
+	other := breez_sdk_spark.SeedMnemonic{Mnemonic: "abandon abandon abandon"}
+	_, err := NewWithSeed(inner, other, Config{})
+	if !errors.Is(err, breez_sdk_spark.ErrStorageErrorInitializationError) {
+		t.Fatalf("got %v, want InitializationError", err)
+	}
+	if _, err := NewWithSeed(inner, testSeed, Config{}); err != nil {
+		t.Fatalf("reopening with the right seed: %v", err)
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	inner := memstorage.NewStorage()
+	s, err := NewWithPassphrase(inner, "correct horse", Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCachedItem("k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWithPassphrase(inner, "wrong horse", Config{}); !errors.Is(err, breez_sdk_spark.ErrStorageErrorInitializationError) {
+		t.Fatalf("got %v, want InitializationError", err)
+	}
+	reopened, err := NewWithPassphrase(inner, "correct horse", Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := reopened.GetCachedItem("k"); err != nil || v == nil || *v != "v" {
+		t.Fatalf("GetCachedItem = %v, %v", v, err)
+	}
+}
+
+func TestSwappedCiphertextRejected(t *testing.T) {
+	inner := memstorage.NewStorage()
+	s := newStorage(t, inner)
+	if err := s.SetCachedItem("a", "value a"); err != nil {
+		t.Fatal(err)
+	}
+	sealedA, _ := inner.GetCachedItem("a")
+	if err := inner.SetCachedItem("b", *sealedA); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetCachedItem("b"); !errors.Is(err, breez_sdk_spark.ErrStorageErrorImplementation) {
+		t.Fatalf("got %v, want a decryption error", err)
+	}
+}
+
+func TestPlaintextReadBack(t *testing.T) {
+	inner := memstorage.NewStorage()
+	if err := inner.SetCachedItem("legacy", "written before encryption"); err != nil {
+		t.Fatal(err)
+	}
+	if err := inner.InsertPayment(donation()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the migration option plaintext is an error.
+	s := newStorage(t, inner)
+	if _, err := s.GetCachedItem("legacy"); !errors.Is(err, breez_sdk_spark.ErrStorageErrorImplementation) {
+		t.Fatalf("GetCachedItem = %v, want an error", err)
+	}
+	if _, err := s.GetPaymentById("p1"); err == nil {
+		t.Fatal("read a payment stored in clear")
+	}
+
+	s, err := NewWithSeed(inner, testSeed, Config{AllowPlaintext: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.GetCachedItem("legacy"); err != nil || v == nil || *v != "written before encryption" {
+		t.Fatalf("GetCachedItem = %v, %v", v, err)
+	}
+	p, err := s.GetPaymentByInvoice("lnbc1secretinvoice")
+	if err != nil || p == nil {
+		t.Fatalf("GetPaymentByInvoice = %v, %v", p, err)
+	}
+	if d := (*p.Details).(breez_sdk_spark.PaymentDetailsLightning); d.Description == nil || *d.Description != "secret description" {
+		t.Fatalf("description = %v", d.Description)
+	}
+}
+
+func TestPlaintextKeyCheckRejected(t *testing.T) {
+	inner := memstorage.NewStorage()
+	if err := inner.SetCachedItem(keyCheckKey, keyCheckText); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewWithSeed(inner, testSeed, Config{AllowPlaintext: true})
+	if !errors.Is(err, breez_sdk_spark.ErrStorageErrorInitializationError) {
+		t.Fatalf("got %v, want InitializationError", err)
+	}
+}
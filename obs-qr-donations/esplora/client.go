@@ -0,0 +1,264 @@
+// Package esplora implements the SDK BitcoinChainService against the
+// Esplora REST API (blockstream.info, mempool.space and self-hosted
+// electrs), using a Go http.Client the caller controls.
+//
+// Client implements breez_sdk_spark.BitcoinChainServiceCtx, so requests are
+// cancelled when the SDK gives up on a call:
+//
+//	c, err := esplora.New(esplora.Config{BaseURL: "https://blockstream.info/api"})
+//	...
+//	builder.WithChainService(breez_sdk_spark.NewBitcoinChainServiceFromCtx(c))
+package esplora
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// maxResponseSize bounds how much of a response body is read. The largest
+// legitimate responses are UTXO lists of busy addresses.
+const maxResponseSize = 16 << 20
+
+// Config configures a Client.
+type Config struct {
+	// BaseURL is the API root, e.g. "https://blockstream.info/api" or
+	// "https://mempool.space/testnet/api".
+	BaseURL string
+	// Credentials, when set, are sent as HTTP basic auth.
+	Credentials *breez_sdk_spark.Credentials
+	// HTTPClient sends the requests. Set its Transport to add proxying or
+	// request logging. Defaults to a client with a 30 second timeout.
+	HTTPClient *http.Client
+	// Logger receives one debug record per request. Defaults to no logging.
+	Logger *slog.Logger
+}
+
+// Client is an Esplora backed breez_sdk_spark.BitcoinChainServiceCtx.
+type Client struct {
+	baseURL     string
+	credentials *breez_sdk_spark.Credentials
+	http        *http.Client
+	logger      *slog.Logger
+}
+
+var _ breez_sdk_spark.BitcoinChainServiceCtx = (*Client)(nil)
+
+// New returns a Client for cfg.
+func New(cfg Config) (*Client, error) {
+	u, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", cfg.BaseURL)
+	}
+	c := &Client{
+		baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+		credentials: cfg.Credentials,
+		http:        cfg.HTTPClient,
+		logger:      cfg.Logger,
+	}
+	if c.http == nil {
+		c.http = &http.Client{Timeout: 30 * time.Second}
+	}
+	if c.logger == nil {
+		c.logger = slog.New(slog.DiscardHandler)
+	}
+	return c, nil
+}
+
+// statusError is a non-2xx response.
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	if e.body == "" {
+		return fmt.Sprintf("esplora: HTTP %d", e.status)
+	}
+	return fmt.Sprintf("esplora: HTTP %d: %s", e.status, e.body)
+}
+
+// do sends a request to path and returns the response body. Errors are
+// already mapped to *ChainServiceError.
+func (c *Client) do(ctx context.Context, method, path string, body string) ([]byte, error) {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, breez_sdk_spark.NewChainServiceErrorGeneric(err.Error())
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "text/plain")
+	}
+	if c.credentials != nil {
+		req.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	}
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		c.logger.DebugContext(ctx, "esplora request failed", "method", method, "path", path, "err", err)
+		return nil, breez_sdk_spark.NewChainServiceErrorServiceConnectivity(err.Error())
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	c.logger.DebugContext(ctx, "esplora request", "method", method, "path", path,
+		"status", resp.StatusCode, "bytes", len(data), "duration", time.Since(start))
+	if err != nil {
+		return nil, breez_sdk_spark.NewChainServiceErrorServiceConnectivity(err.Error())
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, mapStatus(&statusError{status: resp.StatusCode, body: strings.TrimSpace(string(data))})
+	}
+	return data, nil
+}
+
+// mapStatus turns an HTTP error into the ChainServiceError variant the SDK
+// expects: unavailability and rate limiting are connectivity problems the
+// SDK retries, rejected addresses are InvalidAddress and the rest Generic.
+func mapStatus(e *statusError) error {
+	switch {
+	case e.status == http.StatusTooManyRequests || e.status >= 500:
+		return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(e.Error())
+	case e.status == http.StatusUnauthorized || e.status == http.StatusForbidden:
+		return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(e.Error())
+	case e.status == http.StatusBadRequest && strings.Contains(strings.ToLower(e.body), "address"):
+		return breez_sdk_spark.NewChainServiceErrorInvalidAddress(e.body)
+	default:
+		return breez_sdk_spark.NewChainServiceErrorGeneric(e.Error())
+	}
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, v any) error {
+	data, err := c.do(ctx, http.MethodGet, path, "")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return breez_sdk_spark.NewChainServiceErrorGeneric(fmt.Sprintf("esplora: decoding %s: %v", path, err))
+	}
+	return nil
+}
+
+type txStatus struct {
+	Confirmed   bool    `json:"confirmed"`
+	BlockHeight *uint32 `json:"block_height"`
+	BlockTime   *uint64 `json:"block_time"`
+}
+
+func (s txStatus) toSdk() breez_sdk_spark.TxStatus {
+	if !s.Confirmed {
+		return breez_sdk_spark.TxStatus{}
+	}
+	return breez_sdk_spark.TxStatus{Confirmed: true, BlockHeight: s.BlockHeight, BlockTime: s.BlockTime}
+}
+
+func (c *Client) GetAddressUtxos(ctx context.Context, address string) ([]breez_sdk_spark.Utxo, error) {
+	if address == "" {
+		return nil, breez_sdk_spark.NewChainServiceErrorInvalidAddress("empty address")
+	}
+	var utxos []struct {
+		Txid   string   `json:"txid"`
+		Vout   uint32   `json:"vout"`
+		Value  uint64   `json:"value"`
+		Status txStatus `json:"status"`
+	}
+	if err := c.getJSON(ctx, "/address/"+url.PathEscape(address)+"/utxo", &utxos); err != nil {
+		return nil, err
+	}
+	out := make([]breez_sdk_spark.Utxo, len(utxos))
+	for i, u := range utxos {
+		out[i] = breez_sdk_spark.Utxo{Txid: u.Txid, Vout: u.Vout, Value: u.Value, Status: u.Status.toSdk()}
+	}
+	return out, nil
+}
+
+func (c *Client) GetTransactionStatus(ctx context.Context, txid string) (breez_sdk_spark.TxStatus, error) {
+	var status txStatus
+	if err := c.getJSON(ctx, "/tx/"+url.PathEscape(txid)+"/status", &status); err != nil {
+		return breez_sdk_spark.TxStatus{}, err
+	}
+	return status.toSdk(), nil
+}
+
+func (c *Client) GetTransactionHex(ctx context.Context, txid string) (string, error) {
+	data, err := c.do(ctx, http.MethodGet, "/tx/"+url.PathEscape(txid)+"/hex", "")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (c *Client) BroadcastTransaction(ctx context.Context, tx string) error {
+	_, err := c.do(ctx, http.MethodPost, "/tx", strings.TrimSpace(tx))
+	return err
+}
+
+// Confirmation targets, in blocks, behind each RecommendedFees field. They
+// match the ones the Rust REST chain service uses for Esplora.
+const (
+	fastestTarget  = 1
+	halfHourTarget = 3
+	hourTarget     = 6
+	economyTarget  = 25
+	minimumTarget  = 1008
+)
+
+func (c *Client) RecommendedFees(ctx context.Context) (breez_sdk_spark.RecommendedFees, error) {
+	var raw map[string]float64
+	if err := c.getJSON(ctx, "/fee-estimates", &raw); err != nil {
+		return breez_sdk_spark.RecommendedFees{}, err
+	}
+	estimates := make(map[int]float64, len(raw))
+	for k, v := range raw {
+		target, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		estimates[target] = v
+	}
+	if len(estimates) == 0 {
+		return breez_sdk_spark.RecommendedFees{}, breez_sdk_spark.NewChainServiceErrorGeneric("esplora: no fee estimates")
+	}
+	return breez_sdk_spark.RecommendedFees{
+		FastestFee:  feeFor(estimates, fastestTarget),
+		HalfHourFee: feeFor(estimates, halfHourTarget),
+		HourFee:     feeFor(estimates, hourTarget),
+		EconomyFee:  feeFor(estimates, economyTarget),
+		MinimumFee:  feeFor(estimates, minimumTarget),
+	}, nil
+}
+
+// feeFor returns the estimate for target in sat/vB, rounded up. Backends
+// only report some targets, so the closest slower one stands in, or the
+// slowest reported when target is beyond all of them.
+func feeFor(estimates map[int]float64, target int) uint64 {
+	targets := make([]int, 0, len(estimates))
+	for t := range estimates {
+		targets = append(targets, t)
+	}
+	sort.Ints(targets)
+	chosen := targets[len(targets)-1]
+	for _, t := range targets {
+		if t >= target {
+			chosen = t
+			break
+		}
+	}
+	return uint64(math.Ceil(estimates[chosen]))
+}
@@ -0,0 +1,199 @@
+package esplora
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// fakeEsplora serves canned responses keyed by "METHOD path" and records
+// the last request.
+type fakeEsplora struct {
+	routes   map[string]func(w http.ResponseWriter, r *http.Request)
+	lastBody string
+	lastAuth [2]string
+}
+
+func newFake(t *testing.T) (*fakeEsplora, *httptest.Server) {
+	f := &fakeEsplora{routes: map[string]func(http.ResponseWriter, *http.Request){}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		f.lastAuth = [2]string{user, pass}
+		body, _ := io.ReadAll(r.Body)
+		f.lastBody = string(body)
+		route, ok := f.routes[r.Method+" "+r.URL.EscapedPath()]
+		if !ok {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		route(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func respond(status int, body string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+}
+
+func newClient(t *testing.T, baseURL string, creds *breez_sdk_spark.Credentials) *Client {
+	t.Helper()
+	c, err := New(Config{BaseURL: baseURL, Credentials: creds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestGetAddressUtxos(t *testing.T) {
+	f, srv := newFake(t)
+	f.routes["GET /api/address/bc1qdonate/utxo"] = respond(200, `[
+		{"txid":"aa","vout":1,"value":5000,"status":{"confirmed":true,"block_height":800000,"block_hash":"00","block_time":1700000000}},
+		{"txid":"bb","vout":0,"value":1200,"status":{"confirmed":false}}
+	]`)
+	creds := &breez_sdk_spark.Credentials{Username: "streamer", Password: "hunter2"}
+	c := newClient(t, srv.URL+"/api/", creds)
+
+	utxos, err := c.GetAddressUtxos(context.Background(), "bc1qdonate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.lastAuth != [2]string{"streamer", "hunter2"} {
+		t.Fatalf("basic auth = %v", f.lastAuth)
+	}
+	if len(utxos) != 2 {
+		t.Fatalf("got %d utxos", len(utxos))
+	}
+	u := utxos[0]
+	if u.Txid != "aa" || u.Vout != 1 || u.Value != 5000 || !u.Status.Confirmed ||
+		u.Status.BlockHeight == nil || *u.Status.BlockHeight != 800000 || u.Status.BlockTime == nil || *u.Status.BlockTime != 1700000000 {
+		t.Fatalf("utxo = %+v", u)
+	}
+	if s := utxos[1].Status; s.Confirmed || s.BlockHeight != nil || s.BlockTime != nil {
+		t.Fatalf("unconfirmed status = %+v", s)
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	f, srv := newFake(t)
+	f.routes["GET /tx/cc/status"] = respond(200, `{"confirmed":true,"block_height":12,"block_hash":"00","block_time":99}`)
+	f.routes["GET /tx/cc/hex"] = respond(200, "0200000001\n")
+	f.routes["POST /tx"] = respond(200, "cc")
+	c := newClient(t, srv.URL, nil)
+
+	status, err := c.GetTransactionStatus(context.Background(), "cc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Confirmed || *status.BlockHeight != 12 || *status.BlockTime != 99 {
+		t.Fatalf("status = %+v", status)
+	}
+	hex, err := c.GetTransactionHex(context.Background(), "cc")
+	if err != nil || hex != "0200000001" {
+		t.Fatalf("hex = %q, %v", hex, err)
+	}
+	if err := c.BroadcastTransaction(context.Background(), "0200000001\n"); err != nil {
+		t.Fatal(err)
+	}
+	if f.lastBody != "0200000001" {
+		t.Fatalf("broadcast body = %q", f.lastBody)
+	}
+	if f.lastAuth != [2]string{"", ""} {
+		t.Fatalf("unexpected auth %v", f.lastAuth)
+	}
+}
+
+func TestRecommendedFees(t *testing.T) {
+	f, srv := newFake(t)
+	f.routes["GET /fee-estimates"] = respond(200, `{"1":20.5,"2":18,"3":15.1,"4":12,"6":10,"10":8,"144":2.2,"504":1.5}`)
+	c := newClient(t, srv.URL, nil)
+
+	fees, err := c.RecommendedFees(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := breez_sdk_spark.RecommendedFees{FastestFee: 21, HalfHourFee: 16, HourFee: 10, EconomyFee: 3, MinimumFee: 2}
+	if fees != want {
+		t.Fatalf("fees = %+v, want %+v", fees, want)
+	}
+
+	f.routes["GET /fee-estimates"] = respond(200, `{}`)
+	if _, err := c.RecommendedFees(context.Background()); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) {
+		t.Fatalf("empty estimates: got %v", err)
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	f, srv := newFake(t)
+	f.routes["GET /address/nope/utxo"] = respond(400, "Invalid Bitcoin address")
+	f.routes["GET /address/busy/utxo"] = respond(429, "Too Many Requests")
+	f.routes["GET /address/down/utxo"] = respond(503, "")
+	f.routes["GET /address/garbled/utxo"] = respond(200, `{"not":"a list"}`)
+	f.routes["POST /tx"] = respond(400, "sendrawtransaction RPC error: bad-txns-inputs-missingorspent")
+	c := newClient(t, srv.URL, nil)
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"invalid address", func() error { _, err := c.GetAddressUtxos(ctx, "nope"); return err }, breez_sdk_spark.ErrChainServiceErrorInvalidAddress},
+		{"empty address", func() error { _, err := c.GetAddressUtxos(ctx, ""); return err }, breez_sdk_spark.ErrChainServiceErrorInvalidAddress},
+		{"rate limited", func() error { _, err := c.GetAddressUtxos(ctx, "busy"); return err }, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity},
+		{"unavailable", func() error { _, err := c.GetAddressUtxos(ctx, "down"); return err }, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity},
+		{"bad json", func() error { _, err := c.GetAddressUtxos(ctx, "garbled"); return err }, breez_sdk_spark.ErrChainServiceErrorGeneric},
+		{"unknown tx", func() error { _, err := c.GetTransactionHex(ctx, "missing"); return err }, breez_sdk_spark.ErrChainServiceErrorGeneric},
+		{"rejected broadcast", func() error { return c.BroadcastTransaction(ctx, "00") }, breez_sdk_spark.ErrChainServiceErrorGeneric},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestConnectivity(t *testing.T) {
+	_, srv := newFake(t)
+	c := newClient(t, srv.URL, nil)
+	srv.Close()
+	if _, err := c.GetTransactionStatus(context.Background(), "aa"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("closed server: got %v", err)
+	}
+
+	f, srv := newFake(t)
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	f.routes["GET /tx/slow/status"] = func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}
+	c = newClient(t, srv.URL, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetTransactionStatus(ctx, "slow"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("cancelled call: got %v", err)
+	}
+}
+
+func TestNewValidatesBaseURL(t *testing.T) {
+	for _, base := range []string{"", "blockstream.info/api", "ftp://example.com", "http://[::1"} {
+		if _, err := New(Config{BaseURL: base}); err == nil {
+			t.Errorf("New(%q) succeeded", base)
+		}
+	}
+}
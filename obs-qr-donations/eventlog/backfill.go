@@ -0,0 +1,99 @@
+package eventlog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// DefaultBackfillOverlap is how far before the last logged record Backfill
+// looks when not given a start, to catch payments whose events were still
+// in flight when the plugin stopped.
+const DefaultBackfillOverlap = 24 * time.Hour
+
+// backfillPage is how many payments Backfill lists at a time.
+const backfillPage = 100
+
+// PaymentLister is the part of *breez_sdk_spark.BreezSdk that Backfill
+// uses.
+type PaymentLister interface {
+	ListPaymentsCtx(ctx context.Context, request breez_sdk_spark.ListPaymentsRequest) (breez_sdk_spark.ListPaymentsResponse, error)
+}
+
+var _ PaymentLister = (*breez_sdk_spark.BreezSdk)(nil)
+
+// Backfill reconstructs the payment events missed while nothing was
+// listening from the payments created from time from on, oldest first, and
+// returns how many it appended. A payment event already logged, for the
+// same payment and kind, is not appended again. A zero from starts
+// DefaultBackfillOverlap before the last record; with an empty log, the
+// whole payment history is listed.
+//
+// Only the latest status of a payment is known to ListPayments: a payment
+// that went from pending to completed unseen gets a single succeeded
+// event.
+func (l *Log) Backfill(ctx context.Context, src PaymentLister, from time.Time) (int, error) {
+	l.mu.Lock()
+	if from.IsZero() && !l.last.IsZero() {
+		from = l.last.Add(-DefaultBackfillOverlap)
+	}
+	l.mu.Unlock()
+	var since uint64
+	if from.Unix() > 0 {
+		since = uint64(from.Unix())
+	}
+
+	ascending, limit := true, uint32(backfillPage)
+	added := 0
+	for offset := uint32(0); ; offset += backfillPage {
+		resp, err := src.ListPaymentsCtx(ctx, breez_sdk_spark.ListPaymentsRequest{
+			FromTimestamp: &since,
+			SortAscending: &ascending,
+			Offset:        &offset,
+			Limit:         &limit,
+		})
+		if err != nil {
+			return added, fmt.Errorf("eventlog: listing payments: %w", err)
+		}
+		for _, p := range resp.Payments {
+			e, ok := paymentEvent(p)
+			if !ok {
+				continue
+			}
+			n, err := l.backfill(e)
+			if err != nil {
+				return added, err
+			}
+			added += n
+		}
+		if len(resp.Payments) < backfillPage {
+			return added, nil
+		}
+	}
+}
+
+// backfill appends e unless it was logged already.
+func (l *Log) backfill(e breez_sdk_spark.SdkEvent) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, appended, err := l.appendLocked(e, true)
+	if err != nil || !appended {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// paymentEvent returns the event the SDK emits for p in its status.
+func paymentEvent(p breez_sdk_spark.Payment) (breez_sdk_spark.SdkEvent, bool) {
+	switch p.Status {
+	case breez_sdk_spark.PaymentStatusCompleted:
+		return breez_sdk_spark.SdkEventPaymentSucceeded{Payment: p}, true
+	case breez_sdk_spark.PaymentStatusPending:
+		return breez_sdk_spark.SdkEventPaymentPending{Payment: p}, true
+	case breez_sdk_spark.PaymentStatusFailed:
+		return breez_sdk_spark.SdkEventPaymentFailed{Payment: p}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,424 @@
+// Package eventlog keeps SDK events on disk, so that none is lost when the
+// plugin restarts mid-stream. A Log is an EventListener appending every
+// event to a file under a sequential offset; consumers such as the
+// overlay, the chat bot and accounting read from the offset after the last
+// one they acknowledged:
+//
+//	events, err := eventlog.Open(eventlog.Config{Path: "events.jsonl"})
+//	...
+//	sdk.AddEventListener(events)
+//	// Catch up with what happened while nothing was listening.
+//	events.Backfill(ctx, sdk, time.Time{})
+//	...
+//	for rec := range events.Follow(ctx, events.Acked("overlay")+1) {
+//		show(rec.Event)
+//		events.Ack("overlay", rec.Offset)
+//	}
+package eventlog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// ErrClosed is returned by the methods of a closed Log.
+var ErrClosed = errors.New("eventlog: closed")
+
+// Config configures a Log.
+type Config struct {
+	// Path of the log file. The acknowledged offsets are kept next to it,
+	// in Path+".acks".
+	Path string
+	// Logger receives an error for every event that could not be logged,
+	// and a warning when a partly written entry is dropped on opening.
+	// Defaults to no logging.
+	Logger *slog.Logger
+}
+
+// Record is a logged event.
+type Record struct {
+	// Offset numbers the records from 1, without gaps.
+	Offset uint64
+	// Time is when the event was logged.
+	Time  time.Time
+	Event breez_sdk_spark.SdkEvent
+	// Backfilled records were reconstructed from the payment history by
+	// Backfill rather than delivered by the SDK.
+	Backfilled bool
+}
+
+// Log is an append-only file of SDK events and a
+// breez_sdk_spark.EventListener appending to it. It is safe for
+// concurrent use.
+type Log struct {
+	path   string
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu sync.Mutex
+	f  *os.File
+	// starts holds the file position of each record, and size the end of
+	// the last.
+	starts []int64
+	size   int64
+	last   time.Time
+	// seen holds the offset of the payment events logged, by paymentKey.
+	seen map[string]uint64
+	acks map[string]uint64
+	// appended is closed and replaced at every append, to wake followers.
+	appended chan struct{}
+}
+
+var _ breez_sdk_spark.EventListener = (*Log)(nil)
+
+// Open opens the log at cfg.Path, creating it if needed. A last entry cut
+// short by a crash is dropped; any other damage is an error.
+func Open(cfg Config) (*Log, error) {
+	l := &Log{
+		path:     cfg.Path,
+		logger:   cfg.Logger,
+		now:      time.Now,
+		seen:     make(map[string]uint64),
+		acks:     make(map[string]uint64),
+		appended: make(chan struct{}),
+	}
+	if l.logger == nil {
+		l.logger = slog.New(slog.DiscardHandler)
+	}
+	f, err := os.OpenFile(cfg.Path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("eventlog: %w", err)
+	}
+	l.f = f
+	if err := l.load(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("eventlog: %s: %w", cfg.Path, err)
+	}
+	data, err := os.ReadFile(l.acksPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		f.Close()
+		return nil, fmt.Errorf("eventlog: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &l.acks); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("eventlog: %s: %w", l.acksPath(), err)
+		}
+	}
+	return l, nil
+}
+
+// load indexes the file and positions it for appending.
+func (l *Log) load() error {
+	r := bufio.NewReader(l.f)
+	var pos int64
+	for {
+		data, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(data) > 0 {
+				l.logger.Warn("dropping partly written event log entry", "path", l.path, "offset", len(l.starts)+1)
+				if err := l.f.Truncate(pos); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		rec, err := decode(data)
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", len(l.starts)+1, err)
+		}
+		if rec.Offset != uint64(len(l.starts))+1 {
+			return fmt.Errorf("entry %d has offset %d", len(l.starts)+1, rec.Offset)
+		}
+		l.starts = append(l.starts, pos)
+		l.last = rec.Time
+		if key, ok := paymentKey(rec.Event); ok {
+			l.seen[key] = rec.Offset
+		}
+		pos += int64(len(data))
+	}
+	l.size = pos
+	_, err := l.f.Seek(pos, io.SeekStart)
+	return err
+}
+
+// OnEvent appends e, logging failures.
+func (l *Log) OnEvent(e breez_sdk_spark.SdkEvent) {
+	if _, err := l.Append(e); err != nil {
+		l.logger.Error("event not logged", "kind", breez_sdk_spark.SdkEventKindOf(e), "err", err)
+	}
+}
+
+// Append writes e to disk and returns its offset. A payment event already
+// logged for the same payment and kind, by Backfill for instance, is not
+// written again: the offset of the first one is returned.
+func (l *Log) Append(e breez_sdk_spark.SdkEvent) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	offset, _, err := l.appendLocked(e, false)
+	return offset, err
+}
+
+// appendLocked appends e unless it is a payment event logged already, and
+// reports whether it did.
+func (l *Log) appendLocked(e breez_sdk_spark.SdkEvent, backfilled bool) (uint64, bool, error) {
+	if l.f == nil {
+		return 0, false, ErrClosed
+	}
+	key, isPayment := paymentKey(e)
+	if offset, ok := l.seen[key]; isPayment && ok {
+		return offset, false, nil
+	}
+	rec := Record{Offset: uint64(len(l.starts)) + 1, Time: l.now().UTC().Round(0), Event: e, Backfilled: backfilled}
+	data, err := encode(rec)
+	if err != nil {
+		return 0, false, fmt.Errorf("eventlog: %w", err)
+	}
+	_, err = l.f.Write(data)
+	if err == nil {
+		err = l.f.Sync()
+	}
+	if err != nil {
+		// Do not leave an entry, partial or not synced, for the next one to
+		// follow under the same offset.
+		l.f.Truncate(l.size)
+		l.f.Seek(l.size, io.SeekStart)
+		return 0, false, fmt.Errorf("eventlog: %w", err)
+	}
+	l.starts = append(l.starts, l.size)
+	l.size += int64(len(data))
+	l.last = rec.Time
+	if isPayment {
+		l.seen[key] = rec.Offset
+	}
+	close(l.appended)
+	l.appended = make(chan struct{})
+	return rec.Offset, true, nil
+}
+
+// Last returns the offset of the last record, 0 when the log is empty.
+func (l *Log) Last() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return uint64(len(l.starts))
+}
+
+// Read returns up to limit records from offset from on.
+func (l *Log) Read(from uint64, limit int) ([]Record, error) {
+	l.mu.Lock()
+	if l.f == nil {
+		l.mu.Unlock()
+		return nil, ErrClosed
+	}
+	from = max(from, 1)
+	n := uint64(len(l.starts))
+	if from > n || limit <= 0 {
+		l.mu.Unlock()
+		return nil, nil
+	}
+	to := min(n, from+uint64(limit)-1)
+	start, end := l.starts[from-1], l.size
+	if to < n {
+		end = l.starts[to]
+	}
+	f := l.f
+	l.mu.Unlock()
+
+	// Written records do not change, so they are read without the lock.
+	data := make([]byte, end-start)
+	if _, err := f.ReadAt(data, start); err != nil {
+		return nil, fmt.Errorf("eventlog: %w", err)
+	}
+	records := make([]Record, 0, to-from+1)
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		rec, err := decode(data[:i+1])
+		if err != nil {
+			return nil, fmt.Errorf("eventlog: offset %d: %w", from+uint64(len(records)), err)
+		}
+		records = append(records, rec)
+		data = data[i+1:]
+	}
+	return records, nil
+}
+
+// followBatch is how many records Follow reads at a time.
+const followBatch = 256
+
+// Follow delivers the records from offset from on, then the new ones as
+// they are appended, until ctx is done or the log is closed, when the
+// channel is closed. Delivery waits for the receiver.
+func (l *Log) Follow(ctx context.Context, from uint64) <-chan Record {
+	ch := make(chan Record)
+	go func() {
+		defer close(ch)
+		from = max(from, 1)
+		for {
+			l.mu.Lock()
+			appended, closed := l.appended, l.f == nil
+			l.mu.Unlock()
+			if closed {
+				return
+			}
+			records, err := l.Read(from, followBatch)
+			if err != nil {
+				if !errors.Is(err, ErrClosed) {
+					l.logger.Error("event log not readable", "offset", from, "err", err)
+				}
+				return
+			}
+			for _, rec := range records {
+				select {
+				case ch <- rec:
+				case <-ctx.Done():
+					return
+				}
+			}
+			from += uint64(len(records))
+			if len(records) == followBatch {
+				continue
+			}
+			select {
+			case <-appended:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Ack records that consumer has handled the records up to offset.
+func (l *Log) Ack(consumer string, offset uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return ErrClosed
+	}
+	if offset > uint64(len(l.starts)) {
+		return fmt.Errorf("eventlog: offset %d is past the end of the log", offset)
+	}
+	l.acks[consumer] = offset
+	data, err := json.MarshalIndent(l.acks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(l.acksPath(), data)
+}
+
+// Acked returns the last offset consumer acknowledged, 0 if none.
+func (l *Log) Acked(consumer string) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.acks[consumer]
+}
+
+// Close closes the file and ends the followers.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	close(l.appended)
+	l.appended = make(chan struct{})
+	return err
+}
+
+func (l *Log) acksPath() string {
+	return l.path + ".acks"
+}
+
+func encode(rec Record) ([]byte, error) {
+	event, err := toEventJSON(rec.Event)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(line{
+		Version:    formatVersion,
+		Offset:     rec.Offset,
+		Time:       rec.Time,
+		Kind:       breez_sdk_spark.SdkEventKindOf(rec.Event).String(),
+		Backfilled: rec.Backfilled,
+		Event:      event,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func decode(data []byte) (Record, error) {
+	var ln line
+	if err := json.Unmarshal(data, &ln); err != nil {
+		return Record{}, err
+	}
+	if ln.Version != formatVersion {
+		return Record{}, fmt.Errorf("unsupported format version %d", ln.Version)
+	}
+	event, err := fromEventJSON(ln.Kind, ln.Event)
+	if err != nil {
+		return Record{}, err
+	}
+	return Record{Offset: ln.Offset, Time: ln.Time, Event: event, Backfilled: ln.Backfilled}, nil
+}
+
+// payment returns the payment of a payment event.
+func payment(e breez_sdk_spark.SdkEvent) (breez_sdk_spark.Payment, bool) {
+	switch e := e.(type) {
+	case breez_sdk_spark.SdkEventPaymentSucceeded:
+		return e.Payment, true
+	case breez_sdk_spark.SdkEventPaymentPending:
+		return e.Payment, true
+	case breez_sdk_spark.SdkEventPaymentFailed:
+		return e.Payment, true
+	}
+	return breez_sdk_spark.Payment{}, false
+}
+
+// paymentKey identifies a payment event by payment and kind, for the log
+// not to hold it twice.
+func paymentKey(e breez_sdk_spark.SdkEvent) (string, bool) {
+	p, ok := payment(e)
+	if !ok {
+		return "", false
+	}
+	return breez_sdk_spark.SdkEventKindOf(e).String() + "/" + p.Id, true
+}
+
+// writeFile replaces the file at path atomically.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("eventlog: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("eventlog: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("eventlog: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("eventlog: %w", err)
+	}
+	return nil
+}
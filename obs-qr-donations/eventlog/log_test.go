@@ -0,0 +1,325 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+func donation(id string, status breez_sdk_spark.PaymentStatus, ts uint64) breez_sdk_spark.Payment {
+	return breez_sdk_spark.Payment{
+		Id:          id,
+		PaymentType: breez_sdk_spark.PaymentTypeReceive,
+		Status:      status,
+		Amount:      big.NewInt(21_000),
+		Fees:        big.NewInt(0),
+		Timestamp:   ts,
+		Method:      breez_sdk_spark.PaymentMethodSpark,
+	}
+}
+
+func open(t *testing.T, path string) *Log {
+	t.Helper()
+	l, err := Open(Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func ids(records []Record) []string {
+	var got []string
+	for _, rec := range records {
+		p, _ := payment(rec.Event)
+		got = append(got, fmt.Sprintf("%d:%s:%s", rec.Offset, breez_sdk_spark.SdkEventKindOf(rec.Event), p.Id))
+	}
+	return got
+}
+
+func TestAppendAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	l := open(t, path)
+	l.OnEvent(breez_sdk_spark.SdkEventSynced{})
+	l.OnEvent(breez_sdk_spark.SdkEventPaymentPending{Payment: donation("p1", breez_sdk_spark.PaymentStatusPending, 100)})
+	l.OnEvent(breez_sdk_spark.SdkEventPaymentSucceeded{Payment: donation("p1", breez_sdk_spark.PaymentStatusCompleted, 100)})
+	if err := l.Ack("overlay", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Ack("overlay", 4); err == nil {
+		t.Fatal("acknowledged an offset past the end")
+	}
+	l.Close()
+	if _, err := l.Append(breez_sdk_spark.SdkEventSynced{}); !errors.Is(err, ErrClosed) {
+		t.Fatalf("append after close: %v", err)
+	}
+
+	// A crash in the middle of a write leaves a partial last line.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"offset":4,"time":`)
+	f.Close()
+
+	l = open(t, path)
+	if l.Last() != 3 || l.Acked("overlay") != 2 || l.Acked("bot") != 0 {
+		t.Fatalf("last %d, acked %d", l.Last(), l.Acked("overlay"))
+	}
+	if off, err := l.Append(breez_sdk_spark.SdkEventDataSynced{DidPullNewRecords: true}); err != nil || off != 4 {
+		t.Fatalf("append: %d, %v", off, err)
+	}
+	records, err := l.Read(l.Acked("overlay")+1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(records); !slices.Equal(got, []string{"3:payment_succeeded:p1", "4:data_synced:"}) {
+		t.Fatalf("got %v", got)
+	}
+	p, _ := payment(records[0].Event)
+	if p.Amount.Int64() != 21_000 || records[0].Backfilled {
+		t.Fatalf("record %+v", records[0])
+	}
+	if e, ok := records[1].Event.(breez_sdk_spark.SdkEventDataSynced); !ok || !e.DidPullNewRecords {
+		t.Fatalf("event %#v", records[1].Event)
+	}
+	if records, _ := l.Read(2, 1); len(records) != 1 || records[0].Offset != 2 {
+		t.Fatalf("limited read: %v", ids(records))
+	}
+
+	// Damage before the end is not mistaken for a crash.
+	l.Close()
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, append([]byte("garbage\n"), data...), 0o600)
+	if _, err := Open(Config{Path: path}); err == nil {
+		t.Fatal("opened a damaged log")
+	}
+}
+
+func TestFollow(t *testing.T) {
+	l := open(t, filepath.Join(t.TempDir(), "events.jsonl"))
+	l.Append(breez_sdk_spark.SdkEventSynced{})
+	l.Append(breez_sdk_spark.SdkEventPaymentSucceeded{Payment: donation("p1", breez_sdk_spark.PaymentStatusCompleted, 100)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := l.Follow(ctx, 2)
+	next := func() Record {
+		t.Helper()
+		select {
+		case rec, ok := <-ch:
+			if !ok {
+				t.Fatal("follow ended")
+			}
+			return rec
+		case <-time.After(time.Second):
+			t.Fatal("no record")
+		}
+		return Record{}
+	}
+	if rec := next(); rec.Offset != 2 {
+		t.Fatalf("got %d", rec.Offset)
+	}
+	go l.OnEvent(breez_sdk_spark.SdkEventPaymentSucceeded{Payment: donation("p2", breez_sdk_spark.PaymentStatusCompleted, 200)})
+	if rec := next(); rec.Offset != 3 {
+		t.Fatalf("got %d", rec.Offset)
+	}
+
+	l.Close()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("record after close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("follow did not end with the log")
+	}
+}
+
+// lister serves payments the way ListPayments does.
+type lister struct {
+	payments []breez_sdk_spark.Payment
+	calls    int
+}
+
+func (s *lister) ListPaymentsCtx(_ context.Context, req breez_sdk_spark.ListPaymentsRequest) (breez_sdk_spark.ListPaymentsResponse, error) {
+	s.calls++
+	var matched []breez_sdk_spark.Payment
+	for _, p := range s.payments {
+		if req.FromTimestamp == nil || p.Timestamp >= *req.FromTimestamp {
+			matched = append(matched, p)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if *req.SortAscending {
+			return matched[i].Timestamp < matched[j].Timestamp
+		}
+		return matched[i].Timestamp > matched[j].Timestamp
+	})
+	matched = matched[min(int(*req.Offset), len(matched)):]
+	matched = matched[:min(int(*req.Limit), len(matched))]
+	return breez_sdk_spark.ListPaymentsResponse{Payments: matched}, nil
+}
+
+func TestBackfill(t *testing.T) {
+	l := open(t, filepath.Join(t.TempDir(), "events.jsonl"))
+	start := time.Unix(1_700_000_000, 0)
+	l.now = func() time.Time { return start }
+	ts := uint64(start.Unix())
+	l.Append(breez_sdk_spark.SdkEventPaymentPending{Payment: donation("p1", breez_sdk_spark.PaymentStatusPending, ts)})
+	l.Append(breez_sdk_spark.SdkEventPaymentSucceeded{Payment: donation("p2", breez_sdk_spark.PaymentStatusCompleted, ts)})
+
+	// While the plugin was down, p1 completed, p3 came in and p4 failed;
+	// p0 is older than the overlap.
+	src := &lister{payments: []breez_sdk_spark.Payment{
+		donation("p0", breez_sdk_spark.PaymentStatusCompleted, ts-uint64(2*DefaultBackfillOverlap/time.Second)),
+		donation("p1", breez_sdk_spark.PaymentStatusCompleted, ts),
+		donation("p2", breez_sdk_spark.PaymentStatusCompleted, ts),
+		donation("p4", breez_sdk_spark.PaymentStatusFailed, ts+20),
+		donation("p3", breez_sdk_spark.PaymentStatusPending, ts+10),
+	}}
+	n, err := l.Backfill(context.Background(), src, time.Time{})
+	if err != nil || n != 3 {
+		t.Fatalf("backfilled %d, %v", n, err)
+	}
+	records, _ := l.Read(3, 10)
+	if got := ids(records); !slices.Equal(got, []string{"3:payment_succeeded:p1", "4:payment_pending:p3", "5:payment_failed:p4"}) {
+		t.Fatalf("got %v", got)
+	}
+	if !records[0].Backfilled {
+		t.Fatal("not marked as backfilled")
+	}
+
+	// Backfilling again adds nothing.
+	if n, err := l.Backfill(context.Background(), src, start.Add(-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("again: %d, %v", n, err)
+	}
+	// Nor does the SDK delivering late what was backfilled.
+	last := l.Last()
+	l.OnEvent(breez_sdk_spark.SdkEventPaymentSucceeded{Payment: donation("p1", breez_sdk_spark.PaymentStatusCompleted, ts)})
+	if off, err := l.Append(breez_sdk_spark.SdkEventPaymentPending{Payment: donation("p3", breez_sdk_spark.PaymentStatusPending, ts+10)}); err != nil || off != 4 {
+		t.Fatalf("append of a backfilled event: %d, %v", off, err)
+	}
+	if l.Last() != last {
+		t.Fatalf("logged %d backfilled events again", l.Last()-last)
+	}
+
+	// Histories longer than a page are listed to the end.
+	src = &lister{}
+	for i := range backfillPage + 5 {
+		src.payments = append(src.payments, donation(fmt.Sprint("q", i), breez_sdk_spark.PaymentStatusCompleted, ts+uint64(i)))
+	}
+	if n, err := l.Backfill(context.Background(), src, start); err != nil || n != backfillPage+5 || src.calls != 2 {
+		t.Fatalf("paged: %d in %d calls, %v", n, src.calls, err)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestEventSchema(t *testing.T) {
+	fee := breez_sdk_spark.Fee(breez_sdk_spark.FeeRate{SatPerVbyte: 3})
+	claimErr := breez_sdk_spark.DepositClaimError(breez_sdk_spark.DepositClaimErrorMaxDepositClaimFeeExceeded{
+		Tx: "tx1", Vout: 1, MaxFee: &fee, RequiredFeeSats: 900, RequiredFeeRateSatPerVbyte: 5,
+	})
+	processed := breez_sdk_spark.SuccessActionProcessed(breez_sdk_spark.SuccessActionProcessedAes{
+		Result: breez_sdk_spark.AesSuccessActionDataResultDecrypted{
+			Data: breez_sdk_spark.AesSuccessActionDataDecrypted{Description: "code", Plaintext: "1234"},
+		},
+	})
+	raw := breez_sdk_spark.SuccessAction(breez_sdk_spark.SuccessActionUrl{
+		Data: breez_sdk_spark.UrlSuccessActionData{Description: "thanks", Url: "https://example.com", MatchesCallbackDomain: true},
+	})
+	lightning := breez_sdk_spark.PaymentDetails(breez_sdk_spark.PaymentDetailsLightning{
+		Description:       ptr("for the stream"),
+		Invoice:           "lnbc1",
+		PaymentHash:       "h1",
+		DestinationPubkey: "02ab",
+		LnurlPayInfo: &breez_sdk_spark.LnurlPayInfo{
+			LnAddress:              ptr("streamer@example.com"),
+			ProcessedSuccessAction: &processed,
+			RawSuccessAction:       &raw,
+		},
+		LnurlReceiveMetadata: &breez_sdk_spark.LnurlReceiveMetadata{SenderComment: ptr("gg")},
+	})
+	token := breez_sdk_spark.PaymentDetails(breez_sdk_spark.PaymentDetailsToken{
+		Metadata: breez_sdk_spark.TokenMetadata{
+			Identifier: "btkn1", Name: "Stream", Ticker: "STR", Decimals: 8,
+			MaxSupply: new(big.Int).Lsh(big.NewInt(1), 100), IsFreezable: true,
+		},
+		TxHash:         "th1",
+		InvoiceDetails: &breez_sdk_spark.SparkInvoicePaymentDetails{Invoice: "spark1"},
+	})
+	spark := breez_sdk_spark.PaymentDetails(breez_sdk_spark.PaymentDetailsSpark{
+		HtlcDetails: &breez_sdk_spark.SparkHtlcDetails{PaymentHash: "h2", ExpiryTime: 10, Status: breez_sdk_spark.SparkHtlcStatusPreimageShared},
+	})
+	withLightning := donation("p1", breez_sdk_spark.PaymentStatusCompleted, 100)
+	withLightning.Method = breez_sdk_spark.PaymentMethodLightning
+	withLightning.Details = &lightning
+	withToken := donation("p2", breez_sdk_spark.PaymentStatusFailed, 100)
+	withToken.Method = breez_sdk_spark.PaymentMethodToken
+	withToken.Amount = new(big.Int).Lsh(big.NewInt(1), 90)
+	withToken.Details = &token
+	withSpark := donation("p3", breez_sdk_spark.PaymentStatusPending, 100)
+	withSpark.Details = &spark
+
+	for _, e := range []breez_sdk_spark.SdkEvent{
+		breez_sdk_spark.SdkEventSynced{},
+		breez_sdk_spark.SdkEventDataSynced{DidPullNewRecords: true},
+		breez_sdk_spark.SdkEventUnclaimedDeposits{UnclaimedDeposits: []breez_sdk_spark.DepositInfo{
+			{Txid: "tx1", Vout: 1, AmountSats: 50_000, ClaimError: &claimErr},
+			{Txid: "tx2", AmountSats: 1_000, RefundTx: ptr("00"), RefundTxId: ptr("r1")},
+		}},
+		breez_sdk_spark.SdkEventPaymentSucceeded{Payment: withLightning},
+		breez_sdk_spark.SdkEventPaymentFailed{Payment: withToken},
+		breez_sdk_spark.SdkEventPaymentPending{Payment: withSpark},
+	} {
+		data, err := encode(Record{Offset: 1, Event: e})
+		if err != nil {
+			t.Fatalf("%T: %v", e, err)
+		}
+		rec, err := decode(data)
+		if err != nil {
+			t.Fatalf("%T: %v", e, err)
+		}
+		again, _ := encode(rec)
+		if string(again) != string(data) {
+			t.Fatalf("%T changed:\n%s%s", e, data, again)
+		}
+	}
+
+	// Lines written by this version stay readable.
+	rec, err := decode([]byte(`{"v":1,"offset":7,"time":"2026-01-02T03:04:05Z","kind":"payment_succeeded","backfilled":true,` +
+		`"event":{"payment":{"id":"p1","type":"receive","status":"completed","amount":"21000","fees":"0","timestamp":100,"method":"lightning",` +
+		`"details":{"type":"lightning","invoice":"lnbc1","payment_hash":"h1","lnurl_pay_info":{"processed_success_action":{"type":"message","message":"gg"}}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, ok := rec.Event.(breez_sdk_spark.SdkEventPaymentSucceeded)
+	if !ok || rec.Offset != 7 || !rec.Backfilled || e.Payment.Id != "p1" || e.Payment.Amount.Int64() != 21_000 ||
+		e.Payment.PaymentType != breez_sdk_spark.PaymentTypeReceive || e.Payment.Method != breez_sdk_spark.PaymentMethodLightning {
+		t.Fatalf("got %+v", rec)
+	}
+	d, ok := (*e.Payment.Details).(breez_sdk_spark.PaymentDetailsLightning)
+	if !ok || d.Invoice != "lnbc1" || d.LnurlPayInfo == nil ||
+		*d.LnurlPayInfo.ProcessedSuccessAction != breez_sdk_spark.SuccessActionProcessed(breez_sdk_spark.SuccessActionProcessedMessage{Data: breez_sdk_spark.MessageSuccessActionData{Message: "gg"}}) {
+		t.Fatalf("details %+v", *e.Payment.Details)
+	}
+
+	for _, bad := range []string{
+		`{"v":2,"offset":1,"time":"2026-01-02T03:04:05Z","kind":"synced","event":{}}`,
+		`{"offset":1,"time":"2026-01-02T03:04:05Z","kind":"synced","event":{}}`,
+		`{"v":1,"offset":1,"time":"2026-01-02T03:04:05Z","kind":"reorged","event":{}}`,
+		`{"v":1,"offset":1,"time":"2026-01-02T03:04:05Z","kind":"payment_failed","event":{}}`,
+		`{"v":1,"offset":1,"time":"2026-01-02T03:04:05Z","kind":"payment_failed","event":{"payment":{"id":"p1","type":"refund","status":"failed","amount":"1","fees":"0","method":"spark"}}}`,
+	} {
+		if _, err := decode([]byte(bad)); err == nil {
+			t.Fatalf("decoded %s", bad)
+		}
+	}
+}
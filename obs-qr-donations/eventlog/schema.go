@@ -0,0 +1,641 @@
+package eventlog
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// formatVersion is the version of the record layout, written in the "v"
+// field of every line. The layout only names fields and values of its own,
+// so that logs stay readable whatever the SDK bindings change; a change
+// that old readers would misread needs a new version.
+const formatVersion = 1
+
+// line is the layout of a record in the file.
+type line struct {
+	Version    int       `json:"v"`
+	Offset     uint64    `json:"offset"`
+	Time       time.Time `json:"time"`
+	Kind       string    `json:"kind"`
+	Backfilled bool      `json:"backfilled,omitempty"`
+	Event      eventJSON `json:"event"`
+}
+
+// eventJSON holds the fields of the event kinds: none for synced,
+// DidPullNewRecords for data_synced, Deposits for the deposit events and
+// Payment for the payment events.
+type eventJSON struct {
+	DidPullNewRecords bool          `json:"did_pull_new_records,omitempty"`
+	Deposits          []depositJSON `json:"deposits,omitempty"`
+	Payment           *paymentJSON  `json:"payment,omitempty"`
+}
+
+type paymentJSON struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	// Amount and Fees are decimal: token amounts do not always fit in a
+	// JSON number.
+	Amount    string       `json:"amount"`
+	Fees      string       `json:"fees"`
+	Timestamp uint64       `json:"timestamp"`
+	Method    string       `json:"method"`
+	Details   *detailsJSON `json:"details,omitempty"`
+}
+
+// detailsJSON holds the fields of the payment detail kinds, told apart by
+// Type.
+type detailsJSON struct {
+	Type string `json:"type"`
+	// spark and token
+	InvoiceDetails *sparkInvoiceJSON `json:"invoice_details,omitempty"`
+	// spark
+	HtlcDetails *htlcJSON `json:"htlc_details,omitempty"`
+	// token
+	Token  *tokenJSON `json:"token,omitempty"`
+	TxHash string     `json:"tx_hash,omitempty"`
+	// lightning
+	Description       *string           `json:"description,omitempty"`
+	Preimage          *string           `json:"preimage,omitempty"`
+	Invoice           string            `json:"invoice,omitempty"`
+	PaymentHash       string            `json:"payment_hash,omitempty"`
+	DestinationPubkey string            `json:"destination_pubkey,omitempty"`
+	LnurlPayInfo      *lnurlPayJSON     `json:"lnurl_pay_info,omitempty"`
+	LnurlWithdrawURL  *string           `json:"lnurl_withdraw_url,omitempty"`
+	LnurlReceive      *lnurlReceiveJSON `json:"lnurl_receive_metadata,omitempty"`
+	// withdraw and deposit
+	TxID string `json:"tx_id,omitempty"`
+}
+
+type sparkInvoiceJSON struct {
+	Description *string `json:"description,omitempty"`
+	Invoice     string  `json:"invoice"`
+}
+
+type htlcJSON struct {
+	PaymentHash string  `json:"payment_hash"`
+	Preimage    *string `json:"preimage,omitempty"`
+	ExpiryTime  uint64  `json:"expiry_time"`
+	Status      string  `json:"status"`
+}
+
+type tokenJSON struct {
+	Identifier      string `json:"identifier"`
+	IssuerPublicKey string `json:"issuer_public_key"`
+	Name            string `json:"name"`
+	Ticker          string `json:"ticker"`
+	Decimals        uint32 `json:"decimals"`
+	MaxSupply       string `json:"max_supply"`
+	IsFreezable     bool   `json:"is_freezable"`
+}
+
+type lnurlPayJSON struct {
+	LnAddress              *string            `json:"ln_address,omitempty"`
+	Comment                *string            `json:"comment,omitempty"`
+	Domain                 *string            `json:"domain,omitempty"`
+	Metadata               *string            `json:"metadata,omitempty"`
+	ProcessedSuccessAction *successActionJSON `json:"processed_success_action,omitempty"`
+	RawSuccessAction       *successActionJSON `json:"raw_success_action,omitempty"`
+}
+
+// successActionJSON holds the fields of the LUD-09 success action kinds:
+// aes, message and url. Processed aes actions carry Result instead of the
+// ciphertext.
+type successActionJSON struct {
+	Type                  string         `json:"type"`
+	Description           string         `json:"description,omitempty"`
+	Ciphertext            string         `json:"ciphertext,omitempty"`
+	IV                    string         `json:"iv,omitempty"`
+	Result                *aesResultJSON `json:"result,omitempty"`
+	Message               string         `json:"message,omitempty"`
+	URL                   string         `json:"url,omitempty"`
+	MatchesCallbackDomain bool           `json:"matches_callback_domain,omitempty"`
+}
+
+// aesResultJSON is a decrypted aes action, or why it could not be.
+type aesResultJSON struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Plaintext   string `json:"plaintext,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+type lnurlReceiveJSON struct {
+	NostrZapRequest *string `json:"nostr_zap_request,omitempty"`
+	NostrZapReceipt *string `json:"nostr_zap_receipt,omitempty"`
+	SenderComment   *string `json:"sender_comment,omitempty"`
+}
+
+type depositJSON struct {
+	Txid       string          `json:"txid"`
+	Vout       uint32          `json:"vout"`
+	AmountSats uint64          `json:"amount_sats"`
+	RefundTx   *string         `json:"refund_tx,omitempty"`
+	RefundTxID *string         `json:"refund_tx_id,omitempty"`
+	ClaimError *claimErrorJSON `json:"claim_error,omitempty"`
+}
+
+// claimErrorJSON holds the fields of the claim error kinds:
+// max_deposit_claim_fee_exceeded, missing_utxo and generic.
+type claimErrorJSON struct {
+	Type                       string   `json:"type"`
+	Tx                         string   `json:"tx,omitempty"`
+	Vout                       uint32   `json:"vout,omitempty"`
+	MaxFee                     *feeJSON `json:"max_fee,omitempty"`
+	RequiredFeeSats            uint64   `json:"required_fee_sats,omitempty"`
+	RequiredFeeRateSatPerVbyte uint64   `json:"required_fee_rate_sat_per_vbyte,omitempty"`
+	Message                    string   `json:"message,omitempty"`
+}
+
+// feeJSON is a fixed fee or a fee rate.
+type feeJSON struct {
+	Type        string `json:"type"`
+	Amount      uint64 `json:"amount,omitempty"`
+	SatPerVbyte uint64 `json:"sat_per_vbyte,omitempty"`
+}
+
+// names maps the values of an SDK enum to the names the log uses for them.
+type names[T comparable] map[T]string
+
+func (n names[T]) name(v T) (string, error) {
+	if s, ok := n[v]; ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown %T %v", v, v)
+}
+
+func (n names[T]) value(s string) (T, error) {
+	for v, name := range n {
+		if name == s {
+			return v, nil
+		}
+	}
+	var zero T
+	return zero, fmt.Errorf("unknown %T %q", zero, s)
+}
+
+var (
+	paymentTypes = names[breez_sdk_spark.PaymentType]{
+		breez_sdk_spark.PaymentTypeSend:    "send",
+		breez_sdk_spark.PaymentTypeReceive: "receive",
+	}
+	paymentStatuses = names[breez_sdk_spark.PaymentStatus]{
+		breez_sdk_spark.PaymentStatusCompleted: "completed",
+		breez_sdk_spark.PaymentStatusPending:   "pending",
+		breez_sdk_spark.PaymentStatusFailed:    "failed",
+	}
+	paymentMethods = names[breez_sdk_spark.PaymentMethod]{
+		breez_sdk_spark.PaymentMethodLightning: "lightning",
+		breez_sdk_spark.PaymentMethodSpark:     "spark",
+		breez_sdk_spark.PaymentMethodToken:     "token",
+		breez_sdk_spark.PaymentMethodDeposit:   "deposit",
+		breez_sdk_spark.PaymentMethodWithdraw:  "withdraw",
+		breez_sdk_spark.PaymentMethodUnknown:   "unknown",
+	}
+	htlcStatuses = names[breez_sdk_spark.SparkHtlcStatus]{
+		breez_sdk_spark.SparkHtlcStatusWaitingForPreimage: "waiting_for_preimage",
+		breez_sdk_spark.SparkHtlcStatusPreimageShared:     "preimage_shared",
+		breez_sdk_spark.SparkHtlcStatusReturned:           "returned",
+	}
+)
+
+// eventKinds are the event kinds by the name the log uses for them.
+var eventKinds = map[string]breez_sdk_spark.SdkEventKind{}
+
+func init() {
+	for k := breez_sdk_spark.SdkEventKindSynced; k <= breez_sdk_spark.SdkEventKindPaymentFailed; k++ {
+		eventKinds[k.String()] = k
+	}
+}
+
+func toEventJSON(e breez_sdk_spark.SdkEvent) (eventJSON, error) {
+	var out eventJSON
+	var err error
+	switch e := e.(type) {
+	case breez_sdk_spark.SdkEventSynced:
+	case breez_sdk_spark.SdkEventDataSynced:
+		out.DidPullNewRecords = e.DidPullNewRecords
+	case breez_sdk_spark.SdkEventUnclaimedDeposits:
+		out.Deposits, err = toDepositsJSON(e.UnclaimedDeposits)
+	case breez_sdk_spark.SdkEventClaimedDeposits:
+		out.Deposits, err = toDepositsJSON(e.ClaimedDeposits)
+	default:
+		p, ok := payment(e)
+		if !ok {
+			return out, fmt.Errorf("unknown event %T", e)
+		}
+		out.Payment, err = toPaymentJSON(p)
+	}
+	return out, err
+}
+
+func fromEventJSON(kind string, in eventJSON) (breez_sdk_spark.SdkEvent, error) {
+	k, ok := eventKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown event kind %q", kind)
+	}
+	switch k {
+	case breez_sdk_spark.SdkEventKindSynced:
+		return breez_sdk_spark.SdkEventSynced{}, nil
+	case breez_sdk_spark.SdkEventKindDataSynced:
+		return breez_sdk_spark.SdkEventDataSynced{DidPullNewRecords: in.DidPullNewRecords}, nil
+	case breez_sdk_spark.SdkEventKindUnclaimedDeposits, breez_sdk_spark.SdkEventKindClaimedDeposits:
+		deposits, err := fromDepositsJSON(in.Deposits)
+		if err != nil {
+			return nil, err
+		}
+		if k == breez_sdk_spark.SdkEventKindClaimedDeposits {
+			return breez_sdk_spark.SdkEventClaimedDeposits{ClaimedDeposits: deposits}, nil
+		}
+		return breez_sdk_spark.SdkEventUnclaimedDeposits{UnclaimedDeposits: deposits}, nil
+	}
+	if in.Payment == nil {
+		return nil, fmt.Errorf("%s event without a payment", kind)
+	}
+	p, err := fromPaymentJSON(*in.Payment)
+	if err != nil {
+		return nil, err
+	}
+	switch k {
+	case breez_sdk_spark.SdkEventKindPaymentSucceeded:
+		return breez_sdk_spark.SdkEventPaymentSucceeded{Payment: p}, nil
+	case breez_sdk_spark.SdkEventKindPaymentPending:
+		return breez_sdk_spark.SdkEventPaymentPending{Payment: p}, nil
+	default:
+		return breez_sdk_spark.SdkEventPaymentFailed{Payment: p}, nil
+	}
+}
+
+func toPaymentJSON(p breez_sdk_spark.Payment) (*paymentJSON, error) {
+	out := &paymentJSON{ID: p.Id, Amount: decimal(p.Amount), Fees: decimal(p.Fees), Timestamp: p.Timestamp}
+	var err error
+	if out.Type, err = paymentTypes.name(p.PaymentType); err != nil {
+		return nil, err
+	}
+	if out.Status, err = paymentStatuses.name(p.Status); err != nil {
+		return nil, err
+	}
+	if out.Method, err = paymentMethods.name(p.Method); err != nil {
+		return nil, err
+	}
+	if p.Details != nil {
+		if out.Details, err = toDetailsJSON(*p.Details); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func fromPaymentJSON(in paymentJSON) (breez_sdk_spark.Payment, error) {
+	p := breez_sdk_spark.Payment{Id: in.ID, Timestamp: in.Timestamp}
+	var err error
+	if p.PaymentType, err = paymentTypes.value(in.Type); err != nil {
+		return p, err
+	}
+	if p.Status, err = paymentStatuses.value(in.Status); err != nil {
+		return p, err
+	}
+	if p.Method, err = paymentMethods.value(in.Method); err != nil {
+		return p, err
+	}
+	if p.Amount, err = parseDecimal(in.Amount); err != nil {
+		return p, err
+	}
+	if p.Fees, err = parseDecimal(in.Fees); err != nil {
+		return p, err
+	}
+	if in.Details != nil {
+		d, err := fromDetailsJSON(*in.Details)
+		if err != nil {
+			return p, err
+		}
+		p.Details = &d
+	}
+	return p, nil
+}
+
+func toDetailsJSON(d breez_sdk_spark.PaymentDetails) (*detailsJSON, error) {
+	switch d := d.(type) {
+	case breez_sdk_spark.PaymentDetailsSpark:
+		out := &detailsJSON{Type: "spark", InvoiceDetails: toSparkInvoiceJSON(d.InvoiceDetails)}
+		if h := d.HtlcDetails; h != nil {
+			status, err := htlcStatuses.name(h.Status)
+			if err != nil {
+				return nil, err
+			}
+			out.HtlcDetails = &htlcJSON{PaymentHash: h.PaymentHash, Preimage: h.Preimage, ExpiryTime: h.ExpiryTime, Status: status}
+		}
+		return out, nil
+	case breez_sdk_spark.PaymentDetailsToken:
+		m := d.Metadata
+		return &detailsJSON{
+			Type: "token",
+			Token: &tokenJSON{
+				Identifier:      m.Identifier,
+				IssuerPublicKey: m.IssuerPublicKey,
+				Name:            m.Name,
+				Ticker:          m.Ticker,
+				Decimals:        m.Decimals,
+				MaxSupply:       decimal(m.MaxSupply),
+				IsFreezable:     m.IsFreezable,
+			},
+			TxHash:         d.TxHash,
+			InvoiceDetails: toSparkInvoiceJSON(d.InvoiceDetails),
+		}, nil
+	case breez_sdk_spark.PaymentDetailsLightning:
+		out := &detailsJSON{
+			Type:              "lightning",
+			Description:       d.Description,
+			Preimage:          d.Preimage,
+			Invoice:           d.Invoice,
+			PaymentHash:       d.PaymentHash,
+			DestinationPubkey: d.DestinationPubkey,
+		}
+		if info := d.LnurlPayInfo; info != nil {
+			out.LnurlPayInfo = &lnurlPayJSON{LnAddress: info.LnAddress, Comment: info.Comment, Domain: info.Domain, Metadata: info.Metadata}
+			var err error
+			if info.ProcessedSuccessAction != nil {
+				if out.LnurlPayInfo.ProcessedSuccessAction, err = toProcessedActionJSON(*info.ProcessedSuccessAction); err != nil {
+					return nil, err
+				}
+			}
+			if info.RawSuccessAction != nil {
+				if out.LnurlPayInfo.RawSuccessAction, err = toRawActionJSON(*info.RawSuccessAction); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if d.LnurlWithdrawInfo != nil {
+			out.LnurlWithdrawURL = &d.LnurlWithdrawInfo.WithdrawUrl
+		}
+		if m := d.LnurlReceiveMetadata; m != nil {
+			out.LnurlReceive = &lnurlReceiveJSON{NostrZapRequest: m.NostrZapRequest, NostrZapReceipt: m.NostrZapReceipt, SenderComment: m.SenderComment}
+		}
+		return out, nil
+	case breez_sdk_spark.PaymentDetailsWithdraw:
+		return &detailsJSON{Type: "withdraw", TxID: d.TxId}, nil
+	case breez_sdk_spark.PaymentDetailsDeposit:
+		return &detailsJSON{Type: "deposit", TxID: d.TxId}, nil
+	}
+	return nil, fmt.Errorf("unknown payment details %T", d)
+}
+
+func fromDetailsJSON(in detailsJSON) (breez_sdk_spark.PaymentDetails, error) {
+	switch in.Type {
+	case "spark":
+		out := breez_sdk_spark.PaymentDetailsSpark{InvoiceDetails: fromSparkInvoiceJSON(in.InvoiceDetails)}
+		if h := in.HtlcDetails; h != nil {
+			status, err := htlcStatuses.value(h.Status)
+			if err != nil {
+				return nil, err
+			}
+			out.HtlcDetails = &breez_sdk_spark.SparkHtlcDetails{PaymentHash: h.PaymentHash, Preimage: h.Preimage, ExpiryTime: h.ExpiryTime, Status: status}
+		}
+		return out, nil
+	case "token":
+		if in.Token == nil {
+			return nil, errors.New("token payment without token metadata")
+		}
+		maxSupply, err := parseDecimal(in.Token.MaxSupply)
+		if err != nil {
+			return nil, err
+		}
+		return breez_sdk_spark.PaymentDetailsToken{
+			Metadata: breez_sdk_spark.TokenMetadata{
+				Identifier:      in.Token.Identifier,
+				IssuerPublicKey: in.Token.IssuerPublicKey,
+				Name:            in.Token.Name,
+				Ticker:          in.Token.Ticker,
+				Decimals:        in.Token.Decimals,
+				MaxSupply:       maxSupply,
+				IsFreezable:     in.Token.IsFreezable,
+			},
+			TxHash:         in.TxHash,
+			InvoiceDetails: fromSparkInvoiceJSON(in.InvoiceDetails),
+		}, nil
+	case "lightning":
+		out := breez_sdk_spark.PaymentDetailsLightning{
+			Description:       in.Description,
+			Preimage:          in.Preimage,
+			Invoice:           in.Invoice,
+			PaymentHash:       in.PaymentHash,
+			DestinationPubkey: in.DestinationPubkey,
+		}
+		if info := in.LnurlPayInfo; info != nil {
+			out.LnurlPayInfo = &breez_sdk_spark.LnurlPayInfo{LnAddress: info.LnAddress, Comment: info.Comment, Domain: info.Domain, Metadata: info.Metadata}
+			if info.ProcessedSuccessAction != nil {
+				a, err := fromProcessedActionJSON(*info.ProcessedSuccessAction)
+				if err != nil {
+					return nil, err
+				}
+				out.LnurlPayInfo.ProcessedSuccessAction = &a
+			}
+			if info.RawSuccessAction != nil {
+				a, err := fromRawActionJSON(*info.RawSuccessAction)
+				if err != nil {
+					return nil, err
+				}
+				out.LnurlPayInfo.RawSuccessAction = &a
+			}
+		}
+		if in.LnurlWithdrawURL != nil {
+			out.LnurlWithdrawInfo = &breez_sdk_spark.LnurlWithdrawInfo{WithdrawUrl: *in.LnurlWithdrawURL}
+		}
+		if m := in.LnurlReceive; m != nil {
+			out.LnurlReceiveMetadata = &breez_sdk_spark.LnurlReceiveMetadata{NostrZapRequest: m.NostrZapRequest, NostrZapReceipt: m.NostrZapReceipt, SenderComment: m.SenderComment}
+		}
+		return out, nil
+	case "withdraw":
+		return breez_sdk_spark.PaymentDetailsWithdraw{TxId: in.TxID}, nil
+	case "deposit":
+		return breez_sdk_spark.PaymentDetailsDeposit{TxId: in.TxID}, nil
+	}
+	return nil, fmt.Errorf("unknown payment details %q", in.Type)
+}
+
+func toSparkInvoiceJSON(d *breez_sdk_spark.SparkInvoicePaymentDetails) *sparkInvoiceJSON {
+	if d == nil {
+		return nil
+	}
+	return &sparkInvoiceJSON{Description: d.Description, Invoice: d.Invoice}
+}
+
+func fromSparkInvoiceJSON(d *sparkInvoiceJSON) *breez_sdk_spark.SparkInvoicePaymentDetails {
+	if d == nil {
+		return nil
+	}
+	return &breez_sdk_spark.SparkInvoicePaymentDetails{Description: d.Description, Invoice: d.Invoice}
+}
+
+func toRawActionJSON(a breez_sdk_spark.SuccessAction) (*successActionJSON, error) {
+	switch a := a.(type) {
+	case breez_sdk_spark.SuccessActionAes:
+		return &successActionJSON{Type: "aes", Description: a.Data.Description, Ciphertext: a.Data.Ciphertext, IV: a.Data.Iv}, nil
+	case breez_sdk_spark.SuccessActionMessage:
+		return &successActionJSON{Type: "message", Message: a.Data.Message}, nil
+	case breez_sdk_spark.SuccessActionUrl:
+		return urlActionJSON(a.Data), nil
+	}
+	return nil, fmt.Errorf("unknown success action %T", a)
+}
+
+func fromRawActionJSON(in successActionJSON) (breez_sdk_spark.SuccessAction, error) {
+	switch in.Type {
+	case "aes":
+		return breez_sdk_spark.SuccessActionAes{Data: breez_sdk_spark.AesSuccessActionData{Description: in.Description, Ciphertext: in.Ciphertext, Iv: in.IV}}, nil
+	case "message":
+		return breez_sdk_spark.SuccessActionMessage{Data: breez_sdk_spark.MessageSuccessActionData{Message: in.Message}}, nil
+	case "url":
+		return breez_sdk_spark.SuccessActionUrl{Data: urlActionData(in)}, nil
+	}
+	return nil, fmt.Errorf("unknown success action %q", in.Type)
+}
+
+func toProcessedActionJSON(a breez_sdk_spark.SuccessActionProcessed) (*successActionJSON, error) {
+	switch a := a.(type) {
+	case breez_sdk_spark.SuccessActionProcessedAes:
+		switch r := a.Result.(type) {
+		case breez_sdk_spark.AesSuccessActionDataResultDecrypted:
+			return &successActionJSON{Type: "aes", Result: &aesResultJSON{Type: "decrypted", Description: r.Data.Description, Plaintext: r.Data.Plaintext}}, nil
+		case breez_sdk_spark.AesSuccessActionDataResultErrorStatus:
+			return &successActionJSON{Type: "aes", Result: &aesResultJSON{Type: "error_status", Reason: r.Reason}}, nil
+		}
+		return nil, fmt.Errorf("unknown aes result %T", a.Result)
+	case breez_sdk_spark.SuccessActionProcessedMessage:
+		return &successActionJSON{Type: "message", Message: a.Data.Message}, nil
+	case breez_sdk_spark.SuccessActionProcessedUrl:
+		return urlActionJSON(a.Data), nil
+	}
+	return nil, fmt.Errorf("unknown success action %T", a)
+}
+
+func fromProcessedActionJSON(in successActionJSON) (breez_sdk_spark.SuccessActionProcessed, error) {
+	switch in.Type {
+	case "aes":
+		if in.Result == nil {
+			return nil, errors.New("processed aes success action without a result")
+		}
+		switch in.Result.Type {
+		case "decrypted":
+			return breez_sdk_spark.SuccessActionProcessedAes{Result: breez_sdk_spark.AesSuccessActionDataResultDecrypted{
+				Data: breez_sdk_spark.AesSuccessActionDataDecrypted{Description: in.Result.Description, Plaintext: in.Result.Plaintext},
+			}}, nil
+		case "error_status":
+			return breez_sdk_spark.SuccessActionProcessedAes{Result: breez_sdk_spark.AesSuccessActionDataResultErrorStatus{Reason: in.Result.Reason}}, nil
+		}
+		return nil, fmt.Errorf("unknown aes result %q", in.Result.Type)
+	case "message":
+		return breez_sdk_spark.SuccessActionProcessedMessage{Data: breez_sdk_spark.MessageSuccessActionData{Message: in.Message}}, nil
+	case "url":
+		return breez_sdk_spark.SuccessActionProcessedUrl{Data: urlActionData(in)}, nil
+	}
+	return nil, fmt.Errorf("unknown success action %q", in.Type)
+}
+
+func urlActionJSON(d breez_sdk_spark.UrlSuccessActionData) *successActionJSON {
+	return &successActionJSON{Type: "url", Description: d.Description, URL: d.Url, MatchesCallbackDomain: d.MatchesCallbackDomain}
+}
+
+func urlActionData(in successActionJSON) breez_sdk_spark.UrlSuccessActionData {
+	return breez_sdk_spark.UrlSuccessActionData{Description: in.Description, Url: in.URL, MatchesCallbackDomain: in.MatchesCallbackDomain}
+}
+
+func toDepositsJSON(deposits []breez_sdk_spark.DepositInfo) ([]depositJSON, error) {
+	out := make([]depositJSON, len(deposits))
+	for i, d := range deposits {
+		out[i] = depositJSON{Txid: d.Txid, Vout: d.Vout, AmountSats: d.AmountSats, RefundTx: d.RefundTx, RefundTxID: d.RefundTxId}
+		if d.ClaimError == nil {
+			continue
+		}
+		switch e := (*d.ClaimError).(type) {
+		case breez_sdk_spark.DepositClaimErrorMaxDepositClaimFeeExceeded:
+			ce := &claimErrorJSON{
+				Type:                       "max_deposit_claim_fee_exceeded",
+				Tx:                         e.Tx,
+				Vout:                       e.Vout,
+				RequiredFeeSats:            e.RequiredFeeSats,
+				RequiredFeeRateSatPerVbyte: e.RequiredFeeRateSatPerVbyte,
+			}
+			if e.MaxFee != nil {
+				switch f := (*e.MaxFee).(type) {
+				case breez_sdk_spark.FeeFixed:
+					ce.MaxFee = &feeJSON{Type: "fixed", Amount: f.Amount}
+				case breez_sdk_spark.FeeRate:
+					ce.MaxFee = &feeJSON{Type: "rate", SatPerVbyte: f.SatPerVbyte}
+				default:
+					return nil, fmt.Errorf("unknown fee %T", f)
+				}
+			}
+			out[i].ClaimError = ce
+		case breez_sdk_spark.DepositClaimErrorMissingUtxo:
+			out[i].ClaimError = &claimErrorJSON{Type: "missing_utxo", Tx: e.Tx, Vout: e.Vout}
+		case breez_sdk_spark.DepositClaimErrorGeneric:
+			out[i].ClaimError = &claimErrorJSON{Type: "generic", Message: e.Message}
+		default:
+			return nil, fmt.Errorf("unknown deposit claim error %T", e)
+		}
+	}
+	return out, nil
+}
+
+func fromDepositsJSON(deposits []depositJSON) ([]breez_sdk_spark.DepositInfo, error) {
+	out := make([]breez_sdk_spark.DepositInfo, len(deposits))
+	for i, d := range deposits {
+		out[i] = breez_sdk_spark.DepositInfo{Txid: d.Txid, Vout: d.Vout, AmountSats: d.AmountSats, RefundTx: d.RefundTx, RefundTxId: d.RefundTxID}
+		ce := d.ClaimError
+		if ce == nil {
+			continue
+		}
+		var claimErr breez_sdk_spark.DepositClaimError
+		switch ce.Type {
+		case "max_deposit_claim_fee_exceeded":
+			e := breez_sdk_spark.DepositClaimErrorMaxDepositClaimFeeExceeded{
+				Tx:                         ce.Tx,
+				Vout:                       ce.Vout,
+				RequiredFeeSats:            ce.RequiredFeeSats,
+				RequiredFeeRateSatPerVbyte: ce.RequiredFeeRateSatPerVbyte,
+			}
+			if ce.MaxFee != nil {
+				var fee breez_sdk_spark.Fee
+				switch ce.MaxFee.Type {
+				case "fixed":
+					fee = breez_sdk_spark.FeeFixed{Amount: ce.MaxFee.Amount}
+				case "rate":
+					fee = breez_sdk_spark.FeeRate{SatPerVbyte: ce.MaxFee.SatPerVbyte}
+				default:
+					return nil, fmt.Errorf("unknown fee %q", ce.MaxFee.Type)
+				}
+				e.MaxFee = &fee
+			}
+			claimErr = e
+		case "missing_utxo":
+			claimErr = breez_sdk_spark.DepositClaimErrorMissingUtxo{Tx: ce.Tx, Vout: ce.Vout}
+		case "generic":
+			claimErr = breez_sdk_spark.DepositClaimErrorGeneric{Message: ce.Message}
+		default:
+			return nil, fmt.Errorf("unknown deposit claim error %q", ce.Type)
+		}
+		out[i].ClaimError = &claimErr
+	}
+	return out, nil
+}
+
+// decimal formats an SDK amount; nil amounts are written as zero.
+func decimal(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
+
+func parseDecimal(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return v, nil
+}
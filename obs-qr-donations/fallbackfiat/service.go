@@ -0,0 +1,174 @@
+// Package fallbackfiat keeps fiat amounts on the overlay while the rate
+// provider is down. Service wraps another FiatService and, when a fetch
+// fails, serves the last currencies and rates it got, reporting how old
+// they are.
+//
+//	rates := fallbackfiat.New(provider, fallbackfiat.Config{MaxAge: 6 * time.Hour, Store: storage})
+//	builder.WithFiatService(breez_sdk_spark.NewFiatServiceFromCtx(rates))
+//	...
+//	if st := rates.Staleness(); st.Stale {
+//		overlay.Note(fmt.Sprintf("rates as of %s", st.FetchedAt.Format(time.Kitchen)))
+//	}
+package fallbackfiat
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// Keys of the cached items written to Config.Store.
+const (
+	ratesKey      = "fallbackfiat_rates"
+	currenciesKey = "fallbackfiat_currencies"
+)
+
+// Store persists the last known values across restarts.
+// breez_sdk_spark.Storage implementations satisfy it.
+type Store interface {
+	GetCachedItem(key string) (*string, error)
+	SetCachedItem(key string, value string) error
+}
+
+// Config configures a Service.
+type Config struct {
+	// MaxAge is how old the last known values may be and still be served.
+	// Zero serves them however old they are.
+	MaxAge time.Duration
+	// Store, when set, keeps the last known values so that they are
+	// available from the start after a restart.
+	Store Store
+	// Logger receives a warning whenever last known values are served.
+	// Defaults to no logging.
+	Logger *slog.Logger
+}
+
+// Staleness describes the rates FetchFiatRates serves.
+type Staleness struct {
+	// FetchedAt is when the rates were fetched from upstream, zero if they
+	// never were.
+	FetchedAt time.Time
+	// Stale is set when the latest fetch failed and older rates were
+	// served instead.
+	Stale bool
+	// Err is the error of the latest fetch, nil if it succeeded.
+	Err error
+}
+
+// Age returns how old the rates are at now.
+func (s Staleness) Age(now time.Time) time.Duration {
+	if s.FetchedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(s.FetchedAt)
+}
+
+// Service is a breez_sdk_spark.FiatServiceCtx falling back to the last
+// values fetched from the wrapped service. Plain breez_sdk_spark.FiatService
+// values can be wrapped through breez_sdk_spark.AsFiatServiceCtx.
+type Service struct {
+	inner  breez_sdk_spark.FiatServiceCtx
+	maxAge time.Duration
+	store  Store
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu         sync.Mutex
+	rates      snapshot[breez_sdk_spark.Rate]
+	currencies snapshot[breez_sdk_spark.FiatCurrency]
+	ratesErr   error
+	ratesStale bool
+}
+
+type snapshot[T any] struct {
+	Values    []T       `json:"values"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+var _ breez_sdk_spark.FiatServiceCtx = (*Service)(nil)
+
+// New returns a Service wrapping inner. Values persisted in cfg.Store by an
+// earlier Service are loaded straight away.
+func New(inner breez_sdk_spark.FiatServiceCtx, cfg Config) *Service {
+	s := &Service{inner: inner, maxAge: cfg.MaxAge, store: cfg.Store, logger: cfg.Logger, now: time.Now}
+	if s.logger == nil {
+		s.logger = slog.New(slog.DiscardHandler)
+	}
+	if s.store != nil {
+		load(s, ratesKey, &s.rates)
+		load(s, currenciesKey, &s.currencies)
+	}
+	return s
+}
+
+// FetchFiatCurrencies returns the upstream currencies, or the last known
+// ones if upstream fails.
+func (s *Service) FetchFiatCurrencies(ctx context.Context) ([]breez_sdk_spark.FiatCurrency, error) {
+	currencies, err := s.inner.FetchFiatCurrencies(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fallback(ctx, s, "currencies", currenciesKey, &s.currencies, currencies, err)
+}
+
+// FetchFiatRates returns the upstream rates, or the last known ones if
+// upstream fails. Staleness tells which.
+func (s *Service) FetchFiatRates(ctx context.Context) ([]breez_sdk_spark.Rate, error) {
+	rates, err := s.inner.FetchFiatRates(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	served, servedErr := fallback(ctx, s, "rates", ratesKey, &s.rates, rates, err)
+	s.ratesErr, s.ratesStale = err, err != nil && servedErr == nil
+	return served, servedErr
+}
+
+// Staleness describes the rates returned by the latest FetchFiatRates.
+func (s *Service) Staleness() Staleness {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Staleness{FetchedAt: s.rates.FetchedAt, Stale: s.ratesStale, Err: s.ratesErr}
+}
+
+// fallback records values if err is nil, and otherwise returns the last
+// known values if there are recent enough ones. It must be called with mu
+// held.
+func fallback[T any](ctx context.Context, s *Service, what, key string, last *snapshot[T], values []T, err error) ([]T, error) {
+	now := s.now()
+	if err == nil {
+		*last = snapshot[T]{Values: slices.Clone(values), FetchedAt: now}
+		if s.store != nil {
+			save(ctx, s, key, last)
+		}
+		return values, nil
+	}
+	if last.FetchedAt.IsZero() || s.maxAge > 0 && now.Sub(last.FetchedAt) > s.maxAge {
+		return nil, err
+	}
+	s.logger.WarnContext(ctx, "fiat "+what+" unavailable, serving last known", "fetched_at", last.FetchedAt, "err", err)
+	return slices.Clone(last.Values), nil
+}
+
+func load[T any](s *Service, key string, into *snapshot[T]) {
+	value, err := s.store.GetCachedItem(key)
+	if err != nil || value == nil {
+		return
+	}
+	if err := json.Unmarshal([]byte(*value), into); err != nil {
+		s.logger.Warn("ignoring unreadable last known fiat values", "key", key, "err", err)
+		*into = snapshot[T]{}
+	}
+}
+
+func save[T any](ctx context.Context, s *Service, key string, snap *snapshot[T]) {
+	data, err := json.Marshal(snap)
+	if err == nil {
+		err = s.store.SetCachedItem(key, string(data))
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "could not persist last known fiat values", "key", key, "err", err)
+	}
+}
@@ -0,0 +1,143 @@
+package fallbackfiat
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/memstorage"
+)
+
+var errDown = breez_sdk_spark.NewServiceConnectivityErrorConnect("connection refused")
+
+// upstream serves rates until fail is set.
+type upstream struct {
+	rates []breez_sdk_spark.Rate
+	fail  error
+}
+
+func (u *upstream) FetchFiatCurrencies(context.Context) ([]breez_sdk_spark.FiatCurrency, error) {
+	if u.fail != nil {
+		return nil, u.fail
+	}
+	currencies := make([]breez_sdk_spark.FiatCurrency, len(u.rates))
+	for i, r := range u.rates {
+		currencies[i] = breez_sdk_spark.FiatCurrency{Id: r.Coin, Info: breez_sdk_spark.CurrencyInfo{Name: r.Coin, FractionSize: 2}}
+	}
+	return currencies, nil
+}
+
+func (u *upstream) FetchFiatRates(context.Context) ([]breez_sdk_spark.Rate, error) {
+	if u.fail != nil {
+		return nil, u.fail
+	}
+	return u.rates, nil
+}
+
+func newService(u *upstream, cfg Config) (*Service, *time.Time) {
+	s := New(u, cfg)
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestFallback(t *testing.T) {
+	u := &upstream{rates: []breez_sdk_spark.Rate{{Coin: "USD", Value: 97_000}}}
+	s, now := newService(u, Config{})
+	ctx := context.Background()
+
+	if _, err := s.FetchFiatRates(ctx); err != nil {
+		t.Fatal(err)
+	}
+	fetchedAt := *now
+	if st := s.Staleness(); st.Stale || st.Err != nil || !st.FetchedAt.Equal(fetchedAt) {
+		t.Fatalf("fresh staleness = %+v", st)
+	}
+
+	u.fail = errDown
+	*now = now.Add(time.Hour)
+	rates, err := s.FetchFiatRates(ctx)
+	if err != nil || len(rates) != 1 || rates[0].Value != 97_000 {
+		t.Fatalf("fallback rates = %+v, %v", rates, err)
+	}
+	st := s.Staleness()
+	if !st.Stale || !errors.Is(st.Err, breez_sdk_spark.ErrServiceConnectivityErrorConnect) || !st.FetchedAt.Equal(fetchedAt) || st.Age(*now) != time.Hour {
+		t.Fatalf("stale staleness = %+v", st)
+	}
+
+	u.fail, u.rates = nil, []breez_sdk_spark.Rate{{Coin: "USD", Value: 98_000}}
+	if rates, _ := s.FetchFiatRates(ctx); rates[0].Value != 98_000 {
+		t.Fatalf("recovered rates = %+v", rates)
+	}
+	if st := s.Staleness(); st.Stale || st.Err != nil || !st.FetchedAt.Equal(*now) {
+		t.Fatalf("recovered staleness = %+v", st)
+	}
+}
+
+func TestNothingToFallBackOn(t *testing.T) {
+	s, _ := newService(&upstream{fail: errDown}, Config{})
+	if _, err := s.FetchFiatRates(context.Background()); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorConnect) {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := s.FetchFiatCurrencies(context.Background()); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorConnect) {
+		t.Fatalf("got %v", err)
+	}
+	if st := s.Staleness(); st.Stale || st.Err == nil || !st.FetchedAt.IsZero() {
+		t.Fatalf("staleness = %+v", st)
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	u := &upstream{rates: []breez_sdk_spark.Rate{{Coin: "EUR", Value: 89_000}}}
+	s, now := newService(u, Config{MaxAge: time.Hour})
+	ctx := context.Background()
+	s.FetchFiatRates(ctx)
+	s.FetchFiatCurrencies(ctx)
+
+	u.fail = errDown
+	*now = now.Add(time.Hour)
+	if _, err := s.FetchFiatRates(ctx); err != nil {
+		t.Fatalf("rates at MaxAge: %v", err)
+	}
+	if currencies, err := s.FetchFiatCurrencies(ctx); err != nil || currencies[0].Id != "EUR" {
+		t.Fatalf("currencies at MaxAge = %+v, %v", currencies, err)
+	}
+	*now = now.Add(time.Second)
+	if _, err := s.FetchFiatRates(ctx); err == nil {
+		t.Fatal("rates older than MaxAge served")
+	}
+	if st := s.Staleness(); st.Stale {
+		t.Fatalf("staleness = %+v after serving nothing", st)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	store := memstorage.NewStorage()
+	u := &upstream{rates: []breez_sdk_spark.Rate{{Coin: "USD", Value: 97_000}}}
+	s, _ := newService(u, Config{Store: store})
+	s.FetchFiatRates(context.Background())
+	s.FetchFiatCurrencies(context.Background())
+
+	// After a restart with the provider down, the rates are there from the
+	// first call.
+	restarted, _ := newService(&upstream{fail: errDown}, Config{Store: store})
+	rates, err := restarted.FetchFiatRates(context.Background())
+	if err != nil || len(rates) != 1 || rates[0].Value != 97_000 {
+		t.Fatalf("rates after restart = %+v, %v", rates, err)
+	}
+	currencies, err := restarted.FetchFiatCurrencies(context.Background())
+	if err != nil || len(currencies) != 1 || currencies[0].Info.FractionSize != 2 {
+		t.Fatalf("currencies after restart = %+v, %v", currencies, err)
+	}
+	if st := restarted.Staleness(); !st.Stale || !st.FetchedAt.Equal(time.Unix(1_700_000_000, 0)) {
+		t.Fatalf("staleness after restart = %+v", st)
+	}
+
+	store.SetCachedItem(ratesKey, "{not json")
+	broken, _ := newService(&upstream{fail: errDown}, Config{Store: store})
+	if _, err := broken.FetchFiatRates(context.Background()); err == nil {
+		t.Fatal("served unreadable persisted rates")
+	}
+}
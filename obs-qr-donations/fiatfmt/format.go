@@ -0,0 +1,293 @@
+// Package fiatfmt formats amounts for display the way the Breez apps do,
+// using the CurrencyInfo the SDK returns from ListFiatCurrencies:
+//
+//	currencies, _ := sdk.ListFiatCurrencies()
+//	rates, _ := sdk.ListFiatRates()
+//	eur := fiatfmt.Find(currencies.Currencies, "EUR")
+//	text := fiatfmt.FormatFiat(21_000, fiatfmt.Rate(rates.Rates, "EUR"), eur, "fr-FR")
+//	sats := fiatfmt.FormatSats(21_000, "fr-FR")
+//
+// Locales are BCP 47 tags such as "en", "de-CH" or "pt_BR"; unknown ones
+// format like "en".
+package fiatfmt
+
+import (
+	"math"
+	"math/big"
+	"strings"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// rlm is the right-to-left mark put around symbols written right to left,
+// so that they stay on the intended side of the amount.
+const rlm = "\u200f"
+
+// FormatFiat returns the value of amountSats at rate, a price per bitcoin,
+// in currency, formatted for locale.
+//
+// The value is rounded half away from zero to the currency's FractionSize,
+// computed exactly rather than in floating point. The symbol is the one of
+// the locale override that matches locale, or else the currency's; it is
+// placed after the amount when its Position is 1 and before it otherwise,
+// separated by Spacing spaces. A symbol without a Position but with a
+// Template, such as "1 €", is laid out by the template, "1" standing for
+// the amount. Without any symbol, the currency id follows the amount.
+func FormatFiat(amountSats uint64, rate float64, currency breez_sdk_spark.FiatCurrency, locale string) string {
+	info := currency.Info
+	value := fiatValue(amountSats, rate)
+	number := formatDecimal(value, int(info.FractionSize), separatorsFor(locale))
+
+	symbol, spacing := info.Symbol, info.Spacing
+	if o := override(info.LocaleOverrides, locale); o != nil {
+		sym := mergeSymbol(symbol, o.Symbol)
+		symbol = &sym
+		if o.Spacing != nil {
+			spacing = o.Spacing
+		}
+	}
+	if symbol == nil || symbol.Grapheme == nil || *symbol.Grapheme == "" {
+		return number + " " + currency.Id
+	}
+
+	grapheme := *symbol.Grapheme
+	if symbol.Rtl != nil && *symbol.Rtl {
+		grapheme = rlm + grapheme + rlm
+	}
+	if symbol.Position == nil && symbol.Template != nil && strings.Contains(*symbol.Template, "1") {
+		return strings.Replace(strings.ReplaceAll(*symbol.Template, *symbol.Grapheme, grapheme), "1", number, 1)
+	}
+	space := ""
+	if spacing != nil {
+		space = strings.Repeat(" ", int(*spacing))
+	}
+	if symbol.Position != nil && *symbol.Position == 1 {
+		return number + space + grapheme
+	}
+	return grapheme + space + number
+}
+
+// FormatSats formats amountSats with the digit grouping of locale, as in
+// "21,000 sats" or "1 sat".
+func FormatSats(amountSats uint64, locale string) string {
+	number := group(new(big.Int).SetUint64(amountSats).String(), separatorsFor(locale))
+	if amountSats == 1 {
+		return number + " sat"
+	}
+	return number + " sats"
+}
+
+// Name returns the name of currency in locale, falling back to its
+// English name.
+func Name(currency breez_sdk_spark.FiatCurrency, locale string) string {
+	for _, tag := range candidates(locale) {
+		for _, n := range currency.Info.LocalizedName {
+			if normalize(n.Locale) == tag {
+				return n.Name
+			}
+		}
+	}
+	return currency.Info.Name
+}
+
+// Find returns the currency with id. If it is not among currencies, the
+// result has only its id and two decimals, which FormatFiat formats
+// without a symbol.
+func Find(currencies []breez_sdk_spark.FiatCurrency, id string) breez_sdk_spark.FiatCurrency {
+	for _, c := range currencies {
+		if c.Id == id {
+			return c
+		}
+	}
+	return breez_sdk_spark.FiatCurrency{Id: id, Info: breez_sdk_spark.CurrencyInfo{Name: id, FractionSize: 2}}
+}
+
+// Rate returns the rate of currency among rates, or zero.
+func Rate(rates []breez_sdk_spark.Rate, currency string) float64 {
+	for _, r := range rates {
+		if r.Coin == currency {
+			return r.Value
+		}
+	}
+	return 0
+}
+
+// fiatValue returns amountSats * rate / 1e8 exactly. Rates that are not
+// finite count as zero.
+func fiatValue(amountSats uint64, rate float64) *big.Rat {
+	if math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return new(big.Rat)
+	}
+	v := new(big.Rat).SetFloat64(rate)
+	v.Mul(v, new(big.Rat).SetInt(new(big.Int).SetUint64(amountSats)))
+	return v.Quo(v, big.NewRat(100_000_000, 1))
+}
+
+type separators struct {
+	group, decimal string
+	// indian groups by two digits past the first thousand.
+	indian bool
+}
+
+var (
+	nbsp       = "\u00a0"
+	narrowNbsp = "\u202f"
+	commaDot   = separators{group: ",", decimal: "."}
+	dotComma   = separators{group: ".", decimal: ","}
+	spaceComma = separators{group: nbsp, decimal: ","}
+)
+
+// localeSeparators holds the CLDR separators of the locales the overlay is
+// translated to, and of the major languages of the currencies the SDK
+// lists. Keys are normalized tags; languages apply to their regions unless
+// a region has its own entry.
+var localeSeparators = map[string]separators{
+	"en":    commaDot,
+	"en-in": {group: ",", decimal: ".", indian: true},
+	"hi":    {group: ",", decimal: ".", indian: true},
+	"ja":    commaDot,
+	"ko":    commaDot,
+	"zh":    commaDot,
+	"th":    commaDot,
+	"he":    commaDot,
+	"ar":    {group: "٬", decimal: "٫"},
+	"es":    dotComma,
+	"es-mx": commaDot,
+	"es-us": commaDot,
+	"de":    dotComma,
+	"de-at": {group: nbsp, decimal: ","},
+	"de-ch": {group: "’", decimal: "."},
+	"it":    dotComma,
+	"it-ch": {group: "’", decimal: "."},
+	"nl":    dotComma,
+	"pt":    spaceComma,
+	"pt-br": dotComma,
+	"id":    dotComma,
+	"da":    dotComma,
+	"tr":    dotComma,
+	"el":    dotComma,
+	"vi":    dotComma,
+	"ro":    dotComma,
+	"fr":    {group: narrowNbsp, decimal: ","},
+	"fr-ch": {group: narrowNbsp, decimal: "."},
+	"ru":    spaceComma,
+	"uk":    spaceComma,
+	"pl":    spaceComma,
+	"cs":    spaceComma,
+	"sk":    spaceComma,
+	"sv":    spaceComma,
+	"nb":    spaceComma,
+	"fi":    spaceComma,
+	"hu":    spaceComma,
+}
+
+func separatorsFor(locale string) separators {
+	for _, tag := range candidates(locale) {
+		if s, ok := localeSeparators[tag]; ok {
+			return s
+		}
+	}
+	return commaDot
+}
+
+// override returns the override of overrides that best matches locale.
+func override(overrides []breez_sdk_spark.LocaleOverrides, locale string) *breez_sdk_spark.LocaleOverrides {
+	for _, tag := range candidates(locale) {
+		for i := range overrides {
+			if normalize(overrides[i].Locale) == tag {
+				return &overrides[i]
+			}
+		}
+	}
+	return nil
+}
+
+// mergeSymbol returns o with the fields it leaves unset taken from base.
+func mergeSymbol(base *breez_sdk_spark.Symbol, o breez_sdk_spark.Symbol) breez_sdk_spark.Symbol {
+	if base == nil {
+		return o
+	}
+	if o.Grapheme == nil {
+		o.Grapheme = base.Grapheme
+	}
+	if o.Template == nil {
+		o.Template = base.Template
+	}
+	if o.Rtl == nil {
+		o.Rtl = base.Rtl
+	}
+	if o.Position == nil {
+		o.Position = base.Position
+	}
+	return o
+}
+
+// candidates returns the normalized tags to try for locale, most specific
+// first: "zh_Hant_TW" gives "zh-hant-tw", "zh-hant" and "zh".
+func candidates(locale string) []string {
+	tag := normalize(locale)
+	var tags []string
+	for tag != "" {
+		tags = append(tags, tag)
+		i := strings.LastIndexByte(tag, '-')
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return tags
+}
+
+func normalize(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// formatDecimal rounds v half away from zero to digits decimals and
+// formats it with seps.
+func formatDecimal(v *big.Rat, digits int, seps separators) string {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	scaled := new(big.Rat).Mul(v, new(big.Rat).SetInt(scale))
+	n, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	// Round up when the remainder is at least half the denominator.
+	if new(big.Int).Mul(rem.Abs(rem), big.NewInt(2)).Cmp(scaled.Denom()) >= 0 {
+		if scaled.Sign() < 0 {
+			n.Sub(n, big.NewInt(1))
+		} else {
+			n.Add(n, big.NewInt(1))
+		}
+	}
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+		n.Neg(n)
+	}
+	s := n.String()
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-digits], s[len(s)-digits:]
+	out := sign + group(whole, seps)
+	if digits > 0 {
+		out += seps.decimal + frac
+	}
+	return out
+}
+
+// group inserts the group separator of seps into the digits of whole.
+func group(whole string, seps separators) string {
+	if len(whole) <= 3 {
+		return whole
+	}
+	head, tail := whole[:len(whole)-3], whole[len(whole)-3:]
+	size := 3
+	if seps.indian {
+		size = 2
+	}
+	var parts []string
+	for len(head) > size {
+		parts = append([]string{head[len(head)-size:]}, parts...)
+		head = head[:len(head)-size]
+	}
+	parts = append([]string{head}, parts...)
+	return strings.Join(append(parts, tail), seps.group)
+}
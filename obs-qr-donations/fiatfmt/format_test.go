@@ -0,0 +1,137 @@
+package fiatfmt
+
+import (
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var (
+	usd = breez_sdk_spark.FiatCurrency{Id: "USD", Info: breez_sdk_spark.CurrencyInfo{
+		Name: "United States Dollar", FractionSize: 2,
+		Symbol:        &breez_sdk_spark.Symbol{Grapheme: ptr("$"), Template: ptr("$1"), Rtl: ptr(false), Position: ptr(uint32(0))},
+		LocalizedName: []breez_sdk_spark.LocalizedName{{Locale: "fr", Name: "dollar des États-Unis"}, {Locale: "pt-BR", Name: "Dólar americano"}},
+		LocaleOverrides: []breez_sdk_spark.LocaleOverrides{
+			{Locale: "fr", Spacing: ptr(uint32(1)), Symbol: breez_sdk_spark.Symbol{Grapheme: ptr("$US"), Position: ptr(uint32(1))}},
+		},
+	}}
+	eur = breez_sdk_spark.FiatCurrency{Id: "EUR", Info: breez_sdk_spark.CurrencyInfo{
+		Name: "Euro", FractionSize: 2, Spacing: ptr(uint32(1)),
+		Symbol: &breez_sdk_spark.Symbol{Grapheme: ptr("€"), Template: ptr("1 €"), Position: ptr(uint32(1))},
+		LocaleOverrides: []breez_sdk_spark.LocaleOverrides{
+			{Locale: "en-IE", Spacing: ptr(uint32(0)), Symbol: breez_sdk_spark.Symbol{Position: ptr(uint32(0))}},
+		},
+	}}
+	jpy = breez_sdk_spark.FiatCurrency{Id: "JPY", Info: breez_sdk_spark.CurrencyInfo{
+		Name: "Japanese Yen", FractionSize: 0, Symbol: &breez_sdk_spark.Symbol{Grapheme: ptr("¥")},
+	}}
+	ils = breez_sdk_spark.FiatCurrency{Id: "ILS", Info: breez_sdk_spark.CurrencyInfo{
+		Name: "Israeli New Shekel", FractionSize: 2, Spacing: ptr(uint32(1)),
+		Symbol: &breez_sdk_spark.Symbol{Grapheme: ptr("₪"), Rtl: ptr(true), Position: ptr(uint32(1))},
+	}}
+	chf = breez_sdk_spark.FiatCurrency{Id: "CHF", Info: breez_sdk_spark.CurrencyInfo{
+		Name: "Swiss Franc", FractionSize: 2, Symbol: &breez_sdk_spark.Symbol{Grapheme: ptr("CHF"), Template: ptr("CHF 1")},
+	}}
+	bhd = breez_sdk_spark.FiatCurrency{Id: "BHD", Info: breez_sdk_spark.CurrencyInfo{Name: "Bahraini Dinar", FractionSize: 3}}
+)
+
+func TestFormatFiat(t *testing.T) {
+	for _, tc := range []struct {
+		sats     uint64
+		rate     float64
+		currency breez_sdk_spark.FiatCurrency
+		locale   string
+		want     string
+	}{
+		{100_000_000, 97_123.45, usd, "en", "$97,123.45"},
+		{21_000, 100_000, usd, "en-US", "$21.00"},
+		{1_234_567_890, 100_000, usd, "en", "$1,234,567.89"},
+		// Locale overrides change the symbol, its side and the spacing.
+		{21_000, 100_000, usd, "fr-CA", "21,00 $US"},
+		{21_000, 90_000, eur, "de-DE", "18,90 €"},
+		{21_000, 90_000, eur, "en_IE", "€18.90"},
+		{21_000, 90_000, eur, "fr", "18,90 €"},
+		{250_000_000, 90_000, eur, "fr", "225\u202f000,00 €"},
+		{250_000_000, 90_000, eur, "de-CH", "225’000.00 €"},
+		{100_000_000, 14_512_345.5, jpy, "ja", "¥14,512,346"},
+		{100_000, 370_000, ils, "he", "370.00 \u200f₪\u200f"},
+		// The template lays the symbol out when there is no position.
+		{100_000, 85_000, chf, "en", "CHF 85.00"},
+		// No symbol at all.
+		{100_000_000, 37_000, bhd, "en", "37,000.000 BHD"},
+		{100_000_000_000, 100_000, usd, "hi", "$10,00,00,000.00"},
+		{0, 100_000, usd, "en", "$0.00"},
+	} {
+		if got := FormatFiat(tc.sats, tc.rate, tc.currency, tc.locale); got != tc.want {
+			t.Errorf("FormatFiat(%d, %v, %s, %q) = %q, want %q", tc.sats, tc.rate, tc.currency.Id, tc.locale, got, tc.want)
+		}
+	}
+}
+
+func TestRounding(t *testing.T) {
+	// 0.125 and 0.135 are not exact in binary: strconv would round the
+	// first down to even and the second by its binary expansion.
+	for _, tc := range []struct {
+		sats uint64
+		rate float64
+		want string
+	}{
+		{125, 100_000, "$0.13"},
+		{135, 100_000, "$0.14"},
+		{124, 100_000, "$0.12"},
+		{1, 500_000, "$0.01"},
+		{1, 499_999, "$0.00"},
+	} {
+		if got := FormatFiat(tc.sats, tc.rate, usd, "en"); got != tc.want {
+			t.Errorf("FormatFiat(%d, %v) = %q, want %q", tc.sats, tc.rate, got, tc.want)
+		}
+	}
+}
+
+func TestFormatSats(t *testing.T) {
+	for _, tc := range []struct {
+		sats   uint64
+		locale string
+		want   string
+	}{
+		{0, "en", "0 sats"},
+		{1, "en", "1 sat"},
+		{999, "en", "999 sats"},
+		{21_000, "en", "21,000 sats"},
+		{21_000, "fr-FR", "21\u202f000 sats"},
+		{2_100_000, "de", "2.100.000 sats"},
+		{2_100_000, "sv-SE", "2\u00a0100\u00a0000 sats"},
+		{2_100_000, "en-IN", "21,00,000 sats"},
+		{2_100_000, "xx", "2,100,000 sats"},
+		{2_100_000, "", "2,100,000 sats"},
+	} {
+		if got := FormatSats(tc.sats, tc.locale); got != tc.want {
+			t.Errorf("FormatSats(%d, %q) = %q, want %q", tc.sats, tc.locale, got, tc.want)
+		}
+	}
+}
+
+func TestNameFindRate(t *testing.T) {
+	if got := Name(usd, "fr-BE"); got != "dollar des États-Unis" {
+		t.Errorf("Name(fr-BE) = %q", got)
+	}
+	if got := Name(usd, "pt_BR"); got != "Dólar americano" {
+		t.Errorf("Name(pt_BR) = %q", got)
+	}
+	if got := Name(usd, "pt-PT"); got != "United States Dollar" {
+		t.Errorf("Name(pt-PT) = %q", got)
+	}
+	currencies := []breez_sdk_spark.FiatCurrency{usd, eur}
+	if Find(currencies, "EUR").Info.Name != "Euro" {
+		t.Error("EUR not found")
+	}
+	if got := FormatFiat(100_000, 10_000, Find(currencies, "GBP"), "en"); got != "10.00 GBP" {
+		t.Errorf("unknown currency formatted as %q", got)
+	}
+	rates := []breez_sdk_spark.Rate{{Coin: "USD", Value: 97_000}}
+	if Rate(rates, "USD") != 97_000 || Rate(rates, "EUR") != 0 {
+		t.Error("Rate")
+	}
+}
@@ -0,0 +1,242 @@
+// Package fiathistory remembers what payments were worth in fiat when they
+// settled. ListFiatRates only has today's rates, while end-of-stream
+// reports and tax exports need the rates at the time of receipt.
+//
+// A Recorder listens to SDK events and, whenever a payment succeeds, stores
+// the current rates alongside it:
+//
+//	history := fiathistory.New(sdk, fiathistory.Config{Store: storage})
+//	defer history.Close()
+//	sdk.AddEventListener(history)
+//	...
+//	usd, err := history.FiatValueAt(paymentID, "USD")
+package fiathistory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/sdkutil"
+)
+
+// keyPrefix prefixes the payment id in the keys of the cached items written
+// to Config.Store.
+const keyPrefix = "fiathistory_"
+
+// DefaultTimeout bounds fetching the rates for one payment.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultQueueSize is how many settled payments may wait for their rates
+// before further ones are dropped.
+const DefaultQueueSize = 256
+
+var (
+	// ErrNotRecorded is returned for payments the Recorder has no rates
+	// for: ones that settled before it was listening, token payments, or
+	// ones whose rates could not be fetched.
+	ErrNotRecorded = errors.New("fiathistory: payment not recorded")
+	// ErrNoRate is returned when the rates recorded for a payment do not
+	// include the requested currency.
+	ErrNoRate = errors.New("fiathistory: no rate for currency")
+)
+
+// RateSource provides the current rates. *breez_sdk_spark.BreezSdk
+// satisfies it.
+type RateSource interface {
+	ListFiatRatesCtx(ctx context.Context) (breez_sdk_spark.ListFiatRatesResponse, error)
+}
+
+// Store keeps the records. breez_sdk_spark.Storage implementations satisfy
+// it, so they can live in the database the SDK already uses.
+type Store interface {
+	GetCachedItem(key string) (*string, error)
+	SetCachedItem(key string, value string) error
+}
+
+// Config configures a Recorder.
+type Config struct {
+	// Store is required.
+	Store Store
+	// Timeout defaults to DefaultTimeout.
+	Timeout time.Duration
+	// QueueSize defaults to DefaultQueueSize.
+	QueueSize int
+	// Logger receives a warning for every payment that could not be
+	// recorded. Defaults to no logging.
+	Logger *slog.Logger
+}
+
+// Record is what a Recorder stores for a payment.
+type Record struct {
+	PaymentID   string                      `json:"payment_id"`
+	PaymentType breez_sdk_spark.PaymentType `json:"payment_type"`
+	AmountSats  uint64                      `json:"amount_sats"`
+	FeesSats    uint64                      `json:"fees_sats"`
+	// SettledAt is when the Recorder learnt that the payment succeeded.
+	SettledAt time.Time `json:"settled_at"`
+	// Rates maps currency ids to their price of one bitcoin.
+	Rates map[string]float64 `json:"rates"`
+}
+
+// FiatValue returns the amount of the payment in currency.
+func (r Record) FiatValue(currency string) (float64, error) {
+	rate, ok := r.Rates[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w %s at payment %s", ErrNoRate, currency, r.PaymentID)
+	}
+	return float64(r.AmountSats) * rate / 1e8, nil
+}
+
+// Recorder is a breez_sdk_spark.EventListener recording the rates at which
+// payments settle. Rates are fetched in the background so that event
+// delivery is never held up.
+type Recorder struct {
+	rates   RateSource
+	store   Store
+	timeout time.Duration
+	logger  *slog.Logger
+	now     func() time.Time
+
+	// mu orders Close after every send to queue.
+	mu     sync.RWMutex
+	closed bool
+	queue  chan breez_sdk_spark.Payment
+	done   chan struct{}
+}
+
+var _ breez_sdk_spark.EventListener = (*Recorder)(nil)
+
+// New returns a Recorder fetching rates from rates, which is usually the
+// SDK itself.
+func New(rates RateSource, cfg Config) *Recorder {
+	r := &Recorder{
+		rates:   rates,
+		store:   cfg.Store,
+		timeout: cfg.Timeout,
+		logger:  cfg.Logger,
+		now:     time.Now,
+		done:    make(chan struct{}),
+	}
+	if r.timeout == 0 {
+		r.timeout = DefaultTimeout
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if r.logger == nil {
+		r.logger = slog.New(slog.DiscardHandler)
+	}
+	r.queue = make(chan breez_sdk_spark.Payment, cfg.QueueSize)
+	go r.run()
+	return r
+}
+
+// OnEvent queues succeeded payments for recording.
+func (r *Recorder) OnEvent(event breez_sdk_spark.SdkEvent) {
+	e, ok := event.(breez_sdk_spark.SdkEventPaymentSucceeded)
+	if !ok {
+		return
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.queue <- e.Payment:
+	default:
+		r.logger.Warn("fiat history queue full, payment not recorded", "payment_id", e.Payment.Id)
+	}
+}
+
+// Close stops listening and returns once the queued payments are recorded.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+	<-r.done
+	return nil
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	for p := range r.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		if _, err := r.Record(ctx, p); err != nil {
+			r.logger.WarnContext(ctx, "payment fiat value not recorded", "payment_id", p.Id, "err", err)
+		}
+		cancel()
+	}
+}
+
+// Record stores the current rates for p, unless rates were already stored
+// for it, and returns the record. OnEvent calls it for every succeeded
+// payment; it is exported for payments that settled while nothing was
+// listening, at the cost of recording today's rates for them.
+func (r *Recorder) Record(ctx context.Context, p breez_sdk_spark.Payment) (Record, error) {
+	if rec, err := r.Lookup(p.Id); !errors.Is(err, ErrNotRecorded) {
+		return rec, err
+	}
+	if p.Method == breez_sdk_spark.PaymentMethodToken {
+		return Record{}, fmt.Errorf("%w: %s is a token payment", ErrNotRecorded, p.Id)
+	}
+	settledAt := r.now()
+	resp, err := r.rates.ListFiatRatesCtx(ctx)
+	if err != nil {
+		return Record{}, fmt.Errorf("fiathistory: fetching rates: %w", err)
+	}
+	rec := Record{
+		PaymentID:   p.Id,
+		PaymentType: p.PaymentType,
+		AmountSats:  sdkutil.Sats(p.Amount),
+		FeesSats:    sdkutil.Sats(p.Fees),
+		SettledAt:   settledAt,
+		Rates:       make(map[string]float64, len(resp.Rates)),
+	}
+	for _, rate := range resp.Rates {
+		rec.Rates[rate.Coin] = rate.Value
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return Record{}, err
+	}
+	if err := r.store.SetCachedItem(keyPrefix+p.Id, string(data)); err != nil {
+		return Record{}, fmt.Errorf("fiathistory: storing %s: %w", p.Id, err)
+	}
+	return rec, nil
+}
+
+// Lookup returns the record of a payment.
+func (r *Recorder) Lookup(paymentID string) (Record, error) {
+	value, err := r.store.GetCachedItem(keyPrefix + paymentID)
+	if err != nil {
+		return Record{}, fmt.Errorf("fiathistory: loading %s: %w", paymentID, err)
+	}
+	if value == nil {
+		return Record{}, fmt.Errorf("%w: %s", ErrNotRecorded, paymentID)
+	}
+	var rec Record
+	if err := json.Unmarshal([]byte(*value), &rec); err != nil {
+		return Record{}, fmt.Errorf("fiathistory: loading %s: %w", paymentID, err)
+	}
+	return rec, nil
+}
+
+// FiatValueAt returns the amount of a payment in currency, at the rate of
+// when it settled.
+func (r *Recorder) FiatValueAt(paymentID, currency string) (float64, error) {
+	rec, err := r.Lookup(paymentID)
+	if err != nil {
+		return 0, err
+	}
+	return rec.FiatValue(currency)
+}
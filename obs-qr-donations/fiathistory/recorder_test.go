@@ -0,0 +1,137 @@
+package fiathistory
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/memstorage"
+)
+
+// market serves usd as the USD rate, and a fixed EUR rate.
+type market struct {
+	mu  sync.Mutex
+	usd float64
+	err error
+}
+
+func (m *market) set(usd float64, err error) {
+	m.mu.Lock()
+	m.usd, m.err = usd, err
+	m.mu.Unlock()
+}
+
+func (m *market) ListFiatRatesCtx(context.Context) (breez_sdk_spark.ListFiatRatesResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return breez_sdk_spark.ListFiatRatesResponse{}, m.err
+	}
+	return breez_sdk_spark.ListFiatRatesResponse{Rates: []breez_sdk_spark.Rate{
+		{Coin: "USD", Value: m.usd},
+		{Coin: "EUR", Value: 90_000},
+	}}, nil
+}
+
+func payment(id string, sats int64, method breez_sdk_spark.PaymentMethod) breez_sdk_spark.Payment {
+	return breez_sdk_spark.Payment{
+		Id:          id,
+		PaymentType: breez_sdk_spark.PaymentTypeReceive,
+		Status:      breez_sdk_spark.PaymentStatusCompleted,
+		Amount:      big.NewInt(sats),
+		Fees:        big.NewInt(0),
+		Method:      method,
+	}
+}
+
+func succeeded(p breez_sdk_spark.Payment) breez_sdk_spark.SdkEvent {
+	return breez_sdk_spark.SdkEventPaymentSucceeded{Payment: p}
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestRecordsAtSettlement(t *testing.T) {
+	m := &market{usd: 100_000}
+	store := memstorage.NewStorage()
+	r := New(m, Config{Store: store})
+
+	r.OnEvent(breez_sdk_spark.SdkEventSynced{})
+	r.OnEvent(succeeded(payment("tip-1", 50_000, breez_sdk_spark.PaymentMethodLightning)))
+	r.OnEvent(breez_sdk_spark.SdkEventPaymentPending{Payment: payment("tip-2", 10_000, breez_sdk_spark.PaymentMethodSpark)})
+	r.OnEvent(succeeded(payment("token-1", 7, breez_sdk_spark.PaymentMethodToken)))
+	r.Close()
+	// Events after Close are ignored.
+	r.OnEvent(succeeded(payment("late", 1, breez_sdk_spark.PaymentMethodLightning)))
+
+	// Today's rates must not change the recorded value.
+	m.set(200_000, nil)
+	r = New(m, Config{Store: store})
+	defer r.Close()
+	if usd, err := r.FiatValueAt("tip-1", "USD"); err != nil || !near(usd, 50) {
+		t.Fatalf("tip-1 in USD = %v, %v", usd, err)
+	}
+	if eur, err := r.FiatValueAt("tip-1", "EUR"); err != nil || !near(eur, 45) {
+		t.Fatalf("tip-1 in EUR = %v, %v", eur, err)
+	}
+	if _, err := r.FiatValueAt("tip-1", "CHF"); !errors.Is(err, ErrNoRate) {
+		t.Fatalf("tip-1 in CHF: %v", err)
+	}
+	for _, id := range []string{"tip-2", "token-1", "late"} {
+		if _, err := r.FiatValueAt(id, "USD"); !errors.Is(err, ErrNotRecorded) {
+			t.Errorf("%s: %v", id, err)
+		}
+	}
+
+	rec, err := r.Lookup("tip-1")
+	if err != nil || rec.AmountSats != 50_000 || rec.PaymentType != breez_sdk_spark.PaymentTypeReceive || rec.SettledAt.IsZero() {
+		t.Fatalf("record = %+v, %v", rec, err)
+	}
+}
+
+func TestRecordKeepsFirstRates(t *testing.T) {
+	m := &market{usd: 100_000}
+	r := New(m, Config{Store: memstorage.NewStorage()})
+	defer r.Close()
+	settled := time.Unix(1_700_000_000, 0)
+	r.now = func() time.Time { return settled }
+	ctx := context.Background()
+
+	p := payment("tip", 10_000, breez_sdk_spark.PaymentMethodSpark)
+	if _, err := r.Record(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	// The SDK redelivers events after a sync; the rates at the first
+	// delivery stay.
+	m.set(300_000, nil)
+	r.now = func() time.Time { return settled.Add(time.Hour) }
+	rec, err := r.Record(ctx, p)
+	if err != nil || rec.Rates["USD"] != 100_000 || !rec.SettledAt.Equal(settled) {
+		t.Fatalf("record = %+v, %v", rec, err)
+	}
+}
+
+func TestRatesUnavailable(t *testing.T) {
+	m := &market{err: breez_sdk_spark.NewServiceConnectivityErrorConnect("connection refused")}
+	r := New(m, Config{Store: memstorage.NewStorage()})
+	defer r.Close()
+	p := payment("tip", 10_000, breez_sdk_spark.PaymentMethodLightning)
+	if _, err := r.Record(context.Background(), p); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorConnect) {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := r.FiatValueAt("tip", "USD"); !errors.Is(err, ErrNotRecorded) {
+		t.Fatalf("got %v", err)
+	}
+	// A later attempt records it.
+	m.set(100_000, nil)
+	if _, err := r.Record(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	if usd, err := r.FiatValueAt("tip", "USD"); err != nil || !near(usd, 10) {
+		t.Fatalf("tip in USD = %v, %v", usd, err)
+	}
+}
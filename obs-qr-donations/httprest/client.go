@@ -0,0 +1,420 @@
+// Package httprest implements the SDK RestClient over Go's net/http, so
+// that LNURL traffic goes through the proxies and certificate authorities
+// the deployment is configured for instead of whatever the Rust side
+// picks:
+//
+//	c, err := httprest.New(httprest.Config{
+//		Proxy:  "socks5h://127.0.0.1:9050", // Tor
+//		CAFile: "/etc/ssl/corp-root.pem",
+//	})
+//	...
+//	builder.WithLnurlClient(breez_sdk_spark.NewRestClientFromCtx(c))
+//
+// GET and DELETE requests are retried with exponential backoff on network
+// errors and on 429, 502, 503 and 504 responses. POST requests, which may
+// have taken effect even when the answer was lost, are sent once.
+package httprest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// Defaults for the zero values of Config.
+const (
+	DefaultTimeout      = 30 * time.Second
+	DefaultDialTimeout  = 10 * time.Second
+	DefaultRetries      = 2
+	DefaultBackoff      = 500 * time.Millisecond
+	DefaultMaxBackoff   = 10 * time.Second
+	DefaultMaxRedirects = 10
+)
+
+// maxResponseSize bounds how much of a response body is read. LNURL
+// responses are small JSON documents.
+const maxResponseSize = 4 << 20
+
+// Config configures a Client.
+type Config struct {
+	// Timeout bounds each attempt, from dialing to reading the whole body.
+	// Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// DialTimeout bounds establishing a connection, TLS handshake
+	// included. Defaults to DefaultDialTimeout.
+	DialTimeout time.Duration
+	// Retries is how many times a failed GET or DELETE is retried.
+	// Defaults to DefaultRetries; a negative value disables retries.
+	Retries int
+	// Backoff is the delay before the first retry, doubled for every
+	// following one up to MaxBackoff. A Retry-After header is honoured up
+	// to MaxBackoff. Default to DefaultBackoff and DefaultMaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// MaxRedirects defaults to DefaultMaxRedirects; a negative value
+	// disables following redirects, returning the redirect response.
+	MaxRedirects int
+	// CheckRedirect, when set, is called with the URL of every redirect
+	// before it is followed. An error it returns fails the request as it
+	// is, without retries; restpolicy.Client.CheckRedirect uses it to hold
+	// redirects to the policy of the first request.
+	CheckRedirect func(ctx context.Context, url string) error
+	// Proxy is the URL of the proxy to send every request through:
+	// "http://proxy.corp:3128", "socks5://..." or, to resolve names through
+	// the proxy as Tor requires, "socks5h://127.0.0.1:9050". Empty uses the
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables; "direct"
+	// ignores them.
+	Proxy string
+	// RootCAs, when set, replaces the system certificate pool.
+	RootCAs *x509.CertPool
+	// CAFile names a PEM file of certificates trusted in addition to
+	// RootCAs, or to the system pool, e.g. the root of a TLS inspecting
+	// corporate proxy.
+	CAFile string
+	// DialControl, when set, is called before every connection is made,
+	// with the address about to be dialed. Returning an error aborts the
+	// connection; restpolicy.DialControl uses it to refuse private
+	// addresses whatever the DNS answers.
+	DialControl func(network, address string, c syscall.RawConn) error
+	// UserAgent is sent unless a request sets its own.
+	UserAgent string
+	// Logger receives one debug record per attempt. Defaults to no
+	// logging.
+	Logger *slog.Logger
+}
+
+// Client is a net/http backed breez_sdk_spark.RestClientCtx.
+type Client struct {
+	http       *http.Client
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	userAgent  string
+	logger     *slog.Logger
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+var _ breez_sdk_spark.RestClientCtx = (*Client)(nil)
+
+var errTooManyRedirects = errors.New("too many redirects")
+
+// redirectRefused carries an error of Config.CheckRedirect through
+// net/http, which wraps it.
+type redirectRefused struct{ err error }
+
+func (e redirectRefused) Error() string { return e.err.Error() }
+
+// New returns a Client for cfg.
+func New(cfg Config) (*Client, error) {
+	c := &Client{
+		retries:    cfg.Retries,
+		backoff:    cfg.Backoff,
+		maxBackoff: cfg.MaxBackoff,
+		userAgent:  cfg.UserAgent,
+		logger:     cfg.Logger,
+		sleep:      sleep,
+	}
+	if c.retries == 0 {
+		c.retries = DefaultRetries
+	}
+	if c.retries < 0 {
+		c.retries = 0
+	}
+	if c.backoff == 0 {
+		c.backoff = DefaultBackoff
+	}
+	if c.maxBackoff == 0 {
+		c.maxBackoff = DefaultMaxBackoff
+	}
+	if c.logger == nil {
+		c.logger = slog.New(slog.DiscardHandler)
+	}
+	timeout, dialTimeout, maxRedirects := cfg.Timeout, cfg.DialTimeout, cfg.MaxRedirects
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	if dialTimeout == 0 {
+		dialTimeout = DefaultDialTimeout
+	}
+	if maxRedirects == 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second, Control: cfg.DialControl}).DialContext
+	transport.TLSHandshakeTimeout = dialTimeout
+	switch cfg.Proxy {
+	case "":
+		transport.Proxy = http.ProxyFromEnvironment
+	case "direct":
+		transport.Proxy = nil
+	default:
+		u, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("httprest: invalid proxy: %w", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("httprest: invalid proxy %q: scheme must be http, https, socks5 or socks5h", cfg.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	if cfg.RootCAs != nil || cfg.CAFile != "" {
+		pool, err := certPool(cfg.RootCAs, cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	c.http = &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if maxRedirects < 0 {
+				return http.ErrUseLastResponse
+			}
+			if len(via) > maxRedirects {
+				return errTooManyRedirects
+			}
+			if cfg.CheckRedirect != nil {
+				if err := cfg.CheckRedirect(req.Context(), req.URL.String()); err != nil {
+					return redirectRefused{err}
+				}
+			}
+			return nil
+		},
+	}
+	return c, nil
+}
+
+// certPool returns roots, or the system pool, with the certificates of
+// caFile added.
+func certPool(roots *x509.CertPool, caFile string) (*x509.CertPool, error) {
+	pool := roots
+	if pool == nil {
+		var err error
+		if pool, err = x509.SystemCertPool(); err != nil {
+			pool = x509.NewCertPool()
+		}
+	} else {
+		pool = pool.Clone()
+	}
+	if caFile == "" {
+		return pool, nil
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("httprest: reading CA file: %w", err)
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("httprest: no certificates in %s", caFile)
+	}
+	return pool, nil
+}
+
+// GetRequest sends a GET request, retrying it on transient failures.
+func (c *Client) GetRequest(ctx context.Context, url string, headers *map[string]string) (breez_sdk_spark.RestResponse, error) {
+	return c.do(ctx, http.MethodGet, url, headers, nil)
+}
+
+// PostRequest sends a POST request once.
+func (c *Client) PostRequest(ctx context.Context, url string, headers *map[string]string, body *string) (breez_sdk_spark.RestResponse, error) {
+	return c.do(ctx, http.MethodPost, url, headers, body)
+}
+
+// DeleteRequest sends a DELETE request, retrying it on transient
+// failures.
+func (c *Client) DeleteRequest(ctx context.Context, url string, headers *map[string]string, body *string) (breez_sdk_spark.RestResponse, error) {
+	return c.do(ctx, http.MethodDelete, url, headers, body)
+}
+
+// do sends the request, retrying idempotent ones. Responses of any status
+// are returned as such, the last one if all attempts got a retryable
+// status; only failures to get a response are errors.
+func (c *Client) do(ctx context.Context, method, rawURL string, headers *map[string]string, body *string) (breez_sdk_spark.RestResponse, error) {
+	attempts := 1
+	if method != http.MethodPost {
+		attempts += c.retries
+	}
+	for n := 1; ; n++ {
+		r := c.once(ctx, method, rawURL, headers, body)
+		if n >= attempts || ctx.Err() != nil || !r.transient {
+			return r.resp, r.err
+		}
+		delay := c.delay(n, r.retryAfter)
+		c.logger.DebugContext(ctx, "retrying request", "method", method, "url", redact(rawURL), "attempt", n, "delay", delay)
+		if err := c.sleep(ctx, delay); err != nil {
+			return breez_sdk_spark.RestResponse{}, mapError(err)
+		}
+	}
+}
+
+// attempt is the outcome of one try at a request.
+type attempt struct {
+	resp breez_sdk_spark.RestResponse
+	err  error
+	// transient is set when trying again may succeed.
+	transient bool
+	// retryAfter is the delay asked for by a Retry-After header.
+	retryAfter time.Duration
+}
+
+// once makes one attempt.
+func (c *Client) once(ctx context.Context, method, rawURL string, headers *map[string]string, body *string) attempt {
+	var reader io.Reader
+	if body != nil {
+		reader = strings.NewReader(*body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return attempt{err: breez_sdk_spark.NewServiceConnectivityErrorBuild(err.Error())}
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return attempt{err: breez_sdk_spark.NewServiceConnectivityErrorBuild(fmt.Sprintf("unsupported URL scheme %q", req.URL.Scheme))}
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if headers != nil {
+		for k, v := range *headers {
+			req.Header.Set(k, v)
+		}
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		c.logger.DebugContext(ctx, "request failed", "method", method, "url", redact(rawURL), "err", err)
+		var refused redirectRefused
+		if errors.As(err, &refused) {
+			return attempt{err: refused.err}
+		}
+		// Certificates and redirect loops do not fix themselves.
+		transient := !errors.Is(err, errTooManyRedirects) && !isCertError(err)
+		return attempt{err: mapError(err), transient: transient}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	c.logger.DebugContext(ctx, "request", "method", method, "url", redact(rawURL),
+		"status", resp.StatusCode, "bytes", len(data), "duration", time.Since(start))
+	if err != nil {
+		return attempt{err: mapBodyError(err), transient: true}
+	}
+	if len(data) > maxResponseSize {
+		return attempt{err: breez_sdk_spark.NewServiceConnectivityErrorBody(fmt.Sprintf("response larger than %d bytes", maxResponseSize))}
+	}
+	r := attempt{resp: breez_sdk_spark.RestResponse{Status: uint16(resp.StatusCode), Body: string(data)}}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		r.transient = true
+		r.retryAfter = retryAfter(resp.Header.Get("Retry-After"))
+	}
+	return r
+}
+
+// delay returns how long to wait before retry number attempt: the backoff
+// with up to 50% of jitter, or what the server asked for.
+func (c *Client) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, c.maxBackoff)
+	}
+	d := c.backoff << (attempt - 1)
+	if d <= 0 || d > c.maxBackoff {
+		d = c.maxBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// mapError turns a failure to get a response into the
+// ServiceConnectivityError variant the SDK expects.
+func mapError(err error) error {
+	msg := err.Error()
+	var netErr net.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	switch {
+	case errors.Is(err, errTooManyRedirects):
+		return breez_sdk_spark.NewServiceConnectivityErrorRedirect(msg)
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return breez_sdk_spark.NewServiceConnectivityErrorTimeout(msg)
+	case errors.Is(err, context.Canceled):
+		return breez_sdk_spark.NewServiceConnectivityErrorOther(msg)
+	case isCertError(err), errors.As(err, &dnsErr), errors.As(err, &opErr):
+		return breez_sdk_spark.NewServiceConnectivityErrorConnect(msg)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return breez_sdk_spark.NewServiceConnectivityErrorIo(msg)
+	default:
+		return breez_sdk_spark.NewServiceConnectivityErrorOther(msg)
+	}
+}
+
+// isCertError tells whether err is a certificate verification failure.
+func isCertError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	return errors.As(err, &certErr) || errors.As(err, &unknownAuthority) || errors.As(err, &hostname)
+}
+
+func mapBodyError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return breez_sdk_spark.NewServiceConnectivityErrorTimeout(err.Error())
+	}
+	return breez_sdk_spark.NewServiceConnectivityErrorBody(err.Error())
+}
+
+// redact strips the query string and credentials from rawURL for logging:
+// LNURL queries carry k1 secrets and signatures.
+func redact(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid URL)"
+	}
+	u.User = nil
+	if u.RawQuery != "" {
+		u.RawQuery = "..."
+	}
+	return u.String()
+}
@@ -0,0 +1,389 @@
+package httprest
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// newClient returns a Client that records its backoff delays instead of
+// sleeping.
+func newClient(t *testing.T, cfg Config) (*Client, *[]time.Duration) {
+	t.Helper()
+	if cfg.Proxy == "" {
+		cfg.Proxy = "direct"
+	}
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var delays []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		delays = append(delays, d)
+		mu.Unlock()
+		return ctx.Err()
+	}
+	return c, &delays
+}
+
+func TestRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusTeapot)
+		io.WriteString(w, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("X-Api-Key")+" "+r.UserAgent()+" "+string(body))
+	}))
+	defer srv.Close()
+	c, _ := newClient(t, Config{UserAgent: "obs-qr-donations"})
+	ctx := context.Background()
+	headers := &map[string]string{"X-Api-Key": "secret"}
+	body := `{"pr":"lnbc1"}`
+
+	for _, tc := range []struct {
+		call func() (breez_sdk_spark.RestResponse, error)
+		want string
+	}{
+		{func() (breez_sdk_spark.RestResponse, error) {
+			return c.GetRequest(ctx, srv.URL+"/lnurlp/tips?k1=x", headers)
+		}, "GET /lnurlp/tips?k1=x secret obs-qr-donations "},
+		{func() (breez_sdk_spark.RestResponse, error) { return c.PostRequest(ctx, srv.URL+"/p", nil, &body) }, `POST /p  obs-qr-donations {"pr":"lnbc1"}`},
+		{func() (breez_sdk_spark.RestResponse, error) { return c.DeleteRequest(ctx, srv.URL+"/d", headers, nil) }, "DELETE /d secret obs-qr-donations "},
+	} {
+		resp, err := tc.call()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != http.StatusTeapot || resp.Body != tc.want {
+			t.Errorf("got %d %q, want %q", resp.Status, resp.Body, tc.want)
+		}
+	}
+
+	if _, err := c.GetRequest(ctx, "ftp://example.com/", nil); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorBuild) {
+		t.Errorf("ftp URL: %v", err)
+	}
+	if _, err := c.GetRequest(ctx, "http://[::1", nil); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorBuild) {
+		t.Errorf("bad URL: %v", err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n := calls.Add(1); {
+		case r.URL.Path == "/always-busy":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/missing":
+			http.NotFound(w, r)
+		case n == 1:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case n == 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			io.WriteString(w, "ok")
+		}
+	}))
+	defer srv.Close()
+	c, delays := newClient(t, Config{Retries: 3, Backoff: time.Second, MaxBackoff: 4 * time.Second})
+	ctx := context.Background()
+
+	resp, err := c.GetRequest(ctx, srv.URL, nil)
+	if err != nil || resp.Status != 200 || resp.Body != "ok" || calls.Load() != 3 {
+		t.Fatalf("got %+v, %v after %d calls", resp, err, calls.Load())
+	}
+	// The first delay is the server's, the second the backoff of the
+	// second retry, between 1s and 2s.
+	if len(*delays) != 2 || (*delays)[0] != 3*time.Second || (*delays)[1] < time.Second || (*delays)[1] > 2*time.Second {
+		t.Fatalf("delays = %v", *delays)
+	}
+
+	// POST is sent once whatever the status.
+	calls.Store(0)
+	if resp, _ := c.PostRequest(ctx, srv.URL, nil, nil); resp.Status != http.StatusTooManyRequests || calls.Load() != 1 {
+		t.Fatalf("POST: %+v after %d calls", resp, calls.Load())
+	}
+
+	// Once retries are exhausted the last response is returned.
+	calls.Store(0)
+	*delays = nil
+	resp, err = c.DeleteRequest(ctx, srv.URL+"/always-busy", nil, nil)
+	if err != nil || resp.Status != http.StatusServiceUnavailable || calls.Load() != 4 {
+		t.Fatalf("got %+v, %v after %d calls", resp, err, calls.Load())
+	}
+	if d := (*delays)[2]; d < 2*time.Second || d > 4*time.Second {
+		t.Fatalf("delays = %v", *delays)
+	}
+
+	// 4xx other than 429 are final.
+	calls.Store(0)
+	if resp, _ := c.GetRequest(ctx, srv.URL+"/missing", nil); resp.Status != 404 || calls.Load() != 1 {
+		t.Fatalf("404: %+v after %d calls", resp, calls.Load())
+	}
+}
+
+func TestConnectErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	c, delays := newClient(t, Config{})
+	_, err = c.GetRequest(context.Background(), "http://"+addr+"/", nil)
+	if !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorConnect) {
+		t.Fatalf("got %v", err)
+	}
+	if len(*delays) != DefaultRetries {
+		t.Fatalf("retried %d times", len(*delays))
+	}
+
+	// A cancelled caller is not retried.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	*delays = nil
+	if _, err := c.GetRequest(ctx, "http://"+addr+"/", nil); err == nil || len(*delays) != 0 {
+		t.Fatalf("got %v after %d retries", err, len(*delays))
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	c, _ := newClient(t, Config{Timeout: 50 * time.Millisecond, Retries: -1})
+	if _, err := c.GetRequest(context.Background(), srv.URL, nil); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorTimeout) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+		if r.URL.Path == "/loop" || n > 0 {
+			http.Redirect(w, r, r.URL.Path+"?n="+strconv.Itoa(n-1), http.StatusFound)
+			return
+		}
+		io.WriteString(w, "landed")
+	}))
+	defer srv.Close()
+
+	c, delays := newClient(t, Config{MaxRedirects: 3})
+	if resp, err := c.GetRequest(context.Background(), srv.URL+"/hop?n=3", nil); err != nil || resp.Body != "landed" {
+		t.Fatalf("got %+v, %v", resp, err)
+	}
+	if _, err := c.GetRequest(context.Background(), srv.URL+"/loop", nil); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorRedirect) || len(*delays) != 0 {
+		t.Fatalf("got %v after %d retries", err, len(*delays))
+	}
+
+	c, _ = newClient(t, Config{MaxRedirects: -1})
+	if resp, err := c.GetRequest(context.Background(), srv.URL+"/hop?n=1", nil); err != nil || resp.Status != http.StatusFound {
+		t.Fatalf("got %+v, %v", resp, err)
+	}
+
+	refused := breez_sdk_spark.NewServiceConnectivityErrorOther("not there")
+	var seen []string
+	c, delays = newClient(t, Config{CheckRedirect: func(_ context.Context, url string) error {
+		seen = append(seen, url)
+		if len(seen) == 2 {
+			return refused
+		}
+		return nil
+	}})
+	if _, err := c.GetRequest(context.Background(), srv.URL+"/hop?n=3", nil); err != refused || len(*delays) != 0 {
+		t.Fatalf("got %v after %d retries", err, len(*delays))
+	}
+	if len(seen) != 2 || seen[0] != srv.URL+"/hop?n=2" {
+		t.Fatalf("checked %v", seen)
+	}
+}
+
+func TestResponseTooLarge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, maxResponseSize+1))
+	}))
+	defer srv.Close()
+	c, _ := newClient(t, Config{})
+	if _, err := c.GetRequest(context.Background(), srv.URL, nil); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorBody) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "trusted")
+	}))
+	defer srv.Close()
+
+	c, delays := newClient(t, Config{})
+	if _, err := c.GetRequest(context.Background(), srv.URL, nil); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorConnect) || len(*delays) != 0 {
+		t.Fatalf("untrusted server: %v after %d retries", err, len(*delays))
+	}
+
+	pool := srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	c, _ = newClient(t, Config{RootCAs: pool})
+	if resp, err := c.GetRequest(context.Background(), srv.URL, nil); err != nil || resp.Body != "trusted" {
+		t.Fatalf("RootCAs: %+v, %v", resp, err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600)
+	c, _ = newClient(t, Config{CAFile: caFile})
+	if resp, err := c.GetRequest(context.Background(), srv.URL, nil); err != nil || resp.Body != "trusted" {
+		t.Fatalf("CAFile: %+v, %v", resp, err)
+	}
+
+	os.WriteFile(caFile, []byte("not a certificate"), 0o600)
+	if _, err := New(Config{CAFile: caFile}); err == nil {
+		t.Fatal("accepted a CA file without certificates")
+	}
+}
+
+func TestHTTPProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy gets the absolute URL.
+		io.WriteString(w, "via proxy to "+r.URL.Host)
+	}))
+	defer proxy.Close()
+	c, _ := newClient(t, Config{Proxy: proxy.URL})
+	resp, err := c.GetRequest(context.Background(), "http://lnurl.example/.well-known/lnurlp/tips", nil)
+	if err != nil || resp.Body != "via proxy to lnurl.example" {
+		t.Fatalf("got %+v, %v", resp, err)
+	}
+
+	if _, err := New(Config{Proxy: "ftp://proxy"}); err == nil {
+		t.Fatal("accepted an ftp proxy")
+	}
+}
+
+func TestSOCKSProxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello "+r.Host)
+	}))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	// Like Tor, the proxy resolves the names: the client must not.
+	socks := newSOCKS5(t, map[string]string{"tips.onion": "127.0.0.1"})
+	c, _ := newClient(t, Config{Proxy: "socks5h://" + socks.addr})
+	resp, err := c.GetRequest(context.Background(), "http://tips.onion:"+port+"/", nil)
+	if err != nil || resp.Body != "hello tips.onion:"+port {
+		t.Fatalf("got %+v, %v", resp, err)
+	}
+	if got := socks.targets(); len(got) != 1 || got[0] != "tips.onion:"+port {
+		t.Fatalf("proxy asked for %v", got)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://user:pw@lnurl.example/withdraw?k1=secret&sig=abc": "https://lnurl.example/withdraw?...",
+		"https://lnurl.example/.well-known/lnurlp/tips":            "https://lnurl.example/.well-known/lnurlp/tips",
+	} {
+		if got := redact(in); got != want {
+			t.Errorf("redact(%q) = %q", in, got)
+		}
+	}
+}
+
+// socks5 is a minimal SOCKS5 proxy: no authentication, CONNECT only,
+// resolving names from hosts.
+type socks5 struct {
+	addr  string
+	mu    sync.Mutex
+	asked []string
+}
+
+func (s *socks5) targets() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.asked...)
+}
+
+func newSOCKS5(t *testing.T, hosts map[string]string) *socks5 {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	s := &socks5{addr: l.Addr().String()}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, hosts)
+		}
+	}()
+	return s
+}
+
+func (s *socks5) serve(conn net.Conn, hosts map[string]string) {
+	defer conn.Close()
+	buf := make([]byte, 262)
+	// Greeting: version, method count, methods.
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return
+	}
+	conn.Write([]byte{5, 0})
+	// Request: version, CONNECT, reserved, address type.
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil || buf[1] != 1 {
+		return
+	}
+	var host string
+	switch buf[3] {
+	case 1:
+		io.ReadFull(conn, buf[:4])
+		host = net.IP(buf[:4]).String()
+	case 3:
+		io.ReadFull(conn, buf[:1])
+		n := buf[0]
+		io.ReadFull(conn, buf[:n])
+		host = string(buf[:n])
+	default:
+		return
+	}
+	io.ReadFull(conn, buf[:2])
+	port := strconv.Itoa(int(binary.BigEndian.Uint16(buf[:2])))
+	s.mu.Lock()
+	s.asked = append(s.asked, net.JoinHostPort(host, port))
+	s.mu.Unlock()
+	if ip, ok := hosts[host]; ok {
+		host = ip
+	}
+	target, err := net.Dial("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		conn.Write([]byte{5, 4, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	go io.Copy(target, conn)
+	io.Copy(conn, target)
+}
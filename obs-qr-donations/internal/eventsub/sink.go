@@ -0,0 +1,141 @@
+// Package eventsub holds the bounded channel behind BreezSdk.Subscribe:
+// callbacks push values into it under an overflow policy, and it closes
+// when the subscriber's context ends.
+package eventsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Errors reported by Err once a sink is closed, besides the cause of its
+// context.
+var (
+	// ErrOverflow closes sinks whose Close policy dropped a value.
+	ErrOverflow = errors.New("eventsub: buffer overflowed")
+	// ErrClosed is the error of sinks closed by Close.
+	ErrClosed = errors.New("eventsub: closed")
+)
+
+// Policy decides what Send does when the channel is full.
+type Policy int
+
+const (
+	// DropOldest discards the oldest buffered value to make room.
+	DropOldest Policy = iota
+	// Block waits for room, or for the sink to close.
+	Block
+	// Close closes the sink, dropping the value.
+	Close
+)
+
+// Sink is a buffered channel fed by Send. Sends after Close are dropped, so
+// producers need not know whether the consumer is still there.
+type Sink[T any] struct {
+	ch     chan T
+	policy Policy
+	done   chan struct{}
+	once   sync.Once
+
+	// mu serializes Send and the closing of ch. Send holds it while
+	// blocking, so err has a lock of its own for Err not to wait.
+	mu     sync.Mutex
+	closed bool
+	errMu  sync.Mutex
+	err    error
+}
+
+// New returns a Sink buffering size values, at least one, which closes
+// when ctx is done.
+func New[T any](ctx context.Context, size int, policy Policy) *Sink[T] {
+	s := &Sink[T]{ch: make(chan T, max(size, 1)), policy: policy, done: make(chan struct{})}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.close(context.Cause(ctx))
+		case <-s.done:
+		}
+	}()
+	return s
+}
+
+// C returns the channel values are received from. It is closed with the
+// sink, after which the values still buffered can be drained.
+func (s *Sink[T]) C() <-chan T {
+	return s.ch
+}
+
+// Done is closed when the sink closes.
+func (s *Sink[T]) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns why the sink closed: ErrOverflow, ErrClosed or the cause of
+// its context. It returns nil while the sink is open, and is set by the
+// time C is closed.
+func (s *Sink[T]) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+// Send delivers v under the policy and reports whether it was buffered.
+func (s *Sink[T]) Send(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	for {
+		select {
+		case s.ch <- v:
+			return true
+		default:
+		}
+		switch s.policy {
+		case DropOldest:
+			// The consumer may have made room meanwhile; either way the
+			// next attempt succeeds, as only Send fills ch.
+			select {
+			case <-s.ch:
+			default:
+			}
+		case Block:
+			select {
+			case s.ch <- v:
+				return true
+			case <-s.done:
+				return false
+			}
+		default:
+			s.closeLocked(ErrOverflow)
+			return false
+		}
+	}
+}
+
+// Close closes the sink with ErrClosed. It can be called more than once.
+func (s *Sink[T]) Close() {
+	s.close(ErrClosed)
+}
+
+func (s *Sink[T]) close(err error) {
+	// Wake a blocked Send first, as it holds mu.
+	s.once.Do(func() { close(s.done) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked(err)
+}
+
+// closeLocked closes the sink with err, unless it is closed already.
+func (s *Sink[T]) closeLocked(err error) {
+	s.once.Do(func() { close(s.done) })
+	if !s.closed {
+		s.closed = true
+		s.errMu.Lock()
+		s.err = err
+		s.errMu.Unlock()
+		close(s.ch)
+	}
+}
@@ -0,0 +1,103 @@
+package eventsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func drain(ch <-chan int) []int {
+	var got []int
+	for v := range ch {
+		got = append(got, v)
+	}
+	return got
+}
+
+func TestDropOldest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := New[int](ctx, 3, DropOldest)
+	for i := range 5 {
+		if !s.Send(i) {
+			t.Fatalf("send %d refused", i)
+		}
+	}
+	if s.Err() != nil {
+		t.Fatalf("open sink reports %v", s.Err())
+	}
+	cancel()
+	<-s.Done()
+	if got := drain(s.C()); len(got) != 3 || got[0] != 2 || got[2] != 4 {
+		t.Fatalf("got %v", got)
+	}
+	if !errors.Is(s.Err(), context.Canceled) {
+		t.Fatalf("err %v", s.Err())
+	}
+	if s.Send(5) {
+		t.Fatal("sent after close")
+	}
+}
+
+func TestBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := New[int](ctx, 1, Block)
+	s.Send(1)
+	sent := make(chan bool)
+	go func() { sent <- s.Send(2) }()
+	select {
+	case <-sent:
+		t.Fatal("did not block")
+	case <-time.After(20 * time.Millisecond):
+	}
+	// Err does not wait for the blocked sender.
+	errs := make(chan error)
+	go func() { errs <- s.Err() }()
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Fatalf("open sink reports %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Err waited for a blocked Send")
+	}
+	if v := <-s.C(); v != 1 {
+		t.Fatalf("got %d", v)
+	}
+	if !<-sent {
+		t.Fatal("blocked send dropped")
+	}
+
+	// Ending the subscription releases a blocked sender.
+	go func() { sent <- s.Send(3) }()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if <-sent {
+		t.Fatal("send reported delivered after close")
+	}
+	if got := drain(s.C()); len(got) != 1 || got[0] != 2 {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestCloseOnOverflow(t *testing.T) {
+	s := New[int](context.Background(), 2, Close)
+	s.Send(1)
+	s.Send(2)
+	if s.Send(3) {
+		t.Fatal("overflowing send accepted")
+	}
+	select {
+	case <-s.Done():
+	default:
+		t.Fatal("not closed")
+	}
+	if got := drain(s.C()); len(got) != 2 {
+		t.Fatalf("buffered events lost: %v", got)
+	}
+	s.Close()
+	if !errors.Is(s.Err(), ErrOverflow) {
+		t.Fatalf("err %v", s.Err())
+	}
+}
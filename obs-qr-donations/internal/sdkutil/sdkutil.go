@@ -0,0 +1,76 @@
+// Package sdkutil holds the helpers the policy, observer and history
+// packages share around SDK types: a plain description of provisional
+// payments, amount conversion and refusal errors the SDK understands.
+package sdkutil
+
+import (
+	"fmt"
+	"math/big"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// Payment describes a provisional payment in terms that can be shown to
+// a person or written to JSON.
+type Payment struct {
+	PaymentID string `json:"payment_id"`
+	// Kind is "bitcoin", "lightning", "spark" or "token".
+	Kind string `json:"kind"`
+	// Amount is in sats, or in base units of TokenID for token payments,
+	// in decimal: token amounts do not always fit in a JSON number.
+	Amount  string `json:"amount"`
+	TokenID string `json:"token_id,omitempty"`
+	// Destination is the withdrawal address, the invoice or the Spark
+	// payment request.
+	Destination string `json:"destination"`
+}
+
+// Describe returns the Payment describing p.
+func Describe(p breez_sdk_spark.ProvisionalPayment) Payment {
+	d := Payment{PaymentID: p.PaymentId, Amount: "0"}
+	if p.Amount != nil {
+		d.Amount = p.Amount.String()
+	}
+	switch details := p.Details.(type) {
+	case breez_sdk_spark.ProvisionalPaymentDetailsBitcoin:
+		d.Kind, d.Destination = "bitcoin", details.WithdrawalAddress
+	case breez_sdk_spark.ProvisionalPaymentDetailsLightning:
+		d.Kind, d.Destination = "lightning", details.Invoice
+	case breez_sdk_spark.ProvisionalPaymentDetailsSpark:
+		d.Kind, d.Destination = "spark", details.PayRequest
+	case breez_sdk_spark.ProvisionalPaymentDetailsToken:
+		d.Kind, d.Destination, d.TokenID = "token", details.PayRequest, details.TokenId
+	default:
+		d.Kind = fmt.Sprintf("%T", p.Details)
+	}
+	return d
+}
+
+// Sats converts an SDK amount, saturating amounts no bitcoin payment has.
+func Sats(v *big.Int) uint64 {
+	if v == nil || v.Sign() < 0 {
+		return 0
+	}
+	if !v.IsUint64() {
+		return ^uint64(0)
+	}
+	return v.Uint64()
+}
+
+// refusal matches both its reason and the SDK error variant it was made
+// with, so callers can test for the reason while the callback dispatcher
+// still hands Rust a typed error.
+type refusal struct {
+	reason error
+	sdk    error
+	detail string
+}
+
+func (e *refusal) Error() string   { return e.reason.Error() + ": " + e.detail }
+func (e *refusal) Unwrap() []error { return []error{e.reason, e.sdk} }
+
+// Refuse returns an error matching reason and the error variant makes of
+// its message, e.g. breez_sdk_spark.NewPaymentObserverErrorGeneric.
+func Refuse[E error](reason error, variant func(string) E, detail string) error {
+	return &refusal{reason: reason, sdk: variant(reason.Error() + ": " + detail), detail: detail}
+}
@@ -0,0 +1,51 @@
+package sdkutil
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+func TestDescribe(t *testing.T) {
+	got := Describe(breez_sdk_spark.ProvisionalPayment{
+		PaymentId: "p1",
+		Amount:    big.NewInt(1_000),
+		Details:   breez_sdk_spark.ProvisionalPaymentDetailsToken{TokenId: "btkn1", PayRequest: "spark1x"},
+	})
+	want := Payment{PaymentID: "p1", Kind: "token", Amount: "1000", TokenID: "btkn1", Destination: "spark1x"}
+	if got != want {
+		t.Fatalf("got %+v", got)
+	}
+	if got := Describe(breez_sdk_spark.ProvisionalPayment{PaymentId: "p2"}); got.Amount != "0" {
+		t.Fatalf("nil amount: %+v", got)
+	}
+}
+
+func TestSats(t *testing.T) {
+	huge := new(big.Int).Lsh(big.NewInt(1), 70)
+	for _, tc := range []struct {
+		in   *big.Int
+		want uint64
+	}{{nil, 0}, {big.NewInt(-5), 0}, {big.NewInt(21), 21}, {huge, ^uint64(0)}} {
+		if got := Sats(tc.in); got != tc.want {
+			t.Errorf("Sats(%v) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRefuse(t *testing.T) {
+	reason := errors.New("pkg: over the limit")
+	err := Refuse(reason, breez_sdk_spark.NewPaymentObserverErrorGeneric, "500 sats")
+	if !errors.Is(err, reason) || !errors.Is(err, breez_sdk_spark.ErrPaymentObserverErrorGeneric) {
+		t.Fatalf("%v does not match both errors", err)
+	}
+	var sdkErr *breez_sdk_spark.PaymentObserverError
+	if !errors.As(err, &sdkErr) {
+		t.Fatal("the SDK would not recognize the error")
+	}
+	if err.Error() != "pkg: over the limit: 500 sats" {
+		t.Fatalf("message %q", err)
+	}
+}
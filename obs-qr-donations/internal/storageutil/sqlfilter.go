@@ -0,0 +1,97 @@
+package storageutil
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// PaymentQuery is the SQL translation of a ListPaymentsRequest against a
+// payments table with the payment_type, status, timestamp, is_token,
+// token_identifier and htlc_status columns.
+type PaymentQuery struct {
+	// Where is either empty or starts with " WHERE ".
+	Where string
+	// OrderLimit holds the ORDER BY, LIMIT and OFFSET clauses.
+	OrderLimit string
+	Args       []any
+}
+
+// BuildPaymentQuery translates req. placeholder returns the bind parameter
+// for the n-th argument (1-based), e.g. "?" for SQLite or "$n" for Postgres.
+// Column names may be qualified with prefix, e.g. "p.".
+func BuildPaymentQuery(req breez_sdk_spark.ListPaymentsRequest, prefix string, placeholder func(n int) string) PaymentQuery {
+	var q PaymentQuery
+	var conds []string
+	bind := func(v any) string {
+		q.Args = append(q.Args, v)
+		return placeholder(len(q.Args))
+	}
+	in := func(column string, values []string) {
+		ph := make([]string, len(values))
+		for i, v := range values {
+			ph[i] = bind(v)
+		}
+		conds = append(conds, fmt.Sprintf("%s%s IN (%s)", prefix, column, strings.Join(ph, ", ")))
+	}
+
+	if req.TypeFilter != nil && len(*req.TypeFilter) > 0 {
+		values := make([]string, len(*req.TypeFilter))
+		for i, t := range *req.TypeFilter {
+			values[i] = PaymentTypeName(t)
+		}
+		in("payment_type", values)
+	}
+	if req.StatusFilter != nil && len(*req.StatusFilter) > 0 {
+		values := make([]string, len(*req.StatusFilter))
+		for i, s := range *req.StatusFilter {
+			values[i] = PaymentStatusName(s)
+		}
+		in("status", values)
+	}
+	if req.SparkHtlcStatusFilter != nil && len(*req.SparkHtlcStatusFilter) > 0 {
+		values := make([]string, len(*req.SparkHtlcStatusFilter))
+		for i, s := range *req.SparkHtlcStatusFilter {
+			values[i] = HtlcStatusName(s)
+		}
+		in("htlc_status", values)
+	}
+	if req.AssetFilter != nil {
+		switch f := (*req.AssetFilter).(type) {
+		case breez_sdk_spark.AssetFilterBitcoin:
+			conds = append(conds, prefix+"is_token = "+bind(false))
+		case breez_sdk_spark.AssetFilterToken:
+			conds = append(conds, prefix+"is_token = "+bind(true))
+			if f.TokenIdentifier != nil {
+				conds = append(conds, prefix+"token_identifier = "+bind(*f.TokenIdentifier))
+			}
+		}
+	}
+	if req.FromTimestamp != nil {
+		conds = append(conds, prefix+"timestamp >= "+bind(int64(*req.FromTimestamp)))
+	}
+	if req.ToTimestamp != nil {
+		conds = append(conds, prefix+"timestamp < "+bind(int64(*req.ToTimestamp)))
+	}
+	if len(conds) > 0 {
+		q.Where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	dir := "DESC"
+	if req.SortAscending != nil && *req.SortAscending {
+		dir = "ASC"
+	}
+	q.OrderLimit = fmt.Sprintf(" ORDER BY %stimestamp %s, %sid %s", prefix, dir, prefix, dir)
+	if req.Limit != nil {
+		q.OrderLimit += " LIMIT " + bind(int64(*req.Limit))
+	} else {
+		// SQLite only accepts OFFSET after a LIMIT.
+		q.OrderLimit += " LIMIT " + bind(int64(math.MaxInt64))
+	}
+	if req.Offset != nil {
+		q.OrderLimit += " OFFSET " + bind(int64(*req.Offset))
+	}
+	return q
+}
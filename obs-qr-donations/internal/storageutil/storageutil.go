@@ -0,0 +1,351 @@
+// Package storageutil holds the pieces shared by the Go Storage backends:
+// a stable binary encoding for SDK records, the columns the
+// ListPaymentsRequest filters run against and StorageError wrapping.
+package storageutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// StorageErr wraps err as a *StorageError so the callback dispatcher hands
+// it back to Rust as a typed error instead of an unexpected one.
+func StorageErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	var storageErr *breez_sdk_spark.StorageError
+	if errors.As(err, &storageErr) {
+		return err
+	}
+	return breez_sdk_spark.NewStorageErrorImplementation(err.Error())
+}
+
+// SyncStorageErr is the SyncStorage counterpart of StorageErr.
+func SyncStorageErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	var syncErr *breez_sdk_spark.SyncStorageError
+	if errors.As(err, &syncErr) {
+		return err
+	}
+	return breez_sdk_spark.NewSyncStorageErrorImplementation(err.Error())
+}
+
+// ErrPaymentNotFound is returned by GetPaymentById when no payment matches.
+func ErrPaymentNotFound(id string) error {
+	return breez_sdk_spark.NewStorageErrorImplementation(fmt.Sprintf("payment %s not found", id))
+}
+
+// readRecovering runs read and turns a panic raised by the generated
+// converters on malformed input into an error.
+func readRecovering(data []byte, read func(*bytes.Reader)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = breez_sdk_spark.NewStorageErrorSerialization(fmt.Sprint(r))
+		}
+	}()
+	read(bytes.NewReader(data))
+	return nil
+}
+
+// EncodePayment serializes p with the same wire format uniffi uses.
+func EncodePayment(p breez_sdk_spark.Payment) []byte {
+	var buf bytes.Buffer
+	breez_sdk_spark.FfiConverterPaymentINSTANCE.Write(&buf, p)
+	return buf.Bytes()
+}
+
+// DecodePayment reverses EncodePayment.
+func DecodePayment(data []byte) (breez_sdk_spark.Payment, error) {
+	var p breez_sdk_spark.Payment
+	err := readRecovering(data, func(r *bytes.Reader) {
+		p = breez_sdk_spark.FfiConverterPaymentINSTANCE.Read(r)
+	})
+	return p, err
+}
+
+// EncodeOptionalLnurlPayInfo serializes v, or returns nil when v is nil.
+func EncodeOptionalLnurlPayInfo(v *breez_sdk_spark.LnurlPayInfo) []byte {
+	if v == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	breez_sdk_spark.FfiConverterLnurlPayInfoINSTANCE.Write(&buf, *v)
+	return buf.Bytes()
+}
+
+// DecodeOptionalLnurlPayInfo reverses EncodeOptionalLnurlPayInfo.
+func DecodeOptionalLnurlPayInfo(data []byte) (*breez_sdk_spark.LnurlPayInfo, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var v breez_sdk_spark.LnurlPayInfo
+	err := readRecovering(data, func(r *bytes.Reader) {
+		v = breez_sdk_spark.FfiConverterLnurlPayInfoINSTANCE.Read(r)
+	})
+	return &v, err
+}
+
+// EncodeOptionalLnurlWithdrawInfo serializes v, or returns nil when v is nil.
+func EncodeOptionalLnurlWithdrawInfo(v *breez_sdk_spark.LnurlWithdrawInfo) []byte {
+	if v == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	breez_sdk_spark.FfiConverterLnurlWithdrawInfoINSTANCE.Write(&buf, *v)
+	return buf.Bytes()
+}
+
+// DecodeOptionalLnurlWithdrawInfo reverses EncodeOptionalLnurlWithdrawInfo.
+func DecodeOptionalLnurlWithdrawInfo(data []byte) (*breez_sdk_spark.LnurlWithdrawInfo, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var v breez_sdk_spark.LnurlWithdrawInfo
+	err := readRecovering(data, func(r *bytes.Reader) {
+		v = breez_sdk_spark.FfiConverterLnurlWithdrawInfoINSTANCE.Read(r)
+	})
+	return &v, err
+}
+
+// EncodeDepositClaimError serializes e.
+func EncodeDepositClaimError(e breez_sdk_spark.DepositClaimError) []byte {
+	var buf bytes.Buffer
+	breez_sdk_spark.FfiConverterDepositClaimErrorINSTANCE.Write(&buf, e)
+	return buf.Bytes()
+}
+
+// DecodeOptionalDepositClaimError decodes a stored claim error, returning
+// nil when none was stored.
+func DecodeOptionalDepositClaimError(data []byte) (*breez_sdk_spark.DepositClaimError, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var e breez_sdk_spark.DepositClaimError
+	err := readRecovering(data, func(r *bytes.Reader) {
+		e = breez_sdk_spark.FfiConverterDepositClaimErrorINSTANCE.Read(r)
+	})
+	return &e, err
+}
+
+// PaymentIndex holds the values of a payment that ListPaymentsRequest
+// filters and GetPaymentByInvoice look at. Backends store them next to the
+// encoded payment so they can be queried without decoding.
+type PaymentIndex struct {
+	// TokenIdentifier is set for token payments only.
+	TokenIdentifier *string
+	// IsToken is true for token payments, even when the identifier is unknown.
+	IsToken bool
+	// HtlcStatus is set for Spark payments that carry HTLC details.
+	HtlcStatus *breez_sdk_spark.SparkHtlcStatus
+	// Invoice is the Lightning or Spark invoice the payment settled, if any.
+	Invoice *string
+	// PaymentHash is set for Lightning payments and is what LNURL receive
+	// metadata is keyed on.
+	PaymentHash *string
+}
+
+// IndexPayment extracts the filterable values of p.
+func IndexPayment(p breez_sdk_spark.Payment) PaymentIndex {
+	var idx PaymentIndex
+	if p.Method == breez_sdk_spark.PaymentMethodToken {
+		idx.IsToken = true
+	}
+	if p.Details == nil {
+		return idx
+	}
+	switch d := (*p.Details).(type) {
+	case breez_sdk_spark.PaymentDetailsToken:
+		idx.IsToken = true
+		id := d.Metadata.Identifier
+		idx.TokenIdentifier = &id
+		if d.InvoiceDetails != nil {
+			invoice := d.InvoiceDetails.Invoice
+			idx.Invoice = &invoice
+		}
+	case breez_sdk_spark.PaymentDetailsSpark:
+		if d.HtlcDetails != nil {
+			status := d.HtlcDetails.Status
+			idx.HtlcStatus = &status
+		}
+		if d.InvoiceDetails != nil {
+			invoice := d.InvoiceDetails.Invoice
+			idx.Invoice = &invoice
+		}
+	case breez_sdk_spark.PaymentDetailsLightning:
+		invoice := d.Invoice
+		idx.Invoice = &invoice
+		hash := d.PaymentHash
+		idx.PaymentHash = &hash
+	}
+	return idx
+}
+
+// MatchesFilters reports whether p passes every filter in req. Pagination
+// and ordering are left to the caller. An empty filter slice does not
+// restrict the result, matching the Rust storage.
+func MatchesFilters(p breez_sdk_spark.Payment, req breez_sdk_spark.ListPaymentsRequest) bool {
+	if req.TypeFilter != nil && len(*req.TypeFilter) > 0 && !contains(*req.TypeFilter, p.PaymentType) {
+		return false
+	}
+	if req.StatusFilter != nil && len(*req.StatusFilter) > 0 && !contains(*req.StatusFilter, p.Status) {
+		return false
+	}
+	if req.FromTimestamp != nil && p.Timestamp < *req.FromTimestamp {
+		return false
+	}
+	if req.ToTimestamp != nil && p.Timestamp >= *req.ToTimestamp {
+		return false
+	}
+	idx := IndexPayment(p)
+	if req.SparkHtlcStatusFilter != nil && len(*req.SparkHtlcStatusFilter) > 0 {
+		if idx.HtlcStatus == nil || !contains(*req.SparkHtlcStatusFilter, *idx.HtlcStatus) {
+			return false
+		}
+	}
+	if req.AssetFilter != nil {
+		switch f := (*req.AssetFilter).(type) {
+		case breez_sdk_spark.AssetFilterBitcoin:
+			if idx.IsToken {
+				return false
+			}
+		case breez_sdk_spark.AssetFilterToken:
+			if !idx.IsToken {
+				return false
+			}
+			if f.TokenIdentifier != nil && (idx.TokenIdentifier == nil || *idx.TokenIdentifier != *f.TokenIdentifier) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SortAndPage orders payments by timestamp (newest first unless
+// req.SortAscending is set, ties broken by id) and applies Offset and Limit.
+func SortAndPage(payments []breez_sdk_spark.Payment, req breez_sdk_spark.ListPaymentsRequest) []breez_sdk_spark.Payment {
+	ascending := req.SortAscending != nil && *req.SortAscending
+	sort.SliceStable(payments, func(i, j int) bool {
+		a, b := payments[i], payments[j]
+		if a.Timestamp != b.Timestamp {
+			return (a.Timestamp < b.Timestamp) == ascending
+		}
+		return (a.Id < b.Id) == ascending
+	})
+	offset := 0
+	if req.Offset != nil {
+		offset = int(*req.Offset)
+	}
+	if offset >= len(payments) {
+		return nil
+	}
+	payments = payments[offset:]
+	if req.Limit != nil && int(*req.Limit) < len(payments) {
+		payments = payments[:*req.Limit]
+	}
+	return payments
+}
+
+// ApplyMetadata merges stored payment metadata and LNURL receive metadata
+// into the details of a Lightning payment, the way the Rust storage does on
+// read. Other payment kinds are returned unchanged.
+func ApplyMetadata(p breez_sdk_spark.Payment, metadata *breez_sdk_spark.PaymentMetadata, receive *breez_sdk_spark.LnurlReceiveMetadata) breez_sdk_spark.Payment {
+	if p.Details == nil {
+		return p
+	}
+	d, ok := (*p.Details).(breez_sdk_spark.PaymentDetailsLightning)
+	if !ok {
+		return p
+	}
+	if metadata != nil {
+		if metadata.LnurlPayInfo != nil {
+			d.LnurlPayInfo = metadata.LnurlPayInfo
+		}
+		if metadata.LnurlWithdrawInfo != nil {
+			d.LnurlWithdrawInfo = metadata.LnurlWithdrawInfo
+		}
+		if metadata.LnurlDescription != nil {
+			d.Description = metadata.LnurlDescription
+		}
+	}
+	if receive != nil {
+		d.LnurlReceiveMetadata = receive
+	}
+	var details breez_sdk_spark.PaymentDetails = d
+	p.Details = &details
+	return p
+}
+
+// MergeMetadata overlays the fields set in update onto existing, so that a
+// later SetPaymentMetadata call never clears what an earlier one stored.
+func MergeMetadata(existing *breez_sdk_spark.PaymentMetadata, update breez_sdk_spark.PaymentMetadata) breez_sdk_spark.PaymentMetadata {
+	if existing == nil {
+		return update
+	}
+	merged := *existing
+	if update.LnurlPayInfo != nil {
+		merged.LnurlPayInfo = update.LnurlPayInfo
+	}
+	if update.LnurlWithdrawInfo != nil {
+		merged.LnurlWithdrawInfo = update.LnurlWithdrawInfo
+	}
+	if update.LnurlDescription != nil {
+		merged.LnurlDescription = update.LnurlDescription
+	}
+	return merged
+}
+
+// HtlcStatusName is the stable string stored in the htlc_status column.
+func HtlcStatusName(s breez_sdk_spark.SparkHtlcStatus) string {
+	switch s {
+	case breez_sdk_spark.SparkHtlcStatusWaitingForPreimage:
+		return "waiting_for_preimage"
+	case breez_sdk_spark.SparkHtlcStatusPreimageShared:
+		return "preimage_shared"
+	case breez_sdk_spark.SparkHtlcStatusReturned:
+		return "returned"
+	default:
+		return fmt.Sprintf("unknown_%d", uint(s))
+	}
+}
+
+// PaymentTypeName is the stable string stored in the payment_type column.
+func PaymentTypeName(t breez_sdk_spark.PaymentType) string {
+	switch t {
+	case breez_sdk_spark.PaymentTypeSend:
+		return "send"
+	case breez_sdk_spark.PaymentTypeReceive:
+		return "receive"
+	default:
+		return fmt.Sprintf("unknown_%d", uint(t))
+	}
+}
+
+// PaymentStatusName is the stable string stored in the status column.
+func PaymentStatusName(s breez_sdk_spark.PaymentStatus) string {
+	switch s {
+	case breez_sdk_spark.PaymentStatusCompleted:
+		return "completed"
+	case breez_sdk_spark.PaymentStatusPending:
+		return "pending"
+	case breez_sdk_spark.PaymentStatusFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("unknown_%d", uint(s))
+	}
+}
+
+func contains[T comparable](values []T, v T) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
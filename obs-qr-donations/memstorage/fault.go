@@ -0,0 +1,79 @@
+// Package memstorage provides thread-safe in-memory implementations of the
+// SDK Storage and SyncStorage interfaces for tests, with optional fault
+// injection.
+package memstorage
+
+import "sync"
+
+// FaultFunc is consulted before every interface call with the method name,
+// e.g. "ListPayments" or "AddOutgoingChange". A non-nil error fails the call
+// before any state is touched. Errors that are not already a StorageError
+// or SyncStorageError are wrapped as an Implementation variant.
+type FaultFunc func(op string) error
+
+// FailOn returns a FaultFunc that fails the listed methods with err, or
+// every method when none are listed.
+func FailOn(err error, ops ...string) FaultFunc {
+	set := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		set[op] = true
+	}
+	return func(op string) error {
+		if len(set) == 0 || set[op] {
+			return err
+		}
+		return nil
+	}
+}
+
+// FailAfter returns a FaultFunc that lets the first n calls to op succeed
+// and fails every later one with err.
+func FailAfter(op string, n int, err error) FaultFunc {
+	var mu sync.Mutex
+	calls := 0
+	return func(called string) error {
+		if called != op {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls > n {
+			return err
+		}
+		return nil
+	}
+}
+
+// faults holds the injected FaultFunc and per-method call counts.
+type faults struct {
+	mu    sync.Mutex
+	fn    FaultFunc
+	calls map[string]int
+}
+
+func (f *faults) check(op string) error {
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[op]++
+	fn := f.fn
+	f.mu.Unlock()
+	if fn == nil {
+		return nil
+	}
+	return fn(op)
+}
+
+func (f *faults) set(fn FaultFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fn = fn
+}
+
+func (f *faults) count(op string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[op]
+}
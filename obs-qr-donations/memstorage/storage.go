@@ -0,0 +1,277 @@
+package memstorage
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/storageutil"
+)
+
+type depositKey struct {
+	txid string
+	vout uint32
+}
+
+// Storage is an in-memory breez_sdk_spark.Storage. The zero value is not
+// usable; call NewStorage.
+type Storage struct {
+	faults faults
+
+	mu       sync.RWMutex
+	cache    map[string]string
+	payments map[string][]byte
+	metadata map[string]breez_sdk_spark.PaymentMetadata
+	receive  map[string]breez_sdk_spark.LnurlReceiveMetadata
+	deposits map[depositKey]breez_sdk_spark.DepositInfo
+}
+
+var _ breez_sdk_spark.Storage = (*Storage)(nil)
+
+// NewStorage returns an empty Storage.
+func NewStorage() *Storage {
+	return &Storage{
+		cache:    map[string]string{},
+		payments: map[string][]byte{},
+		metadata: map[string]breez_sdk_spark.PaymentMetadata{},
+		receive:  map[string]breez_sdk_spark.LnurlReceiveMetadata{},
+		deposits: map[depositKey]breez_sdk_spark.DepositInfo{},
+	}
+}
+
+// SetFault installs fn, or removes fault injection when fn is nil.
+func (s *Storage) SetFault(fn FaultFunc) {
+	s.faults.set(fn)
+}
+
+// Calls reports how many times the method op has been called.
+func (s *Storage) Calls(op string) int {
+	return s.faults.count(op)
+}
+
+func (s *Storage) fault(op string) error {
+	return storageutil.StorageErr(s.faults.check(op))
+}
+
+func (s *Storage) DeleteCachedItem(key string) error {
+	if err := s.fault("DeleteCachedItem"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, key)
+	return nil
+}
+
+func (s *Storage) GetCachedItem(key string) (*string, error) {
+	if err := s.fault("GetCachedItem"); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.cache[key]
+	if !ok {
+		return nil, nil
+	}
+	return &value, nil
+}
+
+func (s *Storage) SetCachedItem(key string, value string) error {
+	if err := s.fault("SetCachedItem"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache[key] = value
+	return nil
+}
+
+// load decodes a stored payment and merges its metadata. Payments are kept
+// encoded so callers never share pointers with the store. Callers hold mu.
+func (s *Storage) load(data []byte) (breez_sdk_spark.Payment, error) {
+	p, err := storageutil.DecodePayment(data)
+	if err != nil {
+		return p, err
+	}
+	var metadata *breez_sdk_spark.PaymentMetadata
+	if m, ok := s.metadata[p.Id]; ok {
+		metadata = &m
+	}
+	var receive *breez_sdk_spark.LnurlReceiveMetadata
+	if hash := storageutil.IndexPayment(p).PaymentHash; hash != nil {
+		if r, ok := s.receive[*hash]; ok {
+			receive = &r
+		}
+	}
+	return storageutil.ApplyMetadata(p, metadata, receive), nil
+}
+
+func (s *Storage) ListPayments(request breez_sdk_spark.ListPaymentsRequest) ([]breez_sdk_spark.Payment, error) {
+	if err := s.fault("ListPayments"); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var payments []breez_sdk_spark.Payment
+	for _, data := range s.payments {
+		p, err := s.load(data)
+		if err != nil {
+			return nil, err
+		}
+		if storageutil.MatchesFilters(p, request) {
+			payments = append(payments, p)
+		}
+	}
+	return storageutil.SortAndPage(payments, request), nil
+}
+
+func (s *Storage) InsertPayment(payment breez_sdk_spark.Payment) error {
+	if err := s.fault("InsertPayment"); err != nil {
+		return err
+	}
+	data := storageutil.EncodePayment(payment)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.payments[payment.Id] = data
+	return nil
+}
+
+func (s *Storage) SetPaymentMetadata(paymentId string, metadata breez_sdk_spark.PaymentMetadata) error {
+	if err := s.fault("SetPaymentMetadata"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var existing *breez_sdk_spark.PaymentMetadata
+	if m, ok := s.metadata[paymentId]; ok {
+		existing = &m
+	}
+	s.metadata[paymentId] = storageutil.MergeMetadata(existing, metadata)
+	return nil
+}
+
+func (s *Storage) GetPaymentById(id string) (breez_sdk_spark.Payment, error) {
+	if err := s.fault("GetPaymentById"); err != nil {
+		return breez_sdk_spark.Payment{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.payments[id]
+	if !ok {
+		return breez_sdk_spark.Payment{}, storageutil.ErrPaymentNotFound(id)
+	}
+	return s.load(data)
+}
+
+func (s *Storage) GetPaymentByInvoice(invoice string) (*breez_sdk_spark.Payment, error) {
+	if err := s.fault("GetPaymentByInvoice"); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var found *breez_sdk_spark.Payment
+	for _, data := range s.payments {
+		p, err := s.load(data)
+		if err != nil {
+			return nil, err
+		}
+		idx := storageutil.IndexPayment(p)
+		if idx.Invoice == nil || *idx.Invoice != invoice {
+			continue
+		}
+		if found == nil || p.Timestamp > found.Timestamp {
+			found = &p
+		}
+	}
+	return found, nil
+}
+
+func (s *Storage) AddDeposit(txid string, vout uint32, amountSats uint64) error {
+	if err := s.fault("AddDeposit"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := depositKey{txid, vout}
+	d, ok := s.deposits[key]
+	if !ok {
+		d = breez_sdk_spark.DepositInfo{Txid: txid, Vout: vout}
+	}
+	d.AmountSats = amountSats
+	s.deposits[key] = d
+	return nil
+}
+
+func (s *Storage) DeleteDeposit(txid string, vout uint32) error {
+	if err := s.fault("DeleteDeposit"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.deposits, depositKey{txid, vout})
+	return nil
+}
+
+func (s *Storage) ListDeposits() ([]breez_sdk_spark.DepositInfo, error) {
+	if err := s.fault("ListDeposits"); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var deposits []breez_sdk_spark.DepositInfo
+	for _, d := range s.deposits {
+		deposits = append(deposits, d)
+	}
+	sort.Slice(deposits, func(i, j int) bool {
+		if deposits[i].Txid != deposits[j].Txid {
+			return deposits[i].Txid < deposits[j].Txid
+		}
+		return deposits[i].Vout < deposits[j].Vout
+	})
+	return deposits, nil
+}
+
+// UpdateDeposit records a claim error or refund. Like the SQL backends, an
+// unknown deposit is inserted with a zero amount.
+func (s *Storage) UpdateDeposit(txid string, vout uint32, payload breez_sdk_spark.UpdateDepositPayload) error {
+	if err := s.fault("UpdateDeposit"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := depositKey{txid, vout}
+	d, ok := s.deposits[key]
+	if !ok {
+		d = breez_sdk_spark.DepositInfo{Txid: txid, Vout: vout}
+	}
+	switch p := payload.(type) {
+	case breez_sdk_spark.UpdateDepositPayloadClaimError:
+		claimErr := p.Error
+		d.ClaimError = &claimErr
+	case breez_sdk_spark.UpdateDepositPayloadRefund:
+		refundTx, refundTxid := p.RefundTx, p.RefundTxid
+		d.RefundTx = &refundTx
+		d.RefundTxId = &refundTxid
+	default:
+		return storageutil.StorageErr(fmt.Errorf("unknown deposit update %T", payload))
+	}
+	s.deposits[key] = d
+	return nil
+}
+
+func (s *Storage) SetLnurlMetadata(metadata []breez_sdk_spark.SetLnurlMetadataItem) error {
+	if err := s.fault("SetLnurlMetadata"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range metadata {
+		s.receive[item.PaymentHash] = breez_sdk_spark.LnurlReceiveMetadata{
+			NostrZapRequest: item.NostrZapRequest,
+			NostrZapReceipt: item.NostrZapReceipt,
+			SenderComment:   item.SenderComment,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package memstorage
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/storagetest"
+)
+
+func TestStorageSuite(t *testing.T) {
+	storagetest.RunStorageSuite(t, func(t *testing.T) breez_sdk_spark.Storage {
+		return NewStorage()
+	})
+}
+
+func TestSyncStorageSuite(t *testing.T) {
+	storagetest.RunSyncStorageSuite(t, func(t *testing.T) breez_sdk_spark.SyncStorage {
+		return NewSyncStorage()
+	})
+}
+
+func change(dataId string, fields map[string]string) breez_sdk_spark.UnversionedRecordChange {
+	return breez_sdk_spark.UnversionedRecordChange{
+		Id:            breez_sdk_spark.RecordId{Type: "payment", DataId: dataId},
+		SchemaVersion: "1",
+		UpdatedFields: fields,
+	}
+}
+
+func revisions(changes []breez_sdk_spark.OutgoingChange) []uint64 {
+	var out []uint64
+	for _, c := range changes {
+		out = append(out, c.Change.Revision)
+	}
+	return out
+}
+
+func equal(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGetPaymentByInvoiceMissing(t *testing.T) {
+	s := NewStorage()
+	p, err := s.GetPaymentByInvoice("lnbc1missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != nil {
+		t.Fatalf("got %+v, want nil", p)
+	}
+	if _, err := s.GetPaymentById("missing"); !errors.Is(err, breez_sdk_spark.ErrStorageErrorImplementation) {
+		t.Fatalf("GetPaymentById: got %v", err)
+	}
+}
+
+func TestUpdateUnknownDeposit(t *testing.T) {
+	s := NewStorage()
+	if err := s.UpdateDeposit("tx1", 0, breez_sdk_spark.UpdateDepositPayloadRefund{RefundTx: "00", RefundTxid: "r1"}); err != nil {
+		t.Fatal(err)
+	}
+	deposits, _ := s.ListDeposits()
+	if len(deposits) != 1 || deposits[0].AmountSats != 0 || *deposits[0].RefundTxId != "r1" {
+		t.Fatalf("got %+v", deposits)
+	}
+	// Adding it later sets the amount and keeps the refund.
+	s.AddDeposit("tx1", 0, 50_000)
+	deposits, _ = s.ListDeposits()
+	if deposits[0].AmountSats != 50_000 || deposits[0].RefundTxId == nil {
+		t.Fatalf("got %+v", deposits)
+	}
+}
+
+func TestPendingOutgoingOrder(t *testing.T) {
+	s := NewSyncStorage()
+	if err := s.UpdateRecordFromIncoming(breez_sdk_spark.Record{
+		Id:       breez_sdk_spark.RecordId{Type: "payment", DataId: "a"},
+		Revision: 5,
+		Data:     map[string]string{"x": "1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := s.AddOutgoingChange(change(id, map[string]string{"x": id})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pending, err := s.GetPendingOutgoingChanges(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := revisions(pending); !equal(got, []uint64{6, 7, 8}) {
+		t.Fatalf("revisions = %v", got)
+	}
+	if pending[0].Parent == nil || pending[0].Parent.Revision != 5 {
+		t.Fatalf("parent = %+v", pending[0].Parent)
+	}
+	if pending[1].Parent != nil {
+		t.Fatalf("unexpected parent %+v", pending[1].Parent)
+	}
+	if limited, _ := s.GetPendingOutgoingChanges(2); !equal(revisions(limited), []uint64{6, 7}) {
+		t.Fatalf("limited revisions = %v", revisions(limited))
+	}
+
+	// Another device pushed up to revision 9 in the meantime.
+	if err := s.RebasePendingOutgoingRecords(9); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ = s.GetPendingOutgoingChanges(10)
+	if got := revisions(pending); !equal(got, []uint64{10, 11, 12}) {
+		t.Fatalf("rebased revisions = %v", got)
+	}
+
+	if err := s.CompleteOutgoingSync(breez_sdk_spark.Record{
+		Id:       pending[0].Change.Id,
+		Revision: 10,
+		Data:     pending[0].Change.UpdatedFields,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if last, _ := s.GetLastRevision(); last != 10 {
+		t.Fatalf("last revision = %d", last)
+	}
+	latest, err := s.GetLatestOutgoingChange()
+	if err != nil || latest == nil || latest.Change.Revision != 12 {
+		t.Fatalf("latest = %+v, %v", latest, err)
+	}
+	if n, _ := s.AddOutgoingChange(change("d", nil)); n != 13 {
+		t.Fatalf("next revision = %d", n)
+	}
+}
+
+func TestIncomingRecords(t *testing.T) {
+	s := NewSyncStorage()
+	a := breez_sdk_spark.Record{Id: breez_sdk_spark.RecordId{Type: "payment", DataId: "a"}, Revision: 1}
+	if err := s.UpdateRecordFromIncoming(a); err != nil {
+		t.Fatal(err)
+	}
+	newA := a
+	newA.Revision = 3
+	b := breez_sdk_spark.Record{Id: breez_sdk_spark.RecordId{Type: "payment", DataId: "b"}, Revision: 2}
+	if err := s.InsertIncomingRecords([]breez_sdk_spark.Record{newA, b}); err != nil {
+		t.Fatal(err)
+	}
+	incoming, err := s.GetIncomingRecords(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incoming) != 2 || incoming[0].NewState.Revision != 2 || incoming[1].NewState.Revision != 3 {
+		t.Fatalf("incoming = %+v", incoming)
+	}
+	if incoming[0].OldState != nil || incoming[1].OldState == nil || incoming[1].OldState.Revision != 1 {
+		t.Fatalf("old states = %+v, %+v", incoming[0].OldState, incoming[1].OldState)
+	}
+	if err := s.DeleteIncomingRecord(b); err != nil {
+		t.Fatal(err)
+	}
+	if incoming, _ = s.GetIncomingRecords(10); len(incoming) != 1 {
+		t.Fatalf("after delete = %+v", incoming)
+	}
+}
+
+func TestFaultInjection(t *testing.T) {
+	s := NewStorage()
+	boom := errors.New("disk on fire")
+	s.SetFault(FailOn(boom, "SetCachedItem"))
+	err := s.SetCachedItem("k", "v")
+	if !errors.Is(err, breez_sdk_spark.ErrStorageErrorImplementation) {
+		t.Fatalf("got %v, want Implementation error", err)
+	}
+	if v, _ := s.GetCachedItem("k"); v != nil {
+		t.Fatalf("failed call changed state: %q", *v)
+	}
+
+	ss := NewSyncStorage()
+	ss.SetFault(FailAfter("AddOutgoingChange", 1, breez_sdk_spark.NewSyncStorageErrorSerialization("bad")))
+	if _, err := ss.AddOutgoingChange(change("a", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ss.AddOutgoingChange(change("b", nil)); !errors.Is(err, breez_sdk_spark.ErrSyncStorageErrorSerialization) {
+		t.Fatalf("got %v, want Serialization error", err)
+	}
+	if n := ss.Calls("AddOutgoingChange"); n != 2 {
+		t.Fatalf("calls = %d", n)
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	s := NewSyncStorage()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, err := s.AddOutgoingChange(change("x", nil)); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	pending, _ := s.GetPendingOutgoingChanges(1000)
+	if len(pending) != 800 {
+		t.Fatalf("got %d pending changes", len(pending))
+	}
+	for i, c := range pending {
+		if c.Change.Revision != uint64(i+1) {
+			t.Fatalf("revision %d at position %d", c.Change.Revision, i)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package memstorage
+
+import (
+	"sort"
+	"sync"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/storageutil"
+)
+
+type incomingKey struct {
+	id       breez_sdk_spark.RecordId
+	revision uint64
+}
+
+// SyncStorage is an in-memory breez_sdk_spark.SyncStorage. The zero value is
+// not usable; call NewSyncStorage.
+//
+// Pending outgoing changes carry local queue revisions numbered above both
+// the last synced revision and every other pending change, so
+// GetPendingOutgoingChanges returns them in the order they were made.
+type SyncStorage struct {
+	faults faults
+
+	mu           sync.Mutex
+	state        map[breez_sdk_spark.RecordId]breez_sdk_spark.Record
+	outgoing     []breez_sdk_spark.RecordChange
+	incoming     map[incomingKey]breez_sdk_spark.Record
+	lastRevision uint64
+}
+
+var _ breez_sdk_spark.SyncStorage = (*SyncStorage)(nil)
+
+// NewSyncStorage returns an empty SyncStorage.
+func NewSyncStorage() *SyncStorage {
+	return &SyncStorage{
+		state:    map[breez_sdk_spark.RecordId]breez_sdk_spark.Record{},
+		incoming: map[incomingKey]breez_sdk_spark.Record{},
+	}
+}
+
+// SetFault installs fn, or removes fault injection when fn is nil.
+func (s *SyncStorage) SetFault(fn FaultFunc) {
+	s.faults.set(fn)
+}
+
+// Calls reports how many times the method op has been called.
+func (s *SyncStorage) Calls(op string) int {
+	return s.faults.count(op)
+}
+
+func (s *SyncStorage) fault(op string) error {
+	return storageutil.SyncStorageErr(s.faults.check(op))
+}
+
+func copyFields(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func copyRecord(r breez_sdk_spark.Record) breez_sdk_spark.Record {
+	r.Data = copyFields(r.Data)
+	return r
+}
+
+// parent returns the synced state of id. Callers hold mu.
+func (s *SyncStorage) parent(id breez_sdk_spark.RecordId) *breez_sdk_spark.Record {
+	r, ok := s.state[id]
+	if !ok {
+		return nil
+	}
+	r = copyRecord(r)
+	return &r
+}
+
+func (s *SyncStorage) outgoingChange(c breez_sdk_spark.RecordChange) breez_sdk_spark.OutgoingChange {
+	c.UpdatedFields = copyFields(c.UpdatedFields)
+	return breez_sdk_spark.OutgoingChange{Change: c, Parent: s.parent(c.Id)}
+}
+
+func (s *SyncStorage) AddOutgoingChange(record breez_sdk_spark.UnversionedRecordChange) (uint64, error) {
+	if err := s.fault("AddOutgoingChange"); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revision := s.lastRevision
+	if n := len(s.outgoing); n > 0 && s.outgoing[n-1].Revision > revision {
+		revision = s.outgoing[n-1].Revision
+	}
+	revision++
+	s.outgoing = append(s.outgoing, breez_sdk_spark.RecordChange{
+		Id:            record.Id,
+		SchemaVersion: record.SchemaVersion,
+		UpdatedFields: copyFields(record.UpdatedFields),
+		Revision:      revision,
+	})
+	return revision, nil
+}
+
+// CompleteOutgoingSync drops the pending change the server accepted as
+// record and makes record the synced state.
+func (s *SyncStorage) CompleteOutgoingSync(record breez_sdk_spark.Record) error {
+	if err := s.fault("CompleteOutgoingSync"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, c := range s.outgoing {
+		if c.Id == record.Id && c.Revision == record.Revision {
+			s.outgoing = append(s.outgoing[:i], s.outgoing[i+1:]...)
+			break
+		}
+	}
+	s.applyLocked(record)
+	return nil
+}
+
+// applyLocked stores record as the synced state. Callers hold mu.
+func (s *SyncStorage) applyLocked(record breez_sdk_spark.Record) {
+	s.state[record.Id] = copyRecord(record)
+	if record.Revision > s.lastRevision {
+		s.lastRevision = record.Revision
+	}
+}
+
+func (s *SyncStorage) GetPendingOutgoingChanges(limit uint32) ([]breez_sdk_spark.OutgoingChange, error) {
+	if err := s.fault("GetPendingOutgoingChanges"); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.outgoing)
+	if int(limit) < n {
+		n = int(limit)
+	}
+	changes := make([]breez_sdk_spark.OutgoingChange, 0, n)
+	for _, c := range s.outgoing[:n] {
+		changes = append(changes, s.outgoingChange(c))
+	}
+	return changes, nil
+}
+
+func (s *SyncStorage) GetLastRevision() (uint64, error) {
+	if err := s.fault("GetLastRevision"); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRevision, nil
+}
+
+func (s *SyncStorage) InsertIncomingRecords(records []breez_sdk_spark.Record) error {
+	if err := s.fault("InsertIncomingRecords"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range records {
+		s.incoming[incomingKey{r.Id, r.Revision}] = copyRecord(r)
+	}
+	return nil
+}
+
+func (s *SyncStorage) DeleteIncomingRecord(record breez_sdk_spark.Record) error {
+	if err := s.fault("DeleteIncomingRecord"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.incoming, incomingKey{record.Id, record.Revision})
+	return nil
+}
+
+// RebasePendingOutgoingRecords shifts every pending change up by the
+// distance between revision and the last synced revision, so local changes
+// stay ordered after records pulled from the server.
+func (s *SyncStorage) RebasePendingOutgoingRecords(revision uint64) error {
+	if err := s.fault("RebasePendingOutgoingRecords"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if revision <= s.lastRevision {
+		return nil
+	}
+	diff := revision - s.lastRevision
+	for i := range s.outgoing {
+		s.outgoing[i].Revision += diff
+	}
+	return nil
+}
+
+func (s *SyncStorage) GetIncomingRecords(limit uint32) ([]breez_sdk_spark.IncomingChange, error) {
+	if err := s.fault("GetIncomingRecords"); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]breez_sdk_spark.Record, 0, len(s.incoming))
+	for _, r := range s.incoming {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Revision != b.Revision {
+			return a.Revision < b.Revision
+		}
+		if a.Id.Type != b.Id.Type {
+			return a.Id.Type < b.Id.Type
+		}
+		return a.Id.DataId < b.Id.DataId
+	})
+	if int(limit) < len(records) {
+		records = records[:limit]
+	}
+	changes := make([]breez_sdk_spark.IncomingChange, 0, len(records))
+	for _, r := range records {
+		changes = append(changes, breez_sdk_spark.IncomingChange{
+			NewState: copyRecord(r),
+			OldState: s.parent(r.Id),
+		})
+	}
+	return changes, nil
+}
+
+func (s *SyncStorage) GetLatestOutgoingChange() (*breez_sdk_spark.OutgoingChange, error) {
+	if err := s.fault("GetLatestOutgoingChange"); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.outgoing) == 0 {
+		return nil, nil
+	}
+	change := s.outgoingChange(s.outgoing[len(s.outgoing)-1])
+	return &change, nil
+}
+
+func (s *SyncStorage) UpdateRecordFromIncoming(record breez_sdk_spark.Record) error {
+	if err := s.fault("UpdateRecordFromIncoming"); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applyLocked(record)
+	return nil
+}
@@ -0,0 +1,361 @@
+// Package multichain spreads BitcoinChainService calls over several
+// backends, so that one flaky block explorer does not stop deposit claims
+// in the middle of a stream.
+//
+// Lookups fail over from one backend to the next, broadcasts go to every
+// backend, fee estimates are the median across backends and transaction
+// statuses must be confirmed by a quorum:
+//
+//	blockstream, _ := esplora.New(esplora.Config{BaseURL: "https://blockstream.info/api"})
+//	mempool, _ := esplora.New(esplora.Config{BaseURL: "https://mempool.space/api"})
+//	node, _ := bitcoind.New(bitcoind.Config{URL: "http://127.0.0.1:8332", CookieFile: cookie})
+//	m, err := multichain.New(multichain.Config{Backends: []multichain.Backend{
+//		{Name: "blockstream", Service: blockstream},
+//		{Name: "mempool.space", Service: mempool},
+//		{Name: "node", Service: node},
+//	}})
+//	...
+//	builder.WithChainService(breez_sdk_spark.NewBitcoinChainServiceFromCtx(m))
+package multichain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// DefaultCooldown is how long a backend that could not be reached is moved
+// to the back of the failover order.
+const DefaultCooldown = 30 * time.Second
+
+// Backend is one chain service behind a MultiChainService. Plain
+// breez_sdk_spark.BitcoinChainService values can be used through
+// breez_sdk_spark.AsBitcoinChainServiceCtx.
+type Backend struct {
+	// Name identifies the backend in errors and logs.
+	Name    string
+	Service breez_sdk_spark.BitcoinChainServiceCtx
+}
+
+// Config configures a MultiChainService.
+type Config struct {
+	// Backends in order of preference. At least one is required.
+	Backends []Backend
+	// Quorum is the number of backends that must report the same status
+	// for GetTransactionStatus to return it. Defaults to a majority of
+	// Backends.
+	Quorum int
+	// Cooldown defaults to DefaultCooldown.
+	Cooldown time.Duration
+	// Logger receives a debug record for every backend failure. Defaults
+	// to no logging.
+	Logger *slog.Logger
+}
+
+// MultiChainService is a breez_sdk_spark.BitcoinChainServiceCtx backed by
+// several others.
+type MultiChainService struct {
+	backends []Backend
+	quorum   int
+	cooldown time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu        sync.Mutex
+	downUntil []time.Time
+}
+
+var _ breez_sdk_spark.BitcoinChainServiceCtx = (*MultiChainService)(nil)
+
+// New returns a MultiChainService for cfg.
+func New(cfg Config) (*MultiChainService, error) {
+	if len(cfg.Backends) == 0 {
+		return nil, errors.New("multichain: no backends")
+	}
+	for i, b := range cfg.Backends {
+		if b.Service == nil {
+			return nil, fmt.Errorf("multichain: backend %d (%q) has no service", i, b.Name)
+		}
+	}
+	m := &MultiChainService{
+		backends:  slices.Clone(cfg.Backends),
+		quorum:    cfg.Quorum,
+		cooldown:  cfg.Cooldown,
+		logger:    cfg.Logger,
+		now:       time.Now,
+		downUntil: make([]time.Time, len(cfg.Backends)),
+	}
+	if m.quorum == 0 {
+		m.quorum = len(m.backends)/2 + 1
+	}
+	if m.quorum < 1 || m.quorum > len(m.backends) {
+		return nil, fmt.Errorf("multichain: quorum %d out of range for %d backends", cfg.Quorum, len(m.backends))
+	}
+	if m.cooldown == 0 {
+		m.cooldown = DefaultCooldown
+	}
+	if m.logger == nil {
+		m.logger = slog.New(slog.DiscardHandler)
+	}
+	return m, nil
+}
+
+// GetAddressUtxos returns the answer of the first backend that gives one.
+// An invalid address is reported straight away, since every other backend
+// would reject it too.
+func (m *MultiChainService) GetAddressUtxos(ctx context.Context, address string) ([]breez_sdk_spark.Utxo, error) {
+	return failover(ctx, m, "GetAddressUtxos", func(ctx context.Context, s breez_sdk_spark.BitcoinChainServiceCtx) ([]breez_sdk_spark.Utxo, error) {
+		return s.GetAddressUtxos(ctx, address)
+	})
+}
+
+// GetTransactionHex returns the answer of the first backend that gives one.
+func (m *MultiChainService) GetTransactionHex(ctx context.Context, txid string) (string, error) {
+	return failover(ctx, m, "GetTransactionHex", func(ctx context.Context, s breez_sdk_spark.BitcoinChainServiceCtx) (string, error) {
+		return s.GetTransactionHex(ctx, txid)
+	})
+}
+
+// GetTransactionStatus asks every backend and returns the status as soon as
+// Quorum of them agree on it. It fails if too few backends answer or if
+// their answers are split, which is common for a block or two around a
+// new tip; the SDK retries later.
+func (m *MultiChainService) GetTransactionStatus(ctx context.Context, txid string) (breez_sdk_spark.TxStatus, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := fanOut(ctx, m, "GetTransactionStatus", func(ctx context.Context, s breez_sdk_spark.BitcoinChainServiceCtx) (breez_sdk_spark.TxStatus, error) {
+		return s.GetTransactionStatus(ctx, txid)
+	})
+
+	type vote struct {
+		status breez_sdk_spark.TxStatus
+		names  []string
+	}
+	var votes []*vote
+	var errs []error
+	for range m.backends {
+		r := <-results
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		i := slices.IndexFunc(votes, func(v *vote) bool { return sameStatus(v.status, r.value) })
+		if i < 0 {
+			votes = append(votes, &vote{status: r.value})
+			i = len(votes) - 1
+		}
+		votes[i].names = append(votes[i].names, r.name)
+		if len(votes[i].names) >= m.quorum {
+			return votes[i].status, nil
+		}
+	}
+
+	answered := len(m.backends) - len(errs)
+	if answered < m.quorum {
+		return breez_sdk_spark.TxStatus{}, combine("GetTransactionStatus",
+			fmt.Sprintf("%d of %d backends answered, quorum is %d", answered, len(m.backends), m.quorum), errs)
+	}
+	split := make([]string, len(votes))
+	for i, v := range votes {
+		split[i] = fmt.Sprintf("%s say %s", strings.Join(v.names, ", "), formatStatus(v.status))
+	}
+	return breez_sdk_spark.TxStatus{}, breez_sdk_spark.NewChainServiceErrorGeneric(fmt.Sprintf(
+		"multichain: GetTransactionStatus: no quorum of %d: %s", m.quorum, strings.Join(split, "; ")))
+}
+
+// BroadcastTransaction sends tx to every backend and succeeds if any of
+// them accepts it.
+func (m *MultiChainService) BroadcastTransaction(ctx context.Context, tx string) error {
+	results := fanOut(ctx, m, "BroadcastTransaction", func(ctx context.Context, s breez_sdk_spark.BitcoinChainServiceCtx) (struct{}, error) {
+		return struct{}{}, s.BroadcastTransaction(ctx, tx)
+	})
+	accepted := false
+	var errs []error
+	for range m.backends {
+		if r := <-results; r.err != nil {
+			errs = append(errs, r.err)
+		} else {
+			accepted = true
+		}
+	}
+	if accepted {
+		return nil
+	}
+	return combine("BroadcastTransaction", "no backend accepted the transaction", errs)
+}
+
+// RecommendedFees asks every backend and returns the median of each fee
+// among those that answer. With an even number of answers the higher of the
+// two middle values is used, preferring a prompt confirmation over a
+// cheap one.
+func (m *MultiChainService) RecommendedFees(ctx context.Context) (breez_sdk_spark.RecommendedFees, error) {
+	results := fanOut(ctx, m, "RecommendedFees", func(ctx context.Context, s breez_sdk_spark.BitcoinChainServiceCtx) (breez_sdk_spark.RecommendedFees, error) {
+		return s.RecommendedFees(ctx)
+	})
+	var fees []breez_sdk_spark.RecommendedFees
+	var errs []error
+	for range m.backends {
+		if r := <-results; r.err != nil {
+			errs = append(errs, r.err)
+		} else {
+			fees = append(fees, r.value)
+		}
+	}
+	if len(fees) == 0 {
+		return breez_sdk_spark.RecommendedFees{}, combine("RecommendedFees", "no backend answered", errs)
+	}
+	median := func(field func(breez_sdk_spark.RecommendedFees) uint64) uint64 {
+		values := make([]uint64, len(fees))
+		for i, f := range fees {
+			values[i] = field(f)
+		}
+		slices.Sort(values)
+		return values[len(values)/2]
+	}
+	return breez_sdk_spark.RecommendedFees{
+		FastestFee:  median(func(f breez_sdk_spark.RecommendedFees) uint64 { return f.FastestFee }),
+		HalfHourFee: median(func(f breez_sdk_spark.RecommendedFees) uint64 { return f.HalfHourFee }),
+		HourFee:     median(func(f breez_sdk_spark.RecommendedFees) uint64 { return f.HourFee }),
+		EconomyFee:  median(func(f breez_sdk_spark.RecommendedFees) uint64 { return f.EconomyFee }),
+		MinimumFee:  median(func(f breez_sdk_spark.RecommendedFees) uint64 { return f.MinimumFee }),
+	}, nil
+}
+
+type call[T any] func(context.Context, breez_sdk_spark.BitcoinChainServiceCtx) (T, error)
+
+type result[T any] struct {
+	name  string
+	value T
+	err   error
+}
+
+// failover tries the backends one at a time in failover order.
+func failover[T any](ctx context.Context, m *MultiChainService, op string, fn call[T]) (T, error) {
+	var zero T
+	var errs []error
+	for _, i := range m.order() {
+		v, err := try(ctx, m, i, op, fn)
+		if err == nil {
+			return v, nil
+		}
+		errs = append(errs, err)
+		if errors.Is(err, breez_sdk_spark.ErrChainServiceErrorInvalidAddress) || ctx.Err() != nil {
+			break
+		}
+	}
+	return zero, combine(op, "all backends failed", errs)
+}
+
+// fanOut calls every backend concurrently. The returned channel receives
+// exactly one result per backend.
+func fanOut[T any](ctx context.Context, m *MultiChainService, op string, fn call[T]) <-chan result[T] {
+	results := make(chan result[T], len(m.backends))
+	for i := range m.backends {
+		go func() {
+			v, err := try(ctx, m, i, op, fn)
+			results <- result[T]{name: m.backends[i].Name, value: v, err: err}
+		}()
+	}
+	return results
+}
+
+// try calls backend i and keeps track of its health. Errors are prefixed
+// with the backend name.
+func try[T any](ctx context.Context, m *MultiChainService, i int, op string, fn call[T]) (T, error) {
+	b := m.backends[i]
+	v, err := fn(ctx, b.Service)
+	if err == nil {
+		m.markUp(i)
+		return v, nil
+	}
+	m.logger.DebugContext(ctx, "chain backend failed", "backend", b.Name, "op", op, "err", err)
+	// Calls abandoned by the caller, or cancelled once a quorum was
+	// reached, say nothing about the backend.
+	if ctx.Err() == nil && errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		m.markDown(i)
+	}
+	return v, fmt.Errorf("%s: %w", b.Name, err)
+}
+
+// order returns the backend indexes in failover order: backends in
+// cooldown go last, the one that comes out of it first leading.
+func (m *MultiChainService) order() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	order := make([]int, 0, len(m.backends))
+	var down []int
+	for i, until := range m.downUntil {
+		if until.After(now) {
+			down = append(down, i)
+		} else {
+			order = append(order, i)
+		}
+	}
+	slices.SortStableFunc(down, func(a, b int) int { return m.downUntil[a].Compare(m.downUntil[b]) })
+	return append(order, down...)
+}
+
+func (m *MultiChainService) markUp(i int) {
+	m.mu.Lock()
+	m.downUntil[i] = time.Time{}
+	m.mu.Unlock()
+}
+
+func (m *MultiChainService) markDown(i int) {
+	m.mu.Lock()
+	m.downUntil[i] = m.now().Add(m.cooldown)
+	m.mu.Unlock()
+}
+
+// combine reports the failure of op across backends. It is a
+// ServiceConnectivity error when no backend could be reached, and Generic
+// otherwise, unless a backend rejected the address.
+func combine(op, summary string, errs []error) error {
+	msgs := make([]string, len(errs))
+	connectivity := len(errs) > 0
+	for i, err := range errs {
+		msgs[i] = err.Error()
+		if errors.Is(err, breez_sdk_spark.ErrChainServiceErrorInvalidAddress) {
+			return breez_sdk_spark.NewChainServiceErrorInvalidAddress(fmt.Sprintf("multichain: %s: %s", op, msgs[i]))
+		}
+		connectivity = connectivity && errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity)
+	}
+	msg := fmt.Sprintf("multichain: %s: %s", op, summary)
+	if len(msgs) > 0 {
+		msg += ": " + strings.Join(msgs, "; ")
+	}
+	if connectivity {
+		return breez_sdk_spark.NewChainServiceErrorServiceConnectivity(msg)
+	}
+	return breez_sdk_spark.NewChainServiceErrorGeneric(msg)
+}
+
+func sameStatus(a, b breez_sdk_spark.TxStatus) bool {
+	return a.Confirmed == b.Confirmed &&
+		equalPtr(a.BlockHeight, b.BlockHeight) && equalPtr(a.BlockTime, b.BlockTime)
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatStatus(s breez_sdk_spark.TxStatus) string {
+	if !s.Confirmed {
+		return "unconfirmed"
+	}
+	if s.BlockHeight == nil {
+		return "confirmed"
+	}
+	return fmt.Sprintf("confirmed at %d", *s.BlockHeight)
+}
@@ -0,0 +1,298 @@
+package multichain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+var (
+	errDown    = breez_sdk_spark.NewChainServiceErrorServiceConnectivity("connection refused")
+	errUnknown = breez_sdk_spark.NewChainServiceErrorGeneric("transaction not found")
+)
+
+// fakeBackend answers with its fields; a nil func means "not expected".
+type fakeBackend struct {
+	utxos     func() ([]breez_sdk_spark.Utxo, error)
+	status    func(ctx context.Context) (breez_sdk_spark.TxStatus, error)
+	hex       func() (string, error)
+	broadcast func() error
+	fees      func() (breez_sdk_spark.RecommendedFees, error)
+	calls     atomic.Int32
+}
+
+func (f *fakeBackend) GetAddressUtxos(_ context.Context, _ string) ([]breez_sdk_spark.Utxo, error) {
+	f.calls.Add(1)
+	return f.utxos()
+}
+
+func (f *fakeBackend) GetTransactionStatus(ctx context.Context, _ string) (breez_sdk_spark.TxStatus, error) {
+	f.calls.Add(1)
+	return f.status(ctx)
+}
+
+func (f *fakeBackend) GetTransactionHex(_ context.Context, _ string) (string, error) {
+	f.calls.Add(1)
+	return f.hex()
+}
+
+func (f *fakeBackend) BroadcastTransaction(_ context.Context, _ string) error {
+	f.calls.Add(1)
+	return f.broadcast()
+}
+
+func (f *fakeBackend) RecommendedFees(_ context.Context) (breez_sdk_spark.RecommendedFees, error) {
+	f.calls.Add(1)
+	return f.fees()
+}
+
+func newService(t *testing.T, quorum int, fakes ...*fakeBackend) *MultiChainService {
+	t.Helper()
+	backends := make([]Backend, len(fakes))
+	for i, f := range fakes {
+		backends[i] = Backend{Name: string(rune('a' + i)), Service: f}
+	}
+	m, err := New(Config{Backends: backends, Quorum: quorum})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func confirmedAt(height uint32) breez_sdk_spark.TxStatus {
+	blockTime := uint64(1_700_000_000) + uint64(height)*600
+	return breez_sdk_spark.TxStatus{Confirmed: true, BlockHeight: &height, BlockTime: &blockTime}
+}
+
+func hexFrom(hex string, err error) func() (string, error) {
+	return func() (string, error) { return hex, err }
+}
+
+func TestFailover(t *testing.T) {
+	a := &fakeBackend{hex: hexFrom("", errDown)}
+	b := &fakeBackend{hex: hexFrom("", errUnknown)}
+	c := &fakeBackend{hex: hexFrom("0200", nil)}
+	m := newService(t, 0, a, b, c)
+
+	hex, err := m.GetTransactionHex(context.Background(), "aa")
+	if err != nil || hex != "0200" {
+		t.Fatalf("hex = %q, %v", hex, err)
+	}
+	if a.calls.Load() != 1 || b.calls.Load() != 1 || c.calls.Load() != 1 {
+		t.Fatalf("calls = %d, %d, %d", a.calls.Load(), b.calls.Load(), c.calls.Load())
+	}
+
+	c.hex = hexFrom("", errDown)
+	_, err = m.GetTransactionHex(context.Background(), "aa")
+	if !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) {
+		t.Fatalf("mixed failures: got %v", err)
+	}
+	for _, name := range []string{"a: ", "b: ", "c: "} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not mention backend %q", err, name)
+		}
+	}
+
+	b.hex = hexFrom("", errDown)
+	if _, err := m.GetTransactionHex(context.Background(), "aa"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("all unreachable: got %v", err)
+	}
+}
+
+func TestInvalidAddressIsNotRetried(t *testing.T) {
+	a := &fakeBackend{utxos: func() ([]breez_sdk_spark.Utxo, error) {
+		return nil, breez_sdk_spark.NewChainServiceErrorInvalidAddress("bad checksum")
+	}}
+	b := &fakeBackend{}
+	m := newService(t, 0, a, b)
+	if _, err := m.GetAddressUtxos(context.Background(), "bc1qtypo"); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorInvalidAddress) {
+		t.Fatalf("got %v", err)
+	}
+	if b.calls.Load() != 0 {
+		t.Fatal("invalid address was retried on the next backend")
+	}
+}
+
+func TestCooldown(t *testing.T) {
+	a := &fakeBackend{hex: hexFrom("", errDown)}
+	b := &fakeBackend{hex: hexFrom("0200", nil)}
+	m := newService(t, 0, a, b)
+	now := time.Unix(1_700_000_000, 0)
+	m.now = func() time.Time { return now }
+
+	m.GetTransactionHex(context.Background(), "aa")
+	m.GetTransactionHex(context.Background(), "aa")
+	if a.calls.Load() != 1 || b.calls.Load() != 2 {
+		t.Fatalf("during cooldown: calls = %d, %d", a.calls.Load(), b.calls.Load())
+	}
+
+	now = now.Add(DefaultCooldown)
+	a.hex = hexFrom("0200", nil)
+	m.GetTransactionHex(context.Background(), "aa")
+	if a.calls.Load() != 2 || b.calls.Load() != 2 {
+		t.Fatalf("after cooldown: calls = %d, %d", a.calls.Load(), b.calls.Load())
+	}
+
+	// Backends in cooldown are still tried when nothing else works.
+	a.hex = hexFrom("", errDown)
+	m.GetTransactionHex(context.Background(), "aa")
+	b.hex = hexFrom("", errDown)
+	if _, err := m.GetTransactionHex(context.Background(), "aa"); err == nil || a.calls.Load() != 4 {
+		t.Fatalf("all down: err = %v, calls to a = %d", err, a.calls.Load())
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	a := &fakeBackend{broadcast: func() error { return errDown }}
+	b := &fakeBackend{broadcast: func() error { return nil }}
+	c := &fakeBackend{broadcast: func() error { return errUnknown }}
+	m := newService(t, 0, a, b, c)
+
+	if err := m.BroadcastTransaction(context.Background(), "0200"); err != nil {
+		t.Fatal(err)
+	}
+	if a.calls.Load() != 1 || b.calls.Load() != 1 || c.calls.Load() != 1 {
+		t.Fatal("transaction was not sent to every backend")
+	}
+
+	b.broadcast = func() error { return breez_sdk_spark.NewChainServiceErrorGeneric("bad-txns-inputs-missingorspent") }
+	err := m.BroadcastTransaction(context.Background(), "0200")
+	if !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorGeneric) || !strings.Contains(err.Error(), "missingorspent") {
+		t.Fatalf("all rejected: got %v", err)
+	}
+}
+
+func TestRecommendedFeesMedian(t *testing.T) {
+	feesOf := func(fastest, minimum uint64) func() (breez_sdk_spark.RecommendedFees, error) {
+		return func() (breez_sdk_spark.RecommendedFees, error) {
+			return breez_sdk_spark.RecommendedFees{FastestFee: fastest, HalfHourFee: fastest, HourFee: minimum, EconomyFee: minimum, MinimumFee: minimum}, nil
+		}
+	}
+	a := &fakeBackend{fees: feesOf(20, 1)}
+	b := &fakeBackend{fees: feesOf(500, 2)} // a wild estimate
+	c := &fakeBackend{fees: feesOf(25, 3)}
+	m := newService(t, 0, a, b, c)
+
+	fees, err := m.RecommendedFees(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (breez_sdk_spark.RecommendedFees{FastestFee: 25, HalfHourFee: 25, HourFee: 2, EconomyFee: 2, MinimumFee: 2}); fees != want {
+		t.Fatalf("fees = %+v, want %+v", fees, want)
+	}
+
+	c.fees = func() (breez_sdk_spark.RecommendedFees, error) { return breez_sdk_spark.RecommendedFees{}, errDown }
+	fees, err = m.RecommendedFees(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fees.FastestFee != 500 || fees.MinimumFee != 2 {
+		t.Fatalf("two answers: fees = %+v, want the higher middle values", fees)
+	}
+
+	a.fees, b.fees = c.fees, c.fees
+	if _, err := m.RecommendedFees(context.Background()); !errors.Is(err, breez_sdk_spark.ErrChainServiceErrorServiceConnectivity) {
+		t.Fatalf("no answers: got %v", err)
+	}
+}
+
+func TestTransactionStatusQuorum(t *testing.T) {
+	type answer func(context.Context) (breez_sdk_spark.TxStatus, error)
+	says := func(s breez_sdk_spark.TxStatus) answer {
+		return func(context.Context) (breez_sdk_spark.TxStatus, error) { return s, nil }
+	}
+	fails := func(err error) answer {
+		return func(context.Context) (breez_sdk_spark.TxStatus, error) { return breez_sdk_spark.TxStatus{}, err }
+	}
+	// hangs answers only once the call is cancelled, so a test using it
+	// only finishes if the quorum is reached without it.
+	hangs := func(ctx context.Context) (breez_sdk_spark.TxStatus, error) {
+		<-ctx.Done()
+		return breez_sdk_spark.TxStatus{}, breez_sdk_spark.NewChainServiceErrorServiceConnectivity(ctx.Err().Error())
+	}
+	unconfirmed := breez_sdk_spark.TxStatus{}
+
+	cases := []struct {
+		name    string
+		quorum  int
+		answers []answer
+		want    *breez_sdk_spark.TxStatus
+		wantErr error
+		errText string
+	}{
+		{name: "majority", answers: []answer{says(confirmedAt(100)), says(unconfirmed), says(confirmedAt(100))}, want: ptr(confirmedAt(100))},
+		{name: "slow backend", answers: []answer{says(unconfirmed), hangs, says(unconfirmed)}, want: &unconfirmed},
+		{name: "quorum of one", quorum: 1, answers: []answer{hangs, says(confirmedAt(7))}, want: ptr(confirmedAt(7))},
+		{
+			name:    "reorg",
+			answers: []answer{says(confirmedAt(100)), says(confirmedAt(101)), says(unconfirmed)},
+			wantErr: breez_sdk_spark.ErrChainServiceErrorGeneric, errText: "no quorum",
+		},
+		{
+			name:    "too few answers",
+			answers: []answer{says(confirmedAt(100)), fails(errDown), fails(errDown)},
+			wantErr: breez_sdk_spark.ErrChainServiceErrorServiceConnectivity, errText: "1 of 3 backends answered",
+		},
+		{
+			name:    "unknown to some",
+			answers: []answer{says(confirmedAt(100)), fails(errUnknown), fails(errDown)},
+			wantErr: breez_sdk_spark.ErrChainServiceErrorGeneric,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakes := make([]*fakeBackend, len(tc.answers))
+			for i, a := range tc.answers {
+				fakes[i] = &fakeBackend{status: a}
+			}
+			status, err := newService(t, tc.quorum, fakes...).GetTransactionStatus(context.Background(), "aa")
+			if tc.want != nil {
+				if err != nil || !sameStatus(status, *tc.want) {
+					t.Fatalf("status = %+v, %v; want %+v", status, err, *tc.want)
+				}
+				return
+			}
+			if !errors.Is(err, tc.wantErr) || !strings.Contains(err.Error(), tc.errText) {
+				t.Fatalf("got %v, want %v containing %q", err, tc.wantErr, tc.errText)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestPlainBackend(t *testing.T) {
+	plain := breez_sdk_spark.NewBitcoinChainServiceFromCtx(&fakeBackend{hex: hexFrom("0300", nil)})
+	m, err := New(Config{Backends: []Backend{{Name: "plain", Service: breez_sdk_spark.AsBitcoinChainServiceCtx(plain)}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex, err := m.GetTransactionHex(context.Background(), "aa"); err != nil || hex != "0300" {
+		t.Fatalf("hex = %q, %v", hex, err)
+	}
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	ok := Backend{Name: "a", Service: &fakeBackend{}}
+	for name, cfg := range map[string]Config{
+		"no backends":     {},
+		"nil service":     {Backends: []Backend{ok, {Name: "b"}}},
+		"quorum too high": {Backends: []Backend{ok}, Quorum: 2},
+		"negative quorum": {Backends: []Backend{ok}, Quorum: -1},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("%s: New succeeded", name)
+		}
+	}
+	m, err := New(Config{Backends: []Backend{ok, ok, ok, ok}})
+	if err != nil || m.quorum != 3 {
+		t.Fatalf("default quorum of four backends = %v, %v", m.quorum, err)
+	}
+}
@@ -0,0 +1,300 @@
+// Package multifiat combines several fiat rate providers, so that a single
+// provider glitching does not make donation goals shown in USD or EUR jump
+// around on the overlay.
+//
+// Every provider is asked for its rates. For each currency the median of
+// the answers is taken, answers too far from it are dropped as outliers and
+// the median of the rest is returned. Currency metadata is merged across
+// the providers that have some:
+//
+//	rates, err := multifiat.New(multifiat.Config{Sources: []multifiat.Source{
+//		{Name: "breez", Provider: breez_sdk_spark.AsFiatServiceCtx(defaultFiat)},
+//		{Name: "coingecko", Provider: coingecko},
+//		{Name: "kraken", Provider: kraken},
+//	}})
+//	...
+//	builder.WithFiatService(breez_sdk_spark.NewFiatServiceFromCtx(rates))
+package multifiat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// DefaultMaxDeviation is how far, relative to the median, a rate may be
+// from it before it is dropped as an outlier.
+const DefaultMaxDeviation = 0.05
+
+// Provider is a source of rates. Every breez_sdk_spark.FiatServiceCtx is a
+// Provider; a price feed that only has rates needs nothing more.
+type Provider interface {
+	FetchFiatRates(ctx context.Context) ([]breez_sdk_spark.Rate, error)
+}
+
+// CurrencyProvider is implemented by providers that also describe their
+// currencies.
+type CurrencyProvider interface {
+	FetchFiatCurrencies(ctx context.Context) ([]breez_sdk_spark.FiatCurrency, error)
+}
+
+// Source is one provider behind a Service.
+type Source struct {
+	// Name identifies the source in errors and logs.
+	Name     string
+	Provider Provider
+}
+
+// Config configures a Service.
+type Config struct {
+	// Sources in order of preference, which decides whose metadata wins
+	// when currencies are merged. At least one is required.
+	Sources []Source
+	// MaxDeviation defaults to DefaultMaxDeviation. A negative value keeps
+	// every rate.
+	MaxDeviation float64
+	// MinSources is the number of sources that must agree on a rate, once
+	// outliers are dropped, for it to be returned. Defaults to 1.
+	MinSources int
+	// Logger receives a warning for every outlier and a debug record for
+	// every source failure. Defaults to no logging.
+	Logger *slog.Logger
+}
+
+// Service is a breez_sdk_spark.FiatServiceCtx aggregating several sources.
+type Service struct {
+	sources      []Source
+	maxDeviation float64
+	minSources   int
+	logger       *slog.Logger
+}
+
+var _ breez_sdk_spark.FiatServiceCtx = (*Service)(nil)
+
+// New returns a Service for cfg.
+func New(cfg Config) (*Service, error) {
+	if len(cfg.Sources) == 0 {
+		return nil, errors.New("multifiat: no sources")
+	}
+	for i, src := range cfg.Sources {
+		if src.Provider == nil {
+			return nil, fmt.Errorf("multifiat: source %d (%q) has no provider", i, src.Name)
+		}
+	}
+	s := &Service{
+		sources:      slices.Clone(cfg.Sources),
+		maxDeviation: cfg.MaxDeviation,
+		minSources:   cfg.MinSources,
+		logger:       cfg.Logger,
+	}
+	if s.maxDeviation == 0 {
+		s.maxDeviation = DefaultMaxDeviation
+	}
+	if s.minSources == 0 {
+		s.minSources = 1
+	}
+	if s.minSources < 1 || s.minSources > len(s.sources) {
+		return nil, fmt.Errorf("multifiat: MinSources %d out of range for %d sources", cfg.MinSources, len(s.sources))
+	}
+	if s.logger == nil {
+		s.logger = slog.New(slog.DiscardHandler)
+	}
+	return s, nil
+}
+
+// FetchFiatRates asks every source and returns, for each currency, the
+// median of the rates that are not outliers. Currencies that too few
+// sources agree on are left out. It fails only if no source answers.
+func (s *Service) FetchFiatRates(ctx context.Context) ([]breez_sdk_spark.Rate, error) {
+	results := fanOut(ctx, s, "FetchFiatRates", func(ctx context.Context, p Provider) ([]breez_sdk_spark.Rate, error) {
+		return p.FetchFiatRates(ctx)
+	})
+	type quote struct {
+		source string
+		value  float64
+	}
+	quotes := make(map[string][]quote)
+	var errs []error
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		for _, rate := range r.value {
+			if rate.Value <= 0 || math.IsInf(rate.Value, 0) || math.IsNaN(rate.Value) {
+				s.logger.WarnContext(ctx, "ignoring invalid fiat rate", "source", r.name, "coin", rate.Coin, "value", rate.Value)
+				continue
+			}
+			quotes[rate.Coin] = append(quotes[rate.Coin], quote{r.name, rate.Value})
+		}
+	}
+	if len(errs) == len(results) {
+		return nil, combine("FetchFiatRates", errs)
+	}
+
+	rates := make([]breez_sdk_spark.Rate, 0, len(quotes))
+	for coin, qs := range quotes {
+		values := make([]float64, len(qs))
+		for i, q := range qs {
+			values[i] = q.value
+		}
+		m := median(values)
+		kept := values[:0]
+		for _, q := range qs {
+			if s.maxDeviation >= 0 && math.Abs(q.value-m)/m > s.maxDeviation {
+				s.logger.WarnContext(ctx, "dropping outlier fiat rate", "source", q.source, "coin", coin, "value", q.value, "median", m)
+				continue
+			}
+			kept = append(kept, q.value)
+		}
+		if len(kept) < s.minSources {
+			s.logger.WarnContext(ctx, "too few sources agree on fiat rate", "coin", coin, "agreeing", len(kept), "quotes", len(qs))
+			continue
+		}
+		rates = append(rates, breez_sdk_spark.Rate{Coin: coin, Value: median(kept)})
+	}
+	slices.SortFunc(rates, func(a, b breez_sdk_spark.Rate) int { return strings.Compare(a.Coin, b.Coin) })
+	return rates, nil
+}
+
+// FetchFiatCurrencies asks every source that is a CurrencyProvider and
+// merges their answers. A currency is described by the first source, in
+// preference order, that knows it; fields it leaves empty, and localized
+// names and locale overrides for other locales, are taken from the
+// following sources. It fails only if no such source answers.
+func (s *Service) FetchFiatCurrencies(ctx context.Context) ([]breez_sdk_spark.FiatCurrency, error) {
+	results := fanOut(ctx, s, "FetchFiatCurrencies", func(ctx context.Context, p Provider) ([]breez_sdk_spark.FiatCurrency, error) {
+		cp, ok := p.(CurrencyProvider)
+		if !ok {
+			return nil, errNoCurrencies
+		}
+		return cp.FetchFiatCurrencies(ctx)
+	})
+	var merged []breez_sdk_spark.FiatCurrency
+	index := make(map[string]int)
+	var errs []error
+	answered := false
+	for _, r := range results {
+		if errors.Is(r.err, errNoCurrencies) {
+			continue
+		}
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		answered = true
+		for _, c := range r.value {
+			if i, ok := index[c.Id]; ok {
+				merged[i].Info = mergeInfo(merged[i].Info, c.Info)
+				continue
+			}
+			index[c.Id] = len(merged)
+			c.Info.LocalizedName = slices.Clone(c.Info.LocalizedName)
+			c.Info.LocaleOverrides = slices.Clone(c.Info.LocaleOverrides)
+			merged = append(merged, c)
+		}
+	}
+	if !answered && len(errs) > 0 {
+		return nil, combine("FetchFiatCurrencies", errs)
+	}
+	if !answered {
+		return nil, breez_sdk_spark.NewServiceConnectivityErrorOther("multifiat: FetchFiatCurrencies: no source provides currencies")
+	}
+	return merged, nil
+}
+
+var errNoCurrencies = errors.New("not a CurrencyProvider")
+
+// mergeInfo fills the gaps of a with b.
+func mergeInfo(a, b breez_sdk_spark.CurrencyInfo) breez_sdk_spark.CurrencyInfo {
+	if a.Name == "" {
+		a.Name = b.Name
+	}
+	if a.Spacing == nil {
+		a.Spacing = b.Spacing
+	}
+	if a.Symbol == nil {
+		a.Symbol = b.Symbol
+	}
+	if a.UniqSymbol == nil {
+		a.UniqSymbol = b.UniqSymbol
+	}
+	for _, n := range b.LocalizedName {
+		if !slices.ContainsFunc(a.LocalizedName, func(m breez_sdk_spark.LocalizedName) bool { return m.Locale == n.Locale }) {
+			a.LocalizedName = append(a.LocalizedName, n)
+		}
+	}
+	for _, o := range b.LocaleOverrides {
+		if !slices.ContainsFunc(a.LocaleOverrides, func(p breez_sdk_spark.LocaleOverrides) bool { return p.Locale == o.Locale }) {
+			a.LocaleOverrides = append(a.LocaleOverrides, o)
+		}
+	}
+	return a
+}
+
+// median returns the median of values, the mean of the two middle ones
+// for an even count. It reorders values.
+func median(values []float64) float64 {
+	slices.Sort(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+type result[T any] struct {
+	name  string
+	value T
+	err   error
+}
+
+// fanOut calls every source concurrently and returns their results in
+// source order. Errors are prefixed with the source name.
+func fanOut[T any](ctx context.Context, s *Service, op string, fn func(context.Context, Provider) (T, error)) []result[T] {
+	results := make([]result[T], len(s.sources))
+	done := make(chan struct{}, len(s.sources))
+	for i, src := range s.sources {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			v, err := fn(ctx, src.Provider)
+			if err != nil && !errors.Is(err, errNoCurrencies) {
+				s.logger.DebugContext(ctx, "fiat source failed", "source", src.Name, "op", op, "err", err)
+				err = fmt.Errorf("%s: %w", src.Name, err)
+			}
+			results[i] = result[T]{name: src.Name, value: v, err: err}
+		}()
+	}
+	for range s.sources {
+		<-done
+	}
+	return results
+}
+
+// combine reports that every source failed op. The error keeps the variant
+// of the sources' errors when they all share it.
+func combine(op string, errs []error) error {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	msg := fmt.Sprintf("multifiat: %s: all sources failed: %s", op, strings.Join(msgs, "; "))
+	for _, v := range []struct {
+		target error
+		wrap   func(string) *breez_sdk_spark.ServiceConnectivityError
+	}{
+		{breez_sdk_spark.ErrServiceConnectivityErrorTimeout, breez_sdk_spark.NewServiceConnectivityErrorTimeout},
+		{breez_sdk_spark.ErrServiceConnectivityErrorConnect, breez_sdk_spark.NewServiceConnectivityErrorConnect},
+	} {
+		if !slices.ContainsFunc(errs, func(err error) bool { return !errors.Is(err, v.target) }) {
+			return v.wrap(msg)
+		}
+	}
+	return breez_sdk_spark.NewServiceConnectivityErrorOther(msg)
+}
@@ -0,0 +1,189 @@
+package multifiat
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+var (
+	errDown    = breez_sdk_spark.NewServiceConnectivityErrorConnect("connection refused")
+	errTimeout = breez_sdk_spark.NewServiceConnectivityErrorTimeout("deadline exceeded")
+)
+
+// feed is a Provider with rates only.
+type feed struct {
+	rates map[string]float64
+	err   error
+}
+
+func (f feed) FetchFiatRates(context.Context) ([]breez_sdk_spark.Rate, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var rates []breez_sdk_spark.Rate
+	for coin, v := range f.rates {
+		rates = append(rates, breez_sdk_spark.Rate{Coin: coin, Value: v})
+	}
+	return rates, nil
+}
+
+// fullFeed also describes its currencies.
+type fullFeed struct {
+	feed
+	currencies []breez_sdk_spark.FiatCurrency
+	currErr    error
+}
+
+func (f fullFeed) FetchFiatCurrencies(context.Context) ([]breez_sdk_spark.FiatCurrency, error) {
+	return f.currencies, f.currErr
+}
+
+func newService(t *testing.T, cfg Config, providers ...Provider) *Service {
+	t.Helper()
+	for i, p := range providers {
+		cfg.Sources = append(cfg.Sources, Source{Name: string(rune('a' + i)), Provider: p})
+	}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func ratesOf(t *testing.T, s *Service) map[string]float64 {
+	t.Helper()
+	rates, err := s.FetchFiatRates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := make(map[string]float64)
+	for i, r := range rates {
+		if i > 0 && rates[i-1].Coin >= r.Coin {
+			t.Fatalf("rates not sorted: %+v", rates)
+		}
+		m[r.Coin] = r.Value
+	}
+	return m
+}
+
+func TestMedianAndOutliers(t *testing.T) {
+	s := newService(t, Config{},
+		feed{rates: map[string]float64{"USD": 97_000, "EUR": 89_000}},
+		feed{rates: map[string]float64{"USD": 97_400, "EUR": 89_200, "JPY": 14_500_000}},
+		// A glitching source: its USD rate is off by a factor of 10.
+		feed{rates: map[string]float64{"USD": 9_700, "EUR": 89_100}},
+		feed{rates: map[string]float64{"USD": 97_200, "EUR": -1}},
+		feed{err: errDown},
+	)
+	got := ratesOf(t, s)
+	want := map[string]float64{"USD": 97_200, "EUR": 89_100, "JPY": 14_500_000}
+	if len(got) != len(want) {
+		t.Fatalf("rates = %v", got)
+	}
+	for coin, v := range want {
+		if got[coin] != v {
+			t.Errorf("%s = %v, want %v", coin, got[coin], v)
+		}
+	}
+}
+
+func TestEvenCount(t *testing.T) {
+	s := newService(t, Config{},
+		feed{rates: map[string]float64{"USD": 100_000}},
+		feed{rates: map[string]float64{"USD": 101_000}},
+	)
+	if got := ratesOf(t, s); got["USD"] != 100_500 {
+		t.Fatalf("USD = %v", got["USD"])
+	}
+
+	// Two sources too far apart: there is no telling which is right.
+	s = newService(t, Config{},
+		feed{rates: map[string]float64{"USD": 100_000, "EUR": 90_000}},
+		feed{rates: map[string]float64{"USD": 200_000, "EUR": 90_000}},
+	)
+	if got := ratesOf(t, s); len(got) != 1 || got["EUR"] != 90_000 {
+		t.Fatalf("rates = %v", got)
+	}
+	s = newService(t, Config{MaxDeviation: -1},
+		feed{rates: map[string]float64{"USD": 100_000}},
+		feed{rates: map[string]float64{"USD": 200_000}},
+	)
+	if got := ratesOf(t, s); got["USD"] != 150_000 {
+		t.Fatalf("USD = %v with outlier rejection off", got["USD"])
+	}
+}
+
+func TestMinSources(t *testing.T) {
+	s := newService(t, Config{MinSources: 2},
+		feed{rates: map[string]float64{"USD": 97_000, "EUR": 89_000}},
+		feed{rates: map[string]float64{"USD": 97_100}},
+		feed{rates: map[string]float64{"USD": 50_000, "EUR": 45_000}},
+	)
+	if got := ratesOf(t, s); len(got) != 1 || got["USD"] != 97_050 {
+		t.Fatalf("rates = %v", got)
+	}
+	if _, err := New(Config{Sources: s.sources, MinSources: 4}); err == nil {
+		t.Fatal("accepted MinSources above the number of sources")
+	}
+}
+
+func TestAllFail(t *testing.T) {
+	s := newService(t, Config{}, feed{err: errDown}, feed{err: errDown})
+	_, err := s.FetchFiatRates(context.Background())
+	if !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorConnect) || !strings.Contains(err.Error(), "b: ") {
+		t.Fatalf("got %v", err)
+	}
+	s = newService(t, Config{}, feed{err: errDown}, feed{err: errTimeout})
+	if _, err := s.FetchFiatRates(context.Background()); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorOther) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestCurrencies(t *testing.T) {
+	dollar, euro, eur := "$", "€", "EUR"
+	space := uint32(1)
+	s := newService(t, Config{},
+		feed{rates: map[string]float64{"USD": 97_000}},
+		fullFeed{currencies: []breez_sdk_spark.FiatCurrency{
+			{Id: "USD", Info: breez_sdk_spark.CurrencyInfo{Name: "US Dollar", FractionSize: 2,
+				LocalizedName: []breez_sdk_spark.LocalizedName{{Locale: "fr", Name: "dollar américain"}}}},
+		}},
+		fullFeed{currErr: errDown},
+		fullFeed{currencies: []breez_sdk_spark.FiatCurrency{
+			{Id: "USD", Info: breez_sdk_spark.CurrencyInfo{Name: "Dollar", FractionSize: 0, Symbol: &breez_sdk_spark.Symbol{Grapheme: &dollar},
+				LocalizedName: []breez_sdk_spark.LocalizedName{{Locale: "fr", Name: "dollar"}, {Locale: "de", Name: "US-Dollar"}}}},
+			{Id: "EUR", Info: breez_sdk_spark.CurrencyInfo{Name: "Euro", FractionSize: 2, Spacing: &space,
+				Symbol: &breez_sdk_spark.Symbol{Grapheme: &euro}, UniqSymbol: &breez_sdk_spark.Symbol{Grapheme: &eur}}},
+		}},
+	)
+	currencies, err := s.FetchFiatCurrencies(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(currencies) != 2 || currencies[0].Id != "USD" || currencies[1].Id != "EUR" {
+		t.Fatalf("currencies = %+v", currencies)
+	}
+	usd := currencies[0].Info
+	if usd.Name != "US Dollar" || usd.FractionSize != 2 || usd.Symbol == nil || *usd.Symbol.Grapheme != "$" {
+		t.Fatalf("USD = %+v", usd)
+	}
+	if len(usd.LocalizedName) != 2 || usd.LocalizedName[0].Name != "dollar américain" || usd.LocalizedName[1].Locale != "de" {
+		t.Fatalf("USD names = %+v", usd.LocalizedName)
+	}
+	if eur := currencies[1].Info; *eur.Spacing != 1 || *eur.UniqSymbol.Grapheme != "EUR" {
+		t.Fatalf("EUR = %+v", eur)
+	}
+
+	s = newService(t, Config{}, feed{rates: map[string]float64{"USD": 97_000}})
+	if _, err := s.FetchFiatCurrencies(context.Background()); err == nil {
+		t.Fatal("no currency source, no error")
+	}
+	s = newService(t, Config{}, feed{}, fullFeed{currErr: errTimeout})
+	if _, err := s.FetchFiatCurrencies(context.Background()); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorTimeout) {
+		t.Fatalf("got %v", err)
+	}
+}
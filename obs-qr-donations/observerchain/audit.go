@@ -0,0 +1,228 @@
+package observerchain
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/sdkutil"
+)
+
+// ErrTampered is returned for audit logs whose hash chain is broken.
+var ErrTampered = errors.New("observerchain: audit log tampered with")
+
+// genesis is the Prev of the first entry of a log.
+var genesis = strings.Repeat("0", 64)
+
+// MinKeySize is the shortest audit log key accepted.
+const MinKeySize = 16
+
+// Entry is a decision in the audit log.
+type Entry struct {
+	// Seq numbers the entries from 1.
+	Seq      uint64    `json:"seq"`
+	Time     time.Time `json:"time"`
+	Payments []Payment `json:"payments"`
+	Allowed  bool      `json:"allowed"`
+	// Passed lists the observers that allowed the payments, in order.
+	Passed []string `json:"passed,omitempty"`
+	// VetoedBy and Reason are the observer that refused the payments and
+	// its error.
+	VetoedBy string `json:"vetoed_by,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// Prev is the Hash of the previous entry, and Hash the hex
+	// HMAC-SHA256, under the log's key, of this entry encoded with an empty
+	// Hash. Without the key, changing, removing or reordering entries
+	// breaks the chain.
+	Prev string `json:"prev"`
+	Hash string `json:"hash"`
+}
+
+// Payment describes a payment of an Entry.
+type Payment = sdkutil.Payment
+
+func (e Entry) digest(key []byte) (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// AuditLog is an append-only file of hash-chained entries, one JSON
+// object per line. The hashes are keyed, so only holders of the key can
+// write entries that verify: keep the key apart from the log, e.g. in the
+// OS keychain. The chain shows tampering with the entries, not their
+// truncation from the end: keep the latest hash elsewhere, e.g. by
+// logging Head periodically, to detect that too.
+type AuditLog struct {
+	now func() time.Time
+	key []byte
+
+	mu   sync.Mutex
+	f    *os.File
+	seq  uint64
+	head string
+	// size is the length of the file up to the last entry.
+	size int64
+}
+
+// OpenAuditLog opens the log at path, creating it if needed, with key of
+// at least MinKeySize bytes. An existing log is verified first, and one
+// with a broken chain is refused, as is one whose last entry was cut short
+// by a crash: that entry must be looked at and removed by hand.
+func OpenAuditLog(path string, key []byte) (*AuditLog, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("observerchain: %w", err)
+	}
+	last, err := verify(f, key)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("observerchain: %w", err)
+	}
+	return &AuditLog{now: time.Now, key: bytes.Clone(key), f: f, seq: last.Seq, head: last.Hash, size: size}, nil
+}
+
+// VerifyAuditLog checks the chain of the log at path against key and
+// returns its number of entries.
+func VerifyAuditLog(path string, key []byte) (uint64, error) {
+	if err := checkKey(key); err != nil {
+		return 0, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("observerchain: %w", err)
+	}
+	defer f.Close()
+	last, err := verify(f, key)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+	return last.Seq, nil
+}
+
+func checkKey(key []byte) error {
+	if len(key) < MinKeySize {
+		return fmt.Errorf("observerchain: audit log key must be at least %d bytes", MinKeySize)
+	}
+	return nil
+}
+
+// verify reads a log and returns its last entry, or one with the genesis
+// hash when it is empty.
+func verify(r io.Reader, key []byte) (Entry, error) {
+	last := Entry{Hash: genesis}
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) == 0 && err == io.EOF {
+			return last, nil
+		}
+		if err != nil && err != io.EOF {
+			return Entry{}, err
+		}
+		if err == io.EOF {
+			// Every entry is written with its newline.
+			return Entry{}, fmt.Errorf("%w: entry %d is truncated", ErrTampered, last.Seq+1)
+		}
+		var e Entry
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&e); err != nil {
+			return Entry{}, fmt.Errorf("%w: entry %d: %v", ErrTampered, last.Seq+1, err)
+		}
+		hash, err := e.digest(key)
+		if err != nil {
+			return Entry{}, err
+		}
+		if e.Seq != last.Seq+1 || e.Prev != last.Hash || !hmac.Equal([]byte(e.Hash), []byte(hash)) {
+			return Entry{}, fmt.Errorf("%w at entry %d", ErrTampered, last.Seq+1)
+		}
+		last = e
+	}
+}
+
+// Append chains e to the log and writes it to disk before returning. Its
+// Seq, Time, Prev and Hash are set by the log.
+func (l *AuditLog) Append(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return errors.New("observerchain: audit log closed")
+	}
+	e.Seq = l.seq + 1
+	e.Time = l.now().UTC().Round(0)
+	e.Prev = l.head
+	hash, err := e.digest(l.key)
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = l.f.Write(data)
+	if err == nil {
+		err = l.f.Sync()
+	}
+	if err != nil {
+		// A partial entry would break the chain for the next one.
+		l.f.Truncate(l.size)
+		return fmt.Errorf("observerchain: %w", err)
+	}
+	l.seq, l.head = e.Seq, e.Hash
+	l.size += int64(len(data))
+	return nil
+}
+
+// Head returns the number of entries and the hash of the last one.
+func (l *AuditLog) Head() (uint64, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seq, l.head
+}
+
+// Close closes the file.
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+func describe(payments []breez_sdk_spark.ProvisionalPayment) []Payment {
+	described := make([]Payment, 0, len(payments))
+	for _, p := range payments {
+		described = append(described, sdkutil.Describe(p))
+	}
+	return described
+}
@@ -0,0 +1,171 @@
+// Package observerchain combines payment observers. The SDK takes a
+// single PaymentObserver; a ChainObserver runs several in order, so that
+// spend limits, screening and approval stay separate pieces, and records
+// every decision in a tamper-evident AuditLog:
+//
+//	audit, err := observerchain.OpenAuditLog("payments-audit.jsonl", auditKey)
+//	...
+//	chain, err := observerchain.New(observerchain.Config{
+//		Observers: []observerchain.Link{
+//			{Name: "limits", Observer: limits},
+//			{Name: "screening", Observer: screening},
+//			{Name: "approval", Observer: queue},
+//		},
+//		Audit: audit,
+//	})
+//	...
+//	builder.WithPaymentObserver(chain)
+package observerchain
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// ErrAudit refuses payments whose decision could not be written to the
+// audit log: a payment that leaves no trace is not allowed.
+var ErrAudit = errors.New("observerchain: audit log unavailable")
+
+// Link is an observer of a chain, with the name decisions report it by.
+type Link struct {
+	Name     string
+	Observer breez_sdk_spark.PaymentObserver
+}
+
+// VetoObserver is implemented by observers that keep state about the
+// payments they allow, such as spending caps. When a later link refuses
+// payments, or their decision cannot be audited, the chain calls Vetoed on
+// the links that allowed them, so they can forget them again.
+type VetoObserver interface {
+	Vetoed(payments []breez_sdk_spark.ProvisionalPayment)
+}
+
+// Config configures a ChainObserver.
+type Config struct {
+	// Observers are run in order. Names must be unique.
+	Observers []Link
+	// Audit, when set, receives every decision.
+	Audit *AuditLog
+	// Logger receives a warning for every veto. Defaults to no logging.
+	Logger *slog.Logger
+}
+
+// ChainObserver is a breez_sdk_spark.PaymentObserver allowing payments
+// that all of its observers allow.
+type ChainObserver struct {
+	links  []Link
+	audit  *AuditLog
+	logger *slog.Logger
+}
+
+var _ breez_sdk_spark.PaymentObserver = (*ChainObserver)(nil)
+
+// New returns a ChainObserver for cfg.
+func New(cfg Config) (*ChainObserver, error) {
+	seen := make(map[string]bool)
+	for _, l := range cfg.Observers {
+		if l.Name == "" || l.Observer == nil {
+			return nil, errors.New("observerchain: observers need a name and an observer")
+		}
+		if seen[l.Name] {
+			return nil, fmt.Errorf("observerchain: observer %q listed twice", l.Name)
+		}
+		seen[l.Name] = true
+	}
+	c := &ChainObserver{links: cfg.Observers, audit: cfg.Audit, logger: cfg.Logger}
+	if c.logger == nil {
+		c.logger = slog.New(slog.DiscardHandler)
+	}
+	return c, nil
+}
+
+// BeforeSend runs the observers in order and stops at the first that
+// refuses the payments, returning a *VetoError naming it. An observer that
+// panics refuses. The links that allowed refused payments are told
+// through VetoObserver.
+func (c *ChainObserver) BeforeSend(payments []breez_sdk_spark.ProvisionalPayment) error {
+	entry := Entry{Payments: describe(payments), Allowed: true}
+	var veto *VetoError
+	var passed []Link
+	for _, l := range c.links {
+		if err := run(l.Observer, payments); err != nil {
+			veto = &VetoError{Observer: l.Name, Err: err}
+			entry.Allowed, entry.VetoedBy, entry.Reason = false, l.Name, err.Error()
+			c.logger.Warn("payment vetoed", "observer", l.Name, "err", err)
+			break
+		}
+		entry.Passed = append(entry.Passed, l.Name)
+		passed = append(passed, l)
+	}
+	if c.audit != nil {
+		if err := c.audit.Append(entry); err != nil {
+			c.logger.Error("payment decision not audited", "err", err)
+			if veto == nil {
+				c.vetoed(passed, payments)
+				return &auditError{err: err, sdk: breez_sdk_spark.NewPaymentObserverErrorGeneric(fmt.Sprintf("%v: %v", ErrAudit, err))}
+			}
+		}
+	}
+	if veto != nil {
+		c.vetoed(passed, payments)
+		return veto
+	}
+	return nil
+}
+
+// vetoed tells the links that allowed refused payments about the refusal.
+func (c *ChainObserver) vetoed(passed []Link, payments []breez_sdk_spark.ProvisionalPayment) {
+	for _, l := range passed {
+		v, ok := l.Observer.(VetoObserver)
+		if !ok {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					c.logger.Error("observer panicked on veto", "observer", l.Name, "panic", r)
+				}
+			}()
+			v.Vetoed(payments)
+		}()
+	}
+}
+
+// run calls o, turning a panic into a refusal.
+func run(o breez_sdk_spark.PaymentObserver, payments []breez_sdk_spark.ProvisionalPayment) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = breez_sdk_spark.NewPaymentObserverErrorGeneric(fmt.Sprintf("observer panicked: %v", r))
+		}
+	}()
+	return o.BeforeSend(payments)
+}
+
+// VetoError is the error of the observer that refused payments. It unwraps
+// to that error, and to a *breez_sdk_spark.PaymentObserverError when the
+// observer returned another kind of error, so that the SDK always gets
+// one.
+type VetoError struct {
+	// Observer is the name of the link that refused.
+	Observer string
+	Err      error
+}
+
+func (e *VetoError) Error() string {
+	return fmt.Sprintf("vetoed by %s: %v", e.Observer, e.Err)
+}
+
+func (e *VetoError) Unwrap() []error {
+	return []error{e.Err, breez_sdk_spark.NewPaymentObserverErrorGeneric(e.Error())}
+}
+
+type auditError struct {
+	err error
+	sdk error
+}
+
+func (e *auditError) Error() string   { return fmt.Sprintf("%v: %v", ErrAudit, e.err) }
+func (e *auditError) Unwrap() []error { return []error{ErrAudit, e.err, e.sdk} }
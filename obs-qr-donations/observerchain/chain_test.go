@@ -0,0 +1,231 @@
+package observerchain
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/approval"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/spendpolicy"
+)
+
+// observer refuses with err, after counting its calls.
+type observer struct {
+	calls int
+	err   error
+	panic bool
+}
+
+func (o *observer) BeforeSend([]breez_sdk_spark.ProvisionalPayment) error {
+	o.calls++
+	if o.panic {
+		panic("boom")
+	}
+	return o.err
+}
+
+// vetoObserver allows payments and counts the vetoes it is told of.
+type vetoObserver struct {
+	observer
+	vetoed int
+}
+
+func (o *vetoObserver) Vetoed([]breez_sdk_spark.ProvisionalPayment) { o.vetoed++ }
+
+var payments = []breez_sdk_spark.ProvisionalPayment{{
+	PaymentId: "p1",
+	Amount:    big.NewInt(21_000),
+	Details:   breez_sdk_spark.ProvisionalPaymentDetailsSpark{PayRequest: "spark1editor"},
+}}
+
+var auditKey = []byte("0123456789abcdef")
+
+func openLog(t *testing.T) (*AuditLog, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := OpenAuditLog(path, auditKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l, path
+}
+
+func TestChain(t *testing.T) {
+	limits, screening, approval := &observer{}, &observer{}, &observer{}
+	audit, path := openLog(t)
+	c, err := New(Config{
+		Observers: []Link{{"limits", limits}, {"screening", screening}, {"approval", approval}},
+		Audit:     audit,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.BeforeSend(payments); err != nil {
+		t.Fatal(err)
+	}
+	screening.err = breez_sdk_spark.NewPaymentObserverErrorServiceConnectivity("screening list unavailable")
+	err = c.BeforeSend(payments)
+	var veto *VetoError
+	if !errors.As(err, &veto) || veto.Observer != "screening" {
+		t.Fatalf("got %v", err)
+	}
+	// The observer's own error reaches the SDK.
+	if !errors.Is(err, breez_sdk_spark.ErrPaymentObserverErrorServiceConnectivity) {
+		t.Fatalf("lost the observer's error: %v", err)
+	}
+	if limits.calls != 2 || screening.calls != 2 || approval.calls != 1 {
+		t.Fatalf("calls: %d %d %d", limits.calls, screening.calls, approval.calls)
+	}
+
+	// Errors that are not PaymentObserverErrors are made into one.
+	screening.err = errors.New("sanctioned address")
+	err = c.BeforeSend(payments)
+	var sdkErr *breez_sdk_spark.PaymentObserverError
+	if !errors.As(err, &sdkErr) || !errors.Is(err, breez_sdk_spark.ErrPaymentObserverErrorGeneric) {
+		t.Fatalf("got %v", err)
+	}
+
+	screening.err, limits.panic = nil, true
+	if err := c.BeforeSend(payments); !errors.As(err, &veto) || veto.Observer != "limits" || !strings.Contains(err.Error(), "panicked") {
+		t.Fatalf("panic: %v", err)
+	}
+
+	if n, err := VerifyAuditLog(path, auditKey); err != nil || n != 4 {
+		t.Fatalf("verify: %d, %v", n, err)
+	}
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i, want := range []string{
+		`"allowed":true,"passed":["limits","screening","approval"]`,
+		`"allowed":false,"passed":["limits"],"vetoed_by":"screening"`,
+		`"reason":"sanctioned address"`,
+		`"allowed":false,"vetoed_by":"limits"`,
+	} {
+		if !strings.Contains(lines[i], want) || !strings.Contains(lines[i], `"payment_id":"p1","kind":"spark","amount":"21000"`) {
+			t.Errorf("entry %d = %s", i+1, lines[i])
+		}
+	}
+
+	if _, err := New(Config{Observers: []Link{{"a", limits}, {"a", screening}}}); err == nil {
+		t.Fatal("accepted duplicate names")
+	}
+}
+
+func TestVetoReleasesLimits(t *testing.T) {
+	limits, err := spendpolicy.New(spendpolicy.Config{Rules: spendpolicy.Rules{DailySats: 30_000}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := make(chan approval.Request, 1)
+	queue := approval.New(approval.Config{Notify: requests})
+	defer queue.Close()
+	audit, _ := openLog(t)
+	c, err := New(Config{Observers: []Link{{"limits", limits}, {"approval", queue}}, Audit: audit})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decide := func(approve bool) {
+		r := <-requests
+		if approve {
+			queue.Approve(r.ID, "streamer")
+		} else {
+			queue.Reject(r.ID, "streamer", "not today")
+		}
+	}
+	go decide(false)
+	if err := c.BeforeSend(payments); !errors.Is(err, approval.ErrRejected) {
+		t.Fatalf("got %v", err)
+	}
+	// The rejected payment no longer counts against the cap...
+	if u := limits.Usage(); u.DailySats != 0 {
+		t.Fatalf("rejected payment counted: %+v", u)
+	}
+	// ...so the next one fits, and counts once approved.
+	go decide(true)
+	if err := c.BeforeSend(payments); err != nil {
+		t.Fatal(err)
+	}
+	if u := limits.Usage(); u.DailySats != 21_000 {
+		t.Fatalf("usage %+v", u)
+	}
+}
+
+func TestAuditFailureRefuses(t *testing.T) {
+	audit, _ := openLog(t)
+	audit.Close()
+	limits := &vetoObserver{}
+	c, _ := New(Config{Observers: []Link{{"limits", limits}}, Audit: audit})
+	err := c.BeforeSend(payments)
+	var sdkErr *breez_sdk_spark.PaymentObserverError
+	if !errors.Is(err, ErrAudit) || !errors.As(err, &sdkErr) {
+		t.Fatalf("got %v", err)
+	}
+	if limits.vetoed != 1 {
+		t.Fatalf("told of %d vetoes", limits.vetoed)
+	}
+}
+
+func TestAuditLogTampering(t *testing.T) {
+	audit, path := openLog(t)
+	for _, allowed := range []bool{true, false, true} {
+		if err := audit.Append(Entry{Payments: describe(payments), Allowed: allowed}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	seq, head := audit.Head()
+	audit.Close()
+
+	// Reopening continues the chain.
+	audit, err := OpenAuditLog(path, auditKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, h := audit.Head(); s != seq || h != head {
+		t.Fatalf("head after reopening: %d %s", s, h)
+	}
+	audit.Append(Entry{Allowed: true})
+	audit.Close()
+	if n, err := VerifyAuditLog(path, auditKey); err != nil || n != 4 {
+		t.Fatalf("verify: %d, %v", n, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.SplitAfter(string(data), "\n")[:4]
+	for name, tampered := range map[string]string{
+		"edited":    lines[0] + strings.Replace(lines[1], `"allowed":false`, `"allowed":true`, 1) + lines[2] + lines[3],
+		"removed":   lines[0] + lines[2] + lines[3],
+		"reordered": lines[0] + lines[2] + lines[1] + lines[3],
+		"truncated": lines[0] + lines[1] + lines[2][:40],
+		"rehashed":  lines[0] + strings.Replace(lines[1], `"hash":"`, `"hash":"00`, 1)[:len(lines[1])] + "\n" + lines[2],
+	} {
+		os.WriteFile(path, []byte(tampered), 0o600)
+		if _, err := VerifyAuditLog(path, auditKey); !errors.Is(err, ErrTampered) {
+			t.Errorf("%s: got %v", name, err)
+		}
+		if _, err := OpenAuditLog(path, auditKey); !errors.Is(err, ErrTampered) {
+			t.Errorf("%s: opened", name)
+		}
+	}
+
+	// A chain rewritten without the key does not verify.
+	os.Remove(path)
+	forged, err := OpenAuditLog(path, []byte("not the audit key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged.Append(Entry{Allowed: true})
+	forged.Close()
+	if _, err := VerifyAuditLog(path, auditKey); !errors.Is(err, ErrTampered) {
+		t.Errorf("forged: got %v", err)
+	}
+	if _, err := OpenAuditLog(path, []byte("short")); err == nil {
+		t.Error("accepted a short key")
+	}
+}
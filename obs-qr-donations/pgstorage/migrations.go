@@ -0,0 +1,101 @@
+package pgstorage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockID is the advisory lock key that keeps several servers
+// starting at once from applying the same migration twice.
+const migrationLockID = 0x6272657a // "brez"
+
+// migrations are applied in order and recorded in schema_migrations. Never
+// edit a released migration, append a new one.
+var migrations = []string{
+	`CREATE TABLE payments (
+		id               TEXT PRIMARY KEY,
+		payment_type     TEXT NOT NULL,
+		status           TEXT NOT NULL,
+		amount           TEXT NOT NULL,
+		fees             TEXT NOT NULL,
+		timestamp        BIGINT NOT NULL,
+		method           INTEGER NOT NULL,
+		is_token         BOOLEAN NOT NULL,
+		token_identifier TEXT,
+		htlc_status      TEXT,
+		invoice          TEXT,
+		payment_hash     TEXT,
+		data             BYTEA NOT NULL
+	);
+	CREATE INDEX payments_timestamp_idx ON payments (timestamp, id);
+	CREATE INDEX payments_type_status_idx ON payments (payment_type, status, timestamp);
+	CREATE INDEX payments_token_idx ON payments (is_token, token_identifier, timestamp);
+	CREATE INDEX payments_htlc_status_idx ON payments (htlc_status) WHERE htlc_status IS NOT NULL;
+	CREATE INDEX payments_invoice_idx ON payments (invoice) WHERE invoice IS NOT NULL;
+	CREATE INDEX payments_payment_hash_idx ON payments (payment_hash) WHERE payment_hash IS NOT NULL;
+
+	CREATE TABLE payment_metadata (
+		payment_id          TEXT PRIMARY KEY,
+		lnurl_pay_info      BYTEA,
+		lnurl_withdraw_info BYTEA,
+		lnurl_description   TEXT
+	);
+
+	CREATE TABLE unclaimed_deposits (
+		txid         TEXT NOT NULL,
+		vout         BIGINT NOT NULL,
+		amount_sats  BIGINT NOT NULL,
+		claim_error  BYTEA,
+		refund_tx    TEXT,
+		refund_tx_id TEXT,
+		PRIMARY KEY (txid, vout)
+	);
+
+	CREATE TABLE cached_items (
+		key   TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);
+
+	CREATE TABLE lnurl_receive_metadata (
+		payment_hash      TEXT PRIMARY KEY,
+		nostr_zap_request TEXT,
+		nostr_zap_receipt TEXT,
+		sender_comment    TEXT
+	);`,
+}
+
+// migrate brings the schema up to date. The whole run holds a transaction
+// scoped advisory lock, so concurrent callers wait for each other.
+func migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`CREATE TABLE IF NOT EXISTS schema_migrations (
+				version    INTEGER PRIMARY KEY,
+				applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+			)`); err != nil {
+			return err
+		}
+		var version int
+		if err := tx.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+			return err
+		}
+		if version > len(migrations) {
+			return fmt.Errorf("database schema version %d is newer than supported version %d", version, len(migrations))
+		}
+		for i := version; i < len(migrations); i++ {
+			if _, err := tx.Exec(ctx, migrations[i]); err != nil {
+				return fmt.Errorf("migration %d: %w", i+1, err)
+			}
+			if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", i+1); err != nil {
+				return fmt.Errorf("migration %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,402 @@
+// Package pgstorage implements the SDK Storage interface on PostgreSQL so
+// several overlay servers can share one wallet database.
+//
+// The schema mirrors sqlitestorage: payments are stored as their uniffi
+// encoding next to indexed columns for every ListPaymentsRequest filter.
+package pgstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/storageutil"
+)
+
+// Storage is a PostgreSQL backed breez_sdk_spark.Storage. Its methods run
+// without a deadline; hand the SDK the context-aware view instead, so that
+// queries stop when the SDK gives up on a call:
+//
+//	builder.WithStorage(breez_sdk_spark.NewStorageFromCtx(s.Ctx()))
+type Storage struct {
+	pool *pgxpool.Pool
+	// ownsPool is set when New created the pool, so Close releases it.
+	ownsPool bool
+}
+
+var _ breez_sdk_spark.Storage = (*Storage)(nil)
+
+// storageCtx implements the queries of a Storage.
+type storageCtx struct {
+	pool *pgxpool.Pool
+}
+
+var _ breez_sdk_spark.StorageCtx = storageCtx{}
+
+// New connects to connString and migrates the schema.
+func New(ctx context.Context, connString string) (*Storage, error) {
+	pool, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+	}
+	s, err := NewWithPool(ctx, pool)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	s.ownsPool = true
+	return s, nil
+}
+
+// NewWithPool migrates the schema reachable through pool and returns a
+// Storage using it. The caller keeps ownership of pool.
+func NewWithPool(ctx context.Context, pool *pgxpool.Pool) (*Storage, error) {
+	if err := migrate(ctx, pool); err != nil {
+		return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+	}
+	return &Storage{pool: pool}, nil
+}
+
+// Pool exposes the connection pool for reporting queries.
+func (s *Storage) Pool() *pgxpool.Pool {
+	return s.pool
+}
+
+// Close releases the pool if New created it.
+func (s *Storage) Close() {
+	if s.ownsPool {
+		s.pool.Close()
+	}
+}
+
+// Ctx returns the view of s whose queries are cancelled with their context.
+func (s *Storage) Ctx() breez_sdk_spark.StorageCtx {
+	return storageCtx{s.pool}
+}
+
+func (s *Storage) DeleteCachedItem(key string) error {
+	return s.Ctx().DeleteCachedItem(context.Background(), key)
+}
+
+func (s *Storage) GetCachedItem(key string) (*string, error) {
+	return s.Ctx().GetCachedItem(context.Background(), key)
+}
+
+func (s *Storage) SetCachedItem(key string, value string) error {
+	return s.Ctx().SetCachedItem(context.Background(), key, value)
+}
+
+func (s *Storage) ListPayments(request breez_sdk_spark.ListPaymentsRequest) ([]breez_sdk_spark.Payment, error) {
+	return s.Ctx().ListPayments(context.Background(), request)
+}
+
+func (s *Storage) InsertPayment(payment breez_sdk_spark.Payment) error {
+	return s.Ctx().InsertPayment(context.Background(), payment)
+}
+
+func (s *Storage) SetPaymentMetadata(paymentId string, metadata breez_sdk_spark.PaymentMetadata) error {
+	return s.Ctx().SetPaymentMetadata(context.Background(), paymentId, metadata)
+}
+
+func (s *Storage) GetPaymentById(id string) (breez_sdk_spark.Payment, error) {
+	return s.Ctx().GetPaymentById(context.Background(), id)
+}
+
+func (s *Storage) GetPaymentByInvoice(invoice string) (*breez_sdk_spark.Payment, error) {
+	return s.Ctx().GetPaymentByInvoice(context.Background(), invoice)
+}
+
+func (s *Storage) AddDeposit(txid string, vout uint32, amountSats uint64) error {
+	return s.Ctx().AddDeposit(context.Background(), txid, vout, amountSats)
+}
+
+func (s *Storage) DeleteDeposit(txid string, vout uint32) error {
+	return s.Ctx().DeleteDeposit(context.Background(), txid, vout)
+}
+
+func (s *Storage) ListDeposits() ([]breez_sdk_spark.DepositInfo, error) {
+	return s.Ctx().ListDeposits(context.Background())
+}
+
+func (s *Storage) UpdateDeposit(txid string, vout uint32, payload breez_sdk_spark.UpdateDepositPayload) error {
+	return s.Ctx().UpdateDeposit(context.Background(), txid, vout, payload)
+}
+
+func (s *Storage) SetLnurlMetadata(metadata []breez_sdk_spark.SetLnurlMetadataItem) error {
+	return s.Ctx().SetLnurlMetadata(context.Background(), metadata)
+}
+
+func (s storageCtx) DeleteCachedItem(ctx context.Context, key string) error {
+	_, err := s.pool.Exec(ctx, "DELETE FROM cached_items WHERE key = $1", key)
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) GetCachedItem(ctx context.Context, key string) (*string, error) {
+	var value string
+	err := s.pool.QueryRow(ctx, "SELECT value FROM cached_items WHERE key = $1", key).Scan(&value)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, storageutil.StorageErr(err)
+	}
+	return &value, nil
+}
+
+func (s storageCtx) SetCachedItem(ctx context.Context, key string, value string) error {
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO cached_items (key, value) VALUES ($1, $2)
+		 ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		key, value)
+	return storageutil.StorageErr(err)
+}
+
+const selectPayments = `SELECT p.data,
+	m.lnurl_pay_info, m.lnurl_withdraw_info, m.lnurl_description,
+	r.payment_hash, r.nostr_zap_request, r.nostr_zap_receipt, r.sender_comment
+FROM payments p
+LEFT JOIN payment_metadata m ON m.payment_id = p.id
+LEFT JOIN lnurl_receive_metadata r ON r.payment_hash = p.payment_hash`
+
+func (s storageCtx) ListPayments(ctx context.Context, request breez_sdk_spark.ListPaymentsRequest) ([]breez_sdk_spark.Payment, error) {
+	q := storageutil.BuildPaymentQuery(request, "p.", func(n int) string { return "$" + strconv.Itoa(n) })
+	rows, err := s.pool.Query(ctx, selectPayments+q.Where+q.OrderLimit, q.Args...)
+	if err != nil {
+		return nil, storageutil.StorageErr(err)
+	}
+	defer rows.Close()
+
+	var payments []breez_sdk_spark.Payment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, storageutil.StorageErr(err)
+		}
+		payments = append(payments, p)
+	}
+	return payments, storageutil.StorageErr(rows.Err())
+}
+
+func (s storageCtx) InsertPayment(ctx context.Context, payment breez_sdk_spark.Payment) error {
+	idx := storageutil.IndexPayment(payment)
+	var htlcStatus *string
+	if idx.HtlcStatus != nil {
+		name := storageutil.HtlcStatusName(*idx.HtlcStatus)
+		htlcStatus = &name
+	}
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO payments (id, payment_type, status, amount, fees, timestamp, method,
+			is_token, token_identifier, htlc_status, invoice, payment_hash, data)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		 ON CONFLICT (id) DO UPDATE SET
+			payment_type = excluded.payment_type,
+			status = excluded.status,
+			amount = excluded.amount,
+			fees = excluded.fees,
+			timestamp = excluded.timestamp,
+			method = excluded.method,
+			is_token = excluded.is_token,
+			token_identifier = excluded.token_identifier,
+			htlc_status = excluded.htlc_status,
+			invoice = excluded.invoice,
+			payment_hash = excluded.payment_hash,
+			data = excluded.data`,
+		payment.Id,
+		storageutil.PaymentTypeName(payment.PaymentType),
+		storageutil.PaymentStatusName(payment.Status),
+		amountString(payment.Amount),
+		amountString(payment.Fees),
+		int64(payment.Timestamp),
+		int32(payment.Method),
+		idx.IsToken,
+		idx.TokenIdentifier,
+		htlcStatus,
+		idx.Invoice,
+		idx.PaymentHash,
+		storageutil.EncodePayment(payment),
+	)
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) SetPaymentMetadata(ctx context.Context, paymentId string, metadata breez_sdk_spark.PaymentMetadata) error {
+	// COALESCE keeps fields from earlier calls that this one leaves unset.
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO payment_metadata AS m (payment_id, lnurl_pay_info, lnurl_withdraw_info, lnurl_description)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (payment_id) DO UPDATE SET
+			lnurl_pay_info = COALESCE(excluded.lnurl_pay_info, m.lnurl_pay_info),
+			lnurl_withdraw_info = COALESCE(excluded.lnurl_withdraw_info, m.lnurl_withdraw_info),
+			lnurl_description = COALESCE(excluded.lnurl_description, m.lnurl_description)`,
+		paymentId,
+		storageutil.EncodeOptionalLnurlPayInfo(metadata.LnurlPayInfo),
+		storageutil.EncodeOptionalLnurlWithdrawInfo(metadata.LnurlWithdrawInfo),
+		metadata.LnurlDescription,
+	)
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) GetPaymentById(ctx context.Context, id string) (breez_sdk_spark.Payment, error) {
+	p, err := scanPayment(s.pool.QueryRow(ctx, selectPayments+" WHERE p.id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return breez_sdk_spark.Payment{}, storageutil.ErrPaymentNotFound(id)
+	}
+	return p, storageutil.StorageErr(err)
+}
+
+func (s storageCtx) GetPaymentByInvoice(ctx context.Context, invoice string) (*breez_sdk_spark.Payment, error) {
+	p, err := scanPayment(s.pool.QueryRow(ctx,
+		selectPayments+" WHERE p.invoice = $1 ORDER BY p.timestamp DESC LIMIT 1", invoice))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, storageutil.StorageErr(err)
+	}
+	return &p, nil
+}
+
+// AddDeposit upserts the deposit, keeping any claim error or refund already
+// recorded for it by another instance.
+func (s storageCtx) AddDeposit(ctx context.Context, txid string, vout uint32, amountSats uint64) error {
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO unclaimed_deposits (txid, vout, amount_sats) VALUES ($1, $2, $3)
+			 ON CONFLICT (txid, vout) DO UPDATE SET amount_sats = excluded.amount_sats`,
+			txid, int64(vout), int64(amountSats))
+		return err
+	})
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) DeleteDeposit(ctx context.Context, txid string, vout uint32) error {
+	_, err := s.pool.Exec(ctx,
+		"DELETE FROM unclaimed_deposits WHERE txid = $1 AND vout = $2", txid, int64(vout))
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) ListDeposits(ctx context.Context) ([]breez_sdk_spark.DepositInfo, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT txid, vout, amount_sats, claim_error, refund_tx, refund_tx_id
+		 FROM unclaimed_deposits ORDER BY txid, vout`)
+	if err != nil {
+		return nil, storageutil.StorageErr(err)
+	}
+	defer rows.Close()
+
+	var deposits []breez_sdk_spark.DepositInfo
+	for rows.Next() {
+		var (
+			d          breez_sdk_spark.DepositInfo
+			vout       int64
+			amount     int64
+			claimError []byte
+		)
+		if err := rows.Scan(&d.Txid, &vout, &amount, &claimError, &d.RefundTx, &d.RefundTxId); err != nil {
+			return nil, storageutil.StorageErr(err)
+		}
+		d.Vout = uint32(vout)
+		d.AmountSats = uint64(amount)
+		if d.ClaimError, err = storageutil.DecodeOptionalDepositClaimError(claimError); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, d)
+	}
+	return deposits, storageutil.StorageErr(rows.Err())
+}
+
+// UpdateDeposit upserts the claim error or refund of a deposit. A deposit
+// not seen by this instance yet is created with a zero amount, which a later
+// AddDeposit fills in.
+func (s storageCtx) UpdateDeposit(ctx context.Context, txid string, vout uint32, payload breez_sdk_spark.UpdateDepositPayload) error {
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		switch p := payload.(type) {
+		case breez_sdk_spark.UpdateDepositPayloadClaimError:
+			_, err := tx.Exec(ctx,
+				`INSERT INTO unclaimed_deposits (txid, vout, amount_sats, claim_error) VALUES ($1, $2, 0, $3)
+				 ON CONFLICT (txid, vout) DO UPDATE SET claim_error = excluded.claim_error`,
+				txid, int64(vout), storageutil.EncodeDepositClaimError(p.Error))
+			return err
+		case breez_sdk_spark.UpdateDepositPayloadRefund:
+			_, err := tx.Exec(ctx,
+				`INSERT INTO unclaimed_deposits (txid, vout, amount_sats, refund_tx, refund_tx_id) VALUES ($1, $2, 0, $3, $4)
+				 ON CONFLICT (txid, vout) DO UPDATE SET refund_tx = excluded.refund_tx, refund_tx_id = excluded.refund_tx_id`,
+				txid, int64(vout), p.RefundTx, p.RefundTxid)
+			return err
+		default:
+			return fmt.Errorf("unknown deposit update %T", payload)
+		}
+	})
+	return storageutil.StorageErr(err)
+}
+
+// SetLnurlMetadata stores all items in one round trip and one transaction,
+// so either every item is written or none is.
+func (s storageCtx) SetLnurlMetadata(ctx context.Context, metadata []breez_sdk_spark.SetLnurlMetadataItem) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, item := range metadata {
+			batch.Queue(
+				`INSERT INTO lnurl_receive_metadata (payment_hash, nostr_zap_request, nostr_zap_receipt, sender_comment)
+				 VALUES ($1, $2, $3, $4)
+				 ON CONFLICT (payment_hash) DO UPDATE SET
+					nostr_zap_request = excluded.nostr_zap_request,
+					nostr_zap_receipt = excluded.nostr_zap_receipt,
+					sender_comment = excluded.sender_comment`,
+				item.PaymentHash, item.NostrZapRequest, item.NostrZapReceipt, item.SenderComment)
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	return storageutil.StorageErr(err)
+}
+
+func scanPayment(row pgx.Row) (breez_sdk_spark.Payment, error) {
+	var (
+		data         []byte
+		payInfo      []byte
+		withdrawInfo []byte
+		description  *string
+		receiveHash  *string
+		metadata     breez_sdk_spark.PaymentMetadata
+		receive      breez_sdk_spark.LnurlReceiveMetadata
+	)
+	if err := row.Scan(&data, &payInfo, &withdrawInfo, &description,
+		&receiveHash, &receive.NostrZapRequest, &receive.NostrZapReceipt, &receive.SenderComment); err != nil {
+		return breez_sdk_spark.Payment{}, err
+	}
+	p, err := storageutil.DecodePayment(data)
+	if err != nil {
+		return p, err
+	}
+	if metadata.LnurlPayInfo, err = storageutil.DecodeOptionalLnurlPayInfo(payInfo); err != nil {
+		return p, err
+	}
+	if metadata.LnurlWithdrawInfo, err = storageutil.DecodeOptionalLnurlWithdrawInfo(withdrawInfo); err != nil {
+		return p, err
+	}
+	metadata.LnurlDescription = description
+	var metadataPtr *breez_sdk_spark.PaymentMetadata
+	if payInfo != nil || withdrawInfo != nil || description != nil {
+		metadataPtr = &metadata
+	}
+	var receivePtr *breez_sdk_spark.LnurlReceiveMetadata
+	if receiveHash != nil {
+		receivePtr = &receive
+	}
+	return storageutil.ApplyMetadata(p, metadataPtr, receivePtr), nil
+}
+
+func amountString(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
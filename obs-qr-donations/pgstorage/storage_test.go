@@ -0,0 +1,308 @@
+package pgstorage
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/storagetest"
+)
+
+// The suite talks to PGSTORAGE_TEST_DSN when set. Otherwise it spawns a
+// throwaway server with the initdb and postgres binaries found in PG_BIN or
+// on PATH, and skips when neither is available.
+
+var (
+	serverDSN  string
+	serverSkip string
+	databaseID atomic.Int32
+)
+
+func TestMain(m *testing.M) {
+	stop := func() {}
+	if dsn := os.Getenv("PGSTORAGE_TEST_DSN"); dsn != "" {
+		serverDSN = dsn
+	} else {
+		dsn, stopServer, err := startPostgres()
+		if err != nil {
+			serverSkip = err.Error()
+		} else {
+			serverDSN, stop = dsn, stopServer
+		}
+	}
+	code := m.Run()
+	stop()
+	os.Exit(code)
+}
+
+func pgBinary(name string) (string, error) {
+	if dir := os.Getenv("PG_BIN"); dir != "" {
+		return filepath.Join(dir, name), nil
+	}
+	return exec.LookPath(name)
+}
+
+func startPostgres() (string, func(), error) {
+	initdb, err := pgBinary("initdb")
+	if err != nil {
+		return "", nil, fmt.Errorf("no PGSTORAGE_TEST_DSN and no initdb: %w", err)
+	}
+	postgres, err := pgBinary("postgres")
+	if err != nil {
+		return "", nil, fmt.Errorf("no postgres binary: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "pgstorage-test-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	out, err := exec.Command(initdb, "-D", filepath.Join(dir, "data"), "-U", "postgres", "-A", "trust", "--no-sync").CombinedOutput()
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("initdb: %v: %s", err, out)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	cmd := exec.Command(postgres, "-D", filepath.Join(dir, "data"), "-p", fmt.Sprint(port),
+		"-k", dir, "-c", "listen_addresses=127.0.0.1", "-c", "fsync=off")
+	if err := cmd.Start(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	stop := func() {
+		cmd.Process.Signal(syscall.SIGINT)
+		cmd.Wait()
+		cleanup()
+	}
+
+	dsn := fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?sslmode=disable", port)
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		conn, err := pgx.Connect(context.Background(), dsn)
+		if err == nil {
+			conn.Close(context.Background())
+			return dsn, stop, nil
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return "", nil, fmt.Errorf("postgres did not come up: %w", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// newDatabase creates an empty database for one test and returns its DSN.
+func newDatabase(t *testing.T) string {
+	t.Helper()
+	if serverSkip != "" {
+		t.Skip(serverSkip)
+	}
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, serverDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close(ctx)
+
+	name := fmt.Sprintf("pgstorage_test_%d_%d", os.Getpid(), databaseID.Add(1))
+	if _, err := conn.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn, err := pgx.Connect(ctx, serverDSN)
+		if err != nil {
+			return
+		}
+		defer conn.Close(ctx)
+		conn.Exec(ctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)")
+	})
+
+	cfg, err := pgx.ParseConfig(serverDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("postgres://%s@%s:%d/%s?sslmode=disable", cfg.User, cfg.Host, cfg.Port, name)
+}
+
+func newStorage(t *testing.T) *Storage {
+	t.Helper()
+	s, err := New(context.Background(), newDatabase(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestStorageSuite(t *testing.T) {
+	storagetest.RunStorageSuite(t, func(t *testing.T) breez_sdk_spark.Storage {
+		return newStorage(t)
+	})
+}
+
+func TestIndexesUsed(t *testing.T) {
+	s := newStorage(t)
+	var n int
+	err := s.Pool().QueryRow(context.Background(),
+		"SELECT count(*) FROM pg_indexes WHERE tablename = 'payments' AND indexname LIKE 'payments_%_idx'").Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n < 6 {
+		t.Fatalf("expected filter indexes on payments, found %d", n)
+	}
+}
+
+func TestConcurrentMigrations(t *testing.T) {
+	dsn := newDatabase(t)
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := New(context.Background(), dsn)
+			if err != nil {
+				errs <- err
+				return
+			}
+			s.Close()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestDepositUpserts(t *testing.T) {
+	s := newStorage(t)
+	claimErr := breez_sdk_spark.DepositClaimErrorGeneric{Message: "fee too high"}
+
+	// Another instance may record the claim error before this one sees the deposit.
+	if err := s.UpdateDeposit("tx", 0, breez_sdk_spark.UpdateDepositPayloadClaimError{Error: claimErr}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddDeposit("tx", 0, 5000); err != nil {
+		t.Fatal(err)
+	}
+	deposits, err := s.ListDeposits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 1 || deposits[0].AmountSats != 5000 || deposits[0].ClaimError == nil {
+		t.Fatalf("unexpected deposits %+v", deposits)
+	}
+
+	if err := s.UpdateDeposit("tx", 0, breez_sdk_spark.UpdateDepositPayloadRefund{RefundTxid: "rid", RefundTx: "rhex"}); err != nil {
+		t.Fatal(err)
+	}
+	deposits, _ = s.ListDeposits()
+	if deposits[0].RefundTxId == nil || *deposits[0].RefundTxId != "rid" || deposits[0].ClaimError == nil {
+		t.Fatalf("refund did not merge: %+v", deposits[0])
+	}
+
+	// Concurrent instances adding the same deposit must not conflict.
+	other, err := NewWithPool(context.Background(), s.Pool())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func(st *Storage) {
+			defer wg.Done()
+			if err := st.AddDeposit("tx2", 1, 100); err != nil {
+				t.Error(err)
+			}
+		}([]*Storage{s, other}[i%2])
+	}
+	wg.Wait()
+	deposits, _ = s.ListDeposits()
+	if len(deposits) != 2 {
+		t.Fatalf("expected 2 deposits, got %d", len(deposits))
+	}
+
+	if err := s.DeleteDeposit("tx", 0); err != nil {
+		t.Fatal(err)
+	}
+	deposits, _ = s.ListDeposits()
+	if len(deposits) != 1 || deposits[0].Txid != "tx2" {
+		t.Fatalf("unexpected deposits after delete %+v", deposits)
+	}
+}
+
+func TestSetLnurlMetadataBatch(t *testing.T) {
+	s := newStorage(t)
+	details := breez_sdk_spark.PaymentDetailsLightning{Invoice: "lnbc1", PaymentHash: "hash1"}
+	if err := s.InsertPayment(storagetest.Payment("ln", 1, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, details)); err != nil {
+		t.Fatal(err)
+	}
+
+	gg, ok := "gg", "ok"
+	items := make([]breez_sdk_spark.SetLnurlMetadataItem, 0, 50)
+	for i := range 50 {
+		items = append(items, breez_sdk_spark.SetLnurlMetadataItem{PaymentHash: fmt.Sprintf("hash%d", i), SenderComment: &gg})
+	}
+	if err := s.SetLnurlMetadata(items); err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.GetPaymentByInvoice("lnbc1")
+	if err != nil || p == nil {
+		t.Fatalf("payment by invoice: %v %v", p, err)
+	}
+	ln := (*p.Details).(breez_sdk_spark.PaymentDetailsLightning)
+	if ln.LnurlReceiveMetadata == nil || *ln.LnurlReceiveMetadata.SenderComment != "gg" {
+		t.Fatalf("receive metadata not joined: %+v", ln.LnurlReceiveMetadata)
+	}
+
+	// A NUL byte is rejected by Postgres; the whole batch must roll back.
+	bad := []breez_sdk_spark.SetLnurlMetadataItem{
+		{PaymentHash: "fresh", SenderComment: &ok},
+		{PaymentHash: "broken\x00"},
+	}
+	if err := s.SetLnurlMetadata(bad); err == nil {
+		t.Fatal("expected batch to fail")
+	}
+	var n int
+	if err := s.Pool().QueryRow(context.Background(), "SELECT count(*) FROM lnurl_receive_metadata WHERE payment_hash = 'fresh'").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatal("partial batch was committed")
+	}
+}
+
+func TestCtxCancelsQueries(t *testing.T) {
+	s := newStorage(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Ctx().ListPayments(ctx, breez_sdk_spark.ListPaymentsRequest{}); err == nil {
+		t.Fatal("query ran with a cancelled context")
+	}
+	if err := s.Ctx().AddDeposit(ctx, "tx", 0, 1000); err == nil {
+		t.Fatal("transaction ran with a cancelled context")
+	}
+	if deposits, err := s.ListDeposits(); err != nil || len(deposits) != 0 {
+		t.Fatalf("got %v, %v", deposits, err)
+	}
+}
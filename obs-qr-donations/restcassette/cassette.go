@@ -0,0 +1,328 @@
+// Package restcassette records the HTTP exchanges of an SDK RestClient to a
+// file and replays them, so that LNURL flows (Parse, PrepareLnurlPay,
+// LnurlPay, LnurlWithdraw, Lightning address checks) can be tested offline
+// and reproducibly.
+//
+// Record once against the real services:
+//
+//	live, _ := httprest.New(httprest.Config{})
+//	c, err := restcassette.Open(restcassette.Config{
+//		Path:  "testdata/lnurl_pay.json",
+//		Mode:  restcassette.ModeRecord,
+//		Inner: live,
+//	})
+//	builder.WithLnurlClient(breez_sdk_spark.NewRestClientFromCtx(c))
+//
+// then replay in CI with Mode left at ModeReplay and no Inner.
+package restcassette
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// Redacted replaces the values of redacted headers in the cassette.
+const Redacted = "REDACTED"
+
+// DefaultRedactHeaders are the headers redacted when Config.RedactHeaders
+// is nil.
+var DefaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key"}
+
+// Mode selects where responses come from.
+type Mode int
+
+const (
+	// ModeReplay answers from the cassette only. Requests it has no
+	// interaction for fail.
+	ModeReplay Mode = iota
+	// ModeRecord sends every request to Inner and records it, starting
+	// from an empty cassette.
+	ModeRecord
+	// ModeReplayOrRecord answers from the cassette when it can, and sends
+	// and records the other requests.
+	ModeReplayOrRecord
+)
+
+// Config configures a Cassette.
+type Config struct {
+	// Path of the cassette file. It must exist in ModeReplay.
+	Path string
+	Mode Mode
+	// Inner sends the requests that are recorded. Required unless Mode is
+	// ModeReplay. Plain breez_sdk_spark.RestClient values can be used
+	// through breez_sdk_spark.AsRestClientCtx.
+	Inner breez_sdk_spark.RestClientCtx
+	// RedactHeaders lists, case-insensitively, the request headers whose
+	// values are not written to the cassette. Defaults to
+	// DefaultRedactHeaders; an empty non-nil slice redacts nothing.
+	RedactHeaders []string
+	// Redact, when set, is applied to every interaction before it is
+	// recorded, e.g. to mask secrets in bodies. It is also applied to
+	// requests before they are matched, so that masked requests still
+	// replay. Its changes do not affect the response returned to the
+	// caller.
+	Redact func(*Interaction)
+}
+
+// Interaction is one recorded exchange.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Requests match when their method, URL and
+// body are equal; headers are recorded for reference only.
+type Request struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    *string           `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status uint16 `json:"status"`
+	Body   string `json:"body"`
+}
+
+type file struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Cassette is a breez_sdk_spark.RestClientCtx recording to or replaying
+// from a file. It is safe for concurrent use, though replaying concurrent
+// identical requests makes their order, and so their answers, arbitrary.
+type Cassette struct {
+	path   string
+	mode   Mode
+	inner  breez_sdk_spark.RestClientCtx
+	redact map[string]bool
+	hook   func(*Interaction)
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+var _ breez_sdk_spark.RestClientCtx = (*Cassette)(nil)
+
+// Open returns a Cassette for cfg, loading the file unless recording from
+// scratch.
+func Open(cfg Config) (*Cassette, error) {
+	if cfg.Mode != ModeReplay && cfg.Inner == nil {
+		return nil, errors.New("restcassette: recording needs an Inner client")
+	}
+	c := &Cassette{path: cfg.Path, mode: cfg.Mode, inner: cfg.Inner, hook: cfg.Redact, redact: make(map[string]bool)}
+	headers := cfg.RedactHeaders
+	if headers == nil {
+		headers = DefaultRedactHeaders
+	}
+	for _, h := range headers {
+		c.redact[http.CanonicalHeaderKey(h)] = true
+	}
+	if cfg.Mode == ModeRecord {
+		return c, nil
+	}
+	data, err := os.ReadFile(cfg.Path)
+	if errors.Is(err, os.ErrNotExist) && cfg.Mode == ModeReplayOrRecord {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("restcassette: %w", err)
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("restcassette: %s: %w", cfg.Path, err)
+	}
+	c.interactions = f.Interactions
+	c.used = make([]bool, len(f.Interactions))
+	return c, nil
+}
+
+// GetRequest answers a GET request.
+func (c *Cassette) GetRequest(ctx context.Context, url string, headers *map[string]string) (breez_sdk_spark.RestResponse, error) {
+	return c.do(Request{Method: http.MethodGet, URL: url, Headers: deref(headers)}, func() (breez_sdk_spark.RestResponse, error) {
+		return c.inner.GetRequest(ctx, url, headers)
+	})
+}
+
+// PostRequest answers a POST request.
+func (c *Cassette) PostRequest(ctx context.Context, url string, headers *map[string]string, body *string) (breez_sdk_spark.RestResponse, error) {
+	return c.do(Request{Method: http.MethodPost, URL: url, Headers: deref(headers), Body: body}, func() (breez_sdk_spark.RestResponse, error) {
+		return c.inner.PostRequest(ctx, url, headers, body)
+	})
+}
+
+// DeleteRequest answers a DELETE request.
+func (c *Cassette) DeleteRequest(ctx context.Context, url string, headers *map[string]string, body *string) (breez_sdk_spark.RestResponse, error) {
+	return c.do(Request{Method: http.MethodDelete, URL: url, Headers: deref(headers), Body: body}, func() (breez_sdk_spark.RestResponse, error) {
+		return c.inner.DeleteRequest(ctx, url, headers, body)
+	})
+}
+
+// Unused returns the recorded interactions that were not replayed, for
+// tests to check that a flow made every request it used to.
+func (c *Cassette) Unused() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var unused []Interaction
+	for i, used := range c.used {
+		if !used {
+			unused = append(unused, c.interactions[i])
+		}
+	}
+	return unused
+}
+
+func (c *Cassette) do(req Request, send func() (breez_sdk_spark.RestResponse, error)) (breez_sdk_spark.RestResponse, error) {
+	if c.mode != ModeRecord {
+		if resp, ok := c.replay(req); ok {
+			return resp, nil
+		}
+		if c.mode == ModeReplay {
+			return breez_sdk_spark.RestResponse{}, breez_sdk_spark.NewServiceConnectivityErrorOther(
+				fmt.Sprintf("restcassette: no recorded interaction for %s %s in %s", req.Method, req.URL, c.path))
+		}
+	}
+	resp, err := send()
+	if err != nil {
+		// Failures are not recorded: replaying them would hide that the
+		// cassette is incomplete.
+		return resp, err
+	}
+	if err := c.record(req, resp); err != nil {
+		return breez_sdk_spark.RestResponse{}, breez_sdk_spark.NewServiceConnectivityErrorOther(err.Error())
+	}
+	return resp, nil
+}
+
+// replay returns the response of the first unused interaction matching
+// req, so that repeated requests get their answers in recorded order. Once
+// all are used, the last one keeps being returned.
+func (c *Cassette) replay(req Request) (breez_sdk_spark.RestResponse, bool) {
+	if c.hook != nil {
+		in := Interaction{Request: clone(req)}
+		c.hook(&in)
+		req = in.Request
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last := -1
+	for i, in := range c.interactions {
+		if !matches(in.Request, req) {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return toSDK(in.Response), true
+		}
+		last = i
+	}
+	if last < 0 {
+		return breez_sdk_spark.RestResponse{}, false
+	}
+	return toSDK(c.interactions[last].Response), true
+}
+
+// record appends the exchange and rewrites the file.
+func (c *Cassette) record(req Request, resp breez_sdk_spark.RestResponse) error {
+	in := Interaction{Request: clone(req), Response: Response{Status: resp.Status, Body: resp.Body}}
+	for k := range in.Request.Headers {
+		if c.redact[http.CanonicalHeaderKey(k)] {
+			in.Request.Headers[k] = Redacted
+		}
+	}
+	if c.hook != nil {
+		c.hook(&in)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, in)
+	// What was just recorded has been answered.
+	c.used = append(c.used, true)
+	return c.save()
+}
+
+// save writes the cassette atomically. It must be called with mu held.
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(file{Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("restcassette: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), "."+filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("restcassette: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("restcassette: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("restcassette: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("restcassette: %w", err)
+	}
+	return nil
+}
+
+func matches(recorded, req Request) bool {
+	return strings.EqualFold(recorded.Method, req.Method) && recorded.URL == req.URL && sameBody(recorded.Body, req.Body)
+}
+
+// sameBody compares bodies, as JSON values when both are JSON so that key
+// order and whitespace do not matter.
+func sameBody(a, b *string) bool {
+	if a == nil || b == nil {
+		// A missing body and an empty one make the same request.
+		return (a == nil || *a == "") && (b == nil || *b == "")
+	}
+	if *a == *b {
+		return true
+	}
+	var va, vb any
+	if json.Unmarshal([]byte(*a), &va) != nil || json.Unmarshal([]byte(*b), &vb) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
+
+func toSDK(r Response) breez_sdk_spark.RestResponse {
+	return breez_sdk_spark.RestResponse{Status: r.Status, Body: r.Body}
+}
+
+// clone returns a copy of req that can be changed without affecting it.
+func clone(req Request) Request {
+	req.Headers = maps.Clone(req.Headers)
+	if req.Body != nil {
+		body := *req.Body
+		req.Body = &body
+	}
+	return req
+}
+
+func deref(headers *map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	return *headers
+}
@@ -0,0 +1,198 @@
+package restcassette
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// server stands in for LNURL services: it answers with the request and a
+// counter, so that every answer is distinct.
+type server struct {
+	calls atomic.Int32
+	fail  bool
+}
+
+func (s *server) answer(method, url string, body *string) (breez_sdk_spark.RestResponse, error) {
+	if s.fail {
+		return breez_sdk_spark.RestResponse{}, breez_sdk_spark.NewServiceConnectivityErrorConnect("connection refused")
+	}
+	n := s.calls.Add(1)
+	text := method + " " + url + " #" + string(rune('0'+n))
+	if body != nil {
+		text += " " + *body
+	}
+	return breez_sdk_spark.RestResponse{Status: 200, Body: text}, nil
+}
+
+func (s *server) GetRequest(_ context.Context, url string, _ *map[string]string) (breez_sdk_spark.RestResponse, error) {
+	return s.answer("GET", url, nil)
+}
+
+func (s *server) PostRequest(_ context.Context, url string, _ *map[string]string, body *string) (breez_sdk_spark.RestResponse, error) {
+	return s.answer("POST", url, body)
+}
+
+func (s *server) DeleteRequest(_ context.Context, url string, _ *map[string]string, body *string) (breez_sdk_spark.RestResponse, error) {
+	return s.answer("DELETE", url, body)
+}
+
+const (
+	payURL      = "https://tips.example/.well-known/lnurlp/streamer"
+	callbackURL = "https://tips.example/lnurlp/streamer/callback?amount=21000"
+)
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "lnurl_pay.json")
+	live := &server{}
+	rec, err := Open(Config{Path: path, Mode: ModeRecord, Inner: live})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	headers := &map[string]string{"authorization": "Bearer secret", "Accept": "application/json"}
+	body := `{"a": 1, "b": [true]}`
+
+	var recorded []breez_sdk_spark.RestResponse
+	for _, call := range []func() (breez_sdk_spark.RestResponse, error){
+		func() (breez_sdk_spark.RestResponse, error) { return rec.GetRequest(ctx, payURL, headers) },
+		func() (breez_sdk_spark.RestResponse, error) { return rec.GetRequest(ctx, callbackURL, nil) },
+		func() (breez_sdk_spark.RestResponse, error) { return rec.GetRequest(ctx, callbackURL, nil) },
+		func() (breez_sdk_spark.RestResponse, error) { return rec.PostRequest(ctx, payURL, headers, &body) },
+	} {
+		resp, err := call()
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorded = append(recorded, resp)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") || !strings.Contains(string(data), `"authorization": "REDACTED"`) || !strings.Contains(string(data), "application/json") {
+		t.Fatalf("cassette:\n%s", data)
+	}
+
+	play, err := Open(Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := play.GetRequest(ctx, payURL, nil); err != nil || got != recorded[0] {
+		t.Fatalf("replayed %+v, %v", got, err)
+	}
+	// Repeated requests get their answers in order, then the last again.
+	for _, want := range []breez_sdk_spark.RestResponse{recorded[1], recorded[2], recorded[2]} {
+		if got, _ := play.GetRequest(ctx, callbackURL, nil); got != want {
+			t.Fatalf("replayed %+v, want %+v", got, want)
+		}
+	}
+	if len(play.Unused()) != 1 {
+		t.Fatalf("unused = %+v", play.Unused())
+	}
+	// JSON bodies match whatever their layout.
+	reordered := `{"b":[true],"a":1}`
+	if got, err := play.PostRequest(ctx, payURL, nil, &reordered); err != nil || got != recorded[3] {
+		t.Fatalf("replayed %+v, %v", got, err)
+	}
+	if len(play.Unused()) != 0 {
+		t.Fatalf("unused = %+v", play.Unused())
+	}
+
+	other := `{"a": 2}`
+	for _, call := range []func() (breez_sdk_spark.RestResponse, error){
+		func() (breez_sdk_spark.RestResponse, error) { return play.PostRequest(ctx, payURL, nil, &other) },
+		func() (breez_sdk_spark.RestResponse, error) { return play.DeleteRequest(ctx, payURL, nil, nil) },
+		func() (breez_sdk_spark.RestResponse, error) { return play.GetRequest(ctx, payURL+"?x", nil) },
+	} {
+		if _, err := call(); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorOther) {
+			t.Fatalf("unrecorded request: %v", err)
+		}
+	}
+	if live.calls.Load() != 4 {
+		t.Fatalf("replay reached the server: %d calls", live.calls.Load())
+	}
+}
+
+func TestReplayOrRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.json")
+	live := &server{}
+	ctx := context.Background()
+	c, err := Open(Config{Path: path, Mode: ModeReplayOrRecord, Inner: live})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := c.GetRequest(ctx, payURL, nil)
+
+	c, _ = Open(Config{Path: path, Mode: ModeReplayOrRecord, Inner: live})
+	if again, _ := c.GetRequest(ctx, payURL, nil); again != first {
+		t.Fatalf("got %+v, want %+v", again, first)
+	}
+	c.DeleteRequest(ctx, callbackURL, nil, nil)
+	if live.calls.Load() != 2 {
+		t.Fatalf("%d calls", live.calls.Load())
+	}
+
+	// Failures are passed on, not recorded.
+	live.fail = true
+	if _, err := c.GetRequest(ctx, payURL+"/other", nil); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorConnect) {
+		t.Fatalf("got %v", err)
+	}
+	play, _ := Open(Config{Path: path})
+	if _, err := play.DeleteRequest(ctx, callbackURL, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := play.GetRequest(ctx, payURL+"/other", nil); err == nil {
+		t.Fatal("a failure was recorded")
+	}
+}
+
+func TestRedactHook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.json")
+	mask := func(in *Interaction) {
+		if in.Request.Body != nil {
+			masked := strings.ReplaceAll(*in.Request.Body, "preimage-123", "PREIMAGE")
+			in.Request.Body = &masked
+		}
+		in.Response.Body = strings.ReplaceAll(in.Response.Body, "preimage-123", "PREIMAGE")
+	}
+	live := &server{}
+	c, _ := Open(Config{Path: path, Mode: ModeRecord, Inner: live, Redact: mask, RedactHeaders: []string{}})
+	body := "preimage-123"
+	headers := &map[string]string{"Authorization": "kept"}
+	resp, _ := c.PostRequest(context.Background(), payURL, headers, &body)
+	if !strings.Contains(resp.Body, "preimage-123") || body != "preimage-123" {
+		t.Fatalf("the hook changed what the caller sees: %q, %q", resp.Body, body)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "preimage-123") || !strings.Contains(string(data), "kept") {
+		t.Fatalf("cassette:\n%s", data)
+	}
+
+	play, _ := Open(Config{Path: path, Redact: mask})
+	if got, err := play.PostRequest(context.Background(), payURL, nil, &body); err != nil || !strings.Contains(got.Body, "PREIMAGE") {
+		t.Fatalf("replayed %+v, %v", got, err)
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Open(Config{Path: filepath.Join(dir, "missing.json")}); err == nil {
+		t.Fatal("replaying a missing cassette")
+	}
+	if _, err := Open(Config{Path: filepath.Join(dir, "c.json"), Mode: ModeRecord}); err == nil {
+		t.Fatal("recording without a client")
+	}
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte("{"), 0o600)
+	if _, err := Open(Config{Path: bad}); err == nil {
+		t.Fatal("opened a corrupt cassette")
+	}
+}
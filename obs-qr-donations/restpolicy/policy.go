@@ -0,0 +1,423 @@
+// Package restpolicy restricts the outbound requests of an SDK RestClient.
+// Viewers paste arbitrary LNURLs into the chat bot, which hands them to
+// Parse and PrepareLnurlPay; Client makes sure the resulting fetches only
+// reach public hosts the streamer accepts, at a bounded rate and with
+// bounded answers:
+//
+//	c, err := restpolicy.NewHTTP(httprest.Config{}, restpolicy.Config{
+//		Deny:      []string{"evil.example"},
+//		HostRate:  1,
+//		HostBurst: 5,
+//	})
+//	...
+//	builder.WithLnurlClient(breez_sdk_spark.NewRestClientFromCtx(c))
+//
+// Client resolves host names to check their addresses before handing the
+// request on, but the wrapped client resolves them again, and may follow
+// redirects Client never sees. NewHTTP closes both gaps: the httprest
+// client it wraps checks every redirect with CheckRedirect and dials
+// through DialControl. DialControl checks the address actually connected
+// to, which is the proxy's when requests go through one, such as a local
+// Tor daemon or a corporate proxy on a private address: NewHTTP then
+// leaves it out, and the check of the names before each request is the
+// only guard. Names under .onion are only reachable through Tor and are
+// never resolved. Other wrapped clients must not follow redirects, or
+// must check them with CheckRedirect.
+package restpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/httprest"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/sdkutil"
+)
+
+// Defaults for the zero values of Config.
+const (
+	DefaultHostRate        = 2
+	DefaultHostBurst       = 10
+	DefaultMaxResponseSize = 1 << 20
+)
+
+// Errors identifying why a request was refused. They can be tested with
+// errors.Is; the errors returned also match
+// breez_sdk_spark.ErrServiceConnectivityErrorOther, or ...Body for
+// ErrResponseTooLarge.
+var (
+	ErrDenied           = errors.New("restpolicy: host not allowed")
+	ErrInsecure         = errors.New("restpolicy: plain http not allowed")
+	ErrPrivateAddress   = errors.New("restpolicy: private address")
+	ErrRateLimited      = errors.New("restpolicy: rate limited")
+	ErrResponseTooLarge = errors.New("restpolicy: response too large")
+)
+
+// Config configures a Client.
+type Config struct {
+	// Allow, when not empty, lists the only domains requests may go to. A
+	// domain also covers its subdomains: "example.com" allows
+	// "pay.example.com".
+	Allow []string
+	// Deny lists domains requests may not go to, even if allowed.
+	Deny []string
+	// AllowHTTP lets requests use plain http. LNURL requires https except
+	// for .onion hosts, which are always allowed plain http.
+	AllowHTTP bool
+	// AllowPrivate disables the check that hosts resolve to public
+	// addresses only.
+	AllowPrivate bool
+	// HostRate is the sustained number of requests per second allowed to
+	// each host, and HostBurst how many may be made at once. Requests over
+	// the limit fail straight away rather than queue. Default to
+	// DefaultHostRate and DefaultHostBurst; a negative HostRate disables
+	// rate limiting.
+	HostRate  float64
+	HostBurst int
+	// MaxResponseSize is the largest response body passed on, in bytes.
+	// Defaults to DefaultMaxResponseSize.
+	MaxResponseSize int
+	// Resolver looks host names up. Defaults to net.DefaultResolver.
+	Resolver Resolver
+	// Logger receives a warning for every refused request. Defaults to no
+	// logging.
+	Logger *slog.Logger
+}
+
+// Resolver looks up the addresses of a host. *net.Resolver satisfies it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Client is a breez_sdk_spark.RestClientCtx enforcing a Config on the
+// requests it passes to another. Plain breez_sdk_spark.RestClient values
+// can be wrapped through breez_sdk_spark.AsRestClientCtx.
+type Client struct {
+	inner           breez_sdk_spark.RestClientCtx
+	allow, deny     []string
+	allowHTTP       bool
+	allowPrivate    bool
+	maxResponseSize int
+	resolver        Resolver
+	logger          *slog.Logger
+	limiter         *limiter
+}
+
+var _ breez_sdk_spark.RestClientCtx = (*Client)(nil)
+
+// New returns a Client passing the requests cfg allows to inner.
+func New(inner breez_sdk_spark.RestClientCtx, cfg Config) (*Client, error) {
+	c := &Client{
+		inner:           inner,
+		allowHTTP:       cfg.AllowHTTP,
+		allowPrivate:    cfg.AllowPrivate,
+		maxResponseSize: cfg.MaxResponseSize,
+		resolver:        cfg.Resolver,
+		logger:          cfg.Logger,
+	}
+	for _, list := range []struct {
+		in  []string
+		out *[]string
+	}{{cfg.Allow, &c.allow}, {cfg.Deny, &c.deny}} {
+		for _, d := range list.in {
+			d = normalizeHost(d)
+			if d == "" {
+				return nil, errors.New("restpolicy: empty domain")
+			}
+			*list.out = append(*list.out, d)
+		}
+	}
+	if c.maxResponseSize == 0 {
+		c.maxResponseSize = DefaultMaxResponseSize
+	}
+	if c.resolver == nil {
+		c.resolver = net.DefaultResolver
+	}
+	if c.logger == nil {
+		c.logger = slog.New(slog.DiscardHandler)
+	}
+	rate, burst := cfg.HostRate, cfg.HostBurst
+	if rate == 0 {
+		rate = DefaultHostRate
+	}
+	if burst == 0 {
+		burst = DefaultHostBurst
+	}
+	if burst < 0 {
+		return nil, errors.New("restpolicy: negative HostBurst")
+	}
+	if rate > 0 {
+		c.limiter = newLimiter(rate, burst)
+	}
+	return c, nil
+}
+
+// NewHTTP returns a Client passing the requests cfg allows to an httprest
+// client made from httpCfg. That client checks redirects with
+// CheckRedirect and, unless cfg.AllowPrivate is set or it uses a proxy,
+// dials through DialControl; hooks already set in httpCfg are kept.
+func NewHTTP(httpCfg httprest.Config, cfg Config) (*Client, error) {
+	c, err := New(nil, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if httpCfg.CheckRedirect == nil {
+		httpCfg.CheckRedirect = c.CheckRedirect
+	}
+	if httpCfg.DialControl == nil && !cfg.AllowPrivate && !usesProxy(httpCfg) {
+		httpCfg.DialControl = DialControl
+	}
+	if c.inner, err = httprest.New(httpCfg); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// usesProxy tells whether a client made from cfg sends its requests
+// through a proxy, whose address is then the only one it dials.
+func usesProxy(cfg httprest.Config) bool {
+	switch cfg.Proxy {
+	case "direct":
+		return false
+	case "":
+		for _, name := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"} {
+			if os.Getenv(name) != "" {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// CheckRedirect applies the policy to a redirect about to be followed. It
+// is meant for httprest.Config.CheckRedirect.
+func (c *Client) CheckRedirect(ctx context.Context, url string) error {
+	host, err := c.check(ctx, url)
+	if err != nil {
+		c.logger.WarnContext(ctx, "redirect refused", "host", host, "err", err)
+	}
+	return err
+}
+
+// GetRequest passes the request on if the policy allows it.
+func (c *Client) GetRequest(ctx context.Context, url string, headers *map[string]string) (breez_sdk_spark.RestResponse, error) {
+	return c.do(ctx, "GET", url, func() (breez_sdk_spark.RestResponse, error) {
+		return c.inner.GetRequest(ctx, url, headers)
+	})
+}
+
+// PostRequest passes the request on if the policy allows it.
+func (c *Client) PostRequest(ctx context.Context, url string, headers *map[string]string, body *string) (breez_sdk_spark.RestResponse, error) {
+	return c.do(ctx, "POST", url, func() (breez_sdk_spark.RestResponse, error) {
+		return c.inner.PostRequest(ctx, url, headers, body)
+	})
+}
+
+// DeleteRequest passes the request on if the policy allows it.
+func (c *Client) DeleteRequest(ctx context.Context, url string, headers *map[string]string, body *string) (breez_sdk_spark.RestResponse, error) {
+	return c.do(ctx, "DELETE", url, func() (breez_sdk_spark.RestResponse, error) {
+		return c.inner.DeleteRequest(ctx, url, headers, body)
+	})
+}
+
+func (c *Client) do(ctx context.Context, method, rawURL string, send func() (breez_sdk_spark.RestResponse, error)) (breez_sdk_spark.RestResponse, error) {
+	host, err := c.check(ctx, rawURL)
+	if err != nil {
+		c.logger.WarnContext(ctx, "request refused", "method", method, "host", host, "err", err)
+		return breez_sdk_spark.RestResponse{}, err
+	}
+	resp, err := send()
+	if err != nil {
+		return resp, err
+	}
+	if len(resp.Body) > c.maxResponseSize {
+		err := sdkutil.Refuse(ErrResponseTooLarge, breez_sdk_spark.NewServiceConnectivityErrorBody,
+			fmt.Sprintf("%d bytes from %s, limit is %d", len(resp.Body), host, c.maxResponseSize))
+		c.logger.WarnContext(ctx, "response dropped", "method", method, "host", host, "err", err)
+		return breez_sdk_spark.RestResponse{}, err
+	}
+	return resp, nil
+}
+
+// check applies the policy to rawURL and returns its host.
+func (c *Client) check(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", breez_sdk_spark.NewServiceConnectivityErrorBuild(err.Error())
+	}
+	host := normalizeHost(u.Hostname())
+	if host == "" {
+		return "", breez_sdk_spark.NewServiceConnectivityErrorBuild(fmt.Sprintf("no host in %q", u.Redacted()))
+	}
+	onion := strings.HasSuffix(host, ".onion")
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && (c.allowHTTP || onion):
+	case u.Scheme == "http":
+		return host, sdkutil.Refuse(ErrInsecure, breez_sdk_spark.NewServiceConnectivityErrorOther, host)
+	default:
+		return host, breez_sdk_spark.NewServiceConnectivityErrorBuild(fmt.Sprintf("unsupported URL scheme %q", u.Scheme))
+	}
+	if matchAny(c.deny, host) || len(c.allow) > 0 && !matchAny(c.allow, host) {
+		return host, sdkutil.Refuse(ErrDenied, breez_sdk_spark.NewServiceConnectivityErrorOther, host)
+	}
+	if !c.allowPrivate && !onion {
+		if err := c.checkAddresses(ctx, host); err != nil {
+			return host, err
+		}
+	}
+	if c.limiter != nil && !c.limiter.allow(host) {
+		return host, sdkutil.Refuse(ErrRateLimited, breez_sdk_spark.NewServiceConnectivityErrorOther, host)
+	}
+	return host, nil
+}
+
+// checkAddresses fails unless every address of host is public.
+func (c *Client) checkAddresses(ctx context.Context, host string) error {
+	addrs := []netip.Addr(nil)
+	if ip, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, ip)
+	} else {
+		addrs, err = c.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return breez_sdk_spark.NewServiceConnectivityErrorConnect(fmt.Sprintf("resolving %s: %v", host, err))
+		}
+	}
+	for _, ip := range addrs {
+		if !Public(ip) {
+			return sdkutil.Refuse(ErrPrivateAddress, breez_sdk_spark.NewServiceConnectivityErrorOther, fmt.Sprintf("%s resolves to %s", host, ip))
+		}
+	}
+	return nil
+}
+
+// DialControl refuses connections to addresses that are not Public. It is
+// meant for net.Dialer.Control, e.g. through httprest.Config.DialControl.
+func DialControl(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("restpolicy: dialing %s: %w", address, err)
+	}
+	if !Public(ap.Addr()) {
+		return fmt.Errorf("%w: dialing %s", ErrPrivateAddress, address)
+	}
+	return nil
+}
+
+// nonPublic lists the special purpose ranges, besides those the netip
+// predicates cover, that are not reachable on the public internet.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// nat64 is the well-known NAT64 prefix; the translator forwards its
+// addresses to the IPv4 address in their last 32 bits.
+var nat64 = netip.MustParsePrefix("64:ff9b::/96")
+
+// Public tells whether ip is a globally routable unicast address.
+// IPv4-mapped and NAT64 IPv6 addresses are judged by their IPv4 address.
+func Public(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if nat64.Contains(ip) {
+		b := ip.As16()
+		ip = netip.AddrFrom4([4]byte(b[12:]))
+	}
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return false
+	}
+	for _, p := range nonPublic {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// matchAny tells whether host is one of domains or a subdomain of one.
+func matchAny(domains []string, host string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// limiter is a token bucket per host.
+type limiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// maxIdleBuckets is how many hosts are tracked before full buckets, which
+// are the same as no bucket, are dropped.
+const maxIdleBuckets = 1024
+
+func newLimiter(rate float64, burst int) *limiter {
+	return &limiter{rate: rate, burst: float64(burst), now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+func (l *limiter) allow(host string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b, ok := l.buckets[host]
+	if !ok {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.prune(now)
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[host] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (l *limiter) prune(now time.Time) {
+	for host, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, host)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package restpolicy
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/httprest"
+)
+
+// echo answers every request with its URL, or with body when set.
+type echo struct {
+	calls int
+	body  string
+}
+
+func (e *echo) answer(url string) (breez_sdk_spark.RestResponse, error) {
+	e.calls++
+	if e.body != "" {
+		return breez_sdk_spark.RestResponse{Status: 200, Body: e.body}, nil
+	}
+	return breez_sdk_spark.RestResponse{Status: 200, Body: url}, nil
+}
+
+func (e *echo) GetRequest(_ context.Context, url string, _ *map[string]string) (breez_sdk_spark.RestResponse, error) {
+	return e.answer(url)
+}
+
+func (e *echo) PostRequest(_ context.Context, url string, _ *map[string]string, _ *string) (breez_sdk_spark.RestResponse, error) {
+	return e.answer(url)
+}
+
+func (e *echo) DeleteRequest(_ context.Context, url string, _ *map[string]string, _ *string) (breez_sdk_spark.RestResponse, error) {
+	return e.answer(url)
+}
+
+// zone is a Resolver over fixed records.
+type zone map[string][]string
+
+func (z zone) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	records, ok := z[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	var addrs []netip.Addr
+	for _, r := range records {
+		addrs = append(addrs, netip.MustParseAddr(r))
+	}
+	return addrs, nil
+}
+
+var dns = zone{
+	"pay.example.com":      {"93.184.215.14", "2606:2800:21f:cb07:6820:80da:af6b:8b2c"},
+	"tips.example.org":     {"93.184.215.15"},
+	"evil.example.org":     {"93.184.215.16"},
+	"sub.evil.example.org": {"93.184.215.17"},
+	"rebind.example.net":   {"93.184.215.18", "127.0.0.1"},
+	"metadata.example.net": {"169.254.169.254"},
+	"intranet.example.net": {"10.1.2.3"},
+	"mapped.example.net":   {"::ffff:192.168.1.1"},
+}
+
+func newClient(t *testing.T, cfg Config) (*Client, *echo) {
+	t.Helper()
+	inner := &echo{}
+	if cfg.Resolver == nil {
+		cfg.Resolver = dns
+	}
+	if cfg.HostRate == 0 {
+		cfg.HostRate = -1
+	}
+	c, err := New(inner, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, inner
+}
+
+func TestPolicy(t *testing.T) {
+	c, inner := newClient(t, Config{Deny: []string{"Evil.Example.org."}})
+	ctx := context.Background()
+	for url, want := range map[string]error{
+		"https://pay.example.com/.well-known/lnurlp/tips": nil,
+		"https://tips.example.org:8443/lnurl":             nil,
+		"https://93.184.215.14/lnurl":                     nil,
+		"https://evil.example.org/lnurl":                  ErrDenied,
+		"https://sub.evil.example.org/lnurl":              ErrDenied,
+		"http://pay.example.com/lnurl":                    ErrInsecure,
+		"http://tipsxyz.onion/lnurl":                      nil,
+		"https://rebind.example.net/":                     ErrPrivateAddress,
+		"https://metadata.example.net/latest/meta-data":   ErrPrivateAddress,
+		"https://intranet.example.net/":                   ErrPrivateAddress,
+		"https://mapped.example.net/":                     ErrPrivateAddress,
+		"https://127.0.0.1:9735/":                         ErrPrivateAddress,
+		"https://[::1]/":                                  ErrPrivateAddress,
+		"https://[fd00::1]/":                              ErrPrivateAddress,
+		"https://100.64.1.1/":                             ErrPrivateAddress,
+		"https://[64:ff9b::7f00:1]/":                      ErrPrivateAddress,
+		"https://[64:ff9b::a9fe:a9fe]/":                   ErrPrivateAddress,
+		"https://[64:ff9b::5db8:d70e]/":                   nil,
+	} {
+		inner.calls = 0
+		resp, err := c.GetRequest(ctx, url, nil)
+		switch {
+		case want == nil && (err != nil || resp.Body != url || inner.calls != 1):
+			t.Errorf("%s: %+v, %v", url, resp, err)
+		case want != nil && (!errors.Is(err, want) || inner.calls != 0):
+			t.Errorf("%s: got %v, want %v", url, err, want)
+		case want != nil && !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorOther):
+			t.Errorf("%s: %v is not a ServiceConnectivityError", url, err)
+		}
+	}
+
+	for _, url := range []string{"ftp://pay.example.com/", "https:///path", "https://unknown.example.com/"} {
+		if _, err := c.PostRequest(ctx, url, nil, nil); err == nil {
+			t.Errorf("%s: allowed", url)
+		}
+	}
+}
+
+func TestAllowList(t *testing.T) {
+	c, _ := newClient(t, Config{Allow: []string{"example.com"}, Deny: []string{"blocked.example.com"}, AllowHTTP: true, AllowPrivate: true})
+	ctx := context.Background()
+	for url, allowed := range map[string]bool{
+		"https://pay.example.com/":     true,
+		"http://example.com/":          true,
+		"https://blocked.example.com/": false,
+		"https://notexample.com/":      false,
+		"https://tips.example.org/":    false,
+		"https://tipsxyz.onion/":       false,
+	} {
+		_, err := c.DeleteRequest(ctx, url, nil, nil)
+		if allowed != (err == nil) {
+			t.Errorf("%s: %v", url, err)
+		}
+	}
+
+	// AllowPrivate skips resolution.
+	c, _ = newClient(t, Config{AllowPrivate: true, Resolver: zone{}})
+	if _, err := c.GetRequest(ctx, "https://intranet.example.net/", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	c, inner := newClient(t, Config{HostRate: 2, HostBurst: 3})
+	now := time.Unix(1_700_000_000, 0)
+	c.limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+	get := func(host string) error {
+		_, err := c.GetRequest(ctx, "https://"+host+"/", nil)
+		return err
+	}
+
+	for range 3 {
+		if err := get("pay.example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := get("pay.example.com"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("4th request: %v", err)
+	}
+	// Other hosts have their own budget.
+	if err := get("tips.example.org"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(500 * time.Millisecond)
+	if err := get("pay.example.com"); err != nil {
+		t.Fatalf("after refill: %v", err)
+	}
+	if err := get("pay.example.com"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("refill of one: %v", err)
+	}
+	if inner.calls != 5 {
+		t.Fatalf("%d requests passed", inner.calls)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	c, inner := newClient(t, Config{MaxResponseSize: 10})
+	inner.body = strings.Repeat("x", 11)
+	_, err := c.GetRequest(context.Background(), "https://pay.example.com/", nil)
+	if !errors.Is(err, ErrResponseTooLarge) || !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorBody) {
+		t.Fatalf("got %v", err)
+	}
+	var sdkErr *breez_sdk_spark.ServiceConnectivityError
+	if !errors.As(err, &sdkErr) {
+		t.Fatal("the SDK would not recognize the error")
+	}
+	inner.body = strings.Repeat("x", 10)
+	if _, err := c.GetRequest(context.Background(), "https://pay.example.com/", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	for address, ok := range map[string]bool{
+		"93.184.215.14:443":        true,
+		"[2606:2800::1]:443":       true,
+		"127.0.0.1:443":            false,
+		"[::ffff:10.0.0.1]:80":     false,
+		"169.254.169.254:80":       false,
+		"[fe80::1]:443":            false,
+		"[64:ff9b::a00:1]:443":     false,
+		"[64:ff9b::5db8:d70e]:443": true,
+		"0.0.0.0:443":              false,
+		"not-an-address":           false,
+	} {
+		if err := DialControl("tcp", address, nil); ok != (err == nil) {
+			t.Errorf("%s: %v", address, err)
+		}
+	}
+}
+
+func TestDialControlWithHTTPRest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	c, err := httprest.New(httprest.Config{Proxy: "direct", Retries: -1, DialControl: DialControl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The name check cannot see where a redirect or a DNS answer changing
+	// after it leads; the dial check can.
+	if _, err := c.GetRequest(context.Background(), srv.URL, nil); !errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorConnect) {
+		t.Fatalf("dialled a loopback address: %v", err)
+	}
+}
+
+func TestRedirectsChecked(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/denied":
+			http.Redirect(w, r, "https://evil.example.org/", http.StatusFound)
+		case "/insecure":
+			http.Redirect(w, r, "http://"+r.Host+"/ok", http.StatusFound)
+		case "/same":
+			http.Redirect(w, r, srv.URL+"/ok", http.StatusFound)
+		default:
+			w.Write([]byte("landed"))
+		}
+	}))
+	defer srv.Close()
+	pool := srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	// The test server listens on loopback.
+	c, err := NewHTTP(httprest.Config{Proxy: "direct", Retries: -1, RootCAs: pool},
+		Config{Deny: []string{"evil.example.org"}, AllowPrivate: true, HostRate: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if resp, err := c.GetRequest(ctx, srv.URL+"/same", nil); err != nil || resp.Body != "landed" {
+		t.Fatalf("allowed redirect: %+v, %v", resp, err)
+	}
+	if _, err := c.GetRequest(ctx, srv.URL+"/denied", nil); !errors.Is(err, ErrDenied) {
+		t.Fatalf("redirect to a denied host: %v", err)
+	}
+	if _, err := c.GetRequest(ctx, srv.URL+"/insecure", nil); !errors.Is(err, ErrInsecure) ||
+		!errors.Is(err, breez_sdk_spark.ErrServiceConnectivityErrorOther) {
+		t.Fatalf("redirect to plain http: %v", err)
+	}
+}
+
+func TestThroughProxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello " + r.Host))
+	}))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	// Like a local Tor daemon, the proxy listens on loopback and resolves
+	// the names itself.
+	proxy := socks5(t, "tips.onion", "127.0.0.1")
+	c, err := NewHTTP(httprest.Config{Proxy: "socks5h://" + proxy, Retries: -1}, Config{HostRate: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.GetRequest(context.Background(), "http://tips.onion:"+port+"/", nil)
+	if err != nil || resp.Body != "hello tips.onion:"+port {
+		t.Fatalf("got %+v, %v", resp, err)
+	}
+}
+
+// socks5 runs a minimal SOCKS5 proxy: no authentication, CONNECT to host
+// names only, connecting to ip for host.
+func socks5(t *testing.T, host, ip string) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		buf := make([]byte, 256)
+		// Greeting: version, method count, methods.
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return
+		}
+		if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+			return
+		}
+		conn.Write([]byte{5, 0})
+		// Request: version, CONNECT, reserved, host name, its length.
+		if _, err := io.ReadFull(conn, buf[:5]); err != nil || buf[1] != 1 || buf[3] != 3 {
+			return
+		}
+		n := int(buf[4])
+		if _, err := io.ReadFull(conn, buf[:n+2]); err != nil || string(buf[:n]) != host {
+			conn.Write([]byte{5, 4, 0, 1, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		port := strconv.Itoa(int(binary.BigEndian.Uint16(buf[n : n+2])))
+		target, err := net.Dial("tcp", net.JoinHostPort(ip, port))
+		if err != nil {
+			conn.Write([]byte{5, 4, 0, 1, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		defer target.Close()
+		conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		go io.Copy(target, conn)
+		io.Copy(conn, target)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return l.Addr().String()
+}
@@ -0,0 +1,459 @@
+// Package spendpolicy limits what the wallet can send. Anyone with access
+// to the OBS dock's send dialog, moderators included, can pay from the
+// stream wallet; an Observer checks every payment against the streamer's
+// Rules before the SDK makes it, and cancels those that break them:
+//
+//	rules, err := spendpolicy.ParseFile("spend-rules.yaml")
+//	...
+//	guard, err := spendpolicy.New(spendpolicy.Config{Rules: rules, Store: storage})
+//	...
+//	builder.WithPaymentObserver(guard)
+//	sdk, err := builder.Build()
+//	...
+//	sdk.AddEventListener(guard)
+//
+// Listening to events is optional: it gives the allowance of payments that
+// fail back, which would otherwise count against the caps.
+package spendpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/sdkutil"
+)
+
+// ledgerKey is the key of the cached item the spending history is kept
+// under in Config.Store.
+const ledgerKey = "spendpolicy_ledger"
+
+// DefaultTimeout bounds fetching the fiat rate for a payment.
+const DefaultTimeout = 10 * time.Second
+
+// Errors identifying the rule a payment broke. They can be tested with
+// errors.Is; the errors returned are also *breez_sdk_spark.PaymentObserverError
+// values, as the SDK expects.
+var (
+	ErrPaymentLimit  = errors.New("spendpolicy: payment over the limit")
+	ErrDailyLimit    = errors.New("spendpolicy: daily limit reached")
+	ErrWeeklyLimit   = errors.New("spendpolicy: weekly limit reached")
+	ErrDestination   = errors.New("spendpolicy: destination not allowed")
+	ErrOutsideWindow = errors.New("spendpolicy: outside sending hours")
+	ErrToken         = errors.New("spendpolicy: token not allowed")
+	// ErrNoRate refuses payments whose fiat value is needed but unknown.
+	ErrNoRate = errors.New("spendpolicy: no fiat rate")
+)
+
+// RateSource provides the current rates. *breez_sdk_spark.BreezSdk
+// satisfies it.
+type RateSource interface {
+	ListFiatRatesCtx(ctx context.Context) (breez_sdk_spark.ListFiatRatesResponse, error)
+}
+
+// Store keeps the spending history. breez_sdk_spark.Storage
+// implementations satisfy it.
+type Store interface {
+	GetCachedItem(key string) (*string, error)
+	SetCachedItem(key string, value string) error
+}
+
+// Config configures an Observer.
+type Config struct {
+	Rules Rules
+	// Rates is required when Rules.Fiat is set. It is usually the SDK,
+	// which the Observer is created before: set it with SetRates once the
+	// SDK is built.
+	Rates RateSource
+	// Store, when set, keeps the spending history across restarts, without
+	// which restarting the plugin would reset the daily and weekly caps.
+	Store Store
+	// Timeout defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Logger receives a warning for every refused payment. Defaults to no
+	// logging.
+	Logger *slog.Logger
+}
+
+// entry is a payment counted against the caps.
+type entry struct {
+	PaymentID string    `json:"payment_id"`
+	At        time.Time `json:"at"`
+	Sats      uint64    `json:"sats,omitempty"`
+	// Fiat is the value of Sats in Currency when the payment was sent.
+	Fiat     float64  `json:"fiat,omitempty"`
+	Currency string   `json:"currency,omitempty"`
+	Token    string   `json:"token,omitempty"`
+	Units    *big.Int `json:"units,omitempty"`
+}
+
+// Observer is a breez_sdk_spark.PaymentObserver enforcing Rules. It is
+// also a breez_sdk_spark.EventListener, to forget failed payments.
+type Observer struct {
+	rules   Rules
+	store   Store
+	timeout time.Duration
+	logger  *slog.Logger
+	now     func() time.Time
+
+	mu     sync.Mutex
+	rates  RateSource
+	ledger []entry
+}
+
+var (
+	_ breez_sdk_spark.PaymentObserver = (*Observer)(nil)
+	_ breez_sdk_spark.EventListener   = (*Observer)(nil)
+)
+
+// New returns an Observer for cfg, loading the spending history from
+// cfg.Store. An unreadable history is an error rather than a fresh start,
+// which would lift the caps.
+func New(cfg Config) (*Observer, error) {
+	if err := cfg.Rules.validate(); err != nil {
+		return nil, err
+	}
+	o := &Observer{
+		rules:   cfg.Rules,
+		store:   cfg.Store,
+		timeout: cfg.Timeout,
+		logger:  cfg.Logger,
+		now:     time.Now,
+		rates:   cfg.Rates,
+	}
+	if o.rules.Location == nil {
+		o.rules.Location = time.Local
+	}
+	if o.timeout == 0 {
+		o.timeout = DefaultTimeout
+	}
+	if o.logger == nil {
+		o.logger = slog.New(slog.DiscardHandler)
+	}
+	if o.store != nil {
+		value, err := o.store.GetCachedItem(ledgerKey)
+		if err != nil {
+			return nil, fmt.Errorf("spendpolicy: loading history: %w", err)
+		}
+		if value != nil {
+			if err := json.Unmarshal([]byte(*value), &o.ledger); err != nil {
+				return nil, fmt.Errorf("spendpolicy: loading history: %w", err)
+			}
+		}
+	}
+	return o, nil
+}
+
+// SetRates sets the source of fiat rates.
+func (o *Observer) SetRates(rates RateSource) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.rates = rates
+}
+
+// BeforeSend refuses the payments unless all of them, together with what
+// was sent before, keep within the rules. Allowed payments count against
+// the caps from then on.
+func (o *Observer) BeforeSend(payments []breez_sdk_spark.ProvisionalPayment) error {
+	now := o.now()
+	if err := o.check(payments, now); err != nil {
+		o.logger.Warn("payment refused", "payment_ids", ids(payments), "err", err)
+		return err
+	}
+	return nil
+}
+
+func (o *Observer) check(payments []breez_sdk_spark.ProvisionalPayment, now time.Time) error {
+	if len(o.rules.Windows) > 0 {
+		local := now.In(o.rules.Location)
+		if !slices.ContainsFunc(o.rules.Windows, func(w Window) bool { return w.contains(local) }) {
+			return refuse(ErrOutsideWindow, local.Format("Mon 15:04 MST"))
+		}
+	}
+	rate, err := o.rate(payments)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.prune(now)
+	var batch []entry
+	for _, p := range payments {
+		e, err := o.checkPayment(p, now, rate, batch)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, e)
+	}
+	o.ledger = append(o.ledger, batch...)
+	o.save()
+	return nil
+}
+
+// rate returns the price of one bitcoin in the fiat currency of the rules,
+// or 0 when no payment needs it.
+func (o *Observer) rate(payments []breez_sdk_spark.ProvisionalPayment) (float64, error) {
+	if o.rules.Fiat == nil || !slices.ContainsFunc(payments, func(p breez_sdk_spark.ProvisionalPayment) bool {
+		_, token := p.Details.(breez_sdk_spark.ProvisionalPaymentDetailsToken)
+		return !token
+	}) {
+		return 0, nil
+	}
+	o.mu.Lock()
+	rates := o.rates
+	o.mu.Unlock()
+	if rates == nil {
+		return 0, refuse(ErrNoRate, "no rate source")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+	resp, err := rates.ListFiatRatesCtx(ctx)
+	if err != nil {
+		return 0, sdkutil.Refuse(ErrNoRate, breez_sdk_spark.NewPaymentObserverErrorServiceConnectivity, err.Error())
+	}
+	for _, r := range resp.Rates {
+		if r.Coin == o.rules.Fiat.Currency && r.Value > 0 {
+			return r.Value, nil
+		}
+	}
+	return 0, refuse(ErrNoRate, o.rules.Fiat.Currency)
+}
+
+// checkPayment checks p against the rules, counting the payments of the
+// same batch allowed before it, and returns its entry.
+func (o *Observer) checkPayment(p breez_sdk_spark.ProvisionalPayment, now time.Time, rate float64, batch []entry) (entry, error) {
+	e := entry{PaymentID: p.PaymentId, At: now}
+	var destination string
+	switch d := p.Details.(type) {
+	case breez_sdk_spark.ProvisionalPaymentDetailsBitcoin:
+		destination = d.WithdrawalAddress
+	case breez_sdk_spark.ProvisionalPaymentDetailsSpark:
+		destination = d.PayRequest
+	case breez_sdk_spark.ProvisionalPaymentDetailsToken:
+		destination = d.PayRequest
+		e.Token = d.TokenId
+	case breez_sdk_spark.ProvisionalPaymentDetailsLightning:
+		if len(o.rules.Destinations) > 0 && !o.rules.AllowLightning {
+			return e, refuse(ErrDestination, "Lightning invoices are not allowed")
+		}
+	default:
+		return e, refuse(ErrDestination, fmt.Sprintf("unknown payment kind %T", p.Details))
+	}
+	if destination != "" && len(o.rules.Destinations) > 0 && !slices.Contains(o.rules.Destinations, destination) {
+		return e, refuse(ErrDestination, destination)
+	}
+
+	history := append(slices.Clip(o.ledger), batch...)
+	if e.Token != "" {
+		e.Units = new(big.Int)
+		if p.Amount != nil {
+			e.Units.Set(p.Amount)
+		}
+		return e, o.checkToken(e, now, history)
+	}
+
+	e.Sats = sdkutil.Sats(p.Amount)
+	if err := checkCaps(float64(e.Sats), "sats", float64(o.rules.MaxPaymentSats), float64(o.rules.DailySats), float64(o.rules.WeeklySats),
+		func(e entry) float64 { return float64(e.Sats) }, now, history); err != nil {
+		return e, err
+	}
+	if f := o.rules.Fiat; f != nil {
+		e.Fiat = float64(e.Sats) * rate / 1e8
+		e.Currency = f.Currency
+		if err := checkCaps(e.Fiat, f.Currency, f.MaxPayment, f.Daily, f.Weekly,
+			func(e entry) float64 { return o.fiatValue(e, rate) }, now, history); err != nil {
+			return e, err
+		}
+	}
+	return e, nil
+}
+
+func (o *Observer) checkToken(e entry, now time.Time, history []entry) error {
+	limits, ok := o.rules.Tokens[e.Token]
+	if !ok {
+		if o.rules.AllowOtherTokens {
+			return nil
+		}
+		return refuse(ErrToken, e.Token)
+	}
+	if limits.MaxPayment != nil && e.Units.Cmp(limits.MaxPayment) > 0 {
+		return refuse(ErrPaymentLimit, fmt.Sprintf("%s units of %s, limit is %s", e.Units, e.Token, limits.MaxPayment))
+	}
+	for _, period := range []struct {
+		limit  *big.Int
+		since  time.Time
+		reason error
+	}{
+		{limits.Daily, now.Add(-24 * time.Hour), ErrDailyLimit},
+		{limits.Weekly, now.Add(-7 * 24 * time.Hour), ErrWeeklyLimit},
+	} {
+		if period.limit == nil {
+			continue
+		}
+		spent := new(big.Int)
+		for _, h := range history {
+			if h.Token == e.Token && h.At.After(period.since) && h.Units != nil {
+				spent.Add(spent, h.Units)
+			}
+		}
+		if new(big.Int).Add(spent, e.Units).Cmp(period.limit) > 0 {
+			return refuse(period.reason, fmt.Sprintf("%s units of %s sent, %s more requested, limit is %s", spent, e.Token, e.Units, period.limit))
+		}
+	}
+	return nil
+}
+
+// checkCaps checks amount, in unit, against a per-payment, daily and
+// weekly limit, of which zero ones are ignored.
+func checkCaps(amount float64, unit string, maxPayment, daily, weekly float64, value func(entry) float64, now time.Time, history []entry) error {
+	if maxPayment > 0 && amount > maxPayment {
+		return refuse(ErrPaymentLimit, fmt.Sprintf("%s %s, limit is %s", format(amount), unit, format(maxPayment)))
+	}
+	for _, period := range []struct {
+		limit  float64
+		since  time.Time
+		reason error
+	}{
+		{daily, now.Add(-24 * time.Hour), ErrDailyLimit},
+		{weekly, now.Add(-7 * 24 * time.Hour), ErrWeeklyLimit},
+	} {
+		if period.limit <= 0 {
+			continue
+		}
+		spent := 0.0
+		for _, h := range history {
+			if h.Token == "" && h.At.After(period.since) {
+				spent += value(h)
+			}
+		}
+		if spent+amount > period.limit {
+			return refuse(period.reason, fmt.Sprintf("%s %s sent, %s more requested, limit is %s",
+				format(spent), unit, format(amount), format(period.limit)))
+		}
+	}
+	return nil
+}
+
+// fiatValue returns the value of e in the currency of the rules: its value
+// when sent if it was recorded in that currency, else its value at rate.
+func (o *Observer) fiatValue(e entry, rate float64) float64 {
+	if e.Currency == o.rules.Fiat.Currency {
+		return e.Fiat
+	}
+	return float64(e.Sats) * rate / 1e8
+}
+
+// OnEvent forgets failed payments, so that they no longer count against
+// the caps.
+func (o *Observer) OnEvent(event breez_sdk_spark.SdkEvent) {
+	e, ok := event.(breez_sdk_spark.SdkEventPaymentFailed)
+	if !ok || e.Payment.PaymentType != breez_sdk_spark.PaymentTypeSend {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := len(o.ledger)
+	o.ledger = slices.DeleteFunc(o.ledger, func(l entry) bool { return l.PaymentID == e.Payment.Id })
+	if len(o.ledger) != n {
+		o.save()
+	}
+}
+
+// Vetoed forgets payments that another observer refused after this one
+// allowed them, such as a later link of an observerchain.ChainObserver.
+func (o *Observer) Vetoed(payments []breez_sdk_spark.ProvisionalPayment) {
+	vetoed := ids(payments)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := len(o.ledger)
+	o.ledger = slices.DeleteFunc(o.ledger, func(l entry) bool { return slices.Contains(vetoed, l.PaymentID) })
+	if len(o.ledger) != n {
+		o.save()
+	}
+}
+
+// Usage is what was sent over the rolling periods of the caps. Token
+// payments are not included.
+type Usage struct {
+	DailySats  uint64
+	WeeklySats uint64
+	// DailyFiat and WeeklyFiat are in the currency of the fiat rules, at
+	// the rates of when the payments were sent. They are zero without
+	// fiat rules.
+	DailyFiat  float64
+	WeeklyFiat float64
+}
+
+// Usage returns what was sent, e.g. for the dock to show how much of the
+// caps is left.
+func (o *Observer) Usage() Usage {
+	now := o.now()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var u Usage
+	for _, e := range o.ledger {
+		if e.Token != "" || !e.At.After(now.Add(-7*24*time.Hour)) {
+			continue
+		}
+		fiat := 0.0
+		if o.rules.Fiat != nil && e.Currency == o.rules.Fiat.Currency {
+			fiat = e.Fiat
+		}
+		u.WeeklySats += e.Sats
+		u.WeeklyFiat += fiat
+		if e.At.After(now.Add(-24 * time.Hour)) {
+			u.DailySats += e.Sats
+			u.DailyFiat += fiat
+		}
+	}
+	return u
+}
+
+// prune drops the entries older than the longest period. It must be
+// called with mu held.
+func (o *Observer) prune(now time.Time) {
+	since := now.Add(-7 * 24 * time.Hour)
+	o.ledger = slices.DeleteFunc(o.ledger, func(e entry) bool { return !e.At.After(since) })
+}
+
+// save writes the ledger to the store. It must be called with mu held. A
+// failure is logged only: the caps still hold until the next restart.
+func (o *Observer) save() {
+	if o.store == nil {
+		return
+	}
+	data, err := json.Marshal(o.ledger)
+	if err == nil {
+		err = o.store.SetCachedItem(ledgerKey, string(data))
+	}
+	if err != nil {
+		o.logger.Warn("spending history not saved", "err", err)
+	}
+}
+
+func refuse(reason error, detail string) error {
+	return sdkutil.Refuse(reason, breez_sdk_spark.NewPaymentObserverErrorGeneric, detail)
+}
+
+// format formats an amount of sats, or of fiat to the cent.
+func format(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func ids(payments []breez_sdk_spark.ProvisionalPayment) []string {
+	ids := make([]string, len(payments))
+	for i, p := range payments {
+		ids[i] = p.PaymentId
+	}
+	return ids
+}
@@ -0,0 +1,272 @@
+package spendpolicy
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+type store map[string]string
+
+func (s store) GetCachedItem(key string) (*string, error) {
+	v, ok := s[key]
+	if !ok {
+		return nil, nil
+	}
+	return &v, nil
+}
+
+func (s store) SetCachedItem(key, value string) error {
+	s[key] = value
+	return nil
+}
+
+// rates serves a fixed USD rate, or fails when err is set.
+type rates struct {
+	usd float64
+	err error
+}
+
+func (r *rates) ListFiatRatesCtx(context.Context) (breez_sdk_spark.ListFiatRatesResponse, error) {
+	if r.err != nil {
+		return breez_sdk_spark.ListFiatRatesResponse{}, r.err
+	}
+	return breez_sdk_spark.ListFiatRatesResponse{Rates: []breez_sdk_spark.Rate{{Coin: "EUR", Value: 90_000}, {Coin: "USD", Value: r.usd}}}, nil
+}
+
+func spark(id string, sats int64, to string) breez_sdk_spark.ProvisionalPayment {
+	return breez_sdk_spark.ProvisionalPayment{PaymentId: id, Amount: big.NewInt(sats), Details: breez_sdk_spark.ProvisionalPaymentDetailsSpark{PayRequest: to}}
+}
+
+func lightning(id string, sats int64) breez_sdk_spark.ProvisionalPayment {
+	return breez_sdk_spark.ProvisionalPayment{PaymentId: id, Amount: big.NewInt(sats), Details: breez_sdk_spark.ProvisionalPaymentDetailsLightning{Invoice: "lnbc1"}}
+}
+
+func token(id, tokenID string, units int64) breez_sdk_spark.ProvisionalPayment {
+	return breez_sdk_spark.ProvisionalPayment{PaymentId: id, Amount: big.NewInt(units), Details: breez_sdk_spark.ProvisionalPaymentDetailsToken{TokenId: tokenID, PayRequest: "spark1mod"}}
+}
+
+// newObserver returns an Observer whose clock is at *now.
+func newObserver(t *testing.T, cfg Config) (*Observer, *time.Time) {
+	t.Helper()
+	o, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 6, 21, 0, 0, 0, time.UTC) // a Friday
+	o.now = func() time.Time { return now }
+	return o, &now
+}
+
+func expect(t *testing.T, err, want error) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Fatalf("refused: %v", err)
+		}
+		return
+	}
+	if !errors.Is(err, want) {
+		t.Fatalf("got %v, want %v", err, want)
+	}
+	var sdkErr *breez_sdk_spark.PaymentObserverError
+	if !errors.As(err, &sdkErr) {
+		t.Fatalf("%v is not a PaymentObserverError", err)
+	}
+}
+
+func TestSatsCaps(t *testing.T) {
+	o, now := newObserver(t, Config{Rules: Rules{MaxPaymentSats: 50_000, DailySats: 100_000, WeeklySats: 149_999}})
+	send := func(p ...breez_sdk_spark.ProvisionalPayment) error { return o.BeforeSend(p) }
+
+	expect(t, send(lightning("a", 50_001)), ErrPaymentLimit)
+	expect(t, send(lightning("a", 50_000), spark("b", 40_000, "spark1x")), nil)
+	expect(t, send(spark("c", 10_001, "spark1x")), ErrDailyLimit)
+	// A batch is allowed or refused as a whole.
+	expect(t, send(spark("c", 5_000, "spark1x"), spark("d", 5_001, "spark1x")), ErrDailyLimit)
+	expect(t, send(spark("c", 10_000, "spark1x")), nil)
+
+	// The day rolls, not the calendar.
+	*now = now.Add(24 * time.Hour)
+	expect(t, send(lightning("e", 50_001)), ErrPaymentLimit)
+	expect(t, send(lightning("e", 50_000)), ErrWeeklyLimit)
+	expect(t, send(lightning("e", 50_000-1)), nil)
+	if u := o.Usage(); u.DailySats != 49_999 || u.WeeklySats != 149_999 {
+		t.Fatalf("usage = %+v", u)
+	}
+
+	// A failed payment gives its allowance back.
+	o.OnEvent(breez_sdk_spark.SdkEventPaymentFailed{Payment: breez_sdk_spark.Payment{Id: "a", PaymentType: breez_sdk_spark.PaymentTypeSend}})
+	expect(t, send(lightning("f", 50_000)), nil)
+
+	*now = now.Add(7 * 24 * time.Hour)
+	if u := o.Usage(); u != (Usage{}) {
+		t.Fatalf("usage after a week = %+v", u)
+	}
+}
+
+func TestFiatCaps(t *testing.T) {
+	r := &rates{usd: 100_000}
+	o, now := newObserver(t, Config{Rules: Rules{Fiat: &FiatLimits{Currency: "USD", MaxPayment: 20, Daily: 50}}, Rates: r})
+	send := func(p ...breez_sdk_spark.ProvisionalPayment) error { return o.BeforeSend(p) }
+
+	expect(t, send(lightning("a", 20_001)), ErrPaymentLimit) // $20.001
+	expect(t, send(lightning("a", 20_000), lightning("b", 20_000)), nil)
+	// Earlier payments count at the rate they were sent at.
+	r.usd = 200_000
+	expect(t, send(lightning("c", 5_001)), ErrDailyLimit)
+	expect(t, send(lightning("c", 5_000)), nil)
+	if u := o.Usage(); u.DailyFiat != 50 {
+		t.Fatalf("usage = %+v", u)
+	}
+
+	// Without a rate bitcoin payments are refused, tokens are not priced.
+	*now = now.Add(48 * time.Hour)
+	r.err = breez_sdk_spark.NewServiceConnectivityErrorTimeout("rates")
+	err := send(lightning("d", 1))
+	expect(t, err, ErrNoRate)
+	if !errors.Is(err, breez_sdk_spark.ErrPaymentObserverErrorServiceConnectivity) {
+		t.Fatalf("got %v", err)
+	}
+	o.rules.AllowOtherTokens = true
+	expect(t, send(token("e", "btkn1", 1)), nil)
+
+	o, _ = newObserver(t, Config{Rules: Rules{Fiat: &FiatLimits{Currency: "CHF", Daily: 50}}, Rates: r})
+	expect(t, o.BeforeSend([]breez_sdk_spark.ProvisionalPayment{lightning("a", 1)}), ErrNoRate)
+}
+
+func TestDestinations(t *testing.T) {
+	o, _ := newObserver(t, Config{Rules: Rules{Destinations: []string{"bc1qstreamer", "spark1streamer"}}})
+	bitcoin := func(to string) breez_sdk_spark.ProvisionalPayment {
+		return breez_sdk_spark.ProvisionalPayment{PaymentId: "x", Amount: big.NewInt(1), Details: breez_sdk_spark.ProvisionalPaymentDetailsBitcoin{WithdrawalAddress: to}}
+	}
+	for _, tc := range []struct {
+		payment breez_sdk_spark.ProvisionalPayment
+		want    error
+	}{
+		{bitcoin("bc1qstreamer"), nil},
+		{bitcoin("bc1qmoderator"), ErrDestination},
+		{spark("x", 1, "spark1streamer"), nil},
+		{spark("x", 1, "spark1mod"), ErrDestination},
+		{lightning("x", 1), ErrDestination},
+	} {
+		expect(t, o.BeforeSend([]breez_sdk_spark.ProvisionalPayment{tc.payment}), tc.want)
+	}
+	o.rules.AllowLightning = true
+	expect(t, o.BeforeSend([]breez_sdk_spark.ProvisionalPayment{lightning("x", 1)}), nil)
+}
+
+func TestWindows(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	rules := Rules{
+		Location: berlin,
+		Windows:  []Window{{Days: []time.Weekday{time.Friday, time.Saturday}, From: 20 * time.Hour, To: 2 * time.Hour}},
+	}
+	o, now := newObserver(t, Config{Rules: rules})
+	for at, want := range map[time.Time]error{
+		time.Date(2026, 3, 6, 20, 0, 0, 0, berlin):  nil, // Friday evening
+		time.Date(2026, 3, 6, 19, 59, 0, 0, berlin): ErrOutsideWindow,
+		time.Date(2026, 3, 7, 1, 59, 0, 0, berlin):  nil, // Friday's window, after midnight
+		time.Date(2026, 3, 7, 2, 0, 0, 0, berlin):   ErrOutsideWindow,
+		time.Date(2026, 3, 8, 1, 0, 0, 0, berlin):   nil, // Saturday's
+		time.Date(2026, 3, 9, 1, 0, 0, 0, berlin):   ErrOutsideWindow,
+		time.Date(2026, 3, 5, 21, 0, 0, 0, berlin):  ErrOutsideWindow, // Thursday
+	} {
+		*now = at.UTC()
+		if err := o.BeforeSend([]breez_sdk_spark.ProvisionalPayment{lightning("x", 1)}); !errors.Is(err, want) && (want != nil || err != nil) {
+			t.Errorf("%v: got %v, want %v", at, err, want)
+		}
+	}
+}
+
+func TestTokens(t *testing.T) {
+	o, _ := newObserver(t, Config{Rules: Rules{
+		MaxPaymentSats: 1,
+		Tokens:         map[string]TokenLimits{"btkn1usdb": {MaxPayment: big.NewInt(1_000_000), Daily: big.NewInt(1_500_000)}},
+	}})
+	send := func(p ...breez_sdk_spark.ProvisionalPayment) error { return o.BeforeSend(p) }
+
+	expect(t, send(token("a", "btkn1other", 1)), ErrToken)
+	expect(t, send(token("a", "btkn1usdb", 1_000_001)), ErrPaymentLimit)
+	// Token units are not sats.
+	expect(t, send(token("a", "btkn1usdb", 1_000_000)), nil)
+	expect(t, send(token("b", "btkn1usdb", 500_001)), ErrDailyLimit)
+	expect(t, send(token("b", "btkn1usdb", 500_000)), nil)
+	if u := o.Usage(); u != (Usage{}) {
+		t.Fatalf("tokens counted as sats: %+v", u)
+	}
+}
+
+func TestHistoryPersists(t *testing.T) {
+	s := store{}
+	o, _ := newObserver(t, Config{Rules: Rules{DailySats: 100}, Store: s})
+	expect(t, o.BeforeSend([]breez_sdk_spark.ProvisionalPayment{lightning("a", 60)}), nil)
+
+	// A restart does not reset the caps.
+	o, _ = newObserver(t, Config{Rules: Rules{DailySats: 100}, Store: s})
+	expect(t, o.BeforeSend([]breez_sdk_spark.ProvisionalPayment{lightning("b", 41)}), ErrDailyLimit)
+
+	s[ledgerKey] = "not json"
+	if _, err := New(Config{Store: s}); err == nil {
+		t.Fatal("started with a corrupt history")
+	}
+}
+
+func TestParse(t *testing.T) {
+	yamlRules := `
+timezone: Europe/Berlin
+max_payment_sats: 50000
+daily_sats: 200000
+fiat: {currency: USD, max_payment: 20, daily: 100}
+destinations: [spark1streamer]
+allow_lightning: true
+windows:
+  - {days: [Fri, saturday], from: "20:00", to: "02:00"}
+  - {from: "00:00", to: "24:00"}
+tokens:
+  btkn1usdb: {max_payment: 1000000, daily: "123456789012345678901234567890"}
+`
+	r, err := Parse([]byte(yamlRules))
+	if err != nil {
+		t.Skipf("time zone data: %v", err)
+	}
+	daily, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	switch {
+	case r.Location.String() != "Europe/Berlin" || r.MaxPaymentSats != 50_000 || r.DailySats != 200_000 || r.WeeklySats != 0:
+		t.Errorf("sats rules: %+v", r)
+	case *r.Fiat != FiatLimits{Currency: "USD", MaxPayment: 20, Daily: 100}:
+		t.Errorf("fiat rules: %+v", r.Fiat)
+	case len(r.Destinations) != 1 || !r.AllowLightning:
+		t.Errorf("destinations: %+v", r)
+	case len(r.Windows) != 2 || r.Windows[0].Days[1] != time.Saturday || r.Windows[0].To != 2*time.Hour || r.Windows[1].To != 24*time.Hour:
+		t.Errorf("windows: %+v", r.Windows)
+	case r.Tokens["btkn1usdb"].MaxPayment.Int64() != 1_000_000 || r.Tokens["btkn1usdb"].Daily.Cmp(daily) != 0:
+		t.Errorf("tokens: %+v", r.Tokens)
+	}
+
+	r, err = Parse([]byte(`{"daily_sats": 1000, "tokens": {"btkn1usdb": {"weekly": 5}}}`))
+	if err != nil || r.DailySats != 1000 || r.Tokens["btkn1usdb"].Weekly.Int64() != 5 {
+		t.Fatalf("JSON: %+v, %v", r, err)
+	}
+
+	for _, bad := range []string{
+		"dialy_sats: 1000",
+		"fiat: {daily: 5}",
+		"windows: [{days: [someday], from: '10:00', to: '12:00'}]",
+		"windows: [{from: '25:00', to: '12:00'}]",
+		"tokens: {btkn1: {daily: -1}}",
+		"tokens: {btkn1: {daily: lots}}",
+		`{"daily_sats": -1}`,
+	} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("accepted %q", bad)
+		}
+	}
+}
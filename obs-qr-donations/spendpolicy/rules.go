@@ -0,0 +1,278 @@
+package spendpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules are the limits outgoing payments must stay within. A zero limit
+// is no limit.
+type Rules struct {
+	// MaxPaymentSats caps the amount of a single bitcoin payment, and
+	// DailySats and WeeklySats the total sent over the last 24 hours and
+	// the last 7 days. The periods are rolling, so that the caps cannot
+	// be spent twice around midnight.
+	MaxPaymentSats uint64
+	DailySats      uint64
+	WeeklySats     uint64
+	// Fiat sets the same caps in a fiat currency, converted at the rate of
+	// when each payment is sent. Both kinds of caps apply when both are
+	// set.
+	Fiat *FiatLimits
+	// Destinations, when not empty, lists the only Bitcoin and Spark
+	// addresses payments may go to. Spark and token payment requests must
+	// be equal to one of them.
+	Destinations []string
+	// AllowLightning lets Lightning invoices be paid while Destinations is
+	// set. Invoices do not name a stable destination to list, so they are
+	// otherwise refused.
+	AllowLightning bool
+	// Windows, when not empty, are the only times payments may be sent.
+	Windows []Window
+	// Location is the time zone of Windows. Defaults to time.Local.
+	Location *time.Location
+	// Tokens maps token identifiers to their limits. Payments of tokens
+	// not listed are refused unless AllowOtherTokens is set, in which case
+	// they are not limited.
+	Tokens           map[string]TokenLimits
+	AllowOtherTokens bool
+}
+
+// FiatLimits are caps in Currency, a currency id as listed by
+// ListFiatCurrencies.
+type FiatLimits struct {
+	Currency   string
+	MaxPayment float64
+	Daily      float64
+	Weekly     float64
+}
+
+// TokenLimits are caps in token base units. Nil is no limit.
+type TokenLimits struct {
+	MaxPayment *big.Int
+	Daily      *big.Int
+	Weekly     *big.Int
+}
+
+// Window is a time of day during which payments may be sent, From
+// included and To excluded, both as offsets from midnight. A To before
+// From extends the window past midnight, into the next day.
+type Window struct {
+	// Days the window starts on. Empty means every day.
+	Days []time.Weekday
+	From time.Duration
+	To   time.Duration
+}
+
+// contains tells whether t, in the zone of the rules, is in the window.
+func (w Window) contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	day := t.Weekday()
+	if w.To <= w.From && offset < w.To {
+		// The part after midnight belongs to the window of the day before.
+		day = (day + 6) % 7
+		offset += 24 * time.Hour
+	}
+	end := w.To
+	if w.To <= w.From {
+		end += 24 * time.Hour
+	}
+	return offset >= w.From && offset < end && (len(w.Days) == 0 || slices.Contains(w.Days, day))
+}
+
+func (r *Rules) validate() error {
+	if f := r.Fiat; f != nil {
+		if f.Currency == "" {
+			return errors.New("spendpolicy: fiat limits without a currency")
+		}
+		for _, v := range []float64{f.MaxPayment, f.Daily, f.Weekly} {
+			if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("spendpolicy: invalid %s limit %v", f.Currency, v)
+			}
+		}
+	}
+	for id, t := range r.Tokens {
+		for _, v := range []*big.Int{t.MaxPayment, t.Daily, t.Weekly} {
+			if v != nil && v.Sign() < 0 {
+				return fmt.Errorf("spendpolicy: negative limit for token %s", id)
+			}
+		}
+	}
+	for _, w := range r.Windows {
+		if w.From < 0 || w.From >= 24*time.Hour || w.To < 0 || w.To > 24*time.Hour {
+			return fmt.Errorf("spendpolicy: window %v-%v out of the day", w.From, w.To)
+		}
+	}
+	return nil
+}
+
+// fileRules is the layout of a rules file:
+//
+//	timezone: Europe/Berlin      # defaults to the local zone
+//	max_payment_sats: 50000
+//	daily_sats: 200000
+//	weekly_sats: 1000000
+//	fiat: {currency: USD, max_payment: 20, daily: 100, weekly: 300}
+//	destinations: [bc1q..., spark1...]
+//	allow_lightning: true
+//	windows:
+//	  - {days: [fri, sat], from: "20:00", to: "02:00"}
+//	tokens:
+//	  btkn1...: {max_payment: "1000000", daily: "5000000"}
+//	allow_other_tokens: false
+//
+// Token amounts may be given as strings, as they do not always fit in a
+// JSON number.
+type fileRules struct {
+	Timezone         string               `json:"timezone" yaml:"timezone"`
+	MaxPaymentSats   uint64               `json:"max_payment_sats" yaml:"max_payment_sats"`
+	DailySats        uint64               `json:"daily_sats" yaml:"daily_sats"`
+	WeeklySats       uint64               `json:"weekly_sats" yaml:"weekly_sats"`
+	Fiat             *fileFiat            `json:"fiat" yaml:"fiat"`
+	Destinations     []string             `json:"destinations" yaml:"destinations"`
+	AllowLightning   bool                 `json:"allow_lightning" yaml:"allow_lightning"`
+	Windows          []fileWindow         `json:"windows" yaml:"windows"`
+	Tokens           map[string]fileToken `json:"tokens" yaml:"tokens"`
+	AllowOtherTokens bool                 `json:"allow_other_tokens" yaml:"allow_other_tokens"`
+}
+
+type fileFiat struct {
+	Currency   string  `json:"currency" yaml:"currency"`
+	MaxPayment float64 `json:"max_payment" yaml:"max_payment"`
+	Daily      float64 `json:"daily" yaml:"daily"`
+	Weekly     float64 `json:"weekly" yaml:"weekly"`
+}
+
+type fileWindow struct {
+	Days []string `json:"days" yaml:"days"`
+	From string   `json:"from" yaml:"from"`
+	To   string   `json:"to" yaml:"to"`
+}
+
+type fileToken struct {
+	MaxPayment json.Number `json:"max_payment" yaml:"max_payment"`
+	Daily      json.Number `json:"daily" yaml:"daily"`
+	Weekly     json.Number `json:"weekly" yaml:"weekly"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Parse decodes a rules file, in JSON if it starts with "{" and in YAML
+// otherwise. Unknown fields are rejected, as a misspelt limit would
+// silently be no limit.
+func Parse(data []byte) (Rules, error) {
+	var f fileRules
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		dec.UseNumber()
+		if err := dec.Decode(&f); err != nil {
+			return Rules{}, err
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil {
+			return Rules{}, err
+		}
+	}
+
+	r := Rules{
+		MaxPaymentSats:   f.MaxPaymentSats,
+		DailySats:        f.DailySats,
+		WeeklySats:       f.WeeklySats,
+		Destinations:     f.Destinations,
+		AllowLightning:   f.AllowLightning,
+		AllowOtherTokens: f.AllowOtherTokens,
+	}
+	if f.Timezone != "" {
+		loc, err := time.LoadLocation(f.Timezone)
+		if err != nil {
+			return Rules{}, err
+		}
+		r.Location = loc
+	}
+	if f.Fiat != nil {
+		r.Fiat = &FiatLimits{Currency: f.Fiat.Currency, MaxPayment: f.Fiat.MaxPayment, Daily: f.Fiat.Daily, Weekly: f.Fiat.Weekly}
+	}
+	for _, fw := range f.Windows {
+		var w Window
+		for _, d := range fw.Days {
+			name := strings.ToLower(d)
+			day, ok := weekdays[name[:min(3, len(name))]]
+			if !ok {
+				return Rules{}, fmt.Errorf("unknown day %q", d)
+			}
+			w.Days = append(w.Days, day)
+		}
+		var err error
+		if w.From, err = parseClock(fw.From); err != nil {
+			return Rules{}, err
+		}
+		if w.To, err = parseClock(fw.To); err != nil {
+			return Rules{}, err
+		}
+		r.Windows = append(r.Windows, w)
+	}
+	if len(f.Tokens) > 0 {
+		r.Tokens = make(map[string]TokenLimits, len(f.Tokens))
+	}
+	for id, ft := range f.Tokens {
+		var t TokenLimits
+		for _, v := range []struct {
+			in  json.Number
+			out **big.Int
+		}{{ft.MaxPayment, &t.MaxPayment}, {ft.Daily, &t.Daily}, {ft.Weekly, &t.Weekly}} {
+			if v.in == "" {
+				continue
+			}
+			n, ok := new(big.Int).SetString(string(v.in), 10)
+			if !ok {
+				return Rules{}, fmt.Errorf("invalid amount %q for token %s", v.in, id)
+			}
+			*v.out = n
+		}
+		r.Tokens[id] = t
+	}
+	return r, r.validate()
+}
+
+// ParseFile reads and parses the rules file at path.
+func ParseFile(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, err
+	}
+	r, err := Parse(data)
+	if err != nil {
+		return Rules{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// parseClock parses a time of day as "15:04", or "24:00" for the end of
+// the day.
+func parseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,88 @@
+package sqlitestorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migrations are applied in order; the number of applied entries is kept in
+// PRAGMA user_version. Never edit a released migration, append a new one.
+var migrations = []string{
+	`CREATE TABLE payments (
+		id               TEXT PRIMARY KEY,
+		payment_type     TEXT NOT NULL,
+		status           TEXT NOT NULL,
+		amount           TEXT NOT NULL,
+		fees             TEXT NOT NULL,
+		timestamp        INTEGER NOT NULL,
+		method           INTEGER NOT NULL,
+		is_token         INTEGER NOT NULL,
+		token_identifier TEXT,
+		htlc_status      TEXT,
+		invoice          TEXT,
+		payment_hash     TEXT,
+		data             BLOB NOT NULL
+	);
+	CREATE TABLE payment_metadata (
+		payment_id          TEXT PRIMARY KEY,
+		lnurl_pay_info      BLOB,
+		lnurl_withdraw_info BLOB,
+		lnurl_description   TEXT
+	);
+	CREATE TABLE unclaimed_deposits (
+		txid         TEXT NOT NULL,
+		vout         INTEGER NOT NULL,
+		amount_sats  INTEGER NOT NULL,
+		claim_error  BLOB,
+		refund_tx    TEXT,
+		refund_tx_id TEXT,
+		PRIMARY KEY (txid, vout)
+	);
+	CREATE TABLE cached_items (
+		key   TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);`,
+	`CREATE INDEX payments_timestamp_idx ON payments (timestamp, id);
+	CREATE INDEX payments_type_status_idx ON payments (payment_type, status);
+	CREATE INDEX payments_token_idx ON payments (is_token, token_identifier);
+	CREATE INDEX payments_htlc_status_idx ON payments (htlc_status) WHERE htlc_status IS NOT NULL;
+	CREATE INDEX payments_invoice_idx ON payments (invoice) WHERE invoice IS NOT NULL;
+	CREATE INDEX payments_payment_hash_idx ON payments (payment_hash) WHERE payment_hash IS NOT NULL;`,
+	`CREATE TABLE lnurl_receive_metadata (
+		payment_hash      TEXT PRIMARY KEY,
+		nostr_zap_request TEXT,
+		nostr_zap_receipt TEXT,
+		sender_comment    TEXT
+	);`,
+}
+
+// migrate brings the schema up to date, one transaction per migration.
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		// PRAGMA does not accept bind parameters.
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+// Package sqlitestorage implements the SDK Storage interface on top of
+// SQLite, using the pure-Go modernc.org/sqlite driver so no C toolchain is
+// needed beyond what the SDK bindings already require.
+//
+// Payments are stored as their uniffi encoding next to the columns the
+// ListPaymentsRequest filters run against, so the donation history can be
+// queried directly through DB.
+package sqlitestorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+
+	_ "modernc.org/sqlite"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/internal/storageutil"
+)
+
+// Storage is a SQLite backed breez_sdk_spark.Storage. Its methods run
+// without a deadline; hand the SDK the context-aware view instead, so that
+// queries stop when the SDK gives up on a call:
+//
+//	builder.WithStorage(breez_sdk_spark.NewStorageFromCtx(s.Ctx()))
+type Storage struct {
+	db *sql.DB
+}
+
+var _ breez_sdk_spark.Storage = (*Storage)(nil)
+
+// storageCtx implements the queries of a Storage.
+type storageCtx struct {
+	db *sql.DB
+}
+
+var _ breez_sdk_spark.StorageCtx = storageCtx{}
+
+// New opens (creating if needed) the database at path and migrates it to
+// the latest schema. Use ":memory:" for a throwaway database.
+func New(path string) (*Storage, error) {
+	dsn := path
+	if path != ":memory:" {
+		dsn = "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+	}
+	// SQLite serializes writers anyway; a single connection avoids
+	// SQLITE_BUSY and keeps ":memory:" databases alive.
+	db.SetMaxOpenConns(1)
+	if err := migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, breez_sdk_spark.NewStorageErrorInitializationError(err.Error())
+	}
+	return &Storage{db: db}, nil
+}
+
+// DB exposes the underlying database for read-only reporting queries.
+func (s *Storage) DB() *sql.DB {
+	return s.db
+}
+
+// Close closes the underlying database.
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// Ctx returns the view of s whose queries are cancelled with their context.
+func (s *Storage) Ctx() breez_sdk_spark.StorageCtx {
+	return storageCtx{s.db}
+}
+
+func (s *Storage) DeleteCachedItem(key string) error {
+	return s.Ctx().DeleteCachedItem(context.Background(), key)
+}
+
+func (s *Storage) GetCachedItem(key string) (*string, error) {
+	return s.Ctx().GetCachedItem(context.Background(), key)
+}
+
+func (s *Storage) SetCachedItem(key string, value string) error {
+	return s.Ctx().SetCachedItem(context.Background(), key, value)
+}
+
+func (s *Storage) ListPayments(request breez_sdk_spark.ListPaymentsRequest) ([]breez_sdk_spark.Payment, error) {
+	return s.Ctx().ListPayments(context.Background(), request)
+}
+
+func (s *Storage) InsertPayment(payment breez_sdk_spark.Payment) error {
+	return s.Ctx().InsertPayment(context.Background(), payment)
+}
+
+func (s *Storage) SetPaymentMetadata(paymentId string, metadata breez_sdk_spark.PaymentMetadata) error {
+	return s.Ctx().SetPaymentMetadata(context.Background(), paymentId, metadata)
+}
+
+func (s *Storage) GetPaymentById(id string) (breez_sdk_spark.Payment, error) {
+	return s.Ctx().GetPaymentById(context.Background(), id)
+}
+
+func (s *Storage) GetPaymentByInvoice(invoice string) (*breez_sdk_spark.Payment, error) {
+	return s.Ctx().GetPaymentByInvoice(context.Background(), invoice)
+}
+
+func (s *Storage) AddDeposit(txid string, vout uint32, amountSats uint64) error {
+	return s.Ctx().AddDeposit(context.Background(), txid, vout, amountSats)
+}
+
+func (s *Storage) DeleteDeposit(txid string, vout uint32) error {
+	return s.Ctx().DeleteDeposit(context.Background(), txid, vout)
+}
+
+func (s *Storage) ListDeposits() ([]breez_sdk_spark.DepositInfo, error) {
+	return s.Ctx().ListDeposits(context.Background())
+}
+
+func (s *Storage) UpdateDeposit(txid string, vout uint32, payload breez_sdk_spark.UpdateDepositPayload) error {
+	return s.Ctx().UpdateDeposit(context.Background(), txid, vout, payload)
+}
+
+func (s *Storage) SetLnurlMetadata(metadata []breez_sdk_spark.SetLnurlMetadataItem) error {
+	return s.Ctx().SetLnurlMetadata(context.Background(), metadata)
+}
+
+func (s storageCtx) DeleteCachedItem(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM cached_items WHERE key = ?", key)
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) GetCachedItem(ctx context.Context, key string) (*string, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, "SELECT value FROM cached_items WHERE key = ?", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, storageutil.StorageErr(err)
+	}
+	return &value, nil
+}
+
+func (s storageCtx) SetCachedItem(ctx context.Context, key string, value string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO cached_items (key, value) VALUES (?, ?)
+		 ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		key, value)
+	return storageutil.StorageErr(err)
+}
+
+const selectPayments = `SELECT p.data,
+	m.lnurl_pay_info, m.lnurl_withdraw_info, m.lnurl_description,
+	r.payment_hash, r.nostr_zap_request, r.nostr_zap_receipt, r.sender_comment
+FROM payments p
+LEFT JOIN payment_metadata m ON m.payment_id = p.id
+LEFT JOIN lnurl_receive_metadata r ON r.payment_hash = p.payment_hash`
+
+func (s storageCtx) ListPayments(ctx context.Context, request breez_sdk_spark.ListPaymentsRequest) ([]breez_sdk_spark.Payment, error) {
+	q := storageutil.BuildPaymentQuery(request, "p.", func(int) string { return "?" })
+	rows, err := s.db.QueryContext(ctx, selectPayments+q.Where+q.OrderLimit, q.Args...)
+	if err != nil {
+		return nil, storageutil.StorageErr(err)
+	}
+	defer rows.Close()
+
+	var payments []breez_sdk_spark.Payment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, storageutil.StorageErr(err)
+		}
+		payments = append(payments, p)
+	}
+	return payments, storageutil.StorageErr(rows.Err())
+}
+
+func (s storageCtx) InsertPayment(ctx context.Context, payment breez_sdk_spark.Payment) error {
+	idx := storageutil.IndexPayment(payment)
+	var htlcStatus *string
+	if idx.HtlcStatus != nil {
+		name := storageutil.HtlcStatusName(*idx.HtlcStatus)
+		htlcStatus = &name
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO payments (id, payment_type, status, amount, fees, timestamp, method,
+			is_token, token_identifier, htlc_status, invoice, payment_hash, data)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (id) DO UPDATE SET
+			payment_type = excluded.payment_type,
+			status = excluded.status,
+			amount = excluded.amount,
+			fees = excluded.fees,
+			timestamp = excluded.timestamp,
+			method = excluded.method,
+			is_token = excluded.is_token,
+			token_identifier = excluded.token_identifier,
+			htlc_status = excluded.htlc_status,
+			invoice = excluded.invoice,
+			payment_hash = excluded.payment_hash,
+			data = excluded.data`,
+		payment.Id,
+		storageutil.PaymentTypeName(payment.PaymentType),
+		storageutil.PaymentStatusName(payment.Status),
+		amountString(payment.Amount),
+		amountString(payment.Fees),
+		int64(payment.Timestamp),
+		int64(payment.Method),
+		idx.IsToken,
+		idx.TokenIdentifier,
+		htlcStatus,
+		idx.Invoice,
+		idx.PaymentHash,
+		storageutil.EncodePayment(payment),
+	)
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) SetPaymentMetadata(ctx context.Context, paymentId string, metadata breez_sdk_spark.PaymentMetadata) error {
+	// COALESCE keeps fields from earlier calls that this one leaves unset.
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO payment_metadata (payment_id, lnurl_pay_info, lnurl_withdraw_info, lnurl_description)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT (payment_id) DO UPDATE SET
+			lnurl_pay_info = COALESCE(excluded.lnurl_pay_info, lnurl_pay_info),
+			lnurl_withdraw_info = COALESCE(excluded.lnurl_withdraw_info, lnurl_withdraw_info),
+			lnurl_description = COALESCE(excluded.lnurl_description, lnurl_description)`,
+		paymentId,
+		storageutil.EncodeOptionalLnurlPayInfo(metadata.LnurlPayInfo),
+		storageutil.EncodeOptionalLnurlWithdrawInfo(metadata.LnurlWithdrawInfo),
+		metadata.LnurlDescription,
+	)
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) GetPaymentById(ctx context.Context, id string) (breez_sdk_spark.Payment, error) {
+	p, err := scanPayment(s.db.QueryRowContext(ctx, selectPayments+" WHERE p.id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return breez_sdk_spark.Payment{}, storageutil.ErrPaymentNotFound(id)
+	}
+	return p, storageutil.StorageErr(err)
+}
+
+func (s storageCtx) GetPaymentByInvoice(ctx context.Context, invoice string) (*breez_sdk_spark.Payment, error) {
+	p, err := scanPayment(s.db.QueryRowContext(ctx, selectPayments+" WHERE p.invoice = ? ORDER BY p.timestamp DESC LIMIT 1", invoice))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, storageutil.StorageErr(err)
+	}
+	return &p, nil
+}
+
+func (s storageCtx) AddDeposit(ctx context.Context, txid string, vout uint32, amountSats uint64) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO unclaimed_deposits (txid, vout, amount_sats) VALUES (?, ?, ?)
+		 ON CONFLICT (txid, vout) DO UPDATE SET amount_sats = excluded.amount_sats`,
+		txid, int64(vout), int64(amountSats))
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) DeleteDeposit(ctx context.Context, txid string, vout uint32) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM unclaimed_deposits WHERE txid = ? AND vout = ?", txid, int64(vout))
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) ListDeposits(ctx context.Context) ([]breez_sdk_spark.DepositInfo, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT txid, vout, amount_sats, claim_error, refund_tx, refund_tx_id
+		 FROM unclaimed_deposits ORDER BY txid, vout`)
+	if err != nil {
+		return nil, storageutil.StorageErr(err)
+	}
+	defer rows.Close()
+
+	var deposits []breez_sdk_spark.DepositInfo
+	for rows.Next() {
+		var (
+			d          breez_sdk_spark.DepositInfo
+			vout       int64
+			amount     int64
+			claimError []byte
+		)
+		if err := rows.Scan(&d.Txid, &vout, &amount, &claimError, &d.RefundTx, &d.RefundTxId); err != nil {
+			return nil, storageutil.StorageErr(err)
+		}
+		d.Vout = uint32(vout)
+		d.AmountSats = uint64(amount)
+		if d.ClaimError, err = storageutil.DecodeOptionalDepositClaimError(claimError); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, d)
+	}
+	return deposits, storageutil.StorageErr(rows.Err())
+}
+
+// UpdateDeposit records a claim error or refund. The SDK can report them for
+// a deposit it has not added yet, which is then inserted with a zero amount.
+func (s storageCtx) UpdateDeposit(ctx context.Context, txid string, vout uint32, payload breez_sdk_spark.UpdateDepositPayload) error {
+	var err error
+	switch p := payload.(type) {
+	case breez_sdk_spark.UpdateDepositPayloadClaimError:
+		_, err = s.db.ExecContext(ctx,
+			`INSERT INTO unclaimed_deposits (txid, vout, amount_sats, claim_error) VALUES (?, ?, 0, ?)
+			 ON CONFLICT (txid, vout) DO UPDATE SET claim_error = excluded.claim_error`,
+			txid, int64(vout), storageutil.EncodeDepositClaimError(p.Error))
+	case breez_sdk_spark.UpdateDepositPayloadRefund:
+		_, err = s.db.ExecContext(ctx,
+			`INSERT INTO unclaimed_deposits (txid, vout, amount_sats, refund_tx, refund_tx_id) VALUES (?, ?, 0, ?, ?)
+			 ON CONFLICT (txid, vout) DO UPDATE SET refund_tx = excluded.refund_tx, refund_tx_id = excluded.refund_tx_id`,
+			txid, int64(vout), p.RefundTx, p.RefundTxid)
+	default:
+		err = fmt.Errorf("unknown deposit update %T", payload)
+	}
+	return storageutil.StorageErr(err)
+}
+
+func (s storageCtx) SetLnurlMetadata(ctx context.Context, metadata []breez_sdk_spark.SetLnurlMetadataItem) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storageutil.StorageErr(err)
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO lnurl_receive_metadata (payment_hash, nostr_zap_request, nostr_zap_receipt, sender_comment)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT (payment_hash) DO UPDATE SET
+			nostr_zap_request = excluded.nostr_zap_request,
+			nostr_zap_receipt = excluded.nostr_zap_receipt,
+			sender_comment = excluded.sender_comment`)
+	if err != nil {
+		return storageutil.StorageErr(err)
+	}
+	defer stmt.Close()
+	for _, item := range metadata {
+		if _, err := stmt.ExecContext(ctx, item.PaymentHash, item.NostrZapRequest, item.NostrZapReceipt, item.SenderComment); err != nil {
+			return storageutil.StorageErr(err)
+		}
+	}
+	return storageutil.StorageErr(tx.Commit())
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPayment(row scanner) (breez_sdk_spark.Payment, error) {
+	var (
+		data              []byte
+		payInfo           []byte
+		withdrawInfo      []byte
+		description       *string
+		receiveHash       *string
+		metadata          breez_sdk_spark.PaymentMetadata
+		receive           breez_sdk_spark.LnurlReceiveMetadata
+		hasStoredMetadata bool
+	)
+	if err := row.Scan(&data, &payInfo, &withdrawInfo, &description,
+		&receiveHash, &receive.NostrZapRequest, &receive.NostrZapReceipt, &receive.SenderComment); err != nil {
+		return breez_sdk_spark.Payment{}, err
+	}
+	p, err := storageutil.DecodePayment(data)
+	if err != nil {
+		return p, err
+	}
+	if metadata.LnurlPayInfo, err = storageutil.DecodeOptionalLnurlPayInfo(payInfo); err != nil {
+		return p, err
+	}
+	if metadata.LnurlWithdrawInfo, err = storageutil.DecodeOptionalLnurlWithdrawInfo(withdrawInfo); err != nil {
+		return p, err
+	}
+	metadata.LnurlDescription = description
+	hasStoredMetadata = payInfo != nil || withdrawInfo != nil || description != nil
+	var metadataPtr *breez_sdk_spark.PaymentMetadata
+	if hasStoredMetadata {
+		metadataPtr = &metadata
+	}
+	var receivePtr *breez_sdk_spark.LnurlReceiveMetadata
+	if receiveHash != nil {
+		receivePtr = &receive
+	}
+	return storageutil.ApplyMetadata(p, metadataPtr, receivePtr), nil
+}
+
+func amountString(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
@@ -0,0 +1,169 @@
+package sqlitestorage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+	"github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations/storagetest"
+)
+
+func TestStorageSuite(t *testing.T) {
+	storagetest.RunStorageSuite(t, func(t *testing.T) breez_sdk_spark.Storage {
+		s, err := New(filepath.Join(t.TempDir(), "storage.sql"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
+
+func TestStorageSuiteInMemory(t *testing.T) {
+	storagetest.RunStorageSuite(t, func(t *testing.T) breez_sdk_spark.Storage {
+		s, err := New(":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
+
+func TestRoundTripAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.sql")
+	s, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := storagetest.Payment("p1", 100, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, storagetest.Lightning("lnbc1", "h1"))
+	description := "for the stream"
+	if err := s.InsertPayment(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetPaymentMetadata("p1", breez_sdk_spark.PaymentMetadata{LnurlDescription: &description}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	got, err := s.GetPaymentById("p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Amount.Cmp(p.Amount) != 0 || got.Timestamp != 100 || got.Method != breez_sdk_spark.PaymentMethodLightning {
+		t.Fatalf("got %+v", got)
+	}
+	d, ok := (*got.Details).(breez_sdk_spark.PaymentDetailsLightning)
+	if !ok || d.Invoice != "lnbc1" || d.LnurlPayInfo != nil {
+		t.Fatalf("details %+v", *got.Details)
+	}
+	if byInvoice, err := s.GetPaymentByInvoice("lnbc1"); err != nil || byInvoice == nil || byInvoice.Id != "p1" {
+		t.Fatalf("by invoice: %v, %v", byInvoice, err)
+	}
+}
+
+func TestFilterColumns(t *testing.T) {
+	s, err := New(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for i, status := range []breez_sdk_spark.PaymentStatus{
+		breez_sdk_spark.PaymentStatusCompleted,
+		breez_sdk_spark.PaymentStatusFailed,
+		breez_sdk_spark.PaymentStatusCompleted,
+	} {
+		p := storagetest.Payment(string(rune('a'+i)), uint64(10*(i+1)), breez_sdk_spark.PaymentTypeReceive, status, nil)
+		if err := s.InsertPayment(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from := uint64(20)
+	got, err := s.ListPayments(breez_sdk_spark.ListPaymentsRequest{
+		StatusFilter:  &[]breez_sdk_spark.PaymentStatus{breez_sdk_spark.PaymentStatusCompleted},
+		FromTimestamp: &from,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := storagetest.IDs(got); !slices.Equal(ids, []string{"c"}) {
+		t.Fatalf("got %v", ids)
+	}
+
+	// The filter columns can be queried directly for reports.
+	var total int
+	if err := s.DB().QueryRow("SELECT SUM(CAST(amount AS INTEGER)) FROM payments WHERE status = 'completed'").Scan(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total != 40_000 {
+		t.Fatalf("total %d", total)
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.sql")
+	// A database left at the first schema version by an older release.
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(migrations[0] + "PRAGMA user_version = 1;"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO cached_items (key, value) VALUES ('k', 'v')"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	s, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var version int
+	s.DB().QueryRow("PRAGMA user_version").Scan(&version)
+	if version != len(migrations) {
+		t.Fatalf("schema version %d", version)
+	}
+	if v, err := s.GetCachedItem("k"); err != nil || v == nil || *v != "v" {
+		t.Fatalf("data lost in migration: %v, %v", v, err)
+	}
+	// Tables added by later migrations are there.
+	if err := s.SetLnurlMetadata([]breez_sdk_spark.SetLnurlMetadataItem{{PaymentHash: "h"}}); err != nil {
+		t.Fatal(err)
+	}
+	s.DB().Exec("PRAGMA user_version = 99")
+	s.Close()
+
+	if _, err := New(path); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Fatalf("opened a newer schema: %v", err)
+	}
+}
+
+func TestCtxCancelsQueries(t *testing.T) {
+	s, err := New(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Ctx().ListPayments(ctx, breez_sdk_spark.ListPaymentsRequest{}); err == nil {
+		t.Fatal("query ran with a cancelled context")
+	}
+	if err := s.Ctx().SetCachedItem(ctx, "k", "v"); err == nil {
+		t.Fatal("write ran with a cancelled context")
+	}
+	if v, err := s.GetCachedItem("k"); err != nil || v != nil {
+		t.Fatalf("got %v, %v", v, err)
+	}
+}
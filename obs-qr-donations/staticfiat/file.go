@@ -0,0 +1,237 @@
+package staticfiat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// fileData is the layout of a rates file:
+//
+//	currencies:            # optional, derived from rates when omitted
+//	  - id: USD
+//	    name: US Dollar
+//	    fraction_size: 2
+//	    symbol: {grapheme: "$", template: "$1", position: 1}
+//	rates:
+//	  USD: 97000.5
+//	  EUR: 89000
+type fileData struct {
+	Currencies []fileCurrency     `json:"currencies" yaml:"currencies"`
+	Rates      map[string]float64 `json:"rates" yaml:"rates"`
+}
+
+type fileCurrency struct {
+	ID              string           `json:"id" yaml:"id"`
+	Name            string           `json:"name" yaml:"name"`
+	FractionSize    uint32           `json:"fraction_size" yaml:"fraction_size"`
+	Spacing         *uint32          `json:"spacing" yaml:"spacing"`
+	Symbol          *fileSymbol      `json:"symbol" yaml:"symbol"`
+	UniqSymbol      *fileSymbol      `json:"uniq_symbol" yaml:"uniq_symbol"`
+	LocalizedName   []fileLocalName  `json:"localized_name" yaml:"localized_name"`
+	LocaleOverrides []fileLocaleOver `json:"locale_overrides" yaml:"locale_overrides"`
+}
+
+type fileSymbol struct {
+	Grapheme *string `json:"grapheme" yaml:"grapheme"`
+	Template *string `json:"template" yaml:"template"`
+	Rtl      *bool   `json:"rtl" yaml:"rtl"`
+	Position *uint32 `json:"position" yaml:"position"`
+}
+
+type fileLocalName struct {
+	Locale string `json:"locale" yaml:"locale"`
+	Name   string `json:"name" yaml:"name"`
+}
+
+type fileLocaleOver struct {
+	Locale  string     `json:"locale" yaml:"locale"`
+	Spacing *uint32    `json:"spacing" yaml:"spacing"`
+	Symbol  fileSymbol `json:"symbol" yaml:"symbol"`
+}
+
+func (s *fileSymbol) toSDK() *breez_sdk_spark.Symbol {
+	if s == nil {
+		return nil
+	}
+	return &breez_sdk_spark.Symbol{Grapheme: s.Grapheme, Template: s.Template, Rtl: s.Rtl, Position: s.Position}
+}
+
+// Parse decodes a rates file, in JSON if it starts with "{" and in YAML
+// otherwise. Unknown fields are rejected to catch typos. Currencies default
+// to the ids of the rates, with two decimals.
+func Parse(data []byte) ([]breez_sdk_spark.FiatCurrency, []breez_sdk_spark.Rate, error) {
+	var f fileData
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&f); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	rates := make([]breez_sdk_spark.Rate, 0, len(f.Rates))
+	for coin, value := range f.Rates {
+		if coin == "" || value <= 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, nil, fmt.Errorf("invalid rate %v for %q", value, coin)
+		}
+		rates = append(rates, breez_sdk_spark.Rate{Coin: coin, Value: value})
+	}
+	slices.SortFunc(rates, func(a, b breez_sdk_spark.Rate) int { return strings.Compare(a.Coin, b.Coin) })
+
+	var currencies []breez_sdk_spark.FiatCurrency
+	if f.Currencies == nil {
+		for _, r := range rates {
+			currencies = append(currencies, breez_sdk_spark.FiatCurrency{Id: r.Coin, Info: breez_sdk_spark.CurrencyInfo{Name: r.Coin, FractionSize: 2}})
+		}
+		return currencies, rates, nil
+	}
+	seen := make(map[string]bool)
+	for _, c := range f.Currencies {
+		if c.ID == "" {
+			return nil, nil, errors.New("currency without an id")
+		}
+		if seen[c.ID] {
+			return nil, nil, fmt.Errorf("currency %s listed twice", c.ID)
+		}
+		seen[c.ID] = true
+		info := breez_sdk_spark.CurrencyInfo{
+			Name:         c.Name,
+			FractionSize: c.FractionSize,
+			Spacing:      c.Spacing,
+			Symbol:       c.Symbol.toSDK(),
+			UniqSymbol:   c.UniqSymbol.toSDK(),
+		}
+		for _, n := range c.LocalizedName {
+			info.LocalizedName = append(info.LocalizedName, breez_sdk_spark.LocalizedName{Locale: n.Locale, Name: n.Name})
+		}
+		for _, o := range c.LocaleOverrides {
+			info.LocaleOverrides = append(info.LocaleOverrides, breez_sdk_spark.LocaleOverrides{Locale: o.Locale, Spacing: o.Spacing, Symbol: *o.Symbol.toSDK()})
+		}
+		if info.Name == "" {
+			info.Name = c.ID
+		}
+		currencies = append(currencies, breez_sdk_spark.FiatCurrency{Id: c.ID, Info: info})
+	}
+	return currencies, rates, nil
+}
+
+// FileConfig configures a FileService.
+type FileConfig struct {
+	// Path of the rates file, in the format described at Parse.
+	Path string
+	// Logger receives a warning when the file cannot be reloaded.
+	// Defaults to no logging.
+	Logger *slog.Logger
+}
+
+// FileService is a breez_sdk_spark.FiatService serving the contents of a
+// file. The file is checked for changes on every call and reloaded when
+// its size or modification time changed. If it becomes unreadable or
+// invalid the last good contents keep being served, so a half written
+// file never takes fiat amounts off the overlay.
+type FileService struct {
+	path   string
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	static  *Service
+	size    int64
+	modTime time.Time
+	lastErr error
+}
+
+var _ breez_sdk_spark.FiatService = (*FileService)(nil)
+
+// OpenFile loads the file at cfg.Path, which must exist and be valid.
+func OpenFile(cfg FileConfig) (*FileService, error) {
+	s := &FileService{path: filepath.Clean(cfg.Path), logger: cfg.Logger, static: New(nil, nil)}
+	if s.logger == nil {
+		s.logger = slog.New(slog.DiscardHandler)
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// FetchFiatCurrencies returns the currencies of the file.
+func (s *FileService) FetchFiatCurrencies() ([]breez_sdk_spark.FiatCurrency, error) {
+	s.refresh()
+	return s.static.FetchFiatCurrencies()
+}
+
+// FetchFiatRates returns the rates of the file.
+func (s *FileService) FetchFiatRates() ([]breez_sdk_spark.Rate, error) {
+	s.refresh()
+	return s.static.FetchFiatRates()
+}
+
+// Err returns the reason the latest version of the file could not be
+// loaded, or nil if the service is up to date with it.
+func (s *FileService) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+func (s *FileService) refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.lastErr
+	if err := s.reload(); err != nil && (prev == nil || err.Error() != prev.Error()) {
+		s.logger.Warn("fiat rates file not reloaded, serving previous contents", "path", s.path, "err", err)
+	}
+}
+
+// reload loads the file if it changed. It must be called with mu held,
+// except from OpenFile.
+func (s *FileService) reload() error {
+	info, err := os.Stat(s.path)
+	if err == nil && info.Size() == s.size && info.ModTime().Equal(s.modTime) {
+		return s.lastErr
+	}
+	var data []byte
+	if err == nil {
+		data, err = os.ReadFile(s.path)
+	}
+	var currencies []breez_sdk_spark.FiatCurrency
+	var rates []breez_sdk_spark.Rate
+	if err == nil {
+		currencies, rates, err = Parse(data)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", s.path, err)
+		}
+	}
+	if info != nil {
+		// Remember the bad version too, so that it is not parsed again on
+		// every call.
+		s.size, s.modTime = info.Size(), info.ModTime()
+	}
+	if err != nil {
+		s.lastErr = err
+		return err
+	}
+	s.lastErr = nil
+	s.static.Set(currencies, rates)
+	return nil
+}
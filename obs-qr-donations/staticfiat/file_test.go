@@ -0,0 +1,176 @@
+package staticfiat
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+const yamlRates = `
+currencies:
+  - id: USD
+    name: US Dollar
+    fraction_size: 2
+    symbol: {grapheme: "$", template: "$1", position: 1}
+    localized_name:
+      - {locale: fr, name: dollar américain}
+  - id: EUR
+    name: Euro
+    fraction_size: 2
+    spacing: 1
+    symbol: {grapheme: "€", template: "1 €", rtl: false}
+    locale_overrides:
+      - locale: en-IE
+        spacing: 0
+        symbol: {grapheme: "€", template: "€1"}
+rates:
+  USD: 97000.5
+  EUR: 89000
+`
+
+const jsonRates = `{
+	"rates": {"JPY": 14500000, "CHF": 85000}
+}`
+
+func TestParse(t *testing.T) {
+	currencies, rates, err := Parse([]byte(yamlRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 2 || rates[0] != (breez_sdk_spark.Rate{Coin: "EUR", Value: 89000}) || rates[1].Value != 97000.5 {
+		t.Fatalf("rates = %+v", rates)
+	}
+	if len(currencies) != 2 || currencies[0].Id != "USD" || currencies[1].Id != "EUR" {
+		t.Fatalf("currencies = %+v", currencies)
+	}
+	usd, eur := currencies[0].Info, currencies[1].Info
+	if usd.Name != "US Dollar" || usd.FractionSize != 2 || *usd.Symbol.Grapheme != "$" || *usd.Symbol.Position != 1 || usd.Symbol.Rtl != nil {
+		t.Fatalf("USD = %+v", usd)
+	}
+	if len(usd.LocalizedName) != 1 || usd.LocalizedName[0].Name != "dollar américain" {
+		t.Fatalf("USD names = %+v", usd.LocalizedName)
+	}
+	if *eur.Spacing != 1 || len(eur.LocaleOverrides) != 1 || *eur.LocaleOverrides[0].Spacing != 0 || *eur.LocaleOverrides[0].Symbol.Template != "€1" {
+		t.Fatalf("EUR = %+v", eur)
+	}
+
+	currencies, rates, err = Parse([]byte(jsonRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 2 || rates[0].Coin != "CHF" {
+		t.Fatalf("rates = %+v", rates)
+	}
+	if len(currencies) != 2 || currencies[1].Id != "JPY" || currencies[1].Info.Name != "JPY" || currencies[1].Info.FractionSize != 2 {
+		t.Fatalf("derived currencies = %+v", currencies)
+	}
+
+	for name, bad := range map[string]string{
+		"typo":          "ratez: {USD: 1}",
+		"json typo":     `{"rates": {"USD": 1}, "currency": []}`,
+		"negative rate": "rates: {USD: -1}",
+		"zero rate":     `{"rates": {"USD": 0}}`,
+		"duplicate":     "currencies: [{id: USD}, {id: USD}]",
+		"missing id":    "currencies: [{name: Dollar}]",
+		"not a map":     "rates: [1, 2]",
+	} {
+		if _, _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("%s: parsed", name)
+		}
+	}
+}
+
+func TestStatic(t *testing.T) {
+	rates := []breez_sdk_spark.Rate{{Coin: "USD", Value: 100_000}}
+	s := New([]breez_sdk_spark.FiatCurrency{{Id: "USD"}}, rates)
+	rates[0].Value = 1
+	got, _ := s.FetchFiatRates()
+	if got[0].Value != 100_000 {
+		t.Fatal("Service shares the caller's slice")
+	}
+	s.Set(nil, []breez_sdk_spark.Rate{{Coin: "USD", Value: 50_000}})
+	if got, _ := s.FetchFiatRates(); got[0].Value != 50_000 {
+		t.Fatalf("rates = %+v", got)
+	}
+	if got, _ := s.FetchFiatCurrencies(); len(got) != 0 {
+		t.Fatalf("currencies = %+v", got)
+	}
+}
+
+func writeFile(t *testing.T, path, data string, mtime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Make every write visible whatever the file system's timestamp
+	// granularity.
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.yaml")
+	mtime := time.Unix(1_700_000_000, 0)
+	writeFile(t, path, yamlRates, mtime)
+	var logs bytes.Buffer
+	s, err := OpenFile(FileConfig{Path: path, Logger: slog.New(slog.NewTextHandler(&logs, nil))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates, _ := s.FetchFiatRates(); len(rates) != 2 || rates[1].Value != 97000.5 {
+		t.Fatalf("rates = %+v", rates)
+	}
+
+	writeFile(t, path, "rates: {USD: 98000}", mtime.Add(time.Second))
+	if rates, _ := s.FetchFiatRates(); len(rates) != 1 || rates[0].Value != 98000 {
+		t.Fatalf("after edit: rates = %+v", rates)
+	}
+	if currencies, _ := s.FetchFiatCurrencies(); len(currencies) != 1 || currencies[0].Id != "USD" {
+		t.Fatalf("after edit: currencies = %+v", currencies)
+	}
+
+	// A broken edit keeps the previous rates, and is reported once.
+	writeFile(t, path, "rates: {USD: 99000", mtime.Add(2*time.Second))
+	for range 3 {
+		if rates, _ := s.FetchFiatRates(); len(rates) != 1 || rates[0].Value != 98000 {
+			t.Fatalf("after broken edit: rates = %+v", rates)
+		}
+	}
+	if s.Err() == nil {
+		t.Fatal("Err() = nil after a broken edit")
+	}
+	if n := bytes.Count(logs.Bytes(), []byte("not reloaded")); n != 1 {
+		t.Fatalf("logged %d warnings:\n%s", n, logs.String())
+	}
+
+	os.Remove(path)
+	if rates, _ := s.FetchFiatRates(); len(rates) != 1 {
+		t.Fatalf("after removal: rates = %+v", rates)
+	}
+
+	writeFile(t, path, jsonRates, mtime.Add(3*time.Second))
+	if rates, _ := s.FetchFiatRates(); len(rates) != 2 || rates[0].Coin != "CHF" {
+		t.Fatalf("after fix: rates = %+v", rates)
+	}
+	if s.Err() != nil {
+		t.Fatalf("Err() = %v after a good edit", s.Err())
+	}
+}
+
+func TestOpenFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := OpenFile(FileConfig{Path: filepath.Join(dir, "missing.json")}); err == nil {
+		t.Fatal("opened a missing file")
+	}
+	bad := filepath.Join(dir, "bad.json")
+	writeFile(t, bad, `{"rates": {"USD": "a lot"}}`, time.Now())
+	if _, err := OpenFile(FileConfig{Path: bad}); err == nil {
+		t.Fatal("opened an invalid file")
+	}
+}
@@ -0,0 +1,55 @@
+// Package staticfiat provides FiatService implementations that need no
+// rate provider: a fixed set of rates for tests, and a rates file for
+// streams that run offline or want to pin their rates.
+//
+//	rates, err := staticfiat.OpenFile(staticfiat.FileConfig{Path: "rates.yaml"})
+//	...
+//	builder.WithFiatService(rates)
+package staticfiat
+
+import (
+	"slices"
+	"sync"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// Service is a breez_sdk_spark.FiatService returning fixed currencies and
+// rates. It is safe for concurrent use.
+type Service struct {
+	mu         sync.Mutex
+	currencies []breez_sdk_spark.FiatCurrency
+	rates      []breez_sdk_spark.Rate
+}
+
+var _ breez_sdk_spark.FiatService = (*Service)(nil)
+
+// New returns a Service serving currencies and rates.
+func New(currencies []breez_sdk_spark.FiatCurrency, rates []breez_sdk_spark.Rate) *Service {
+	s := &Service{}
+	s.Set(currencies, rates)
+	return s
+}
+
+// Set replaces the currencies and rates, e.g. for a test to move the
+// price.
+func (s *Service) Set(currencies []breez_sdk_spark.FiatCurrency, rates []breez_sdk_spark.Rate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.currencies = slices.Clone(currencies)
+	s.rates = slices.Clone(rates)
+}
+
+// FetchFiatCurrencies returns the currencies.
+func (s *Service) FetchFiatCurrencies() ([]breez_sdk_spark.FiatCurrency, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.currencies), nil
+}
+
+// FetchFiatRates returns the rates.
+func (s *Service) FetchFiatRates() ([]breez_sdk_spark.Rate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.rates), nil
+}
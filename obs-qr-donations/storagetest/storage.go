@@ -0,0 +1,540 @@
+// Package storagetest checks Storage and SyncStorage implementations against
+// the contract the SDK relies on. Backends call the suites from their own
+// tests:
+//
+//	func TestStorageSuite(t *testing.T) {
+//		storagetest.RunStorageSuite(t, func(t *testing.T) breez_sdk_spark.Storage {
+//			return newStorage(t)
+//		})
+//	}
+package storagetest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// StorageFactory returns an empty Storage for one subtest. Use t.Cleanup to
+// release it.
+type StorageFactory func(t *testing.T) breez_sdk_spark.Storage
+
+// RunStorageSuite runs the Storage conformance tests as subtests of t, each
+// against a fresh Storage from factory.
+func RunStorageSuite(t *testing.T, factory StorageFactory) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, breez_sdk_spark.Storage)
+	}{
+		{"CachedItems", testCachedItems},
+		{"InsertAndGet", testInsertAndGet},
+		{"PaginationOrder", testPaginationOrder},
+		{"PaginationTies", testPaginationTies},
+		{"Filters", testFilters},
+		{"MetadataMerge", testMetadataMerge},
+		{"LnurlReceiveMetadata", testLnurlReceiveMetadata},
+		{"PaymentByInvoice", testPaymentByInvoice},
+		{"DepositLifecycle", testDepositLifecycle},
+		{"UpdateUnknownDeposit", testUpdateUnknownDeposit},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, factory(t))
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// Payment returns a payment of ts*1000 sats at time ts, with details when
+// not nil.
+func Payment(id string, ts uint64, typ breez_sdk_spark.PaymentType, status breez_sdk_spark.PaymentStatus, details breez_sdk_spark.PaymentDetails) breez_sdk_spark.Payment {
+	p := breez_sdk_spark.Payment{
+		Id:          id,
+		PaymentType: typ,
+		Status:      status,
+		Amount:      big.NewInt(int64(ts) * 1000),
+		Fees:        big.NewInt(1),
+		Timestamp:   ts,
+		Method:      breez_sdk_spark.PaymentMethodSpark,
+	}
+	if details != nil {
+		p.Details = &details
+		switch details.(type) {
+		case breez_sdk_spark.PaymentDetailsToken:
+			p.Method = breez_sdk_spark.PaymentMethodToken
+		case breez_sdk_spark.PaymentDetailsLightning:
+			p.Method = breez_sdk_spark.PaymentMethodLightning
+		}
+	}
+	return p
+}
+
+// Lightning returns the details of a Lightning payment.
+func Lightning(invoice, hash string) breez_sdk_spark.PaymentDetails {
+	return breez_sdk_spark.PaymentDetailsLightning{
+		Description:       ptr("original"),
+		Invoice:           invoice,
+		PaymentHash:       hash,
+		DestinationPubkey: "02" + hash,
+	}
+}
+
+// IDs returns the ids of payments, in order.
+func IDs(payments []breez_sdk_spark.Payment) []string {
+	out := make([]string, len(payments))
+	for i, p := range payments {
+		out[i] = p.Id
+	}
+	return out
+}
+
+func insert(t *testing.T, s breez_sdk_spark.Storage, payments ...breez_sdk_spark.Payment) {
+	t.Helper()
+	for _, p := range payments {
+		if err := s.InsertPayment(p); err != nil {
+			t.Fatalf("InsertPayment(%s): %v", p.Id, err)
+		}
+	}
+}
+
+func list(t *testing.T, s breez_sdk_spark.Storage, req breez_sdk_spark.ListPaymentsRequest) []string {
+	t.Helper()
+	payments, err := s.ListPayments(req)
+	if err != nil {
+		t.Fatalf("ListPayments: %v", err)
+	}
+	return IDs(payments)
+}
+
+func lightningDetails(t *testing.T, p breez_sdk_spark.Payment) breez_sdk_spark.PaymentDetailsLightning {
+	t.Helper()
+	if p.Details == nil {
+		t.Fatalf("payment %s has no details", p.Id)
+	}
+	d, ok := (*p.Details).(breez_sdk_spark.PaymentDetailsLightning)
+	if !ok {
+		t.Fatalf("payment %s has %T details, want Lightning", p.Id, *p.Details)
+	}
+	return d
+}
+
+func testCachedItems(t *testing.T, s breez_sdk_spark.Storage) {
+	if v, err := s.GetCachedItem("missing"); err != nil || v != nil {
+		t.Fatalf("GetCachedItem(missing) = %v, %v; want nil, nil", v, err)
+	}
+	if err := s.SetCachedItem("k", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCachedItem("k", "v2"); err != nil {
+		t.Fatalf("overwriting a cached item: %v", err)
+	}
+	if err := s.SetCachedItem("K", "other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCachedItem("empty", ""); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"k": "v2", "K": "other", "empty": ""} {
+		v, err := s.GetCachedItem(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v == nil || *v != want {
+			t.Fatalf("GetCachedItem(%q) = %v, want %q", key, v, want)
+		}
+	}
+	if err := s.DeleteCachedItem("k"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.GetCachedItem("k"); v != nil {
+		t.Fatalf("deleted item still cached: %q", *v)
+	}
+	if v, _ := s.GetCachedItem("K"); v == nil {
+		t.Fatal("deleting k removed K; keys must be case sensitive")
+	}
+	if err := s.DeleteCachedItem("missing"); err != nil {
+		t.Fatalf("deleting a missing item: %v", err)
+	}
+}
+
+func testInsertAndGet(t *testing.T, s breez_sdk_spark.Storage) {
+	want := Payment("p1", 100, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusPending, Lightning("lnbc1p1", "h1"))
+	want.Amount = new(big.Int).Lsh(big.NewInt(1), 100)
+	insert(t, s, want)
+
+	got, err := s.GetPaymentById("p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PaymentType != want.PaymentType || got.Status != want.Status || got.Timestamp != want.Timestamp || got.Method != want.Method {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if got.Amount == nil || got.Amount.Cmp(want.Amount) != 0 || got.Fees == nil || got.Fees.Cmp(want.Fees) != 0 {
+		t.Fatalf("amounts = %v/%v, want %v/%v", got.Amount, got.Fees, want.Amount, want.Fees)
+	}
+	if d := lightningDetails(t, got); d.Invoice != "lnbc1p1" || d.PaymentHash != "h1" {
+		t.Fatalf("details = %+v", d)
+	}
+
+	// Inserting the same id again replaces the payment.
+	want.Status = breez_sdk_spark.PaymentStatusCompleted
+	insert(t, s, want)
+	if got, _ := s.GetPaymentById("p1"); got.Status != breez_sdk_spark.PaymentStatusCompleted {
+		t.Fatalf("status after reinsert = %v", got.Status)
+	}
+	if got := list(t, s, breez_sdk_spark.ListPaymentsRequest{}); len(got) != 1 {
+		t.Fatalf("reinsert duplicated the payment: %v", got)
+	}
+
+	_, err = s.GetPaymentById("missing")
+	var storageErr *breez_sdk_spark.StorageError
+	if !errors.As(err, &storageErr) {
+		t.Fatalf("GetPaymentById(missing) = %v, want a StorageError", err)
+	}
+}
+
+func testPaginationOrder(t *testing.T, s breez_sdk_spark.Storage) {
+	for i := 1; i <= 7; i++ {
+		insert(t, s, Payment(fmt.Sprintf("p%d", i), uint64(i*10), breez_sdk_spark.PaymentTypeSend, breez_sdk_spark.PaymentStatusCompleted, nil))
+	}
+	cases := []struct {
+		name string
+		req  breez_sdk_spark.ListPaymentsRequest
+		want []string
+	}{
+		{"newest first", breez_sdk_spark.ListPaymentsRequest{}, []string{"p7", "p6", "p5", "p4", "p3", "p2", "p1"}},
+		{"ascending", breez_sdk_spark.ListPaymentsRequest{SortAscending: ptr(true)}, []string{"p1", "p2", "p3", "p4", "p5", "p6", "p7"}},
+		{"explicit descending", breez_sdk_spark.ListPaymentsRequest{SortAscending: ptr(false)}, []string{"p7", "p6", "p5", "p4", "p3", "p2", "p1"}},
+		{"limit", breez_sdk_spark.ListPaymentsRequest{Limit: ptr(uint32(2))}, []string{"p7", "p6"}},
+		{"offset", breez_sdk_spark.ListPaymentsRequest{Offset: ptr(uint32(5))}, []string{"p2", "p1"}},
+		{"offset and limit", breez_sdk_spark.ListPaymentsRequest{Offset: ptr(uint32(2)), Limit: ptr(uint32(3))}, []string{"p5", "p4", "p3"}},
+		{"ascending page", breez_sdk_spark.ListPaymentsRequest{Offset: ptr(uint32(2)), Limit: ptr(uint32(2)), SortAscending: ptr(true)}, []string{"p3", "p4"}},
+		{"offset past end", breez_sdk_spark.ListPaymentsRequest{Offset: ptr(uint32(7))}, nil},
+		{"zero limit", breez_sdk_spark.ListPaymentsRequest{Limit: ptr(uint32(0))}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := list(t, s, tc.req); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// testPaginationTies checks that paging through payments with equal
+// timestamps returns each payment exactly once.
+func testPaginationTies(t *testing.T, s breez_sdk_spark.Storage) {
+	var want []string
+	for i := 0; i < 9; i++ {
+		id := fmt.Sprintf("tie%d", i)
+		want = append(want, id)
+		insert(t, s, Payment(id, 42, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, nil))
+	}
+	for _, ascending := range []bool{false, true} {
+		var got []string
+		for offset := uint32(0); ; offset += 2 {
+			page := list(t, s, breez_sdk_spark.ListPaymentsRequest{
+				Offset:        ptr(offset),
+				Limit:         ptr(uint32(2)),
+				SortAscending: ptr(ascending),
+			})
+			if len(page) == 0 {
+				break
+			}
+			got = append(got, page...)
+		}
+		sort.Strings(got)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("ascending=%v: paging returned %v, want each of %v once", ascending, got, want)
+		}
+	}
+}
+
+func testFilters(t *testing.T, s breez_sdk_spark.Storage) {
+	htlc := func(status breez_sdk_spark.SparkHtlcStatus) breez_sdk_spark.PaymentDetails {
+		return breez_sdk_spark.PaymentDetailsSpark{HtlcDetails: &breez_sdk_spark.SparkHtlcDetails{PaymentHash: "h", Status: status}}
+	}
+	token := func(id string) breez_sdk_spark.PaymentDetails {
+		return breez_sdk_spark.PaymentDetailsToken{Metadata: breez_sdk_spark.TokenMetadata{Identifier: id, MaxSupply: big.NewInt(0)}}
+	}
+	insert(t, s,
+		Payment("a", 10, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, nil),
+		Payment("b", 20, breez_sdk_spark.PaymentTypeSend, breez_sdk_spark.PaymentStatusPending, htlc(breez_sdk_spark.SparkHtlcStatusWaitingForPreimage)),
+		Payment("c", 30, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusFailed, token("t1")),
+		Payment("d", 40, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, token("t2")),
+		Payment("e", 50, breez_sdk_spark.PaymentTypeSend, breez_sdk_spark.PaymentStatusCompleted, htlc(breez_sdk_spark.SparkHtlcStatusReturned)),
+		Payment("f", 60, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, Lightning("lnbc1f", "hf")),
+	)
+
+	var bitcoin breez_sdk_spark.AssetFilter = breez_sdk_spark.AssetFilterBitcoin{}
+	var anyToken breez_sdk_spark.AssetFilter = breez_sdk_spark.AssetFilterToken{}
+	var tokenT2 breez_sdk_spark.AssetFilter = breez_sdk_spark.AssetFilterToken{TokenIdentifier: ptr("t2")}
+	var tokenMissing breez_sdk_spark.AssetFilter = breez_sdk_spark.AssetFilterToken{TokenIdentifier: ptr("t9")}
+	send := []breez_sdk_spark.PaymentType{breez_sdk_spark.PaymentTypeSend}
+	receive := []breez_sdk_spark.PaymentType{breez_sdk_spark.PaymentTypeReceive}
+	completed := []breez_sdk_spark.PaymentStatus{breez_sdk_spark.PaymentStatusCompleted}
+
+	cases := []struct {
+		name string
+		req  breez_sdk_spark.ListPaymentsRequest
+		want []string
+	}{
+		{"empty type filter", breez_sdk_spark.ListPaymentsRequest{TypeFilter: &[]breez_sdk_spark.PaymentType{}}, []string{"f", "e", "d", "c", "b", "a"}},
+		{"type", breez_sdk_spark.ListPaymentsRequest{TypeFilter: &send}, []string{"e", "b"}},
+		{"both types", breez_sdk_spark.ListPaymentsRequest{TypeFilter: &[]breez_sdk_spark.PaymentType{breez_sdk_spark.PaymentTypeSend, breez_sdk_spark.PaymentTypeReceive}}, []string{"f", "e", "d", "c", "b", "a"}},
+		{"status", breez_sdk_spark.ListPaymentsRequest{StatusFilter: &[]breez_sdk_spark.PaymentStatus{breez_sdk_spark.PaymentStatusPending, breez_sdk_spark.PaymentStatusFailed}}, []string{"c", "b"}},
+		{"bitcoin", breez_sdk_spark.ListPaymentsRequest{AssetFilter: &bitcoin}, []string{"f", "e", "b", "a"}},
+		{"any token", breez_sdk_spark.ListPaymentsRequest{AssetFilter: &anyToken}, []string{"d", "c"}},
+		{"one token", breez_sdk_spark.ListPaymentsRequest{AssetFilter: &tokenT2}, []string{"d"}},
+		{"unknown token", breez_sdk_spark.ListPaymentsRequest{AssetFilter: &tokenMissing}, nil},
+		{"htlc status", breez_sdk_spark.ListPaymentsRequest{SparkHtlcStatusFilter: &[]breez_sdk_spark.SparkHtlcStatus{breez_sdk_spark.SparkHtlcStatusReturned}}, []string{"e"}},
+		{"from inclusive", breez_sdk_spark.ListPaymentsRequest{FromTimestamp: ptr(uint64(50))}, []string{"f", "e"}},
+		{"to exclusive", breez_sdk_spark.ListPaymentsRequest{ToTimestamp: ptr(uint64(20))}, []string{"a"}},
+		{"time range", breez_sdk_spark.ListPaymentsRequest{FromTimestamp: ptr(uint64(20)), ToTimestamp: ptr(uint64(40))}, []string{"c", "b"}},
+		{"type and status", breez_sdk_spark.ListPaymentsRequest{TypeFilter: &receive, StatusFilter: &completed}, []string{"f", "d", "a"}},
+		{"type status and asset", breez_sdk_spark.ListPaymentsRequest{TypeFilter: &receive, StatusFilter: &completed, AssetFilter: &bitcoin}, []string{"f", "a"}},
+		{"send and htlc", breez_sdk_spark.ListPaymentsRequest{
+			TypeFilter:            &send,
+			SparkHtlcStatusFilter: &[]breez_sdk_spark.SparkHtlcStatus{breez_sdk_spark.SparkHtlcStatusWaitingForPreimage, breez_sdk_spark.SparkHtlcStatusReturned},
+			ToTimestamp:           ptr(uint64(50)),
+		}, []string{"b"}},
+		{"filters with paging", breez_sdk_spark.ListPaymentsRequest{
+			TypeFilter:    &receive,
+			FromTimestamp: ptr(uint64(5)),
+			SortAscending: ptr(true),
+			Offset:        ptr(uint32(1)),
+			Limit:         ptr(uint32(2)),
+		}, []string{"c", "d"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := list(t, s, tc.req); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func testMetadataMerge(t *testing.T, s breez_sdk_spark.Storage) {
+	insert(t, s, Payment("ln", 10, breez_sdk_spark.PaymentTypeSend, breez_sdk_spark.PaymentStatusCompleted, Lightning("lnbc1ln", "hln")))
+
+	// Metadata may arrive before the payment does.
+	if err := s.SetPaymentMetadata("later", breez_sdk_spark.PaymentMetadata{LnurlDescription: ptr("early")}); err != nil {
+		t.Fatal(err)
+	}
+	insert(t, s, Payment("later", 20, breez_sdk_spark.PaymentTypeSend, breez_sdk_spark.PaymentStatusCompleted, Lightning("lnbc1later", "hlater")))
+	later, err := s.GetPaymentById("later")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := lightningDetails(t, later); d.Description == nil || *d.Description != "early" {
+		t.Fatalf("description = %v, want early", d.Description)
+	}
+
+	payInfo := breez_sdk_spark.LnurlPayInfo{LnAddress: ptr("tips@example.com"), Comment: ptr("gg")}
+	steps := []breez_sdk_spark.PaymentMetadata{
+		{LnurlPayInfo: &payInfo},
+		{LnurlDescription: ptr("first")},
+		{LnurlWithdrawInfo: &breez_sdk_spark.LnurlWithdrawInfo{WithdrawUrl: "https://example.com/w"}},
+		{LnurlDescription: ptr("second")},
+		{},
+	}
+	for _, m := range steps {
+		if err := s.SetPaymentMetadata("ln", m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, get := range []func() (breez_sdk_spark.Payment, error){
+		func() (breez_sdk_spark.Payment, error) { return s.GetPaymentById("ln") },
+		func() (breez_sdk_spark.Payment, error) {
+			payments, err := s.ListPayments(breez_sdk_spark.ListPaymentsRequest{FromTimestamp: ptr(uint64(10)), ToTimestamp: ptr(uint64(11))})
+			if err != nil || len(payments) != 1 {
+				return breez_sdk_spark.Payment{}, fmt.Errorf("ListPayments = %v, %v", IDs(payments), err)
+			}
+			return payments[0], nil
+		},
+	} {
+		p, err := get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := lightningDetails(t, p)
+		if d.LnurlPayInfo == nil || d.LnurlPayInfo.LnAddress == nil || *d.LnurlPayInfo.LnAddress != "tips@example.com" ||
+			d.LnurlPayInfo.Comment == nil || *d.LnurlPayInfo.Comment != "gg" {
+			t.Fatalf("pay info = %+v", d.LnurlPayInfo)
+		}
+		if d.LnurlWithdrawInfo == nil || d.LnurlWithdrawInfo.WithdrawUrl != "https://example.com/w" {
+			t.Fatalf("withdraw info = %+v", d.LnurlWithdrawInfo)
+		}
+		if d.Description == nil || *d.Description != "second" {
+			t.Fatalf("description = %v, want second", d.Description)
+		}
+	}
+}
+
+func testLnurlReceiveMetadata(t *testing.T, s breez_sdk_spark.Storage) {
+	insert(t, s,
+		Payment("zap", 10, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, Lightning("lnbc1zap", "hzap")),
+		Payment("plain", 20, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, Lightning("lnbc1plain", "hplain")),
+	)
+	if err := s.SetLnurlMetadata(nil); err != nil {
+		t.Fatalf("SetLnurlMetadata(nil): %v", err)
+	}
+	if err := s.SetLnurlMetadata([]breez_sdk_spark.SetLnurlMetadataItem{
+		{PaymentHash: "hzap", SenderComment: ptr("hello"), NostrZapRequest: ptr("{}")},
+		{PaymentHash: "hunknown", SenderComment: ptr("nobody")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetLnurlMetadata([]breez_sdk_spark.SetLnurlMetadataItem{
+		{PaymentHash: "hzap", SenderComment: ptr("hello again"), NostrZapReceipt: ptr("receipt")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	zap, err := s.GetPaymentById("zap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := lightningDetails(t, zap).LnurlReceiveMetadata
+	if m == nil || m.SenderComment == nil || *m.SenderComment != "hello again" || m.NostrZapReceipt == nil || *m.NostrZapReceipt != "receipt" {
+		t.Fatalf("receive metadata = %+v", m)
+	}
+	if m.NostrZapRequest != nil {
+		t.Fatalf("a later SetLnurlMetadata must replace the whole item, zap request = %q", *m.NostrZapRequest)
+	}
+	plain, err := s.GetPaymentById("plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := lightningDetails(t, plain).LnurlReceiveMetadata; m != nil {
+		t.Fatalf("unrelated payment got receive metadata %+v", m)
+	}
+}
+
+func testPaymentByInvoice(t *testing.T, s breez_sdk_spark.Storage) {
+	insert(t, s,
+		Payment("ln1", 10, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, Lightning("lnbc1one", "h1")),
+		Payment("ln2", 20, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusPending, Lightning("lnbc1two", "h2")),
+		Payment("spark", 30, breez_sdk_spark.PaymentTypeReceive, breez_sdk_spark.PaymentStatusCompleted, nil),
+	)
+	if err := s.SetPaymentMetadata("ln2", breez_sdk_spark.PaymentMetadata{LnurlDescription: ptr("tip")}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.GetPaymentByInvoice("lnbc1two")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil || p.Id != "ln2" {
+		t.Fatalf("GetPaymentByInvoice = %+v, want ln2", p)
+	}
+	if d := lightningDetails(t, *p); d.Description == nil || *d.Description != "tip" {
+		t.Fatalf("metadata not applied, description = %v", d.Description)
+	}
+	p, err = s.GetPaymentByInvoice("lnbc1missing")
+	if err != nil || p != nil {
+		t.Fatalf("GetPaymentByInvoice(missing) = %+v, %v; want nil, nil", p, err)
+	}
+}
+
+func deposits(t *testing.T, s breez_sdk_spark.Storage) map[string]breez_sdk_spark.DepositInfo {
+	t.Helper()
+	list, err := s.ListDeposits()
+	if err != nil {
+		t.Fatalf("ListDeposits: %v", err)
+	}
+	out := make(map[string]breez_sdk_spark.DepositInfo, len(list))
+	for _, d := range list {
+		key := fmt.Sprintf("%s:%d", d.Txid, d.Vout)
+		if _, dup := out[key]; dup {
+			t.Fatalf("deposit %s listed twice", key)
+		}
+		out[key] = d
+	}
+	return out
+}
+
+func claimMessage(d breez_sdk_spark.DepositInfo) string {
+	if d.ClaimError == nil {
+		return ""
+	}
+	if e, ok := (*d.ClaimError).(breez_sdk_spark.DepositClaimErrorGeneric); ok {
+		return e.Message
+	}
+	return fmt.Sprintf("%T", *d.ClaimError)
+}
+
+func testDepositLifecycle(t *testing.T, s breez_sdk_spark.Storage) {
+	if got := deposits(t, s); len(got) != 0 {
+		t.Fatalf("new storage has deposits %v", got)
+	}
+	if err := s.AddDeposit("tx1", 0, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddDeposit("tx1", 1, 2000); err != nil {
+		t.Fatal(err)
+	}
+	got := deposits(t, s)
+	if len(got) != 2 || got["tx1:0"].AmountSats != 1000 || got["tx1:1"].AmountSats != 2000 {
+		t.Fatalf("deposits = %+v", got)
+	}
+	if d := got["tx1:0"]; d.ClaimError != nil || d.RefundTx != nil || d.RefundTxId != nil {
+		t.Fatalf("fresh deposit has details %+v", d)
+	}
+
+	// Adding again updates the amount without duplicating the deposit.
+	if err := s.AddDeposit("tx1", 0, 1500); err != nil {
+		t.Fatal(err)
+	}
+	if got := deposits(t, s); len(got) != 2 || got["tx1:0"].AmountSats != 1500 {
+		t.Fatalf("after re-add = %+v", got)
+	}
+
+	var claimErr breez_sdk_spark.DepositClaimError = breez_sdk_spark.DepositClaimErrorGeneric{Message: "fee too high"}
+	if err := s.UpdateDeposit("tx1", 0, breez_sdk_spark.UpdateDepositPayloadClaimError{Error: claimErr}); err != nil {
+		t.Fatal(err)
+	}
+	if d := deposits(t, s)["tx1:0"]; claimMessage(d) != "fee too high" || d.AmountSats != 1500 {
+		t.Fatalf("after claim error = %+v", d)
+	}
+	if err := s.UpdateDeposit("tx1", 0, breez_sdk_spark.UpdateDepositPayloadRefund{RefundTxid: "refundid", RefundTx: "0200"}); err != nil {
+		t.Fatal(err)
+	}
+	d := deposits(t, s)["tx1:0"]
+	if d.RefundTxId == nil || *d.RefundTxId != "refundid" || d.RefundTx == nil || *d.RefundTx != "0200" {
+		t.Fatalf("after refund = %+v", d)
+	}
+	if claimMessage(d) != "fee too high" {
+		t.Fatalf("refund cleared the claim error: %+v", d)
+	}
+	if other := deposits(t, s)["tx1:1"]; other.ClaimError != nil || other.RefundTx != nil {
+		t.Fatalf("update touched another output: %+v", other)
+	}
+
+	if err := s.DeleteDeposit("tx1", 0); err != nil {
+		t.Fatal(err)
+	}
+	got = deposits(t, s)
+	if _, ok := got["tx1:0"]; ok || len(got) != 1 {
+		t.Fatalf("after delete = %+v", got)
+	}
+	if err := s.DeleteDeposit("tx1", 0); err != nil {
+		t.Fatalf("deleting a missing deposit: %v", err)
+	}
+}
+
+// testUpdateUnknownDeposit checks the documented upsert: details for a
+// deposit the storage has not seen yet are kept.
+func testUpdateUnknownDeposit(t *testing.T, s breez_sdk_spark.Storage) {
+	if err := s.UpdateDeposit("tx2", 3, breez_sdk_spark.UpdateDepositPayloadRefund{RefundTxid: "r", RefundTx: "00"}); err != nil {
+		t.Fatal(err)
+	}
+	d, ok := deposits(t, s)["tx2:3"]
+	if !ok || d.RefundTxId == nil || *d.RefundTxId != "r" {
+		t.Fatalf("deposits = %+v", deposits(t, s))
+	}
+}
@@ -0,0 +1,331 @@
+package storagetest
+
+import (
+	"fmt"
+	"testing"
+
+	breez_sdk_spark "github.com/mbyotwo3-beep/OBS-studio-Plugin/obs-qr-donations"
+)
+
+// SyncStorageFactory returns an empty SyncStorage for one subtest. Use
+// t.Cleanup to release it.
+type SyncStorageFactory func(t *testing.T) breez_sdk_spark.SyncStorage
+
+// RunSyncStorageSuite runs the SyncStorage conformance tests as subtests of
+// t, each against a fresh SyncStorage from factory.
+func RunSyncStorageSuite(t *testing.T, factory SyncStorageFactory) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, breez_sdk_spark.SyncStorage)
+	}{
+		{"Empty", testSyncEmpty},
+		{"OutgoingRevisions", testOutgoingRevisions},
+		{"OutgoingParent", testOutgoingParent},
+		{"CompleteOutgoingSync", testCompleteOutgoingSync},
+		{"Rebase", testRebase},
+		{"IncomingChanges", testIncomingChanges},
+		{"UpdateRecordFromIncoming", testUpdateRecordFromIncoming},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, factory(t))
+		})
+	}
+}
+
+func recordId(dataId string) breez_sdk_spark.RecordId {
+	return breez_sdk_spark.RecordId{Type: "PaymentMetadata", DataId: dataId}
+}
+
+func record(dataId string, revision uint64, data map[string]string) breez_sdk_spark.Record {
+	return breez_sdk_spark.Record{Id: recordId(dataId), Revision: revision, SchemaVersion: "1.0.0", Data: data}
+}
+
+func addOutgoing(t *testing.T, s breez_sdk_spark.SyncStorage, dataId string, fields map[string]string) uint64 {
+	t.Helper()
+	revision, err := s.AddOutgoingChange(breez_sdk_spark.UnversionedRecordChange{
+		Id:            recordId(dataId),
+		SchemaVersion: "1.0.0",
+		UpdatedFields: fields,
+	})
+	if err != nil {
+		t.Fatalf("AddOutgoingChange(%s): %v", dataId, err)
+	}
+	return revision
+}
+
+func pending(t *testing.T, s breez_sdk_spark.SyncStorage, limit uint32) []breez_sdk_spark.OutgoingChange {
+	t.Helper()
+	changes, err := s.GetPendingOutgoingChanges(limit)
+	if err != nil {
+		t.Fatalf("GetPendingOutgoingChanges: %v", err)
+	}
+	return changes
+}
+
+func lastRevision(t *testing.T, s breez_sdk_spark.SyncStorage) uint64 {
+	t.Helper()
+	revision, err := s.GetLastRevision()
+	if err != nil {
+		t.Fatalf("GetLastRevision: %v", err)
+	}
+	return revision
+}
+
+func outgoingSummary(changes []breez_sdk_spark.OutgoingChange) []string {
+	out := make([]string, len(changes))
+	for i, c := range changes {
+		out[i] = fmt.Sprintf("%s@%d", c.Change.Id.DataId, c.Change.Revision)
+	}
+	return out
+}
+
+func incomingSummary(changes []breez_sdk_spark.IncomingChange) []string {
+	out := make([]string, len(changes))
+	for i, c := range changes {
+		out[i] = fmt.Sprintf("%s@%d", c.NewState.Id.DataId, c.NewState.Revision)
+	}
+	return out
+}
+
+func testSyncEmpty(t *testing.T, s breez_sdk_spark.SyncStorage) {
+	if got := lastRevision(t, s); got != 0 {
+		t.Fatalf("last revision = %d, want 0", got)
+	}
+	if got := pending(t, s, 10); len(got) != 0 {
+		t.Fatalf("pending = %v", outgoingSummary(got))
+	}
+	if latest, err := s.GetLatestOutgoingChange(); err != nil || latest != nil {
+		t.Fatalf("GetLatestOutgoingChange = %+v, %v; want nil, nil", latest, err)
+	}
+	if incoming, err := s.GetIncomingRecords(10); err != nil || len(incoming) != 0 {
+		t.Fatalf("GetIncomingRecords = %v, %v", incomingSummary(incoming), err)
+	}
+	if err := s.RebasePendingOutgoingRecords(5); err != nil {
+		t.Fatalf("rebasing with nothing pending: %v", err)
+	}
+}
+
+func testOutgoingRevisions(t *testing.T, s breez_sdk_spark.SyncStorage) {
+	var revisions []uint64
+	for _, id := range []string{"b", "a", "c", "a"} {
+		revisions = append(revisions, addOutgoing(t, s, id, map[string]string{"v": id}))
+	}
+	for i := 1; i < len(revisions); i++ {
+		if revisions[i] <= revisions[i-1] {
+			t.Fatalf("revisions not increasing: %v", revisions)
+		}
+	}
+	if revisions[0] <= lastRevision(t, s) {
+		t.Fatalf("pending revision %d not above last revision %d", revisions[0], lastRevision(t, s))
+	}
+
+	all := pending(t, s, 100)
+	want := []string{
+		fmt.Sprintf("b@%d", revisions[0]),
+		fmt.Sprintf("a@%d", revisions[1]),
+		fmt.Sprintf("c@%d", revisions[2]),
+		fmt.Sprintf("a@%d", revisions[3]),
+	}
+	if fmt.Sprint(outgoingSummary(all)) != fmt.Sprint(want) {
+		t.Fatalf("pending = %v, want %v", outgoingSummary(all), want)
+	}
+	if all[0].Change.UpdatedFields["v"] != "b" || all[0].Change.SchemaVersion != "1.0.0" {
+		t.Fatalf("change = %+v", all[0].Change)
+	}
+	if got := pending(t, s, 2); fmt.Sprint(outgoingSummary(got)) != fmt.Sprint(want[:2]) {
+		t.Fatalf("limited pending = %v, want %v", outgoingSummary(got), want[:2])
+	}
+
+	latest, err := s.GetLatestOutgoingChange()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest == nil || latest.Change.Revision != revisions[3] {
+		t.Fatalf("latest = %+v, want revision %d", latest, revisions[3])
+	}
+	if lastRevision(t, s) != 0 {
+		t.Fatal("adding outgoing changes moved the last synced revision")
+	}
+}
+
+func testOutgoingParent(t *testing.T, s breez_sdk_spark.SyncStorage) {
+	if err := s.UpdateRecordFromIncoming(record("a", 3, map[string]string{"v": "server"})); err != nil {
+		t.Fatal(err)
+	}
+	revision := addOutgoing(t, s, "a", map[string]string{"v": "local"})
+	if revision <= 3 {
+		t.Fatalf("revision %d not above synced revision 3", revision)
+	}
+	addOutgoing(t, s, "b", nil)
+	changes := pending(t, s, 10)
+	if len(changes) != 2 {
+		t.Fatalf("pending = %v", outgoingSummary(changes))
+	}
+	parent := changes[0].Parent
+	if parent == nil || parent.Revision != 3 || parent.Data["v"] != "server" {
+		t.Fatalf("parent = %+v", parent)
+	}
+	if changes[1].Parent != nil {
+		t.Fatalf("new record has parent %+v", changes[1].Parent)
+	}
+}
+
+func testCompleteOutgoingSync(t *testing.T, s breez_sdk_spark.SyncStorage) {
+	r1 := addOutgoing(t, s, "a", map[string]string{"v": "1"})
+	r2 := addOutgoing(t, s, "b", map[string]string{"v": "2"})
+
+	if err := s.CompleteOutgoingSync(record("a", r1, map[string]string{"v": "1"})); err != nil {
+		t.Fatal(err)
+	}
+	if got := pending(t, s, 10); len(got) != 1 || got[0].Change.Revision != r2 {
+		t.Fatalf("pending after completing a = %v", outgoingSummary(got))
+	}
+	if got := lastRevision(t, s); got != r1 {
+		t.Fatalf("last revision = %d, want %d", got, r1)
+	}
+
+	// The synced record is now the parent of further changes to a.
+	addOutgoing(t, s, "a", map[string]string{"v": "3"})
+	changes := pending(t, s, 10)
+	if parent := changes[len(changes)-1].Parent; parent == nil || parent.Revision != r1 || parent.Data["v"] != "1" {
+		t.Fatalf("parent = %+v", parent)
+	}
+
+	if err := s.CompleteOutgoingSync(record("b", r2, map[string]string{"v": "2"})); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastRevision(t, s); got != r2 {
+		t.Fatalf("last revision = %d, want %d", got, r2)
+	}
+	if got := pending(t, s, 10); len(got) != 1 || got[0].Change.Id.DataId != "a" {
+		t.Fatalf("pending = %v", outgoingSummary(got))
+	}
+}
+
+func testRebase(t *testing.T, s breez_sdk_spark.SyncStorage) {
+	if err := s.UpdateRecordFromIncoming(record("x", 2, nil)); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		addOutgoing(t, s, id, nil)
+	}
+	before := pending(t, s, 10)
+
+	// Another device pushed records up to revision 10.
+	if err := s.RebasePendingOutgoingRecords(10); err != nil {
+		t.Fatal(err)
+	}
+	after := pending(t, s, 10)
+	if len(after) != len(before) {
+		t.Fatalf("rebase changed the pending set: %v -> %v", outgoingSummary(before), outgoingSummary(after))
+	}
+	for i, c := range after {
+		if c.Change.Id != before[i].Change.Id {
+			t.Fatalf("rebase reordered changes: %v -> %v", outgoingSummary(before), outgoingSummary(after))
+		}
+		if c.Change.Revision <= 10 {
+			t.Fatalf("revision %d not above rebase point 10: %v", c.Change.Revision, outgoingSummary(after))
+		}
+		if i > 0 && c.Change.Revision <= after[i-1].Change.Revision {
+			t.Fatalf("revisions not increasing after rebase: %v", outgoingSummary(after))
+		}
+	}
+
+	// New changes still sort after the rebased ones.
+	next := addOutgoing(t, s, "d", nil)
+	if next <= after[len(after)-1].Change.Revision {
+		t.Fatalf("new revision %d not above rebased %v", next, outgoingSummary(after))
+	}
+	if latest, _ := s.GetLatestOutgoingChange(); latest == nil || latest.Change.Revision != next {
+		t.Fatalf("latest = %+v, want revision %d", latest, next)
+	}
+}
+
+func testIncomingChanges(t *testing.T, s breez_sdk_spark.SyncStorage) {
+	if err := s.UpdateRecordFromIncoming(record("a", 1, map[string]string{"v": "old"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.InsertIncomingRecords(nil); err != nil {
+		t.Fatalf("InsertIncomingRecords(nil): %v", err)
+	}
+	incoming := []breez_sdk_spark.Record{
+		record("c", 4, map[string]string{"v": "c"}),
+		record("a", 2, map[string]string{"v": "new"}),
+		record("b", 3, map[string]string{"v": "b"}),
+	}
+	if err := s.InsertIncomingRecords(incoming); err != nil {
+		t.Fatal(err)
+	}
+	// Inserting the same records again must not duplicate them.
+	if err := s.InsertIncomingRecords(incoming[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := s.GetIncomingRecords(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a@2", "b@3", "c@4"}
+	if fmt.Sprint(incomingSummary(changes)) != fmt.Sprint(want) {
+		t.Fatalf("incoming = %v, want %v", incomingSummary(changes), want)
+	}
+	if old := changes[0].OldState; old == nil || old.Revision != 1 || old.Data["v"] != "old" {
+		t.Fatalf("old state of a = %+v", old)
+	}
+	if changes[0].NewState.Data["v"] != "new" {
+		t.Fatalf("new state of a = %+v", changes[0].NewState)
+	}
+	if changes[1].OldState != nil {
+		t.Fatalf("old state of b = %+v", changes[1].OldState)
+	}
+	if limited, _ := s.GetIncomingRecords(2); fmt.Sprint(incomingSummary(limited)) != fmt.Sprint(want[:2]) {
+		t.Fatalf("limited incoming = %v", incomingSummary(limited))
+	}
+	if lastRevision(t, s) != 1 {
+		t.Fatal("inserting incoming records moved the last synced revision")
+	}
+
+	if err := s.DeleteIncomingRecord(incoming[1]); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.GetIncomingRecords(10); fmt.Sprint(incomingSummary(got)) != "[b@3 c@4]" {
+		t.Fatalf("after delete = %v", incomingSummary(got))
+	}
+	if err := s.DeleteIncomingRecord(incoming[1]); err != nil {
+		t.Fatalf("deleting a missing incoming record: %v", err)
+	}
+}
+
+func testUpdateRecordFromIncoming(t *testing.T, s breez_sdk_spark.SyncStorage) {
+	if err := s.UpdateRecordFromIncoming(record("a", 5, map[string]string{"v": "5"})); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastRevision(t, s); got != 5 {
+		t.Fatalf("last revision = %d, want 5", got)
+	}
+	if err := s.UpdateRecordFromIncoming(record("b", 3, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastRevision(t, s); got != 5 {
+		t.Fatalf("an older record moved the last revision back to %d", got)
+	}
+	if err := s.UpdateRecordFromIncoming(record("a", 7, map[string]string{"v": "7"})); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastRevision(t, s); got != 7 {
+		t.Fatalf("last revision = %d, want 7", got)
+	}
+
+	// Incoming records see the updated state as their old state.
+	if err := s.InsertIncomingRecords([]breez_sdk_spark.Record{record("a", 8, nil)}); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := s.GetIncomingRecords(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].OldState == nil || changes[0].OldState.Data["v"] != "7" {
+		t.Fatalf("incoming = %+v", changes)
+	}
+}